@@ -2,57 +2,348 @@ package main
 
 import (
 	"encoding/base64"
+	"encoding/json"
+	stderrs "errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler/estimation"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
 )
 
 var usage = `
 Usage:
   compiler -f <script path> [options]
+  compiler -f <base64 tree path> -decompile
 
 Options:
-	-compaction	Compaction mode
+    -compaction         Replace names of dApp's functions and variables with short ones
+    -verbose            Output table of original and compact names in compaction mode
     -remove-unused      Remove unused code
+    -strict             Treat warnings as errors
+    -format             Format of compilation errors output: "text" (default) or "json"
+    -abi                Output signatures of dApp's callable functions in JSON
+    -checksum           Wrap compiled script into envelope with length and SHA-256 checksum, not for submission
+    -builtins-file      Path to JSON file with additional built-in functions definitions
+    -max-callables      Maximum number of dApp's callable functions, zero means no limit
+    -manifest           Write JSON manifest of the script and included files with their SHA-256 hashes to given file
+    -max-literal-size   Warn about string and byte vector literals larger than given size in bytes, zero disables
+    -max-nesting-depth  Warn about conditional expressions nested deeper than given limit, zero disables
+    -optimize           Deduplicate repeated pure subexpressions
+    -require-stdlib-version Fail if the script's STDLIB version differs from the given one
+    -report             Output complexity budget report of dApp in JSON
+    -complexity         Output complexities of dApp's callable functions and verifier
+    -decompile, -d      Decompile base64 encoded script tree from the file into RIDE source code
+    -check-verifier     Warn if the account script has no explicit verifier or it always returns false
 `
 
 func main() {
 	var (
 		scriptPath   string
 		compaction   bool
+		verbose      bool
 		removeUnused bool
+		strict       bool
+		abi          bool
+		builtinsPath string
+		maxCallables int
+		maxLiteral   int
+		maxNesting   int
+		optimize     bool
+		requiredLib  int
+		report       bool
+		complexity   bool
+		checkVer     bool
+		manifestPath string
+		checksum     bool
+		decompile    bool
+		format       string
 	)
 	flag.StringVar(&scriptPath, "script", "", "Path to script file")
-	flag.BoolVar(&compaction, "compaction", false, "Compaction mode")
+	flag.BoolVar(&compaction, "compaction", false, "Replace names of dApp's functions and variables with short ones")
+	flag.BoolVar(&verbose, "verbose", false, "Output table of original and compact names in compaction mode")
 	flag.BoolVar(&removeUnused, "remove-unused", false, "Remove unused code")
+	flag.BoolVar(&strict, "strict", false, "Treat warnings as errors")
+	flag.StringVar(&format, "format", "text",
+		"Format of compilation errors output: \"text\" or \"json\" with positions of errors in the source code")
+	flag.BoolVar(&abi, "abi", false, "Output signatures of dApp's callable functions in JSON")
+	flag.StringVar(&builtinsPath, "builtins-file", "", "Path to JSON file with additional built-in functions definitions")
+	flag.IntVar(&maxCallables, "max-callables", 0, "Maximum number of dApp's callable functions, zero means no limit")
+	flag.IntVar(&maxLiteral, "max-literal-size", 0,
+		fmt.Sprintf("Warn about string and byte vector literals larger than given size in bytes, "+
+			"zero disables the warning, recommended limit is %d", compiler.DefaultMaxLiteralSize))
+	flag.IntVar(&maxNesting, "max-nesting-depth", 0,
+		fmt.Sprintf("Warn about conditional expressions nested deeper than given limit, "+
+			"zero disables the warning, recommended limit is %d", compiler.DefaultMaxNestingDepth))
+	flag.BoolVar(&optimize, "optimize", false, "Deduplicate repeated pure subexpressions")
+	flag.IntVar(&requiredLib, "require-stdlib-version", 0,
+		"Fail if the script's STDLIB version differs from the given one, zero means no check")
+	flag.BoolVar(&report, "report", false, "Output complexity budget report of dApp in JSON")
+	flag.BoolVar(&complexity, "complexity", false,
+		"Output complexities of dApp's callable functions and verifier estimated with the latest estimator")
+	flag.BoolVar(&checkVer, "check-verifier", false,
+		"Warn if the account script has no explicit verifier or it always returns false, error with -strict")
+	flag.BoolVar(&checksum, "checksum", false,
+		"Wrap compiled script into envelope with length and SHA-256 checksum, strip it before submission")
+	flag.BoolVar(&decompile, "decompile", false, "Decompile base64 encoded script tree into RIDE source code")
+	flag.BoolVar(&decompile, "d", false, "Shorthand for -decompile")
+	flag.StringVar(&manifestPath, "manifest", "",
+		"Path to write JSON manifest of the script and included files with their SHA-256 hashes")
 
 	flag.Usage = func() {
 		fmt.Println(usage)
 	}
 	flag.Parse()
 
+	if format != "text" && format != "json" {
+		fmt.Printf("Unsupported format '%s'\n", format)
+		flag.Usage()
+		os.Exit(1)
+	}
+
 	if scriptPath == "" {
 		fmt.Printf("Script path is not specified")
 		flag.Usage()
-		os.Exit(0)
+		os.Exit(1)
 	}
 
 	b, err := os.ReadFile(filepath.Clean(scriptPath))
 	if err != nil {
 		fmt.Printf("Failed to open file: %s", err)
-		os.Exit(0)
+		os.Exit(1)
+	}
+
+	if decompile {
+		src, err := decompileTree(string(b))
+		if err != nil {
+			fmt.Printf("Failed to decompile script: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(src)
+		return
+	}
+
+	if abi {
+		abis, err := compiler.CallableABIs(string(b))
+		if err != nil {
+			fmt.Printf("Failed to compile script: %v\n", err)
+			os.Exit(1)
+		}
+		js, err := json.MarshalIndent(abis, "", "  ")
+		if err != nil {
+			fmt.Printf("Failed to marshal ABI: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(js))
+		return
 	}
 
-	treeBytes, errors := compiler.Compile(string(b), compaction, removeUnused)
+	var builtins []compiler.Builtin
+	if builtinsPath != "" {
+		builtins, err = compiler.LoadBuiltins(builtinsPath)
+		if err != nil {
+			fmt.Printf("Failed to load built-in functions: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if report {
+		if err := printReport(string(b), builtins); err != nil {
+			fmt.Printf("Failed to build report: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	treeBytes, names, errors, warnings := compiler.CompileWithOptions(string(b), compiler.Options{
+		Compact:         compaction,
+		RemoveUnused:    removeUnused,
+		Optimize:        optimize,
+		Builtins:        builtins,
+		MaxCallables:    maxCallables,
+		MaxNestingDepth: maxNesting,
+		MaxLiteralSize:  maxLiteral,
+		CheckVerifier:   checkVer,
+	})
+	if strict {
+		errors = append(errors, warnings...)
+	} else if len(warnings) > 0 {
+		fmt.Fprintln(os.Stderr, "Warnings:")
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "\t%v\n", w)
+		}
+	}
+	if len(errors) > 0 && format == "json" {
+		if err := printErrorsJSON(errors); err != nil {
+			fmt.Printf("Failed to marshal errors: %v\n", err)
+		}
+		os.Exit(1)
+	}
 	if len(errors) > 0 {
 		fmt.Println("Failed to compile script")
 		for _, err := range errors {
 			fmt.Printf("\t%v\n", err)
 		}
-		os.Exit(0)
+		os.Exit(1)
+	}
+	if requiredLib != 0 {
+		if err := checkStdlibVersion(treeBytes, requiredLib); err != nil {
+			fmt.Printf("Failed to verify STDLIB version: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if len(builtins) > 0 {
+		if err := printComplexity(treeBytes, compiler.BuiltinsComplexities(builtins)); err != nil {
+			fmt.Printf("Failed to estimate script: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if complexity {
+		if err := printComplexities(treeBytes, compiler.BuiltinsComplexities(builtins)); err != nil {
+			fmt.Printf("Failed to estimate script: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if manifestPath != "" {
+		if err := writeManifest(manifestPath, scriptPath, string(b), builtins); err != nil {
+			fmt.Printf("Failed to write manifest: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if verbose && len(names) > 0 {
+		printNames(names)
+	}
+	if checksum {
+		treeBytes = serialization.WrapWithChecksum(treeBytes)
 	}
 	fmt.Println(base64.StdEncoding.EncodeToString(treeBytes))
 }
+
+// printErrorsJSON outputs the compilation errors as JSON array of messages with positions in the source code.
+func printErrorsJSON(errs []error) error {
+	js, err := json.MarshalIndent(compiler.NewSourceErrors(errs), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(js))
+	return nil
+}
+
+// decompileTree reconstructs RIDE source code from the base64 encoded script tree.
+func decompileTree(encoded string) (string, error) {
+	treeBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", fmt.Errorf("input is not a base64 string: %w", err)
+	}
+	tree, err := serialization.Parse(treeBytes)
+	if err != nil {
+		return "", fmt.Errorf("input is not a valid script tree: %w", err)
+	}
+	return compiler.Decompile(tree)
+}
+
+// printNames outputs the table of original names and compact ones sorted by the original names.
+func printNames(names map[string]string) {
+	originals := make([]string, 0, len(names))
+	for n := range names {
+		originals = append(originals, n)
+	}
+	sort.Strings(originals)
+	fmt.Fprintln(os.Stderr, "Names:")
+	for _, n := range originals {
+		fmt.Fprintf(os.Stderr, "\t%s -> %s\n", n, names[n])
+	}
+}
+
+// writeManifest writes to the file the JSON manifest listing the script and the files included into it.
+func writeManifest(path, scriptPath, src string, builtins []compiler.Builtin) error {
+	m, err := compiler.BuildManifest(scriptPath, src, builtins)
+	if err != nil {
+		return err
+	}
+	js, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), js, 0600)
+}
+
+// printReport outputs the complexity budget report of the dApp estimated with the latest estimator.
+func printReport(src string, builtins []compiler.Builtin) error {
+	tree, errs, _ := compiler.CompileToTreeWithOptions(src, compiler.Options{Builtins: builtins})
+	if len(errs) > 0 {
+		return stderrs.Join(errs...)
+	}
+	est, err := ride.EstimateTreeWithBuiltins(tree, ride.LatestEstimatorVersion, compiler.BuiltinsComplexities(builtins))
+	if err != nil {
+		return err
+	}
+	r, err := compiler.NewReport(tree, est.Functions, est.Verifier)
+	if err != nil {
+		return err
+	}
+	js, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(js))
+	return nil
+}
+
+// checkStdlibVersion verifies that the compiled script has the required STDLIB version.
+func checkStdlibVersion(treeBytes []byte, required int) error {
+	lv, err := ast.NewLibraryVersion(byte(required))
+	if err != nil {
+		return err
+	}
+	tree, err := serialization.Parse(treeBytes)
+	if err != nil {
+		return err
+	}
+	return compiler.CheckStdlibVersion(tree, lv)
+}
+
+// printComplexity estimates the compiled script with the latest estimator taking built-in functions into account.
+func printComplexity(treeBytes []byte, builtins map[string]int) error {
+	tree, err := serialization.Parse(treeBytes)
+	if err != nil {
+		return err
+	}
+	est, err := ride.EstimateTreeWithBuiltins(tree, ride.LatestEstimatorVersion, builtins)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Complexity: %d\n", est.Estimation)
+	return nil
+}
+
+// printComplexities outputs the complexities of callable functions sorted by names followed by the complexity
+// of the verifier, the script is estimated with the latest estimator taking built-in functions into account.
+func printComplexities(treeBytes []byte, builtins map[string]int) error {
+	complexities, err := compiler.Complexities(treeBytes, estimation.Estimator(builtins))
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(complexities))
+	for n := range complexities {
+		if n != compiler.VerifierComplexityKey {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Complexities:")
+	for _, n := range names {
+		fmt.Fprintf(os.Stderr, "\t%s: %d\n", n, complexities[n])
+	}
+	if c, ok := complexities[compiler.VerifierComplexityKey]; ok {
+		fmt.Fprintf(os.Stderr, "\tverifier: %d\n", c)
+	}
+	return nil
+}
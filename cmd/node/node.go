@@ -14,6 +14,7 @@ import (
 	"net/http/pprof"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -63,6 +64,10 @@ const profilerAddr = "localhost:6060"
 
 const utxPoolMaxSizeBytes = 1024 * mb
 
+const utxEvictionInterval = time.Minute
+
+const utxFileName = "utx.bin"
+
 var defaultPeers = map[string]string{
 	"mainnet":  "34.253.153.4:6868,168.119.116.189:6868,135.181.87.72:6868,162.55.39.115:6868,168.119.155.201:6868",
 	"testnet":  "159.69.126.149:6868,94.130.105.239:6868,159.69.126.153:6868,94.130.172.201:6868,35.157.247.122:6868",
@@ -102,6 +107,9 @@ type config struct {
 	disableBloomFilter         bool
 	reward                     int64
 	obsolescencePeriod         time.Duration
+	utxMaxTxAge                time.Duration
+	utxMaxEthereumTxAge        time.Duration
+	persistUtx                 bool
 	walletPath                 string
 	walletPassword             string
 	limitAllConnections        uint
@@ -117,6 +125,8 @@ type config struct {
 	disableNTP                 bool
 	microblockInterval         time.Duration
 	enableLightMode            bool
+	ethVerificationCacheSize   int
+	maxConcurrentEvaluations   int
 }
 
 var errConfigNotParsed = stderrs.New("config is not parsed")
@@ -156,6 +166,9 @@ func (c *config) logParameters() {
 	zap.S().Debugf("vote: %s", c.minerVoteFeatures)
 	zap.S().Debugf("reward: %d", c.reward)
 	zap.S().Debugf("obsolescence: %s", c.obsolescencePeriod)
+	zap.S().Debugf("utx-max-tx-age: %s", c.utxMaxTxAge)
+	zap.S().Debugf("utx-max-eth-tx-age: %s", c.utxMaxEthereumTxAge)
+	zap.S().Debugf("persist-utx: %t", c.persistUtx)
 	zap.S().Debugf("disable-miner %t", c.disableMiner)
 	zap.S().Debugf("wallet-path: %s", c.walletPath)
 	zap.S().Debugf("hashed wallet-password: %s", crypto.MustKeccak256([]byte(c.walletPassword)).Hex())
@@ -169,6 +182,8 @@ func (c *config) logParameters() {
 	zap.S().Debugf("disable-ntp: %t", c.disableNTP)
 	zap.S().Debugf("microblock-interval: %s", c.microblockInterval)
 	zap.S().Debugf("enable-light-mode: %t", c.enableLightMode)
+	zap.S().Debugf("eth-verification-cache-size: %d", c.ethVerificationCacheSize)
+	zap.S().Debugf("max-concurrent-evaluations: %d", c.maxConcurrentEvaluations)
 }
 
 func (c *config) parse() {
@@ -179,6 +194,7 @@ func (c *config) parse() {
 	const (
 		defaultBlacklistResidenceDuration = 5 * time.Minute
 		defaultObsolescenceDuration       = 4 * time.Hour
+		defaultUtxMaxTxAge                = 2 * time.Hour
 		defaultConnectionsLimit           = 60
 		defaultNewConnectionLimit         = 10
 		defaultMicroblockInterval         = 5 * time.Second
@@ -238,6 +254,12 @@ func (c *config) parse() {
 	flag.Int64Var(&c.reward, "reward", 0, "Miner reward: for example 600000000.")
 	flag.DurationVar(&c.obsolescencePeriod, "obsolescence", defaultObsolescenceDuration,
 		"Blockchain obsolescence period. Disable mining if last block older then given value.")
+	flag.DurationVar(&c.utxMaxTxAge, "utx-max-tx-age", defaultUtxMaxTxAge,
+		"Evict transactions with timestamp older than given value from UTX pool. Zero disables the eviction.")
+	flag.DurationVar(&c.utxMaxEthereumTxAge, "utx-max-eth-tx-age", 0,
+		"Evict Ethereum transactions staying in UTX pool longer than given value. Zero (default) disables the eviction.")
+	flag.BoolVar(&c.persistUtx, "persist-utx", false,
+		"Store UTX pool transactions in the state directory on shutdown and load them back on startup.")
 	flag.StringVar(&c.walletPath, "wallet-path", "", "Path to wallet, or ~/.waves by default.")
 	flag.StringVar(&c.walletPassword, "wallet-password", "", "Pass password for wallet.")
 	flag.UintVar(&c.limitAllConnections, "limit-connections", defaultConnectionsLimit,
@@ -266,6 +288,11 @@ func (c *config) parse() {
 		"Interval between microblocks.")
 	flag.BoolVar(&c.enableLightMode, "enable-light-mode", false,
 		"Start node in light mode")
+	flag.IntVar(&c.ethVerificationCacheSize, "eth-verification-cache-size", proto.DefaultEthereumVerificationCacheSize,
+		"Number of recovered senders' public keys of Ethereum transactions kept in the cache. Zero disables the cache.")
+	flag.IntVar(&c.maxConcurrentEvaluations, "max-concurrent-evaluations", 0,
+		"Maximum number of scripts evaluated concurrently during transactions validation and read-only calls. "+
+			"Evaluations beyond the limit wait for a free slot. Zero means no limit.")
 	flag.Parse()
 	c.logLevel = *l
 }
@@ -393,6 +420,10 @@ func runNode(ctx context.Context, nc *config) (_ io.Closer, retErr error) {
 		return nil, errors.Wrap(err, "failed to create state parameters")
 	}
 
+	if err := proto.SetEthereumVerificationCacheSize(nc.ethVerificationCacheSize); err != nil {
+		return nil, errors.Wrap(err, "failed to set ethereum verification cache size")
+	}
+
 	st, err := state.NewState(path, true, params, cfg, nc.enableLightMode)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize node's state")
@@ -424,7 +455,7 @@ func runNode(ctx context.Context, nc *config) (_ io.Closer, retErr error) {
 		return nil, errors.Wrap(err, "failed to initialize miner scheduler")
 	}
 
-	svs, err := createServices(nc, st, wal, cfg, ntpTime, peerManager, parent, minerScheduler)
+	svs, err := createServices(ctx, nc, st, wal, cfg, ntpTime, peerManager, parent, minerScheduler)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create services")
 	}
@@ -442,7 +473,15 @@ func runNode(ctx context.Context, nc *config) (_ io.Closer, retErr error) {
 		return nil, errors.Wrap(apiErr, "failed to run APIs")
 	}
 
-	return startNode(ctx, nc, svs, features, minerScheduler, parent, declAddr), nil
+	if !nc.persistUtx {
+		return startNode(ctx, nc, svs, features, minerScheduler, parent, declAddr), nil
+	}
+	utx, ok := svs.UtxPool.(mempoolPersister)
+	if !ok {
+		return nil, errors.Errorf("UTX pool of type %T can't be stored", svs.UtxPool)
+	}
+	n := startNode(ctx, nc, svs, features, minerScheduler, parent, declAddr)
+	return &utxPersistingCloser{Closer: n, utx: utx, path: filepath.Join(path, utxFileName)}, nil
 }
 
 func startNode(
@@ -525,6 +564,7 @@ func stateParams(nc *config, ntpTime types.Time) (state.StateParams, error) {
 	params.BuildStateHashes = nc.buildStateHashes
 	params.Time = ntpTime
 	params.DbParams.BloomFilterParams.Disable = nc.disableBloomFilter
+	params.MaxConcurrentEvaluations = nc.maxConcurrentEvaluations
 	return params, nil
 }
 
@@ -743,6 +783,7 @@ func createPeerManager(
 }
 
 func createServices(
+	ctx context.Context,
 	nc *config,
 	st state.State,
 	wal types.EmbeddedWallet,
@@ -756,12 +797,20 @@ func createServices(
 	if err != nil {
 		return services.Services{}, errors.Wrap(err, "failed to initialize UTX")
 	}
+	utx := utxpool.New(utxPoolMaxSizeBytes, utxValidator, cfg, ntpTime)
+	expiration := utxpool.ExpirationPolicy{MaxAge: nc.utxMaxTxAge, MaxEthereumAge: nc.utxMaxEthereumTxAge}
+	go utx.RunEviction(ctx, expiration, utxEvictionInterval, ntpTime)
+	if nc.persistUtx {
+		if err := loadUtx(nc, utx); err != nil {
+			return services.Services{}, err
+		}
+	}
 	return services.Services{
 		State:           st,
 		Peers:           peerManager,
 		Scheduler:       scheduler,
 		BlocksApplier:   blocks_applier.NewBlocksApplier(),
-		UtxPool:         utxpool.New(utxPoolMaxSizeBytes, utxValidator, cfg),
+		UtxPool:         utx,
 		Scheme:          cfg.AddressSchemeCharacter,
 		Time:            ntpTime,
 		Wallet:          wal,
@@ -772,6 +821,41 @@ func createServices(
 	}, nil
 }
 
+// loadUtx loads the UTX pool stored on previous shutdown, see utxPersistingCloser for the storing.
+func loadUtx(nc *config, utx *utxpool.UtxImpl) error {
+	statePath, err := nc.StatePath()
+	if err != nil {
+		return errors.Wrap(err, "failed to get state path")
+	}
+	if err := utx.LoadMempool(filepath.Join(statePath, utxFileName)); err != nil {
+		return errors.Wrap(err, "failed to load UTX pool")
+	}
+	return nil
+}
+
+type mempoolPersister interface {
+	PersistMempool(path string) error
+}
+
+// utxPersistingCloser stores the UTX pool before closing the node, because the node closes the state and
+// the transactions must be stored before the process exits.
+type utxPersistingCloser struct {
+	io.Closer
+	utx  mempoolPersister
+	path string
+}
+
+func (c *utxPersistingCloser) Close() error {
+	var err error
+	if pErr := c.utx.PersistMempool(c.path); pErr != nil {
+		err = errors.Wrap(pErr, "failed to store UTX pool")
+	}
+	if clErr := c.Closer.Close(); clErr != nil {
+		err = stderrs.Join(err, clErr)
+	}
+	return err
+}
+
 func runAPIs(
 	ctx context.Context,
 	nc *config,
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+)
+
+var usage = `
+Usage:
+  ride-eval -script <script path> -dapp <address> -function <name> [options]
+
+Options:
+    -mocks      Path to JSON file with mocked blockchain state
+    -args       JSON array of function arguments, e.g. [{"type":"integer","value":1}]
+    -caller     Base58 encoded public key of the caller
+    -scheme     Network scheme byte (default 'W')
+`
+
+func main() {
+	var (
+		scriptPath string
+		mocksPath  string
+		dAppStr    string
+		function   string
+		argsJSON   string
+		callerStr  string
+		scheme     string
+	)
+	flag.StringVar(&scriptPath, "script", "", "Path to script file")
+	flag.StringVar(&mocksPath, "mocks", "", "Path to JSON file with mocked blockchain state")
+	flag.StringVar(&dAppStr, "dapp", "", "Address of the dApp")
+	flag.StringVar(&function, "function", "", "Name of the callable function")
+	flag.StringVar(&argsJSON, "args", "[]", "JSON array of function arguments")
+	flag.StringVar(&callerStr, "caller", "", "Base58 encoded public key of the caller")
+	flag.StringVar(&scheme, "scheme", "W", "Network scheme byte")
+
+	flag.Usage = func() {
+		fmt.Println(usage)
+	}
+	flag.Parse()
+
+	if err := run(scriptPath, mocksPath, dAppStr, function, argsJSON, callerStr, scheme); err != nil {
+		fmt.Printf("Failed to evaluate script: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(scriptPath, mocksPath, dAppStr, function, argsJSON, callerStr, scheme string) error {
+	if scriptPath == "" || dAppStr == "" || function == "" {
+		flag.Usage()
+		return fmt.Errorf("script path, dApp address and function name are required")
+	}
+	if len(scheme) != 1 {
+		return fmt.Errorf("invalid scheme '%s'", scheme)
+	}
+	b, err := os.ReadFile(filepath.Clean(scriptPath))
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	tree, errs := compiler.CompileToTree(string(b))
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Printf("\t%v\n", e)
+		}
+		return fmt.Errorf("failed to compile script")
+	}
+	state := mockstate.New(1)
+	if mocksPath != "" {
+		mb, err := os.ReadFile(filepath.Clean(mocksPath))
+		if err != nil {
+			return fmt.Errorf("failed to open mocks file: %w", err)
+		}
+		if err := json.Unmarshal(mb, state); err != nil {
+			return err
+		}
+	}
+	dApp, err := proto.NewAddressFromString(dAppStr)
+	if err != nil {
+		return fmt.Errorf("invalid dApp address: %w", err)
+	}
+	var callerPK crypto.PublicKey
+	if callerStr != "" {
+		callerPK, err = crypto.NewPublicKeyFromBase58(callerStr)
+		if err != nil {
+			return fmt.Errorf("invalid caller public key: %w", err)
+		}
+	}
+	var args proto.Arguments
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return fmt.Errorf("invalid function arguments: %w", err)
+	}
+	inv := mockstate.Invocation{
+		DApp:     dApp,
+		CallerPK: callerPK,
+		Call:     proto.NewFunctionCall(function, args),
+	}
+	report, err := mockstate.Evaluate(scheme[0], state, tree, inv)
+	if err != nil {
+		fmt.Printf("Spent complexity: %d\n", report.Complexity)
+		return err
+	}
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal evaluation report: %w", err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
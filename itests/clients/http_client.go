@@ -1,11 +1,14 @@
 package clients
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/wavesplatform/gowaves/itests/config"
@@ -87,6 +90,50 @@ func (c *HTTPClient) TransactionBroadcast(transaction proto.Transaction) (*clien
 	return c.cli.Transactions.Broadcast(ctx, transaction)
 }
 
+type ethRPCRequest struct {
+	JSONRPC string   `json:"jsonrpc"`
+	ID      int      `json:"id"`
+	Method  string   `json:"method"`
+	Params  []string `json:"params"`
+}
+
+type ethRPCResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// EthSendRawTransaction submits the signed Ethereum transaction through the node's Ethereum JSON-RPC API.
+func (c *HTTPClient) EthSendRawTransaction(rawTx []byte) (*client.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	body, err := json.Marshal(ethRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "eth_sendRawTransaction",
+		Params:  []string{proto.EncodeToHexString(rawTx)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cli.GetOptions().BaseUrl+"eth", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	var out ethRPCResponse
+	resp, err := c.cli.Do(ctx, req, &out)
+	if err != nil {
+		return resp, err
+	}
+	if out.Error != nil {
+		return resp, errors.Errorf("eth_sendRawTransaction failed on %s node: %s (code %d)",
+			c.impl.String(), out.Error.Message, out.Error.Code)
+	}
+	return resp, nil
+}
+
 func (c *HTTPClient) WavesBalance(t *testing.T, address proto.WavesAddress) *client.AddressesBalance {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
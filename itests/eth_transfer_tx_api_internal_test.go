@@ -0,0 +1,52 @@
+//go:build !smoke
+
+package itests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	f "github.com/wavesplatform/gowaves/itests/fixtures"
+	utl "github.com/wavesplatform/gowaves/itests/utilities"
+	"github.com/wavesplatform/gowaves/itests/utilities/eth"
+	"github.com/wavesplatform/gowaves/itests/utilities/transfer"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type EthTransferTxApiSuite struct {
+	f.BaseSuite
+}
+
+func (suite *EthTransferTxApiSuite) Test_EthTransferWavesApiPositive() {
+	const (
+		fundsAmount    = 1000000000
+		transferAmount = 100000000
+	)
+	sk := eth.NewEthereumAccount(utl.RandStringBytes(32, utl.LettersAndDigits))
+	ethSender, err := sk.EthereumPublicKey().EthereumAddress().ToWavesAddress(utl.TestChainID)
+	suite.Require().NoError(err)
+	funder := utl.GetAccount(&suite.BaseSuite, utl.DefaultSenderNotMiner)
+	ftx := transfer.Send(&suite.BaseSuite, 2, utl.TestChainID, funder.PublicKey, funder.SecretKey,
+		proto.NewOptionalAssetWaves(), proto.NewOptionalAssetWaves(), utl.GetCurrentTimestampInMs(), fundsAmount,
+		utl.MinTxFeeWaves, proto.NewRecipientFromAddress(ethSender), nil, true)
+	suite.Require().NoError(ftx.WtErr.ErrWtGo, "Reached deadline of funding Transfer tx in Go")
+	suite.Require().NoError(ftx.WtErr.ErrWtScala, "Reached deadline of funding Transfer tx in Scala")
+
+	recipient := utl.GetAccount(&suite.BaseSuite, utl.DefaultRecipientNotMiner).Address
+	tx, diffSender, diffRecipient := eth.BroadcastEthTransferAndGetBalances(&suite.BaseSuite, utl.TestChainID, sk,
+		recipient, transferAmount, eth.MinTxFeeEth, true)
+	errMsg := "Broadcast Ethereum Transfer tx: " + tx.TxID.String()
+	suite.NoError(tx.BrdCstErr.ErrorBrdCstGo, errMsg)
+	suite.NoError(tx.WtErr.ErrWtGo, errMsg)
+	suite.NoError(tx.WtErr.ErrWtScala, errMsg)
+	suite.Equal(int64(transferAmount+eth.MinTxFeeEth), diffSender.BalanceInWavesGo, errMsg)
+	suite.Equal(diffSender.BalanceInWavesGo, diffSender.BalanceInWavesScala, errMsg)
+	suite.Equal(int64(transferAmount), diffRecipient.BalanceInWavesGo, errMsg)
+	suite.Equal(diffRecipient.BalanceInWavesGo, diffRecipient.BalanceInWavesScala, errMsg)
+}
+
+func TestEthTransferTxApiSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(EthTransferTxApiSuite))
+}
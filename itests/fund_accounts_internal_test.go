@@ -0,0 +1,45 @@
+//go:build !smoke
+
+package itests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	f "github.com/wavesplatform/gowaves/itests/fixtures"
+	utl "github.com/wavesplatform/gowaves/itests/utilities"
+	"github.com/wavesplatform/gowaves/itests/utilities/transfer"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type FundAccountsSuite struct {
+	f.BaseSuite
+}
+
+func (suite *FundAccountsSuite) Test_FundAccountsParallel() {
+	const (
+		accountsNumber = 2*transfer.MaxFundingBatchSize + 10 // three MassTransfer transactions
+		fundsAmount    = 100000
+	)
+	targets := make([]proto.WavesAddress, accountsNumber)
+	for i := range targets {
+		_, pk, err := crypto.GenerateKeyPair([]byte(utl.RandStringBytes(32, utl.LettersAndDigits)))
+		suite.Require().NoError(err)
+		targets[i], err = proto.NewAddressFromPublicKey(utl.TestChainID, pk)
+		suite.Require().NoError(err)
+	}
+	transfer.FundAccounts(&suite.BaseSuite, utl.TestChainID, utl.DefaultSenderNotMiner, fundsAmount,
+		transfer.DefaultFundingParallelism, targets...)
+	for _, addr := range targets {
+		balanceGo, balanceScala := utl.GetAvailableBalanceInWaves(&suite.BaseSuite, addr)
+		suite.Equal(int64(fundsAmount), balanceGo, "Go balance of %s", addr.String())
+		suite.Equal(int64(fundsAmount), balanceScala, "Scala balance of %s", addr.String())
+	}
+}
+
+func TestFundAccountsSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(FundAccountsSuite))
+}
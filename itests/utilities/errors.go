@@ -0,0 +1,66 @@
+package utilities
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ErrorCode is the canonical code of the error reported by either Go or Scala node. Codes allow to check the reason
+// of the failure without depending on the exact wording of the messages of the implementations.
+type ErrorCode string
+
+const (
+	ErrCodeUnknown                   ErrorCode = "unknown"
+	ErrCodeTxNotFound                ErrorCode = "tx-not-found"
+	ErrCodeAssetIssuedByOtherAddress ErrorCode = "asset-issued-by-other-address"
+	ErrCodeAssetNotFound             ErrorCode = "asset-not-found"
+	ErrCodeInvalidJSON               ErrorCode = "invalid-json"
+	ErrCodeInsufficientFee           ErrorCode = "insufficient-fee"
+	ErrCodeTimestampInPast           ErrorCode = "timestamp-in-past"
+	ErrCodeTimestampInFuture         ErrorCode = "timestamp-in-future"
+	ErrCodeNegativeBalance           ErrorCode = "negative-balance"
+)
+
+// errorPatterns maps known error messages of both nodes to the canonical codes.
+// Patterns are checked in order, the first matching pattern defines the code.
+var errorPatterns = []struct {
+	code    ErrorCode
+	pattern *regexp.Regexp
+}{
+	{ErrCodeTxNotFound, regexp.MustCompile(`transactions? does not exist`)},
+	{ErrCodeAssetIssuedByOtherAddress, regexp.MustCompile(`(?i)asset was issued by other address`)},
+	{ErrCodeAssetNotFound, regexp.MustCompile(`(?i)referenced assetId not found|unknown asset`)},
+	{ErrCodeInvalidJSON, regexp.MustCompile(`(?i)json data validation error`)},
+	{ErrCodeInsufficientFee, regexp.MustCompile(`(?i)does not exceed minimal value|insufficient fee`)},
+	{ErrCodeTimestampInPast, regexp.MustCompile(`is more than \d+ms in the past`)},
+	{ErrCodeTimestampInFuture, regexp.MustCompile(`is more than \d+ms in the future`)},
+	{ErrCodeNegativeBalance, regexp.MustCompile(`(?i)negative (waves |asset \S+ )?balance`)},
+}
+
+// NormalizeErrorMessage returns the canonical code of the error message of Go or Scala node.
+// ErrCodeUnknown is returned if the message doesn't match any known pattern.
+func NormalizeErrorMessage(msg string) ErrorCode {
+	for _, p := range errorPatterns {
+		if p.pattern.MatchString(msg) {
+			return p.code
+		}
+	}
+	return ErrCodeUnknown
+}
+
+// NormalizeError returns the canonical code of the error, ErrCodeUnknown is returned for nil error.
+func NormalizeError(err error) ErrorCode {
+	if err == nil {
+		return ErrCodeUnknown
+	}
+	return NormalizeErrorMessage(err.Error())
+}
+
+// ErrorCodeCheck checks that errors of both nodes have the expected canonical code.
+func ErrorCodeCheck(t *testing.T, expected ErrorCode, actualErrGo, actualErrScala error, args ...interface{}) {
+	errMsg := makeErrorMessage("Error code mismatch", args...)
+	assert.Equalf(t, expected, NormalizeError(actualErrGo), "Node Go: %s: %v", errMsg, actualErrGo)
+	assert.Equalf(t, expected, NormalizeError(actualErrScala), "Node Scala: %s: %v", errMsg, actualErrScala)
+}
@@ -0,0 +1,72 @@
+package utilities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	for _, test := range []struct {
+		goMsg    string
+		scalaMsg string
+		code     ErrorCode
+	}{
+		{
+			"transactions does not exist",
+			"transactions does not exist",
+			ErrCodeTxNotFound,
+		},
+		{
+			"asset was issued by other address",
+			"Asset was issued by other address",
+			ErrCodeAssetIssuedByOtherAddress,
+		},
+		{
+			"Fee 10 does not exceed minimal value of 100000 WAVES. ",
+			"Fee for SponsorFeeTransaction (10 in WAVES) does not exceed minimal value of 100000000 WAVES.",
+			ErrCodeInsufficientFee,
+		},
+		{
+			"Transaction timestamp 1 is more than 7200000ms in the past: early transaction creation time",
+			"Transaction timestamp 1 is more than 7200000ms in the past relative to previous block timestamp 2",
+			ErrCodeTimestampInPast,
+		},
+		{
+			"Transaction timestamp 2 is more than 5400000ms in the future",
+			"Transaction timestamp 2 is more than 5400000ms in the future relative to block timestamp 1",
+			ErrCodeTimestampInFuture,
+		},
+		{
+			"negative waves balance for addr \"3M...\"",
+			"Accounts balance errors: negative waves balance: 3M..., old: 1, new: -1",
+			ErrCodeNegativeBalance,
+		},
+		{
+			"unknown asset 5Bq...",
+			"Referenced assetId not found",
+			ErrCodeAssetNotFound,
+		},
+		{
+			"failed to decode JSON: json data validation error",
+			"json data validation error, details: {}",
+			ErrCodeInvalidJSON,
+		},
+		{
+			"Error is unknown",
+			"State check failed",
+			ErrCodeUnknown,
+		},
+	} {
+		assert.Equal(t, test.code, NormalizeErrorMessage(test.goMsg), test.goMsg)
+		assert.Equal(t, test.code, NormalizeErrorMessage(test.scalaMsg), test.scalaMsg)
+	}
+}
+
+func TestErrorCodeCheck(t *testing.T) {
+	assert.Equal(t, ErrCodeUnknown, NormalizeError(nil))
+	ErrorCodeCheck(t, ErrCodeAssetIssuedByOtherAddress,
+		errors.New("asset was issued by other address"),
+		errors.New("Asset was issued by other address"))
+}
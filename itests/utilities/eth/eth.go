@@ -0,0 +1,109 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	f "github.com/wavesplatform/gowaves/itests/fixtures"
+	utl "github.com/wavesplatform/gowaves/itests/utilities"
+	"github.com/wavesplatform/gowaves/pkg/client"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// MinTxFeeEth is the minimal fee of Ethereum transfer transaction, fee is set in the gas limit field.
+const MinTxFeeEth = utl.MinTxFeeWaves
+
+// NewEthereumAccount returns the deterministic Ethereum private key derived from the seed.
+func NewEthereumAccount(seed string) *proto.EthereumPrivateKey {
+	d := crypto.MustKeccak256([]byte(seed))
+	sk, _ := btcec.PrivKeyFromBytes(d.Bytes())
+	return (*proto.EthereumPrivateKey)(sk)
+}
+
+// NewSignEthTransferWavesTransaction creates the EIP-155 legacy Ethereum transaction transferring Waves and signs
+// it with the given key. Timestamp is used as the transaction's nonce, fee is set as the gas limit.
+func NewSignEthTransferWavesTransaction(suite *f.BaseSuite, scheme proto.Scheme, sk *proto.EthereumPrivateKey,
+	recipient proto.WavesAddress, amount, fee, timestamp uint64) *proto.EthereumTransaction {
+	to := recipient.EthereumAddress()
+	inner := &proto.EthereumLegacyTx{
+		Nonce:    timestamp,
+		GasPrice: new(big.Int).SetUint64(proto.EthereumGasPrice),
+		Gas:      fee,
+		To:       &to,
+		Value:    proto.WaveletToEthereumWei(amount),
+	}
+	tx := proto.NewEthereumTransaction(inner, nil, nil, nil, 0)
+	secret, err := crypto.NewSecretKeyFromBytes((*btcec.PrivateKey)(sk).Serialize())
+	require.NoError(suite.T(), err, "failed to get secret key of Ethereum account")
+	err = tx.Sign(scheme, secret)
+	require.NoError(suite.T(), err, "failed to sign Ethereum transaction")
+	raw, err := tx.EncodeCanonical()
+	require.NoError(suite.T(), err, "failed to encode Ethereum transaction")
+	// Decode the signed transaction the same way the node does it, so the size and the sender are taken from
+	// the raw transaction instead of the unsigned one.
+	var signed proto.EthereumTransaction
+	err = signed.DecodeCanonical(raw)
+	require.NoError(suite.T(), err, "failed to decode signed Ethereum transaction")
+	err = signed.GenerateID(scheme)
+	require.NoError(suite.T(), err, "failed to generate ID of Ethereum transaction")
+	sender, err := signed.Verify()
+	require.NoError(suite.T(), err, "failed to verify signed Ethereum transaction")
+	require.Equal(suite.T(), sk.EthereumPublicKey().EthereumAddress(), sender.EthereumAddress(),
+		"sender of signed Ethereum transaction differs from the signer")
+	suite.T().Logf("Ethereum Transaction ID: %s, raw: %s", signed.ID.String(), proto.EncodeToHexString(raw))
+	return &signed
+}
+
+// BroadcastEthTxAndWait submits the raw Ethereum transaction to the Go node (and to the Scala node if waitForTx
+// is false) and waits for the transaction in both nodes. The transaction is polled by its Keccak-256 hash ID.
+func BroadcastEthTxAndWait(suite *f.BaseSuite, tx *proto.EthereumTransaction,
+	waitForTx bool) utl.ConsideredTransaction {
+	raw, err := tx.EncodeCanonical()
+	require.NoError(suite.T(), err, "failed to encode Ethereum transaction")
+	require.NotNil(suite.T(), tx.ID, "ID of Ethereum transaction is not generated")
+	id := *tx.ID
+	timeout := utl.DefaultWaitTimeout
+	respGo, errBrdCstGo := suite.Clients.GoClient.HTTPClient.EthSendRawTransaction(raw)
+	var respScala *client.Response = nil
+	var errBrdCstScala error = nil
+	if !waitForTx {
+		timeout = utl.DefaultInitialTimeout
+		respScala, errBrdCstScala = suite.Clients.ScalaClient.HTTPClient.EthSendRawTransaction(raw)
+	}
+	suite.T().Log("Ethereum Tx was successfully Broadcast to nodes")
+
+	suite.T().Log("Waiting for Ethereum Tx appears in Blockchain")
+	errWtGo, errWtScala := suite.Clients.WaitForTransaction(id, timeout)
+	if errWtGo != nil {
+		suite.T().Log(errors.Errorf("Errors after waiting: %s", errWtGo))
+	}
+	if errWtScala != nil {
+		suite.T().Log(errors.Errorf("Errors after waiting: %s", errWtScala))
+	}
+	return utl.NewConsideredTransaction(id, respGo, respScala, errWtGo, errWtScala, errBrdCstGo, errBrdCstScala)
+}
+
+// BroadcastEthTransferAndGetBalances broadcasts the Ethereum transaction transferring Waves and returns
+// the differences of Waves balances of the sender and the recipient in both nodes.
+func BroadcastEthTransferAndGetBalances(suite *f.BaseSuite, scheme proto.Scheme, sk *proto.EthereumPrivateKey,
+	recipient proto.WavesAddress, amount, fee uint64, waitForTx bool) (utl.ConsideredTransaction,
+	utl.BalanceInWaves, utl.BalanceInWaves) {
+	sender, err := sk.EthereumPublicKey().EthereumAddress().ToWavesAddress(scheme)
+	require.NoError(suite.T(), err, "failed to get Waves address of Ethereum account")
+
+	initBalanceGoSender, initBalanceScalaSender := utl.GetAvailableBalanceInWaves(suite, sender)
+	initBalanceGoRecipient, initBalanceScalaRecipient := utl.GetAvailableBalanceInWaves(suite, recipient)
+
+	tx := NewSignEthTransferWavesTransaction(suite, scheme, sk, recipient, amount, fee,
+		utl.GetCurrentTimestampInMs())
+	considered := BroadcastEthTxAndWait(suite, tx, waitForTx)
+
+	diffSender := utl.GetActualDiffBalanceInWaves(suite, sender, initBalanceGoSender, initBalanceScalaSender)
+	diffRecipient := utl.GetActualDiffBalanceInWaves(suite, recipient, initBalanceGoRecipient,
+		initBalanceScalaRecipient)
+	return considered, diffSender, diffRecipient
+}
@@ -0,0 +1,99 @@
+package transfer
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/wavesplatform/gowaves/itests/clients"
+	f "github.com/wavesplatform/gowaves/itests/fixtures"
+	utl "github.com/wavesplatform/gowaves/itests/utilities"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const (
+	// MaxFundingBatchSize is the maximum number of transfers in one MassTransfer transaction.
+	MaxFundingBatchSize = 100
+	// DefaultFundingParallelism is the default number of funding transactions broadcast concurrently.
+	DefaultFundingParallelism = 4
+)
+
+func massTransferFee(transfersCount int) uint64 {
+	return utl.MinTxFeeWaves * uint64(1+(transfersCount+1)/2)
+}
+
+// FundAccounts transfers the amount of Waves from the faucet account to each of the target addresses.
+// Transfers are batched into MassTransfer transactions and up to parallelism transactions are broadcast
+// concurrently, the faucet's transactions don't depend on each other so the order of their application
+// doesn't matter. The function returns after all transactions are confirmed and the balances of all targets
+// are updated on both nodes.
+func FundAccounts(suite *f.BaseSuite, scheme proto.Scheme, faucet int, amount uint64, parallelism int,
+	targets ...proto.WavesAddress) {
+	if len(targets) == 0 {
+		return
+	}
+	if parallelism < 1 {
+		parallelism = DefaultFundingParallelism
+	}
+	sender := utl.GetAccount(suite, faucet)
+
+	expected := make(map[proto.WavesAddress]int64, len(targets))
+	for _, addr := range targets {
+		if _, ok := expected[addr]; !ok {
+			balanceGo, balanceScala := utl.GetAvailableBalanceInWaves(suite, addr)
+			require.Equal(suite.T(), balanceGo, balanceScala, "initial balances of %s mismatch", addr.String())
+			expected[addr] = balanceGo
+		}
+		expected[addr] += int64(amount)
+	}
+
+	batches := make([]*proto.MassTransferWithProofs, 0, (len(targets)+MaxFundingBatchSize-1)/MaxFundingBatchSize)
+	ts := utl.GetCurrentTimestampInMs()
+	for start := 0; start < len(targets); start += MaxFundingBatchSize {
+		end := min(start+MaxFundingBatchSize, len(targets))
+		transfers := make([]proto.MassTransferEntry, 0, end-start)
+		for _, addr := range targets[start:end] {
+			transfers = append(transfers, proto.MassTransferEntry{
+				Recipient: proto.NewRecipientFromAddress(addr),
+				Amount:    amount,
+			})
+		}
+		// Distinct timestamps guarantee distinct IDs of the faucet's transactions.
+		tx := proto.NewUnsignedMassTransferWithProofs(1, sender.PublicKey, proto.NewOptionalAssetWaves(),
+			transfers, massTransferFee(len(transfers)), ts+uint64(len(batches)), nil)
+		require.NoError(suite.T(), tx.Sign(scheme, sender.SecretKey), "failed to sign MassTransfer tx")
+		batches = append(batches, tx)
+	}
+
+	var eg errgroup.Group
+	eg.SetLimit(parallelism)
+	for _, tx := range batches {
+		eg.Go(func() error {
+			res := utl.BroadcastAndWaitTransaction(suite, tx, scheme, true)
+			if res.BrdCstErr.ErrorBrdCstGo != nil {
+				return errors.Wrapf(res.BrdCstErr.ErrorBrdCstGo, "failed to broadcast funding tx %s", res.TxID)
+			}
+			if res.WtErr.ErrWtGo != nil {
+				return errors.Wrapf(res.WtErr.ErrWtGo, "funding tx %s is not confirmed in Go", res.TxID)
+			}
+			if res.WtErr.ErrWtScala != nil {
+				return errors.Wrapf(res.WtErr.ErrWtScala, "funding tx %s is not confirmed in Scala", res.TxID)
+			}
+			return nil
+		})
+	}
+	require.NoError(suite.T(), eg.Wait())
+
+	// Waiting for changing waves balances of all targets.
+	err := clients.Retry(utl.DefaultTimeInterval, func() error {
+		for addr, balance := range expected {
+			balanceGo, balanceScala := utl.GetAvailableBalanceInWaves(suite, addr)
+			if balanceGo != balance || balanceScala != balance {
+				return errors.Errorf("account %s Waves balance mismatch: expected %d, Go %d, Scala %d",
+					addr.String(), balance, balanceGo, balanceScala)
+			}
+		}
+		return nil
+	})
+	require.NoError(suite.T(), err)
+}
@@ -197,7 +197,7 @@ func (RPCService) SMD() smd.ServiceInfo {
 				},
 			},
 			"Eth_Call": {
-				Description: `Eth_Call returns information about assets.
+				Description: `Eth_Call returns information about assets or the result of read-only call of dApp callable.
 - params: the tx call object
 - block: QUANTITY|TAG - integer block number, or the string "latest", "earliest" or "pending"`,
 				Parameters: []smd.JSONSchema{
@@ -207,6 +207,11 @@ func (RPCService) SMD() smd.ServiceInfo {
 						Description: ``,
 						Type:        smd.Object,
 						Properties: map[string]smd.Property{
+							"from": {
+								Description: ``,
+								Ref:         "#/definitions/proto.EthereumAddress",
+								Type:        smd.Object,
+							},
 							"to": {
 								Description: ``,
 								Ref:         "#/definitions/proto.EthereumAddress",
@@ -199,12 +199,13 @@ func (s RPCService) Eth_EstimateGas(req estimateGasRequest) (string, error) {
 }
 
 type ethCallParams struct {
+	From proto.EthereumAddress `json:"from"`
 	To   proto.EthereumAddress `json:"to"`
 	Data string                `json:"data"`
 }
 
 func (c ethCallParams) String() string {
-	return fmt.Sprintf("Eth_callParams(to=%s,data=%s)", c.To, c.Data)
+	return fmt.Sprintf("Eth_callParams(from=%s,to=%s,data=%s)", c.From, c.To, c.Data)
 }
 
 var (
@@ -214,7 +215,7 @@ var (
 	erc20SupportsInterfaceSelector = ethabi.Signature("supportsInterface(bytes4)").Selector() // "0x01ffc9a7"
 )
 
-// Eth_Call returns information about assets.
+// Eth_Call returns information about assets or the result of read-only call of dApp callable.
 //   - params: the tx call object
 //   - block: QUANTITY|TAG - integer block number, or the string "latest", "earliest" or "pending"
 func (s RPCService) Eth_Call(params ethCallParams, blockOrTag string) (string, error) {
@@ -227,7 +228,7 @@ func (s RPCService) Eth_Call(params ethCallParams, blockOrTag string) (string, e
 	return proto.EncodeToHexString(abiVal), nil
 }
 
-func ethCall(state state.State, scheme proto.Scheme, params ethCallParams) ([]byte, error) {
+func ethCall(st state.State, scheme proto.Scheme, params ethCallParams) ([]byte, error) {
 
 	callData, err := proto.DecodeFromHexString(params.Data)
 	if err != nil {
@@ -248,14 +249,14 @@ func ethCall(state state.State, scheme proto.Scheme, params ethCallParams) ([]by
 	)
 	switch selector {
 	case erc20SymbolSelector:
-		fullInfo, err := state.FullAssetInfo(shortAssetID)
+		fullInfo, err := st.FullAssetInfo(shortAssetID)
 		if err != nil {
 			zap.S().Debugf("Eth_Call: failed to fetch full asset info, %s: %v", params.String(), err)
 			return nil, err
 		}
 		return ethabi.String(fullInfo.Name).EncodeToABI(), nil
 	case erc20DecimalsSelector:
-		info, err := state.AssetInfo(shortAssetID)
+		info, err := st.AssetInfo(shortAssetID)
 		if err != nil {
 			zap.S().Debugf("Eth_Call: failed to fetch asset info, %s: %v", params.String(), err)
 			return nil, err
@@ -280,7 +281,7 @@ func ethCall(state state.State, scheme proto.Scheme, params ethCallParams) ([]by
 		if err != nil {
 			return nil, err
 		}
-		accountBalance, err := state.AssetBalance(proto.NewRecipientFromAddress(wavesAddr), shortAssetID)
+		accountBalance, err := st.AssetBalance(proto.NewRecipientFromAddress(wavesAddr), shortAssetID)
 		if err != nil {
 			zap.S().Errorf("Eth_Call: failed to fetch account balance for addr=%q, %s: %v",
 				wavesAddr.String(), params.String(), err,
@@ -291,7 +292,18 @@ func ethCall(state state.State, scheme proto.Scheme, params ethCallParams) ([]by
 	case erc20SupportsInterfaceSelector:
 		return ethabi.Bool(false).EncodeToABI(), nil
 	default:
-		return nil, nil // according to the scala node implementation ("0x" in the result will be returned)
+		// not an ERC20 method, trying to call the dApp callable
+		res, err := st.EthereumCall(params.From, params.To, callData)
+		if err != nil {
+			if state.IsNotFound(err) || state.IsInvalidInput(err) {
+				// not a dApp or not a callable of the dApp, according to the scala node implementation
+				// ("0x" in the result will be returned)
+				return nil, nil
+			}
+			zap.S().Debugf("Eth_Call: failed to call dApp, %s: %v", params.String(), err)
+			return nil, err
+		}
+		return res, nil
 	}
 }
 
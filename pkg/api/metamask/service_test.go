@@ -3,8 +3,15 @@ package metamask
 import (
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/mock"
+	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/proto/ethabi"
+	"github.com/wavesplatform/gowaves/pkg/state"
 )
 
 func TestEthCallSelectors(t *testing.T) {
@@ -21,3 +28,30 @@ func TestEthCallSelectors(t *testing.T) {
 		assert.Equal(t, tc.expected, tc.selector.String())
 	}
 }
+
+func TestEthCallNonERC20Selector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	params := ethCallParams{To: proto.EthereumAddress{1, 2, 3}, Data: "0xdeadbeef"}
+	for _, test := range []struct {
+		err      error
+		expected []byte
+		fails    bool
+	}{
+		{nil, []byte{1, 2, 3}, false},
+		{state.NewStateError(state.NotFoundError, errors.New("not a dApp")), nil, false},
+		{state.NewStateError(state.InvalidInputError, errors.New("unknown method")), nil, false},
+		{state.NewStateError(state.Other, errors.New("evaluation failed")), nil, true},
+	} {
+		st := mock.NewMockState(ctrl)
+		st.EXPECT().EthereumCall(params.From, params.To, []byte{0xde, 0xad, 0xbe, 0xef}).Return(test.expected, test.err)
+		res, err := ethCall(st, proto.TestNetScheme, params)
+		if test.fails {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, res)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/types"
 )
 
@@ -133,6 +134,32 @@ func (p *vrfGenerationSignatureProvider) VerifyGenerationSignature(pk crypto.Pub
 	return ok, hs, nil
 }
 
+// ComputeGenerationSignature computes the generation signature of the block and the VRF value derived from it.
+// For blocks of versions before the protobuf one the reference hit source is the generation signature of the parent
+// block, the generation signature is computed from it and the generator's public key, VRF is nil.
+// For blocks of the protobuf version and later the generation signature is the VRF proof, which can't be computed
+// without the generator's secret key, so the proof is verified against the reference hit source and the VRF value
+// is calculated from it.
+func ComputeGenerationSignature(header *proto.BlockHeader, refHitSource []byte) ([]byte, []byte, error) {
+	if header.Version < proto.ProtobufBlockVersion {
+		gs, err := NXTGenerationSignatureProvider.GenerationSignature(header.GeneratorPublicKey, refHitSource)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to compute generation signature")
+		}
+		return gs, nil, nil
+	}
+	ok, vrf, err := VRFGenerationSignatureProvider.VerifyGenerationSignature(header.GeneratorPublicKey, refHitSource,
+		header.GenSignature)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to compute generation signature")
+	}
+	if !ok {
+		return nil, nil, errors.Errorf("invalid VRF proof '%s' of block '%s'", header.GenSignature.String(),
+			header.ID.String())
+	}
+	return header.GenSignature.Bytes(), vrf, nil
+}
+
 func GenHit(source []byte) (*Hit, error) {
 	s := [HitSize]byte{}
 	copy(s[:], source[:HitSize])
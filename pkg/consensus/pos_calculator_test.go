@@ -1,10 +1,15 @@
 package consensus
 
 import (
+	"encoding/hex"
 	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
 )
 
 func TestFairPosCalculateDelay(t *testing.T) {
@@ -95,3 +100,45 @@ func TestNxtPosCalculateBaseTarget(t *testing.T) {
 	}
 	assert.Equal(t, uint64(727950233), target, "invalid nxt base target")
 }
+
+func TestComputeGenerationSignature(t *testing.T) {
+	mustHex := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		return b
+	}
+	pk, err := crypto.NewPublicKeyFromBytes(mustHex("21F7345F56D9602F1523298F4F6FCECB14DDE2D5B9A9B48BCA8242681492B920"))
+	require.NoError(t, err)
+	msg := mustHex("5468697320697320756E697175652E")
+	proof := mustHex("5D501685D744424DE3EF5CA49ECDDD880FA7421C975CDF94BAE48CA16EC0899737721200EED1A8B0D2D6852826A1" +
+		"EAB78B0DF27F35B3F3E89C96E7AE3DAAA30F037297547886E554AFFC81DE54B575768FB30493C537ECDD5A87577DEB7D8E03")
+	vrf := mustHex("45DC7B816B01B36CFA1645DCAE8AC9BC8E523CD86D007D19953F03E7D54554A0")
+
+	t.Run("VRF", func(t *testing.T) {
+		header := &proto.BlockHeader{Version: proto.ProtobufBlockVersion, GeneratorPublicKey: pk}
+		header.GenSignature = proof
+		gs, actualVRF, err := ComputeGenerationSignature(header, msg)
+		require.NoError(t, err)
+		assert.Equal(t, proof, gs)
+		assert.Equal(t, vrf, actualVRF)
+
+		header.GenSignature = append([]byte{}, proof...)
+		header.GenSignature[len(proof)-1] ^= 0xff
+		_, _, err = ComputeGenerationSignature(header, msg)
+		assert.Error(t, err)
+	})
+	t.Run("NXT", func(t *testing.T) {
+		parentGenSig := make([]byte, crypto.DigestSize)
+		copy(parentGenSig, vrf)
+		header := &proto.BlockHeader{Version: proto.NgBlockVersion, GeneratorPublicKey: pk}
+		expected, err := crypto.FastHash(append(parentGenSig, pk.Bytes()...))
+		require.NoError(t, err)
+		gs, actualVRF, err := ComputeGenerationSignature(header, parentGenSig)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Bytes(), gs)
+		assert.Nil(t, actualVRF)
+
+		_, _, err = ComputeGenerationSignature(header, parentGenSig[:crypto.DigestSize-1])
+		assert.Error(t, err)
+	})
+}
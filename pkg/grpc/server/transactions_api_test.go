@@ -36,7 +36,7 @@ func TestGetTransactions(t *testing.T) {
 	sch := createTestNetWallet(t)
 	validator, err := utxpool.NewValidator(st, ntptime.Stub{}, 24*time.Hour)
 	require.NoError(t, err)
-	err = server.initServer(st, utxpool.New(utxSize, validator, sets), sch)
+	err = server.initServer(st, utxpool.New(utxSize, validator, sets, ntptime.Stub{}), sch)
 	require.NoError(t, err)
 
 	conn := connectAutoClose(t, grpcTestAddr)
@@ -115,7 +115,7 @@ func TestGetStatuses(t *testing.T) {
 	st := newTestState(t, true, params, bs)
 	ctx := withAutoCancel(t, context.Background())
 	sch := createTestNetWallet(t)
-	utx := utxpool.New(utxSize, utxpool.NoOpValidator{}, bs)
+	utx := utxpool.New(utxSize, utxpool.NoOpValidator{}, bs, ntptime.Stub{})
 	err := server.initServer(st, utx, sch)
 	require.NoError(t, err)
 
@@ -168,7 +168,7 @@ func TestGetUnconfirmed(t *testing.T) {
 	st := newTestState(t, true, params, bs)
 	ctx := withAutoCancel(t, context.Background())
 	sch := createTestNetWallet(t)
-	utx := utxpool.New(utxSize, utxpool.NoOpValidator{}, bs)
+	utx := utxpool.New(utxSize, utxpool.NoOpValidator{}, bs, ntptime.Stub{})
 	err := server.initServer(st, utx, sch)
 	require.NoError(t, err)
 
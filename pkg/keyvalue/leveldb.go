@@ -2,7 +2,10 @@ package keyvalue
 
 import (
 	"sync"
+	"syscall"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/coocood/freecache"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -137,6 +140,43 @@ type KeyValParams struct {
 	CompactionTableSize    int
 	CompactionTotalSize    int
 	OpenFilesCacheCapacity int
+	// OpenRetryTimeout is the maximum time to retry opening the DB if it is locked by another process.
+	// Zero value disables retries.
+	OpenRetryTimeout time.Duration
+}
+
+const maxOpenRetryInterval = time.Second
+
+func isLockError(err error) bool {
+	return errors.Is(err, syscall.EWOULDBLOCK) || errors.Is(err, syscall.EAGAIN)
+}
+
+// openLevelDB opens the DB and retries with exponential backoff while the DB lock is held by another process.
+// Errors other than lock contention are returned immediately, so DB corruption is never masked by retries.
+func openLevelDB(path string, options *opt.Options, retryTimeout time.Duration) (*leveldb.DB, error) {
+	db, err := leveldb.OpenFile(path, options)
+	if err == nil || retryTimeout <= 0 || !isLockError(err) {
+		return db, err
+	}
+	b := backoff.NewExponentialBackOff(
+		backoff.WithMaxInterval(maxOpenRetryInterval),
+		backoff.WithMaxElapsedTime(retryTimeout),
+	)
+	op := func() error {
+		var openErr error
+		db, openErr = leveldb.OpenFile(path, options)
+		if openErr != nil && !isLockError(openErr) {
+			return backoff.Permanent(openErr)
+		}
+		return openErr
+	}
+	notify := func(err error, next time.Duration) {
+		zap.S().Warnf("Failed to open DB at '%s': %v; retrying in %v", path, err, next)
+	}
+	if rErr := backoff.RetryNotify(op, b, notify); rErr != nil {
+		return nil, errors.Wrapf(rErr, "failed to open DB after retrying for %v", retryTimeout)
+	}
+	return db, nil
 }
 
 func NewKeyVal(path string, params KeyValParams) (*KeyVal, error) {
@@ -158,7 +198,7 @@ func NewKeyVal(path string, params KeyValParams) (*KeyVal, error) {
 		CompactionTotalSize:    params.CompactionTotalSize,
 		OpenFilesCacheCapacity: openFilesCacheCapacity,
 	}
-	db, err := leveldb.OpenFile(path, dbOptions)
+	db, err := openLevelDB(path, dbOptions, params.OpenRetryTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -2,8 +2,10 @@ package keyvalue
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -97,3 +99,33 @@ func TestKeyVal(t *testing.T) {
 	err = iter.Error()
 	assert.NoError(t, err, "iterator error")
 }
+
+func TestKeyValOpenRetry(t *testing.T) {
+	dbDir := t.TempDir()
+	params := KeyValParams{
+		CacheParams:         CacheParams{cacheSize},
+		BloomFilterParams:   BloomFilterParams{n, falsePositiveProbability, NoOpStore{}, true},
+		WriteBuffer:         writeBuffer,
+		CompactionTableSize: sstableSize,
+		CompactionTotalSize: compactionTotalSize,
+	}
+	kv1, err := NewKeyVal(dbDir, params)
+	require.NoError(t, err)
+
+	// Without retries the locked DB can't be opened.
+	_, err = NewKeyVal(dbDir, params)
+	require.Error(t, err)
+	assert.True(t, isLockError(err))
+
+	// Release the lock after a short delay, the second open must succeed while retrying.
+	released := make(chan error, 1)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		released <- kv1.Close()
+	}()
+	params.OpenRetryTimeout = 5 * time.Second
+	kv2, err := NewKeyVal(dbDir, params)
+	require.NoError(t, err)
+	require.NoError(t, <-released)
+	require.NoError(t, kv2.Close())
+}
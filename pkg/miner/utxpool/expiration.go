@@ -0,0 +1,75 @@
+package utxpool
+
+import (
+	"container/heap"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+// ExpirationPolicy defines the age after which transactions are evicted from the pool.
+// The age of Waves transaction is counted from its timestamp. Timestamp of Ethereum transaction holds the nonce,
+// so the age of Ethereum transaction is counted from its arrival to the pool.
+// Zero value disables the eviction of the corresponding transactions.
+type ExpirationPolicy struct {
+	MaxAge         time.Duration
+	MaxEthereumAge time.Duration
+}
+
+func (p ExpirationPolicy) expired(e *MempoolEntry, now time.Time) bool {
+	if _, ok := e.T.(*proto.EthereumTransaction); ok {
+		return p.MaxEthereumAge > 0 && now.Sub(e.Arrival) > p.MaxEthereumAge
+	}
+	if p.MaxAge <= 0 {
+		return false
+	}
+	ts := time.UnixMilli(int64(e.T.GetTimestamp()))
+	return now.Sub(ts) > p.MaxAge
+}
+
+// EvictExpired removes from the pool the transactions which are expired at the given time according to the policy.
+// The number of evicted transactions is returned.
+func (a *UtxImpl) EvictExpired(policy ExpirationPolicy, now time.Time) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	retained := a.transactions.entries[:0]
+	evicted := 0
+	for _, e := range a.transactions.entries {
+		if !policy.expired(e, now) {
+			retained = append(retained, e)
+			continue
+		}
+		delete(a.transactionIds, makeDigest(e.T.GetID(a.settings.AddressSchemeCharacter)))
+		a.curSize -= uint64(len(e.B))
+		evicted++
+	}
+	for i := len(retained); i < len(a.transactions.entries); i++ {
+		a.transactions.entries[i] = nil
+	}
+	a.transactions.entries = retained
+	if evicted > 0 {
+		heap.Init(&a.transactions)
+	}
+	return evicted
+}
+
+// RunEviction evicts expired transactions from the pool with the given interval until the context is canceled.
+func (a *UtxImpl) RunEviction(ctx context.Context, policy ExpirationPolicy, interval time.Duration, tm types.Time) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := a.EvictExpired(policy, tm.Now()); n > 0 {
+				zap.S().Debugf("UTX: %d expired transactions evicted", n)
+			}
+		}
+	}
+}
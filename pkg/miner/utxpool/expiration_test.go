@@ -0,0 +1,114 @@
+package utxpool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func newTestEthereumTx(t *testing.T, nonce uint64) *proto.EthereumTransaction {
+	to := proto.EthereumAddress{}
+	id, err := crypto.FastHash([]byte{byte(nonce)})
+	require.NoError(t, err)
+	tx := proto.NewEthereumTransaction(&proto.EthereumLegacyTx{
+		Nonce:    nonce,
+		GasPrice: big.NewInt(int64(proto.EthereumGasPrice)),
+		Gas:      100000,
+		To:       &to,
+		Value:    big.NewInt(0),
+	}, nil, &id, nil, 0)
+	return &tx
+}
+
+func TestUtxImpl_EvictExpired(t *testing.T) {
+	now := time.Now()
+	policy := ExpirationPolicy{MaxAge: time.Hour, MaxEthereumAge: 30 * time.Minute}
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
+
+	ts := func(d time.Duration) uint64 { return uint64(now.Add(-d).UnixMilli()) }
+	expired := &transaction{fee: 10, id: []byte{1}, timestamp: ts(2 * time.Hour)}
+	fresh := &transaction{fee: 20, id: []byte{2}, timestamp: ts(10 * time.Minute)}
+	ethTx := newTestEthereumTx(t, 1)
+	require.NoError(t, a.AddWithBytes(expired, []byte{1, 2}))
+	require.NoError(t, a.AddWithBytes(fresh, []byte{1, 2, 3}))
+	require.NoError(t, a.AddWithBytes(ethTx, []byte{1, 2, 3, 4}))
+
+	// Ethereum transaction has just arrived, the nonce in its timestamp is ignored.
+	assert.Equal(t, 1, a.EvictExpired(policy, now))
+	assert.False(t, a.Exists(expired))
+	assert.True(t, a.Exists(fresh))
+	assert.True(t, a.Exists(ethTx))
+	assert.EqualValues(t, 7, a.CurSize())
+
+	// Ethereum transaction expires after it stays in the pool longer than the limit.
+	assert.Equal(t, 1, a.EvictExpired(policy, now.Add(45*time.Minute)))
+	assert.False(t, a.Exists(ethTx))
+	assert.True(t, a.Exists(fresh))
+	assert.EqualValues(t, 3, a.CurSize())
+
+	// Zero ages disable the eviction.
+	assert.Equal(t, 0, a.EvictExpired(ExpirationPolicy{}, now.Add(24*time.Hour)))
+	require.Equal(t, 1, a.Len())
+	assert.EqualValues(t, 20, a.Pop().T.GetFee())
+	assert.Nil(t, a.Pop())
+}
+
+func TestUtxImpl_EvictExpiredArrivalTime(t *testing.T) {
+	// The pool's clock is behind the local one, as NTP-corrected time may be.
+	now := time.Now()
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), tm(now.Add(-time.Hour)))
+	ethTx := newTestEthereumTx(t, 1)
+	require.NoError(t, a.AddWithBytes(ethTx, []byte{1}))
+
+	policy := ExpirationPolicy{MaxEthereumAge: 30 * time.Minute}
+	assert.Equal(t, 0, a.EvictExpired(policy, now.Add(-time.Hour)))
+	assert.Equal(t, 1, a.EvictExpired(policy, now.Add(-15*time.Minute)))
+	assert.False(t, a.Exists(ethTx))
+}
+
+func TestUtxImpl_EvictExpiredKeepsOrder(t *testing.T) {
+	now := time.Now()
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
+	old := uint64(now.Add(-2 * time.Hour).UnixMilli())
+	recent := uint64(now.UnixMilli())
+	for i, fee := range []uint64{5, 40, 15, 30, 25} {
+		timestamp := recent
+		if i%2 == 0 {
+			timestamp = old
+		}
+		require.NoError(t, a.AddWithBytes(&transaction{fee: fee, id: []byte{byte(i)}, timestamp: timestamp}, []byte{1}))
+	}
+	assert.Equal(t, 3, a.EvictExpired(ExpirationPolicy{MaxAge: time.Hour}, now))
+	assert.EqualValues(t, 40, a.Pop().T.GetFee())
+	assert.EqualValues(t, 30, a.Pop().T.GetFee())
+	assert.Nil(t, a.Pop())
+}
+
+func TestUtxImpl_RunEviction(t *testing.T) {
+	now := time.Now()
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
+	expired := &transaction{fee: 10, id: []byte{1}, timestamp: uint64(now.Add(-2 * time.Hour).UnixMilli())}
+	fresh := &transaction{fee: 20, id: []byte{2}, timestamp: uint64(now.UnixMilli())}
+	require.NoError(t, a.AddWithBytes(expired, []byte{1}))
+	require.NoError(t, a.AddWithBytes(fresh, []byte{1}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.RunEviction(ctx, ExpirationPolicy{MaxAge: time.Hour}, time.Millisecond, tm(now))
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return !a.Exists(expired) }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	assert.True(t, a.Exists(fresh))
+}
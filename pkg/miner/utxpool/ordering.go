@@ -0,0 +1,135 @@
+package utxpool
+
+import (
+	"bytes"
+	"math/big"
+	"time"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+// MempoolEntry is a transaction stored in the pool.
+type MempoolEntry struct {
+	*types.TransactionWithBytes
+	// ID is the transaction ID.
+	ID crypto.Digest
+	// Seq is the sequence number of the transaction arrival to the pool.
+	Seq uint64
+	// Arrival is the time of the transaction arrival to the pool.
+	Arrival time.Time
+}
+
+// MempoolOrdering defines the order in which transactions are selected from the pool for block assembly.
+// Implementations must define a strict weak ordering.
+type MempoolOrdering interface {
+	// Less reports whether the transaction a must be selected before the transaction b.
+	Less(a, b *MempoolEntry) bool
+}
+
+// FeePerByteOrdering selects transactions with the higher fee per byte first, it's the default ordering.
+// Ethereum transactions with the same fee per byte are ordered by the effective gas tip. The remaining ties are
+// broken by transaction IDs, so the same pool gives the same selection regardless of the order of arrival.
+type FeePerByteOrdering struct{}
+
+func (FeePerByteOrdering) Less(a, b *MempoolEntry) bool {
+	// skip division by zero, check it when we add transaction
+	fa, fb := feePerByte(a.T.GetFee(), a.B), feePerByte(b.T.GetFee(), b.B)
+	if fa != fb {
+		return fa > fb
+	}
+	if c := effectiveGasTip(a.T).Cmp(effectiveGasTip(b.T)); c != 0 {
+		return c > 0
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
+
+// FIFOOrdering selects transactions in the order of their arrival to the pool.
+type FIFOOrdering struct{}
+
+func (FIFOOrdering) Less(a, b *MempoolEntry) bool {
+	return a.Seq < b.Seq
+}
+
+// SponsorshipAwareOrdering selects transactions with the higher fee per byte in Waves first,
+// fees in sponsored assets are converted to Waves with ToWaves function.
+// Transactions with fee that can't be converted are selected last in the order of arrival.
+type SponsorshipAwareOrdering struct {
+	ToWaves func(asset proto.OptionalAsset, fee uint64) (uint64, bool)
+}
+
+func (o SponsorshipAwareOrdering) Less(a, b *MempoolEntry) bool {
+	fa, okA := o.wavesFeePerByte(a)
+	fb, okB := o.wavesFeePerByte(b)
+	switch {
+	case okA && okB:
+		if fa != fb {
+			return fa > fb
+		}
+		return a.Seq < b.Seq
+	case okA != okB:
+		return okA
+	default:
+		return a.Seq < b.Seq
+	}
+}
+
+func (o SponsorshipAwareOrdering) wavesFeePerByte(e *MempoolEntry) (uint64, bool) {
+	asset := e.T.GetFeeAsset()
+	if !asset.Present {
+		return feePerByte(e.T.GetFee(), e.B), true
+	}
+	fee, ok := o.ToWaves(asset, e.T.GetFee())
+	if !ok {
+		return 0, false
+	}
+	return feePerByte(fee, e.B), true
+}
+
+func feePerByte(fee uint64, b []byte) uint64 {
+	return fee / uint64(len(b))
+}
+
+// effectiveGasTip returns the gas tip that Ethereum transaction pays in the absence of the base fee,
+// zero is returned for other transactions.
+func effectiveGasTip(tx proto.Transaction) *big.Int {
+	ethTx, ok := tx.(*proto.EthereumTransaction)
+	if !ok {
+		return big.NewInt(0)
+	}
+	tip, feeCap := ethTx.GasTipCap(), ethTx.GasFeeCap()
+	if tip.Cmp(feeCap) > 0 {
+		return feeCap
+	}
+	return tip
+}
+
+type transactionsHeap struct {
+	entries  []*MempoolEntry
+	ordering MempoolOrdering
+}
+
+func (a *transactionsHeap) Len() int { return len(a.entries) }
+
+func (a *transactionsHeap) Less(i, j int) bool {
+	return a.ordering.Less(a.entries[i], a.entries[j])
+}
+
+func (a *transactionsHeap) Swap(i, j int) {
+	a.entries[i], a.entries[j] = a.entries[j], a.entries[i]
+}
+
+func (a *transactionsHeap) Push(x interface{}) {
+	item := x.(*MempoolEntry)
+	a.entries = append(a.entries, item)
+}
+
+func (a *transactionsHeap) Pop() interface{} {
+	old := a.entries
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	a.entries = old[0 : n-1]
+	return item
+}
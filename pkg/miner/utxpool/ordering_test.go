@@ -0,0 +1,87 @@
+package utxpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+type sponsoredTransaction struct {
+	*transaction
+	asset proto.OptionalAsset
+}
+
+func (a sponsoredTransaction) GetFeeAsset() proto.OptionalAsset {
+	return a.asset
+}
+
+func TestMempoolOrderings(t *testing.T) {
+	sponsored := *proto.NewOptionalAssetFromDigest(crypto.MustFastHash([]byte("sponsored")))
+	unknown := *proto.NewOptionalAssetFromDigest(crypto.MustFastHash([]byte("unknown")))
+	toWaves := func(asset proto.OptionalAsset, fee uint64) (uint64, bool) {
+		if asset == sponsored {
+			return fee * 100, true
+		}
+		return 0, false
+	}
+	type entry struct {
+		tx   proto.Transaction
+		size int
+	}
+	pool := []entry{
+		{id([]byte{1}, 10), 1},                                  // 10 per byte
+		{id([]byte{2}, 30), 2},                                  // 15 per byte
+		{sponsoredTransaction{id([]byte{3}, 1), sponsored}, 1},  // 1 per byte, 100 in Waves
+		{id([]byte{4}, 5), 1},                                   // 5 per byte
+		{sponsoredTransaction{id([]byte{5}, 1000), unknown}, 1}, // 1000 per byte, not convertible
+		{sponsoredTransaction{id([]byte{6}, 12), sponsored}, 1}, // 12 per byte, 1200 in Waves
+	}
+	for _, test := range []struct {
+		name     string
+		ordering MempoolOrdering
+		expected []byte
+	}{
+		{"default", FeePerByteOrdering{}, []byte{5, 2, 6, 1, 4, 3}},
+		{"fifo", FIFOOrdering{}, []byte{1, 2, 3, 4, 5, 6}},
+		{"sponsorship", SponsorshipAwareOrdering{ToWaves: toWaves}, []byte{6, 3, 2, 1, 4, 5}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			a := NewWithOrdering(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{}, test.ordering)
+			for _, e := range pool {
+				require.NoError(t, a.AddWithBytes(e.tx, make([]byte, e.size)))
+			}
+			require.Len(t, a.AllTransactions(), len(pool))
+			order := make([]byte, 0, len(pool))
+			for tb := a.Pop(); tb != nil; tb = a.Pop() {
+				txID, err := tb.T.GetID(proto.MainNetScheme)
+				require.NoError(t, err)
+				order = append(order, txID[0])
+			}
+			assert.Equal(t, test.expected, order)
+		})
+	}
+}
+
+func TestFeePerByteOrderingTieBreak(t *testing.T) {
+	ids := [][]byte{{5}, {1}, {4}, {2}, {3}}
+	for _, perm := range [][]int{{0, 1, 2, 3, 4}, {4, 3, 2, 1, 0}, {2, 0, 4, 1, 3}} {
+		a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
+		for _, i := range perm {
+			require.NoError(t, a.AddWithBytes(id(ids[i], 10), []byte{1}))
+		}
+		require.NoError(t, a.AddWithBytes(id([]byte{9}, 20), []byte{1}))
+		order := make([]byte, 0, len(ids)+1)
+		for tb := a.Pop(); tb != nil; tb = a.Pop() {
+			txID, err := tb.T.GetID(proto.MainNetScheme)
+			require.NoError(t, err)
+			order = append(order, txID[0])
+		}
+		assert.Equal(t, []byte{9, 1, 2, 3, 4, 5}, order)
+	}
+}
@@ -0,0 +1,158 @@
+package utxpool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const (
+	mempoolFileVersion  byte = 1
+	maxPersistedTxBytes      = 1024 * 1024
+)
+
+// PersistMempool writes pending transactions to the file at the given path in the order of their arrival to the pool.
+// Transactions of all types, including Ethereum ones, are stored in the deterministic protobuf encoding of signed
+// transaction. The file is written to a temporary file first and then renamed, so the previous file is never
+// left half-written.
+func (a *UtxImpl) PersistMempool(path string) error {
+	a.mu.Lock()
+	entries := slices.Clone(a.transactions.entries)
+	a.mu.Unlock()
+	slices.SortFunc(entries, func(x, y *MempoolEntry) int {
+		switch {
+		case x.Seq < y.Seq:
+			return -1
+		case x.Seq > y.Seq:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary mempool file")
+	}
+	tmp := f.Name()
+	defer func() {
+		_ = os.Remove(tmp) // Does nothing after successful rename
+	}()
+	if err := a.writeMempool(f, entries); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temporary mempool file")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrap(err, "failed to replace mempool file")
+	}
+	return nil
+}
+
+func (a *UtxImpl) writeMempool(w io.Writer, entries []*MempoolEntry) error {
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte(mempoolFileVersion); err != nil {
+		return errors.Wrap(err, "failed to write mempool file version")
+	}
+	var size [4]byte
+	for _, e := range entries {
+		b, err := proto.MarshalSignedTxDeterministic(e.T, a.settings.AddressSchemeCharacter)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal transaction")
+		}
+		binary.BigEndian.PutUint32(size[:], uint32(len(b)))
+		if _, err := bw.Write(size[:]); err != nil {
+			return errors.Wrap(err, "failed to write transaction size")
+		}
+		if _, err := bw.Write(b); err != nil {
+			return errors.Wrap(err, "failed to write transaction")
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "failed to flush mempool file")
+	}
+	return nil
+}
+
+// LoadMempool adds to the pool the transactions stored by PersistMempool. Every transaction is validated again
+// against the current state, transactions that became invalid since they were stored are dropped with the reason
+// logged. Missing file is not an error, the pool is left as is in this case.
+func (a *UtxImpl) LoadMempool(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to open mempool file")
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			zap.S().Errorf("Failed to close mempool file: %v", err)
+		}
+	}()
+	txs, err := readMempool(bufio.NewReader(f))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read mempool file '%s'", path)
+	}
+	loaded := 0
+	for _, tx := range txs {
+		if err := a.Add(tx); err != nil {
+			id, idErr := tx.GetID(a.settings.AddressSchemeCharacter)
+			if idErr != nil {
+				zap.S().Debugf("UTX: stored transaction dropped: %v", err)
+				continue
+			}
+			zap.S().Debugf("UTX: stored transaction %s dropped: %v", base58.Encode(id), err)
+			continue
+		}
+		loaded++
+	}
+	zap.S().Infof("UTX: %d of %d stored transactions loaded", loaded, len(txs))
+	return nil
+}
+
+func readMempool(r io.Reader) ([]proto.Transaction, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, errors.Wrap(err, "failed to read mempool file version")
+	}
+	if version[0] != mempoolFileVersion {
+		return nil, errors.Errorf("unsupported mempool file version %d", version[0])
+	}
+	var (
+		txs  []proto.Transaction
+		size [4]byte
+	)
+	for {
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return txs, nil
+			}
+			return nil, errors.Wrap(err, "failed to read transaction size")
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n > maxPersistedTxBytes {
+			return nil, errors.Errorf("invalid transaction size %d", n)
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, errors.Wrap(err, "failed to read transaction")
+		}
+		tx, err := proto.SignedTxFromProtobuf(b)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal transaction")
+		}
+		txs = append(txs, tx)
+	}
+}
@@ -0,0 +1,84 @@
+package utxpool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/util/byte_helpers"
+)
+
+type rejectingValidator struct {
+	rejected proto.TransactionType
+}
+
+func (v rejectingValidator) Validate(t proto.Transaction) error {
+	if t.GetType() == v.rejected {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func TestUtxImpl_PersistLoadMempool(t *testing.T) {
+	ethRaw := "0xf86e82146f8513532f83b3825208949c4c39e3cd2f3d0d930e4c065af5ea4a1fcb4a6e880342e341423780008025a0" +
+		"86bd7bec8019f17fe77be36468656c9ede915514f1fc158a4eee8a36264b8315a0205b9fa92365441fd7c06fdce3f9d431007bfeb0253" +
+		"032fc1f6364683bff37c5"
+	canonical, err := proto.DecodeFromHexString(ethRaw)
+	require.NoError(t, err)
+	var ethTx proto.EthereumTransaction
+	require.NoError(t, ethTx.DecodeCanonical(canonical))
+	txs := []proto.Transaction{
+		byte_helpers.TransferWithSig.Transaction,
+		&ethTx,
+		byte_helpers.IssueWithProofs.Transaction,
+	}
+	bs := settings.MustMainNetSettings()
+	path := filepath.Join(t.TempDir(), "utx.bin")
+
+	a := New(1024*1024, NoOpValidator{}, bs, ntptime.Stub{})
+	for _, tx := range txs {
+		require.NoError(t, a.Add(tx))
+	}
+	require.NoError(t, a.PersistMempool(path))
+
+	loaded := New(1024*1024, NoOpValidator{}, bs, ntptime.Stub{})
+	require.NoError(t, loaded.LoadMempool(path))
+	require.Equal(t, len(txs), loaded.Len())
+	assert.Equal(t, a.CurSize(), loaded.CurSize())
+	for _, tx := range txs {
+		id, err := tx.GetID(bs.AddressSchemeCharacter)
+		require.NoError(t, err)
+		assert.True(t, loaded.ExistsByID(id))
+	}
+
+	// Transactions that are invalid against the current state are dropped.
+	validated := New(1024*1024, rejectingValidator{rejected: proto.IssueTransaction}, bs, ntptime.Stub{})
+	require.NoError(t, validated.LoadMempool(path))
+	assert.Equal(t, len(txs)-1, validated.Len())
+	assert.False(t, validated.Exists(txs[2]))
+
+	// Missing file leaves the pool empty.
+	empty := New(1024*1024, NoOpValidator{}, bs, ntptime.Stub{})
+	require.NoError(t, empty.LoadMempool(filepath.Join(t.TempDir(), "missing.bin")))
+	assert.Zero(t, empty.Len())
+}
+
+func TestUtxImpl_LoadMempoolCorrupted(t *testing.T) {
+	bs := settings.MustMainNetSettings()
+	dir := t.TempDir()
+
+	unsupported := filepath.Join(dir, "unsupported.bin")
+	require.NoError(t, os.WriteFile(unsupported, []byte{mempoolFileVersion + 1}, 0600))
+	assert.Error(t, New(1024, NoOpValidator{}, bs, ntptime.Stub{}).LoadMempool(unsupported))
+
+	truncated := filepath.Join(dir, "truncated.bin")
+	require.NoError(t, os.WriteFile(truncated, []byte{mempoolFileVersion, 0, 0, 0, 10, 1, 2}, 0600))
+	assert.Error(t, New(1024, NoOpValidator{}, bs, ntptime.Stub{}).LoadMempool(truncated))
+}
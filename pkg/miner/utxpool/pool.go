@@ -13,32 +13,6 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/types"
 )
 
-type transactionsHeap []*types.TransactionWithBytes
-
-func (a transactionsHeap) Len() int { return len(a) }
-
-func (a transactionsHeap) Less(i, j int) bool {
-	// skip division by zero, check it when we add transaction
-	return a[i].T.GetFee()/uint64(len(a[i].B)) > a[j].T.GetFee()/uint64(len(a[j].B))
-}
-
-func (a transactionsHeap) Swap(i, j int) {
-	a[i], a[j] = a[j], a[i]
-}
-
-func (a *transactionsHeap) Push(x interface{}) {
-	item := x.(*types.TransactionWithBytes)
-	*a = append(*a, item)
-}
-
-func (a *transactionsHeap) Pop() interface{} {
-	old := *a
-	n := len(old)
-	item := old[n-1]
-	*a = old[0 : n-1]
-	return item
-}
-
 type UtxImpl struct {
 	mu             sync.Mutex
 	transactions   transactionsHeap
@@ -47,14 +21,26 @@ type UtxImpl struct {
 	curSize        uint64
 	validator      Validator
 	settings       *settings.BlockchainSettings
+	nextSeq        uint64
+	tm             types.Time // source of the transactions arrival time, must be the same as used for eviction
 }
 
-func New(sizeLimit uint64, validator Validator, settings *settings.BlockchainSettings) *UtxImpl {
+func New(sizeLimit uint64, validator Validator, settings *settings.BlockchainSettings, tm types.Time) *UtxImpl {
+	return NewWithOrdering(sizeLimit, validator, settings, tm, FeePerByteOrdering{})
+}
+
+// NewWithOrdering creates the pool which selects transactions in the order defined by the given ordering.
+func NewWithOrdering(
+	sizeLimit uint64, validator Validator, settings *settings.BlockchainSettings, tm types.Time,
+	ordering MempoolOrdering,
+) *UtxImpl {
 	return &UtxImpl{
+		transactions:   transactionsHeap{ordering: ordering},
 		transactionIds: make(map[crypto.Digest]struct{}),
 		sizeLimit:      sizeLimit,
 		validator:      validator,
 		settings:       settings,
+		tm:             tm,
 	}
 }
 
@@ -62,8 +48,10 @@ func (a *UtxImpl) AllTransactions() []*types.TransactionWithBytes {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	res := make([]*types.TransactionWithBytes, len(a.transactions))
-	copy(res, a.transactions)
+	res := make([]*types.TransactionWithBytes, len(a.transactions.entries))
+	for i, e := range a.transactions.entries {
+		res[i] = e.TransactionWithBytes
+	}
 	return res
 }
 
@@ -123,8 +111,9 @@ func (a *UtxImpl) addWithBytes(t proto.Transaction, b []byte) error {
 		T: t,
 		B: b,
 	}
-	heap.Push(&a.transactions, tb)
 	id := makeDigest(t.GetID(a.settings.AddressSchemeCharacter))
+	heap.Push(&a.transactions, &MempoolEntry{TransactionWithBytes: tb, ID: id, Seq: a.nextSeq, Arrival: a.tm.Now()})
+	a.nextSeq++
 	a.transactionIds[id] = struct{}{}
 	a.curSize += uint64(len(b))
 	return nil
@@ -133,7 +122,7 @@ func (a *UtxImpl) addWithBytes(t proto.Transaction, b []byte) error {
 func (a *UtxImpl) Count() int {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	return len(a.transactions)
+	return a.transactions.Len()
 }
 
 func makeDigest(b []byte, _ error) crypto.Digest {
@@ -168,7 +157,7 @@ func (a *UtxImpl) Pop() *types.TransactionWithBytes {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	if a.transactions.Len() > 0 {
-		tb := heap.Pop(&a.transactions).(*types.TransactionWithBytes)
+		tb := heap.Pop(&a.transactions).(*MempoolEntry).TransactionWithBytes
 		delete(a.transactionIds, makeDigest(tb.T.GetID(a.settings.AddressSchemeCharacter)))
 		if uint64(len(tb.B)) > a.curSize {
 			panic(fmt.Sprintf("UtxImpl Pop: size of transaction %d > than current size %d", len(tb.B), a.curSize))
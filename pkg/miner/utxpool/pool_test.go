@@ -9,14 +9,16 @@ import (
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/util/byte_helpers"
 )
 
 type transaction struct {
-	fee uint64
-	id  []byte
+	fee       uint64
+	id        []byte
+	timestamp uint64
 }
 
 func (a transaction) BinarySize() int {
@@ -71,8 +73,8 @@ func (transaction) UnmarshalBinary([]byte, proto.Scheme) error {
 	panic("not implemented")
 }
 
-func (transaction) GetTimestamp() uint64 {
-	return 0
+func (a transaction) GetTimestamp() uint64 {
+	return a.timestamp
 }
 
 func (transaction) GenerateID(_ proto.Scheme) error {
@@ -107,6 +109,10 @@ func (a transaction) GetSenderPK() crypto.PublicKey {
 	panic("not implemented")
 }
 
+func (a transaction) GetProofs() (*proto.ProofsV1, error) {
+	panic("not implemented")
+}
+
 func (a transaction) GetSender(_ proto.Scheme) (proto.Address, error) {
 	panic("not implemented")
 }
@@ -120,7 +126,7 @@ func id(b []byte, fee uint64) *transaction {
 }
 
 func TestTransactionPool(t *testing.T) {
-	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings())
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
 
 	require.EqualValues(t, 0, a.CurSize())
 	// add unique by id transactions, then check them sorted
@@ -140,7 +146,7 @@ func TestTransactionPool(t *testing.T) {
 
 func BenchmarkTransactionPool(b *testing.B) {
 	b.ReportAllocs()
-	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings())
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -164,7 +170,7 @@ func BenchmarkTransactionPool(b *testing.B) {
 }
 
 func TestTransactionPool_Exists(t *testing.T) {
-	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings())
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
 
 	require.False(t, a.Exists(id([]byte{1, 2, 3}, 0)))
 
@@ -177,7 +183,7 @@ func TestTransactionPool_Exists(t *testing.T) {
 
 // check transaction not added when limit
 func TestUtxPool_Limit(t *testing.T) {
-	a := New(10, NoOpValidator{}, settings.MustMainNetSettings())
+	a := New(10, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
 	require.Equal(t, 0, a.Len())
 
 	// added
@@ -192,13 +198,13 @@ func TestUtxPool_Limit(t *testing.T) {
 }
 
 func TestUtxImpl_AllTransactions(t *testing.T) {
-	a := New(10, NoOpValidator{}, settings.MustMainNetSettings())
+	a := New(10, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
 	_ = a.AddWithBytes(id([]byte{1, 2, 3}, 10), bytes.Repeat([]byte{1, 2}, 5))
 	require.Len(t, a.AllTransactions(), 1)
 }
 
 func TestUtxImpl_TransactionExists(t *testing.T) {
-	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings())
+	a := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
 	require.NoError(t, a.AddWithBytes(byte_helpers.BurnWithSig.Transaction, byte_helpers.BurnWithSig.TransactionBytes))
 	require.True(t, a.ExistsByID(byte_helpers.BurnWithSig.Transaction.ID.Bytes()))
 	require.False(t, a.ExistsByID(byte_helpers.TransferWithSig.Transaction.ID.Bytes()))
@@ -7,6 +7,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/wavesplatform/gowaves/pkg/libs/ntptime"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/util/byte_helpers"
@@ -22,7 +23,7 @@ func TestBulkValidator_Validate(t *testing.T) {
 	m := NewMockstateWrapper(ctrl)
 	m.EXPECT().TopBlock().Return(emptyBlock)
 	m.EXPECT().Map(gomock.Any()).Return(nil)
-	utx := New(10000, NoOpValidator{}, settings.MustMainNetSettings())
+	utx := New(10000, NoOpValidator{}, settings.MustMainNetSettings(), ntptime.Stub{})
 	require.NoError(t, utx.AddWithBytes(byte_helpers.TransferWithSig.Transaction, byte_helpers.TransferWithSig.TransactionBytes))
 
 	validator := newBulkValidator(m, utx, tm(now))
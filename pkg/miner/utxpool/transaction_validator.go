@@ -38,12 +38,39 @@ func (a *ValidatorImpl) Validate(tx proto.Transaction) error {
 	if now.Add(-a.obsolescence).After(lastBlockTime) {
 		return errors.New("state outdated, transaction not accepted")
 	}
+	if ethTx, ok := tx.(*proto.EthereumTransaction); ok {
+		if err := checkEthereumMinFee(ethTx); err != nil {
+			return err
+		}
+		if err := ethTx.CheckGasSufficient(); err != nil {
+			return err
+		}
+	}
 	return a.state.TxValidation(func(validation state.TxValidation) error {
 		_, err := validation.ValidateNextTx(tx, uint64(now.UnixMilli()), lastBlock.Timestamp, lastBlock.Version, false)
 		return err
 	})
 }
 
+// checkEthereumMinFee rejects Ethereum transactions which gas limit is less than the minimal fee of the operation.
+// The kind of operation is guessed from the call data, so the check doesn't require access to the state.
+func checkEthereumMinFee(tx *proto.EthereumTransaction) error {
+	kind, err := proto.GuessEthereumTransactionKindType(tx.Data())
+	if err != nil {
+		return errors.Wrap(err, "failed to decode ethereum transaction call data")
+	}
+	isInvoke := kind == proto.EthereumInvokeKindType
+	minFee := state.MinEthereumGas(isInvoke)
+	if fee := tx.GetFee(); fee < minFee {
+		operation := "transfer"
+		if isInvoke {
+			operation = "invoke"
+		}
+		return errors.Errorf("ethereum %s transaction fee %d is less than minimal fee %d", operation, fee, minFee)
+	}
+	return nil
+}
+
 type NoOpValidator struct {
 }
 
@@ -1,12 +1,15 @@
 package utxpool
 
 import (
+	"bytes"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/state"
 	"github.com/wavesplatform/gowaves/pkg/util/byte_helpers"
 )
 
@@ -35,3 +38,45 @@ func TestValidatorImpl_Validate(t *testing.T) {
 	err = v.Validate(byte_helpers.BurnWithSig.Transaction)
 	require.NoError(t, err)
 }
+
+func TestValidatorImpl_ValidateEthereumMinFee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	emptyBlock := &proto.Block{}
+	emptyBlock.Timestamp = proto.NewTimestampFromTime(time.Now())
+	now := time.Now()
+
+	m := NewMockstateWrapper(ctrl)
+	v, err := NewValidator(m, tm(now), 24*time.Hour)
+	require.NoError(t, err)
+
+	to := proto.EthereumAddress{}
+	newTx := func(gas uint64, data []byte) *proto.EthereumTransaction {
+		tx := proto.NewEthereumTransaction(&proto.EthereumLegacyTx{
+			GasPrice: big.NewInt(int64(proto.EthereumGasPrice)),
+			Gas:      gas,
+			To:       &to,
+			Value:    big.NewInt(0),
+			Data:     data,
+		}, nil, nil, nil, 0)
+		return &tx
+	}
+	// Selector of some dApp function, the call is an invocation.
+	invokeData := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	m.EXPECT().TopBlock().Return(emptyBlock)
+	err = v.Validate(newTx(state.MinEthereumGas(false), invokeData))
+	require.EqualError(t, err, "ethereum invoke transaction fee 100000 is less than minimal fee 500000")
+
+	m.EXPECT().TopBlock().Return(emptyBlock)
+	m.EXPECT().TxValidation(gomock.Any())
+	err = v.Validate(newTx(state.MinEthereumGas(false), nil))
+	require.NoError(t, err)
+
+	// The minimal fee is paid, but the gas limit doesn't cover the call data.
+	bigInvokeData := append(invokeData, bytes.Repeat([]byte{1}, 30000)...)
+	m.EXPECT().TopBlock().Return(emptyBlock)
+	err = v.Validate(newTx(state.MinEthereumGas(true), bigInvokeData))
+	require.EqualError(t, err, "insufficient gas: gas limit 500000 is less than intrinsic gas 501064")
+}
@@ -5,6 +5,8 @@
 package mock
 
 import (
+	context "context"
+	io "io"
 	big "math/big"
 	reflect "reflect"
 
@@ -118,6 +120,21 @@ func (m *MockStateInfo) EXPECT() *MockStateInfoMockRecorder {
 	return m.recorder
 }
 
+// AccountStateSize mocks base method.
+func (m *MockStateInfo) AccountStateSize(arg0 proto.WavesAddress) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountStateSize", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountStateSize indicates an expected call of AccountStateSize.
+func (mr *MockStateInfoMockRecorder) AccountStateSize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountStateSize", reflect.TypeOf((*MockStateInfo)(nil).AccountStateSize), arg0)
+}
+
 // ActivationHeight mocks base method.
 func (m *MockStateInfo) ActivationHeight(featureID int16) (proto.Height, error) {
 	m.ctrl.T.Helper()
@@ -148,6 +165,36 @@ func (mr *MockStateInfoMockRecorder) AddrByAlias(alias interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddrByAlias", reflect.TypeOf((*MockStateInfo)(nil).AddrByAlias), alias)
 }
 
+// AddressByAliasAt mocks base method.
+func (m *MockStateInfo) AddressByAliasAt(alias proto.Alias, height proto.Height) (proto.WavesAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddressByAliasAt", alias, height)
+	ret0, _ := ret[0].(proto.WavesAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddressByAliasAt indicates an expected call of AddressByAliasAt.
+func (mr *MockStateInfoMockRecorder) AddressByAliasAt(alias, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddressByAliasAt", reflect.TypeOf((*MockStateInfo)(nil).AddressByAliasAt), alias, height)
+}
+
+// AddressTransactions mocks base method.
+func (m *MockStateInfo) AddressTransactions(arg0 proto.WavesAddress, arg1 int, arg2 *crypto.Digest) ([]proto.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddressTransactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]proto.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddressTransactions indicates an expected call of AddressTransactions.
+func (mr *MockStateInfoMockRecorder) AddressTransactions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddressTransactions", reflect.TypeOf((*MockStateInfo)(nil).AddressTransactions), arg0, arg1, arg2)
+}
+
 // AliasesByAddr mocks base method.
 func (m *MockStateInfo) AliasesByAddr(addr proto.WavesAddress) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -163,6 +210,21 @@ func (mr *MockStateInfoMockRecorder) AliasesByAddr(addr interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AliasesByAddr", reflect.TypeOf((*MockStateInfo)(nil).AliasesByAddr), addr)
 }
 
+// AllDataEntries mocks base method.
+func (m *MockStateInfo) AllDataEntries(arg0 proto.WavesAddress) ([]proto.DataEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllDataEntries", arg0)
+	ret0, _ := ret[0].([]proto.DataEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllDataEntries indicates an expected call of AllDataEntries.
+func (mr *MockStateInfoMockRecorder) AllDataEntries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllDataEntries", reflect.TypeOf((*MockStateInfo)(nil).AllDataEntries), arg0)
+}
+
 // AllFeatures mocks base method.
 func (m *MockStateInfo) AllFeatures() ([]int16, error) {
 	m.ctrl.T.Helper()
@@ -238,6 +300,37 @@ func (mr *MockStateInfoMockRecorder) AssetIsSponsored(assetID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetIsSponsored", reflect.TypeOf((*MockStateInfo)(nil).AssetIsSponsored), assetID)
 }
 
+// AssetSupply mocks base method.
+func (m *MockStateInfo) AssetSupply(arg0 proto.AssetID) (uint64, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssetSupply", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AssetSupply indicates an expected call of AssetSupply.
+func (mr *MockStateInfoMockRecorder) AssetSupply(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetSupply", reflect.TypeOf((*MockStateInfo)(nil).AssetSupply), arg0)
+}
+
+// AssetsIssuedBy mocks base method.
+func (m *MockStateInfo) AssetsIssuedBy(addr proto.WavesAddress) ([]proto.AssetID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssetsIssuedBy", addr)
+	ret0, _ := ret[0].([]proto.AssetID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssetsIssuedBy indicates an expected call of AssetsIssuedBy.
+func (mr *MockStateInfoMockRecorder) AssetsIssuedBy(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetsIssuedBy", reflect.TypeOf((*MockStateInfo)(nil).AssetsIssuedBy), addr)
+}
+
 // Block mocks base method.
 func (m *MockStateInfo) Block(blockID proto.BlockID) (*proto.Block, error) {
 	m.ctrl.T.Helper()
@@ -268,6 +361,68 @@ func (mr *MockStateInfoMockRecorder) BlockByHeight(height interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockByHeight", reflect.TypeOf((*MockStateInfo)(nil).BlockByHeight), height)
 }
 
+// BlockBalanceChanges mocks base method.
+func (m *MockStateInfo) BlockBalanceChanges(blockID proto.BlockID, assetID *proto.AssetID) (map[proto.WavesAddress][2]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockBalanceChanges", blockID, assetID)
+	ret0, _ := ret[0].(map[proto.WavesAddress][2]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockBalanceChanges indicates an expected call of BlockBalanceChanges.
+func (mr *MockStateInfoMockRecorder) BlockBalanceChanges(blockID, assetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockBalanceChanges", reflect.TypeOf((*MockStateInfo)(nil).BlockBalanceChanges), blockID, assetID)
+}
+
+// BlockDataChanges mocks base method.
+func (m *MockStateInfo) BlockDataChanges(blockID proto.BlockID) (map[proto.WavesAddress][]proto.DataEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockDataChanges", blockID)
+	ret0, _ := ret[0].(map[proto.WavesAddress][]proto.DataEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockDataChanges indicates an expected call of BlockDataChanges.
+func (mr *MockStateInfoMockRecorder) BlockDataChanges(blockID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockDataChanges", reflect.TypeOf((*MockStateInfo)(nil).BlockDataChanges), blockID)
+}
+
+// BlockGenerationSignature mocks base method.
+func (m *MockStateInfo) BlockGenerationSignature(blockID proto.BlockID) ([]byte, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockGenerationSignature", blockID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BlockGenerationSignature indicates an expected call of BlockGenerationSignature.
+func (mr *MockStateInfoMockRecorder) BlockGenerationSignature(blockID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockGenerationSignature", reflect.TypeOf((*MockStateInfo)(nil).BlockGenerationSignature), blockID)
+}
+
+// BlockHeaderWithTxCount mocks base method.
+func (m *MockStateInfo) BlockHeaderWithTxCount(height proto.Height) (*proto.BlockHeader, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockHeaderWithTxCount", height)
+	ret0, _ := ret[0].(*proto.BlockHeader)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BlockHeaderWithTxCount indicates an expected call of BlockHeaderWithTxCount.
+func (mr *MockStateInfoMockRecorder) BlockHeaderWithTxCount(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHeaderWithTxCount", reflect.TypeOf((*MockStateInfo)(nil).BlockHeaderWithTxCount), height)
+}
+
 // BlockIDToHeight mocks base method.
 func (m *MockStateInfo) BlockIDToHeight(blockID proto.BlockID) (proto.Height, error) {
 	m.ctrl.T.Helper()
@@ -298,6 +453,21 @@ func (mr *MockStateInfoMockRecorder) BlockRewards(generator, height interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockRewards", reflect.TypeOf((*MockStateInfo)(nil).BlockRewards), generator, height)
 }
 
+// BlockSnapshot mocks base method.
+func (m *MockStateInfo) BlockSnapshot(arg0 proto.BlockID) (*proto.BlockSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSnapshot", arg0)
+	ret0, _ := ret[0].(*proto.BlockSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSnapshot indicates an expected call of BlockSnapshot.
+func (mr *MockStateInfoMockRecorder) BlockSnapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSnapshot", reflect.TypeOf((*MockStateInfo)(nil).BlockSnapshot), arg0)
+}
+
 // BlockVRF mocks base method.
 func (m *MockStateInfo) BlockVRF(blockHeader *proto.BlockHeader, blockHeight proto.Height) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -388,6 +558,49 @@ func (mr *MockStateInfoMockRecorder) EstimatorVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimatorVersion", reflect.TypeOf((*MockStateInfo)(nil).EstimatorVersion))
 }
 
+// EthereumCall mocks base method.
+func (m *MockStateInfo) EthereumCall(from proto.EthereumAddress, to proto.EthereumAddress, data []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthereumCall", from, to, data)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthereumCall indicates an expected call of EthereumCall.
+func (mr *MockStateInfoMockRecorder) EthereumCall(from, to, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthereumCall", reflect.TypeOf((*MockStateInfo)(nil).EthereumCall), from, to, data)
+}
+
+// ExportBalancesCSV mocks base method.
+func (m *MockStateInfo) ExportBalancesCSV(arg0 io.Writer, arg1 *proto.AssetID, arg2 proto.Height) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBalancesCSV", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBalancesCSV indicates an expected call of ExportBalancesCSV.
+func (mr *MockStateInfoMockRecorder) ExportBalancesCSV(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBalancesCSV", reflect.TypeOf((*MockStateInfo)(nil).ExportBalancesCSV), arg0, arg1, arg2)
+}
+
+// ExportStateSnapshot mocks base method.
+func (m *MockStateInfo) ExportStateSnapshot(w io.Writer, height proto.Height) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStateSnapshot", w, height)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportStateSnapshot indicates an expected call of ExportStateSnapshot.
+func (mr *MockStateInfoMockRecorder) ExportStateSnapshot(w, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStateSnapshot", reflect.TypeOf((*MockStateInfo)(nil).ExportStateSnapshot), w, height)
+}
+
 // FullAssetInfo mocks base method.
 func (m *MockStateInfo) FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error) {
 	m.ctrl.T.Helper()
@@ -433,6 +646,21 @@ func (mr *MockStateInfoMockRecorder) GeneratingBalance(account, height interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratingBalance", reflect.TypeOf((*MockStateInfo)(nil).GeneratingBalance), account, height)
 }
 
+// GeneratingBalances mocks base method.
+func (m *MockStateInfo) GeneratingBalances(arg0 proto.Height, arg1 []proto.WavesAddress) (map[proto.WavesAddress]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GeneratingBalances", arg0, arg1)
+	ret0, _ := ret[0].(map[proto.WavesAddress]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GeneratingBalances indicates an expected call of GeneratingBalances.
+func (mr *MockStateInfoMockRecorder) GeneratingBalances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratingBalances", reflect.TypeOf((*MockStateInfo)(nil).GeneratingBalances), arg0, arg1)
+}
+
 // Header mocks base method.
 func (m *MockStateInfo) Header(blockID proto.BlockID) (*proto.BlockHeader, error) {
 	m.ctrl.T.Helper()
@@ -628,6 +856,50 @@ func (mr *MockStateInfoMockRecorder) IsAssetExist(assetID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAssetExist", reflect.TypeOf((*MockStateInfo)(nil).IsAssetExist), assetID)
 }
 
+// IterateDataEntries mocks base method.
+func (m *MockStateInfo) IterateDataEntries(arg0 proto.WavesAddress, arg1 func(proto.DataEntry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateDataEntries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateDataEntries indicates an expected call of IterateDataEntries.
+func (mr *MockStateInfoMockRecorder) IterateDataEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateDataEntries", reflect.TypeOf((*MockStateInfo)(nil).IterateDataEntries), arg0, arg1)
+}
+
+// IterateStoredScripts mocks base method.
+func (m *MockStateInfo) IterateStoredScripts(ctx context.Context, fn func(state.StoredScript) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateStoredScripts", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateStoredScripts indicates an expected call of IterateStoredScripts.
+func (mr *MockStateInfoMockRecorder) IterateStoredScripts(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateStoredScripts", reflect.TypeOf((*MockStateInfo)(nil).IterateStoredScripts), ctx, fn)
+}
+
+// LeaseTotals mocks base method.
+func (m *MockStateInfo) LeaseTotals(arg0 proto.WavesAddress, arg1 uint64) (uint64, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseTotals", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LeaseTotals indicates an expected call of LeaseTotals.
+func (mr *MockStateInfoMockRecorder) LeaseTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseTotals", reflect.TypeOf((*MockStateInfo)(nil).LeaseTotals), arg0, arg1)
+}
+
 // LegacyStateHashAtHeight mocks base method.
 func (m *MockStateInfo) LegacyStateHashAtHeight(height proto.Height) (*proto.StateHash, error) {
 	m.ctrl.T.Helper()
@@ -688,6 +960,21 @@ func (mr *MockStateInfoMockRecorder) NewAddrTransactionsIterator(addr interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAddrTransactionsIterator", reflect.TypeOf((*MockStateInfo)(nil).NewAddrTransactionsIterator), addr)
 }
 
+// NewestAccountIsDApp mocks base method.
+func (m *MockStateInfo) NewestAccountIsDApp(addr proto.WavesAddress) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewestAccountIsDApp", addr)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewestAccountIsDApp indicates an expected call of NewestAccountIsDApp.
+func (mr *MockStateInfoMockRecorder) NewestAccountIsDApp(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewestAccountIsDApp", reflect.TypeOf((*MockStateInfo)(nil).NewestAccountIsDApp), addr)
+}
+
 // NewestScriptByAccount mocks base method.
 func (m *MockStateInfo) NewestScriptByAccount(account proto.Recipient) (*ast.Tree, error) {
 	m.ctrl.T.Helper()
@@ -718,6 +1005,21 @@ func (mr *MockStateInfoMockRecorder) NewestScriptBytesByAccount(account interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewestScriptBytesByAccount", reflect.TypeOf((*MockStateInfo)(nil).NewestScriptBytesByAccount), account)
 }
 
+// PendingFeatures mocks base method.
+func (m *MockStateInfo) PendingFeatures(height proto.Height) ([]state.FeatureVoteStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingFeatures", height)
+	ret0, _ := ret[0].([]state.FeatureVoteStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingFeatures indicates an expected call of PendingFeatures.
+func (mr *MockStateInfoMockRecorder) PendingFeatures(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingFeatures", reflect.TypeOf((*MockStateInfo)(nil).PendingFeatures), height)
+}
+
 // ProvidesExtendedApi mocks base method.
 func (m *MockStateInfo) ProvidesExtendedApi() (bool, error) {
 	m.ctrl.T.Helper()
@@ -973,6 +1275,36 @@ func (mr *MockStateInfoMockRecorder) SnapshotsAtHeight(height interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotsAtHeight", reflect.TypeOf((*MockStateInfo)(nil).SnapshotsAtHeight), height)
 }
 
+// StateAt mocks base method.
+func (m *MockStateInfo) StateAt(height proto.Height) (state.ReadOnlyState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateAt", height)
+	ret0, _ := ret[0].(state.ReadOnlyState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateAt indicates an expected call of StateAt.
+func (mr *MockStateInfoMockRecorder) StateAt(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateAt", reflect.TypeOf((*MockStateInfo)(nil).StateAt), height)
+}
+
+// StateDelta mocks base method.
+func (m *MockStateInfo) StateDelta(from proto.Height, to proto.Height) ([]state.BlockSnapshots, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateDelta", from, to)
+	ret0, _ := ret[0].([]state.BlockSnapshots)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateDelta indicates an expected call of StateDelta.
+func (mr *MockStateInfoMockRecorder) StateDelta(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateDelta", reflect.TypeOf((*MockStateInfo)(nil).StateDelta), from, to)
+}
+
 // TopBlock mocks base method.
 func (m *MockStateInfo) TopBlock() *proto.Block {
 	m.ctrl.T.Helper()
@@ -1078,6 +1410,20 @@ func (mr *MockStateInfoMockRecorder) VotesNumAtHeight(featureID, height interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VotesNumAtHeight", reflect.TypeOf((*MockStateInfo)(nil).VotesNumAtHeight), featureID, height)
 }
 
+// WarmCaches mocks base method.
+func (m *MockStateInfo) WarmCaches(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmCaches", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WarmCaches indicates an expected call of WarmCaches.
+func (mr *MockStateInfoMockRecorder) WarmCaches(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmCaches", reflect.TypeOf((*MockStateInfo)(nil).WarmCaches), ctx)
+}
+
 // WavesAddressesNumber mocks base method.
 func (m *MockStateInfo) WavesAddressesNumber() (uint64, error) {
 	m.ctrl.T.Helper()
@@ -1219,6 +1565,20 @@ func (mr *MockStateModifierMockRecorder) AddDeserializedBlocksWithSnapshots(bloc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeserializedBlocksWithSnapshots", reflect.TypeOf((*MockStateModifier)(nil).AddDeserializedBlocksWithSnapshots), blocks, snapshots)
 }
 
+// ApplyStateDelta mocks base method.
+func (m *MockStateModifier) ApplyStateDelta(delta []state.BlockSnapshots) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyStateDelta", delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyStateDelta indicates an expected call of ApplyStateDelta.
+func (mr *MockStateModifierMockRecorder) ApplyStateDelta(delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStateDelta", reflect.TypeOf((*MockStateModifier)(nil).ApplyStateDelta), delta)
+}
+
 // Close mocks base method.
 func (m *MockStateModifier) Close() error {
 	m.ctrl.T.Helper()
@@ -1261,6 +1621,35 @@ func (mr *MockStateModifierMockRecorder) PersistAddressTransactions() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PersistAddressTransactions", reflect.TypeOf((*MockStateModifier)(nil).PersistAddressTransactions))
 }
 
+// ReestimateAllScripts mocks base method.
+func (m *MockStateModifier) ReestimateAllScripts(arg0 context.Context, arg1 int, arg2 func(int, int)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReestimateAllScripts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReestimateAllScripts indicates an expected call of ReestimateAllScripts.
+func (mr *MockStateModifierMockRecorder) ReestimateAllScripts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReestimateAllScripts", reflect.TypeOf((*MockStateModifier)(nil).ReestimateAllScripts), arg0, arg1, arg2)
+}
+
+// ReestimateScripts mocks base method.
+func (m *MockStateModifier) ReestimateScripts(arg0 context.Context, arg1 int, arg2 []byte, arg3 int) (state.ScriptsReestimation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReestimateScripts", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(state.ScriptsReestimation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReestimateScripts indicates an expected call of ReestimateScripts.
+func (mr *MockStateModifierMockRecorder) ReestimateScripts(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReestimateScripts", reflect.TypeOf((*MockStateModifier)(nil).ReestimateScripts), arg0, arg1, arg2, arg3)
+}
+
 // ResetValidationList mocks base method.
 func (m *MockStateModifier) ResetValidationList() {
 	m.ctrl.T.Helper()
@@ -1301,6 +1690,36 @@ func (mr *MockStateModifierMockRecorder) RollbackToHeight(height interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackToHeight", reflect.TypeOf((*MockStateModifier)(nil).RollbackToHeight), height)
 }
 
+// SelectNonConflicting mocks base method.
+func (m *MockStateModifier) SelectNonConflicting(arg0 []proto.Transaction, arg1 uint64) ([]proto.Transaction, []proto.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectNonConflicting", arg0, arg1)
+	ret0, _ := ret[0].([]proto.Transaction)
+	ret1, _ := ret[1].([]proto.Transaction)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectNonConflicting indicates an expected call of SelectNonConflicting.
+func (mr *MockStateModifierMockRecorder) SelectNonConflicting(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectNonConflicting", reflect.TypeOf((*MockStateModifier)(nil).SelectNonConflicting), arg0, arg1)
+}
+
+// SetFeatureActivationHeight mocks base method.
+func (m *MockStateModifier) SetFeatureActivationHeight(arg0 int16, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFeatureActivationHeight", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFeatureActivationHeight indicates an expected call of SetFeatureActivationHeight.
+func (mr *MockStateModifierMockRecorder) SetFeatureActivationHeight(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeatureActivationHeight", reflect.TypeOf((*MockStateModifier)(nil).SetFeatureActivationHeight), arg0, arg1)
+}
+
 // StartProvidingExtendedApi mocks base method.
 func (m *MockStateModifier) StartProvidingExtendedApi() error {
 	m.ctrl.T.Helper()
@@ -1405,6 +1824,21 @@ func (m *MockState) EXPECT() *MockStateMockRecorder {
 	return m.recorder
 }
 
+// AccountStateSize mocks base method.
+func (m *MockState) AccountStateSize(arg0 proto.WavesAddress) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountStateSize", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountStateSize indicates an expected call of AccountStateSize.
+func (mr *MockStateMockRecorder) AccountStateSize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountStateSize", reflect.TypeOf((*MockState)(nil).AccountStateSize), arg0)
+}
+
 // ActivationHeight mocks base method.
 func (m *MockState) ActivationHeight(featureID int16) (proto.Height, error) {
 	m.ctrl.T.Helper()
@@ -1523,6 +1957,36 @@ func (mr *MockStateMockRecorder) AddrByAlias(alias interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddrByAlias", reflect.TypeOf((*MockState)(nil).AddrByAlias), alias)
 }
 
+// AddressByAliasAt mocks base method.
+func (m *MockState) AddressByAliasAt(alias proto.Alias, height proto.Height) (proto.WavesAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddressByAliasAt", alias, height)
+	ret0, _ := ret[0].(proto.WavesAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddressByAliasAt indicates an expected call of AddressByAliasAt.
+func (mr *MockStateMockRecorder) AddressByAliasAt(alias, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddressByAliasAt", reflect.TypeOf((*MockState)(nil).AddressByAliasAt), alias, height)
+}
+
+// AddressTransactions mocks base method.
+func (m *MockState) AddressTransactions(arg0 proto.WavesAddress, arg1 int, arg2 *crypto.Digest) ([]proto.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddressTransactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]proto.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddressTransactions indicates an expected call of AddressTransactions.
+func (mr *MockStateMockRecorder) AddressTransactions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddressTransactions", reflect.TypeOf((*MockState)(nil).AddressTransactions), arg0, arg1, arg2)
+}
+
 // AliasesByAddr mocks base method.
 func (m *MockState) AliasesByAddr(addr proto.WavesAddress) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -1538,6 +2002,21 @@ func (mr *MockStateMockRecorder) AliasesByAddr(addr interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AliasesByAddr", reflect.TypeOf((*MockState)(nil).AliasesByAddr), addr)
 }
 
+// AllDataEntries mocks base method.
+func (m *MockState) AllDataEntries(arg0 proto.WavesAddress) ([]proto.DataEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllDataEntries", arg0)
+	ret0, _ := ret[0].([]proto.DataEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllDataEntries indicates an expected call of AllDataEntries.
+func (mr *MockStateMockRecorder) AllDataEntries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllDataEntries", reflect.TypeOf((*MockState)(nil).AllDataEntries), arg0)
+}
+
 // AllFeatures mocks base method.
 func (m *MockState) AllFeatures() ([]int16, error) {
 	m.ctrl.T.Helper()
@@ -1553,6 +2032,20 @@ func (mr *MockStateMockRecorder) AllFeatures() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllFeatures", reflect.TypeOf((*MockState)(nil).AllFeatures))
 }
 
+// ApplyStateDelta mocks base method.
+func (m *MockState) ApplyStateDelta(delta []state.BlockSnapshots) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyStateDelta", delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyStateDelta indicates an expected call of ApplyStateDelta.
+func (mr *MockStateMockRecorder) ApplyStateDelta(delta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStateDelta", reflect.TypeOf((*MockState)(nil).ApplyStateDelta), delta)
+}
+
 // ApprovalHeight mocks base method.
 func (m *MockState) ApprovalHeight(featureID int16) (proto.Height, error) {
 	m.ctrl.T.Helper()
@@ -1613,6 +2106,37 @@ func (mr *MockStateMockRecorder) AssetIsSponsored(assetID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetIsSponsored", reflect.TypeOf((*MockState)(nil).AssetIsSponsored), assetID)
 }
 
+// AssetSupply mocks base method.
+func (m *MockState) AssetSupply(arg0 proto.AssetID) (uint64, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssetSupply", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AssetSupply indicates an expected call of AssetSupply.
+func (mr *MockStateMockRecorder) AssetSupply(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetSupply", reflect.TypeOf((*MockState)(nil).AssetSupply), arg0)
+}
+
+// AssetsIssuedBy mocks base method.
+func (m *MockState) AssetsIssuedBy(addr proto.WavesAddress) ([]proto.AssetID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssetsIssuedBy", addr)
+	ret0, _ := ret[0].([]proto.AssetID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssetsIssuedBy indicates an expected call of AssetsIssuedBy.
+func (mr *MockStateMockRecorder) AssetsIssuedBy(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetsIssuedBy", reflect.TypeOf((*MockState)(nil).AssetsIssuedBy), addr)
+}
+
 // Block mocks base method.
 func (m *MockState) Block(blockID proto.BlockID) (*proto.Block, error) {
 	m.ctrl.T.Helper()
@@ -1643,6 +2167,68 @@ func (mr *MockStateMockRecorder) BlockByHeight(height interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockByHeight", reflect.TypeOf((*MockState)(nil).BlockByHeight), height)
 }
 
+// BlockBalanceChanges mocks base method.
+func (m *MockState) BlockBalanceChanges(blockID proto.BlockID, assetID *proto.AssetID) (map[proto.WavesAddress][2]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockBalanceChanges", blockID, assetID)
+	ret0, _ := ret[0].(map[proto.WavesAddress][2]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockBalanceChanges indicates an expected call of BlockBalanceChanges.
+func (mr *MockStateMockRecorder) BlockBalanceChanges(blockID, assetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockBalanceChanges", reflect.TypeOf((*MockState)(nil).BlockBalanceChanges), blockID, assetID)
+}
+
+// BlockDataChanges mocks base method.
+func (m *MockState) BlockDataChanges(blockID proto.BlockID) (map[proto.WavesAddress][]proto.DataEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockDataChanges", blockID)
+	ret0, _ := ret[0].(map[proto.WavesAddress][]proto.DataEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockDataChanges indicates an expected call of BlockDataChanges.
+func (mr *MockStateMockRecorder) BlockDataChanges(blockID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockDataChanges", reflect.TypeOf((*MockState)(nil).BlockDataChanges), blockID)
+}
+
+// BlockGenerationSignature mocks base method.
+func (m *MockState) BlockGenerationSignature(blockID proto.BlockID) ([]byte, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockGenerationSignature", blockID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BlockGenerationSignature indicates an expected call of BlockGenerationSignature.
+func (mr *MockStateMockRecorder) BlockGenerationSignature(blockID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockGenerationSignature", reflect.TypeOf((*MockState)(nil).BlockGenerationSignature), blockID)
+}
+
+// BlockHeaderWithTxCount mocks base method.
+func (m *MockState) BlockHeaderWithTxCount(height proto.Height) (*proto.BlockHeader, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockHeaderWithTxCount", height)
+	ret0, _ := ret[0].(*proto.BlockHeader)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BlockHeaderWithTxCount indicates an expected call of BlockHeaderWithTxCount.
+func (mr *MockStateMockRecorder) BlockHeaderWithTxCount(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHeaderWithTxCount", reflect.TypeOf((*MockState)(nil).BlockHeaderWithTxCount), height)
+}
+
 // BlockIDToHeight mocks base method.
 func (m *MockState) BlockIDToHeight(blockID proto.BlockID) (proto.Height, error) {
 	m.ctrl.T.Helper()
@@ -1673,6 +2259,21 @@ func (mr *MockStateMockRecorder) BlockRewards(generator, height interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockRewards", reflect.TypeOf((*MockState)(nil).BlockRewards), generator, height)
 }
 
+// BlockSnapshot mocks base method.
+func (m *MockState) BlockSnapshot(arg0 proto.BlockID) (*proto.BlockSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSnapshot", arg0)
+	ret0, _ := ret[0].(*proto.BlockSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSnapshot indicates an expected call of BlockSnapshot.
+func (mr *MockStateMockRecorder) BlockSnapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSnapshot", reflect.TypeOf((*MockState)(nil).BlockSnapshot), arg0)
+}
+
 // BlockVRF mocks base method.
 func (m *MockState) BlockVRF(blockHeader *proto.BlockHeader, blockHeight proto.Height) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -1777,6 +2378,49 @@ func (mr *MockStateMockRecorder) EstimatorVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimatorVersion", reflect.TypeOf((*MockState)(nil).EstimatorVersion))
 }
 
+// EthereumCall mocks base method.
+func (m *MockState) EthereumCall(from proto.EthereumAddress, to proto.EthereumAddress, data []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EthereumCall", from, to, data)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EthereumCall indicates an expected call of EthereumCall.
+func (mr *MockStateMockRecorder) EthereumCall(from, to, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthereumCall", reflect.TypeOf((*MockState)(nil).EthereumCall), from, to, data)
+}
+
+// ExportBalancesCSV mocks base method.
+func (m *MockState) ExportBalancesCSV(arg0 io.Writer, arg1 *proto.AssetID, arg2 proto.Height) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBalancesCSV", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBalancesCSV indicates an expected call of ExportBalancesCSV.
+func (mr *MockStateMockRecorder) ExportBalancesCSV(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBalancesCSV", reflect.TypeOf((*MockState)(nil).ExportBalancesCSV), arg0, arg1, arg2)
+}
+
+// ExportStateSnapshot mocks base method.
+func (m *MockState) ExportStateSnapshot(w io.Writer, height proto.Height) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStateSnapshot", w, height)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportStateSnapshot indicates an expected call of ExportStateSnapshot.
+func (mr *MockStateMockRecorder) ExportStateSnapshot(w, height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStateSnapshot", reflect.TypeOf((*MockState)(nil).ExportStateSnapshot), w, height)
+}
+
 // FullAssetInfo mocks base method.
 func (m *MockState) FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error) {
 	m.ctrl.T.Helper()
@@ -1822,6 +2466,21 @@ func (mr *MockStateMockRecorder) GeneratingBalance(account, height interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratingBalance", reflect.TypeOf((*MockState)(nil).GeneratingBalance), account, height)
 }
 
+// GeneratingBalances mocks base method.
+func (m *MockState) GeneratingBalances(arg0 proto.Height, arg1 []proto.WavesAddress) (map[proto.WavesAddress]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GeneratingBalances", arg0, arg1)
+	ret0, _ := ret[0].(map[proto.WavesAddress]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GeneratingBalances indicates an expected call of GeneratingBalances.
+func (mr *MockStateMockRecorder) GeneratingBalances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratingBalances", reflect.TypeOf((*MockState)(nil).GeneratingBalances), arg0, arg1)
+}
+
 // Header mocks base method.
 func (m *MockState) Header(blockID proto.BlockID) (*proto.BlockHeader, error) {
 	m.ctrl.T.Helper()
@@ -2017,6 +2676,50 @@ func (mr *MockStateMockRecorder) IsAssetExist(assetID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAssetExist", reflect.TypeOf((*MockState)(nil).IsAssetExist), assetID)
 }
 
+// IterateDataEntries mocks base method.
+func (m *MockState) IterateDataEntries(arg0 proto.WavesAddress, arg1 func(proto.DataEntry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateDataEntries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateDataEntries indicates an expected call of IterateDataEntries.
+func (mr *MockStateMockRecorder) IterateDataEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateDataEntries", reflect.TypeOf((*MockState)(nil).IterateDataEntries), arg0, arg1)
+}
+
+// IterateStoredScripts mocks base method.
+func (m *MockState) IterateStoredScripts(ctx context.Context, fn func(state.StoredScript) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateStoredScripts", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateStoredScripts indicates an expected call of IterateStoredScripts.
+func (mr *MockStateMockRecorder) IterateStoredScripts(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateStoredScripts", reflect.TypeOf((*MockState)(nil).IterateStoredScripts), ctx, fn)
+}
+
+// LeaseTotals mocks base method.
+func (m *MockState) LeaseTotals(arg0 proto.WavesAddress, arg1 uint64) (uint64, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseTotals", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LeaseTotals indicates an expected call of LeaseTotals.
+func (mr *MockStateMockRecorder) LeaseTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseTotals", reflect.TypeOf((*MockState)(nil).LeaseTotals), arg0, arg1)
+}
+
 // LegacyStateHashAtHeight mocks base method.
 func (m *MockState) LegacyStateHashAtHeight(height proto.Height) (*proto.StateHash, error) {
 	m.ctrl.T.Helper()
@@ -2091,6 +2794,21 @@ func (mr *MockStateMockRecorder) NewAddrTransactionsIterator(addr interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewAddrTransactionsIterator", reflect.TypeOf((*MockState)(nil).NewAddrTransactionsIterator), addr)
 }
 
+// NewestAccountIsDApp mocks base method.
+func (m *MockState) NewestAccountIsDApp(addr proto.WavesAddress) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewestAccountIsDApp", addr)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewestAccountIsDApp indicates an expected call of NewestAccountIsDApp.
+func (mr *MockStateMockRecorder) NewestAccountIsDApp(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewestAccountIsDApp", reflect.TypeOf((*MockState)(nil).NewestAccountIsDApp), addr)
+}
+
 // NewestScriptByAccount mocks base method.
 func (m *MockState) NewestScriptByAccount(account proto.Recipient) (*ast.Tree, error) {
 	m.ctrl.T.Helper()
@@ -2121,6 +2839,21 @@ func (mr *MockStateMockRecorder) NewestScriptBytesByAccount(account interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewestScriptBytesByAccount", reflect.TypeOf((*MockState)(nil).NewestScriptBytesByAccount), account)
 }
 
+// PendingFeatures mocks base method.
+func (m *MockState) PendingFeatures(height proto.Height) ([]state.FeatureVoteStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingFeatures", height)
+	ret0, _ := ret[0].([]state.FeatureVoteStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingFeatures indicates an expected call of PendingFeatures.
+func (mr *MockStateMockRecorder) PendingFeatures(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingFeatures", reflect.TypeOf((*MockState)(nil).PendingFeatures), height)
+}
+
 // PersistAddressTransactions mocks base method.
 func (m *MockState) PersistAddressTransactions() error {
 	m.ctrl.T.Helper()
@@ -2165,6 +2898,35 @@ func (mr *MockStateMockRecorder) ProvidesStateHashes() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvidesStateHashes", reflect.TypeOf((*MockState)(nil).ProvidesStateHashes))
 }
 
+// ReestimateAllScripts mocks base method.
+func (m *MockState) ReestimateAllScripts(arg0 context.Context, arg1 int, arg2 func(int, int)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReestimateAllScripts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReestimateAllScripts indicates an expected call of ReestimateAllScripts.
+func (mr *MockStateMockRecorder) ReestimateAllScripts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReestimateAllScripts", reflect.TypeOf((*MockState)(nil).ReestimateAllScripts), arg0, arg1, arg2)
+}
+
+// ReestimateScripts mocks base method.
+func (m *MockState) ReestimateScripts(arg0 context.Context, arg1 int, arg2 []byte, arg3 int) (state.ScriptsReestimation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReestimateScripts", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(state.ScriptsReestimation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReestimateScripts indicates an expected call of ReestimateScripts.
+func (mr *MockStateMockRecorder) ReestimateScripts(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReestimateScripts", reflect.TypeOf((*MockState)(nil).ReestimateScripts), arg0, arg1, arg2, arg3)
+}
+
 // ResetValidationList mocks base method.
 func (m *MockState) ResetValidationList() {
 	m.ctrl.T.Helper()
@@ -2385,6 +3147,36 @@ func (mr *MockStateMockRecorder) ScriptInfoByAsset(assetID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScriptInfoByAsset", reflect.TypeOf((*MockState)(nil).ScriptInfoByAsset), assetID)
 }
 
+// SelectNonConflicting mocks base method.
+func (m *MockState) SelectNonConflicting(arg0 []proto.Transaction, arg1 uint64) ([]proto.Transaction, []proto.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectNonConflicting", arg0, arg1)
+	ret0, _ := ret[0].([]proto.Transaction)
+	ret1, _ := ret[1].([]proto.Transaction)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectNonConflicting indicates an expected call of SelectNonConflicting.
+func (mr *MockStateMockRecorder) SelectNonConflicting(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectNonConflicting", reflect.TypeOf((*MockState)(nil).SelectNonConflicting), arg0, arg1)
+}
+
+// SetFeatureActivationHeight mocks base method.
+func (m *MockState) SetFeatureActivationHeight(arg0 int16, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFeatureActivationHeight", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFeatureActivationHeight indicates an expected call of SetFeatureActivationHeight.
+func (mr *MockStateMockRecorder) SetFeatureActivationHeight(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeatureActivationHeight", reflect.TypeOf((*MockState)(nil).SetFeatureActivationHeight), arg0, arg1)
+}
+
 // ShouldPersistAddressTransactions mocks base method.
 func (m *MockState) ShouldPersistAddressTransactions() (bool, error) {
 	m.ctrl.T.Helper()
@@ -2444,6 +3236,36 @@ func (mr *MockStateMockRecorder) StartProvidingExtendedApi() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartProvidingExtendedApi", reflect.TypeOf((*MockState)(nil).StartProvidingExtendedApi))
 }
 
+// StateAt mocks base method.
+func (m *MockState) StateAt(height proto.Height) (state.ReadOnlyState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateAt", height)
+	ret0, _ := ret[0].(state.ReadOnlyState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateAt indicates an expected call of StateAt.
+func (mr *MockStateMockRecorder) StateAt(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateAt", reflect.TypeOf((*MockState)(nil).StateAt), height)
+}
+
+// StateDelta mocks base method.
+func (m *MockState) StateDelta(from proto.Height, to proto.Height) ([]state.BlockSnapshots, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateDelta", from, to)
+	ret0, _ := ret[0].([]state.BlockSnapshots)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateDelta indicates an expected call of StateDelta.
+func (mr *MockStateMockRecorder) StateDelta(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateDelta", reflect.TypeOf((*MockState)(nil).StateDelta), from, to)
+}
+
 // TopBlock mocks base method.
 func (m *MockState) TopBlock() *proto.Block {
 	m.ctrl.T.Helper()
@@ -2578,6 +3400,20 @@ func (mr *MockStateMockRecorder) VotesNumAtHeight(featureID, height interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VotesNumAtHeight", reflect.TypeOf((*MockState)(nil).VotesNumAtHeight), featureID, height)
 }
 
+// WarmCaches mocks base method.
+func (m *MockState) WarmCaches(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WarmCaches", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WarmCaches indicates an expected call of WarmCaches.
+func (mr *MockStateMockRecorder) WarmCaches(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarmCaches", reflect.TypeOf((*MockState)(nil).WarmCaches), ctx)
+}
+
 // WavesAddressesNumber mocks base method.
 func (m *MockState) WavesAddressesNumber() (uint64, error) {
 	m.ctrl.T.Helper()
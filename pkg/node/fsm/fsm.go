@@ -72,7 +72,8 @@ type BaseInfo struct {
 
 	actions Actions
 
-	utx types.UtxPool
+	utx          types.UtxPool
+	txValidation *proto.ValidationPipeline
 
 	minPeersMining int
 
@@ -154,6 +155,10 @@ func NewFSM(
 	if microblockInterval <= 0 {
 		return nil, nil, errors.New("microblock interval must be positive")
 	}
+	txValidation := services.TxValidation
+	if txValidation == nil {
+		txValidation = proto.DefaultValidationPipeline()
+	}
 	info := BaseInfo{
 		peers:        services.Peers,
 		storage:      services.State,
@@ -175,7 +180,8 @@ func NewFSM(
 
 		actions: &ActionsImpl{services: services},
 
-		utx: services.UtxPool,
+		utx:          services.UtxPool,
+		txValidation: txValidation,
 
 		minPeersMining: services.MinPeersMining,
 
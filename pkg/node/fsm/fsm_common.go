@@ -139,7 +139,7 @@ func tryBroadcastTransaction(
 		return fsm, nil, errors.Wrap(err, "failed to check if LightNode feature is activated")
 	}
 	params := proto.TransactionValidationParams{Scheme: baseInfo.scheme, CheckVersion: lightNodeActivated}
-	if _, err = t.Validate(params); err != nil {
+	if _, err = baseInfo.txValidation.Validate(t, params); err != nil {
 		err = errors.Wrap(err, "failed to validate transaction")
 		if p != nil {
 			baseInfo.peers.AddToBlackList(p, time.Now(), err.Error())
@@ -840,18 +840,28 @@ func (b *Block) transactionsRoot(scheme Scheme) ([]byte, error) {
 	if b.Version < ProtobufBlockVersion {
 		return nil, errors.Errorf("no transactions root prior block version %d, current version %d", ProtobufBlockVersion, b.Version)
 	}
-	tree, err := crypto.NewMerkleTree()
+	root, err := ComputeTransactionMerkleRoot(b.Transactions, scheme)
 	if err != nil {
 		return nil, err
 	}
-	for _, tx := range b.Transactions {
+	return root.Bytes(), nil
+}
+
+// ComputeTransactionMerkleRoot builds the merkle tree of transactions from their MerkleBytes representation and
+// returns the root of the tree. The root is the same as the transactions root of the block with these transactions.
+func ComputeTransactionMerkleRoot(txs []Transaction, scheme Scheme) (crypto.Digest, error) {
+	tree, err := crypto.NewMerkleTree()
+	if err != nil {
+		return crypto.Digest{}, err
+	}
+	for i, tx := range txs {
 		b, err := tx.MerkleBytes(scheme)
 		if err != nil {
-			return nil, err
+			return crypto.Digest{}, errors.Wrapf(err, "failed to get merkle bytes of transaction %d", i)
 		}
 		tree.Push(b)
 	}
-	return tree.Root().Bytes(), nil
+	return tree.Root(), nil
 }
 
 func CreateBlock(
@@ -0,0 +1,161 @@
+package proto
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+// TransactionSignatureError is an error of signature verification of the transaction at the given index of a block.
+type TransactionSignatureError struct {
+	Index int
+	Err   error
+}
+
+func (e TransactionSignatureError) Error() string {
+	return fmt.Sprintf("transaction #%d: %v", e.Index, e.Err)
+}
+
+func (e TransactionSignatureError) Unwrap() error {
+	return e.Err
+}
+
+// ScriptedAccountFunc reports whether the account with the given address has a verifier script.
+type ScriptedAccountFunc func(addr WavesAddress) (bool, error)
+
+type signedBySender interface {
+	GetSenderPK() crypto.PublicKey
+	Verify(scheme Scheme, publicKey crypto.PublicKey) (bool, error)
+}
+
+// VerifyBlockTransactionSignatures verifies signatures of all block's transactions in parallel.
+// Ethereum transactions are checked by recovering the sender's public key, other transactions are verified
+// against the sender's public key. Orders of Exchange transactions are verified too.
+// Proofs of transactions and orders sent from smart accounts are not necessarily signatures, so such
+// transactions and orders are skipped if isScripted reports that the sender has a script. If isScripted is nil,
+// signatures are required for all transactions.
+// The error of type TransactionSignatureError for the transaction with the lowest index is returned on failure.
+func VerifyBlockTransactionSignatures(block *Block, scheme Scheme, isScripted ScriptedAccountFunc) error {
+	txs := block.Transactions
+	workers := runtime.NumCPU()
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	var (
+		failed  atomic.Bool
+		txErrs  = make([]error, len(txs))
+		indexes = make(chan int)
+		wg      = new(sync.WaitGroup)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := verifyTransactionSignature(txs[i], scheme, isScripted); err != nil {
+					txErrs[i] = err
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	for i := range txs {
+		if failed.Load() { // no need to verify the rest of transactions
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range txErrs {
+		if err != nil {
+			return TransactionSignatureError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+func verifyTransactionSignature(tx Transaction, scheme Scheme, isScripted ScriptedAccountFunc) error {
+	switch t := tx.(type) {
+	case *Genesis:
+		return nil
+	case *EthereumTransaction:
+		if _, err := t.Verify(); err != nil {
+			return errors.Wrap(err, "EthereumTransaction signature verification failed")
+		}
+		return nil
+	case Exchange:
+		if err := verifySenderSignature(t, tx.GetType(), scheme, isScripted); err != nil {
+			return err
+		}
+		if err := verifyOrderSignature(t.GetOrder1(), scheme, isScripted); err != nil {
+			return errors.Wrap(err, "first Order")
+		}
+		if err := verifyOrderSignature(t.GetOrder2(), scheme, isScripted); err != nil {
+			return errors.Wrap(err, "second Order")
+		}
+		return nil
+	case signedBySender:
+		return verifySenderSignature(t, tx.GetType(), scheme, isScripted)
+	default:
+		return errors.Errorf("unsupported transaction type %T", tx)
+	}
+}
+
+func verifySenderSignature(tx signedBySender, txType TransactionType, scheme Scheme, isScripted ScriptedAccountFunc) error {
+	if isScripted != nil {
+		addr, err := NewAddressFromPublicKey(scheme, tx.GetSenderPK())
+		if err != nil {
+			return errors.Wrapf(err, "%s sender address", txType.String())
+		}
+		scripted, err := isScripted(addr)
+		if err != nil {
+			return errors.Wrapf(err, "%s sender script check failed", txType.String())
+		}
+		if scripted {
+			return nil
+		}
+	}
+	ok, err := tx.Verify(scheme, tx.GetSenderPK())
+	if err != nil {
+		return errors.Wrapf(err, "%s signature verification failed", txType.String())
+	}
+	if !ok {
+		return errors.Errorf("%s signature verification failed", txType.String())
+	}
+	return nil
+}
+
+func verifyOrderSignature(o Order, scheme Scheme, isScripted ScriptedAccountFunc) error {
+	if isScripted != nil {
+		sender, err := o.GetSender(scheme)
+		if err != nil {
+			return errors.Wrap(err, "sender address")
+		}
+		addr, err := sender.ToWavesAddress(scheme)
+		if err != nil {
+			return errors.Wrap(err, "sender address")
+		}
+		scripted, err := isScripted(addr)
+		if err != nil {
+			return errors.Wrap(err, "sender script check failed")
+		}
+		if scripted {
+			return nil
+		}
+	}
+	ok, err := o.Verify(scheme)
+	if err != nil {
+		return errors.Wrap(err, "signature verification failed")
+	}
+	if !ok {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package proto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+func signedTestTransfers(t testing.TB, n int) []Transaction {
+	sk, pk, err := crypto.GenerateKeyPair([]byte("block signatures"))
+	require.NoError(t, err)
+	addr, err := NewAddressFromPublicKey(TestNetScheme, pk)
+	require.NoError(t, err)
+	rcp := NewRecipientFromAddress(addr)
+	waves := NewOptionalAssetWaves()
+	txs := make([]Transaction, n)
+	for i := range txs {
+		var tx Transaction
+		if i%2 == 0 {
+			tx = NewUnsignedTransferWithSig(pk, waves, waves, uint64(1000+i), 1, 100000, rcp, nil)
+		} else {
+			tx = NewUnsignedTransferWithProofs(3, pk, waves, waves, uint64(1000+i), 1, 100000, rcp, nil)
+		}
+		require.NoError(t, tx.Sign(TestNetScheme, sk))
+		txs[i] = tx
+	}
+	return txs
+}
+
+func TestVerifyBlockTransactionSignatures(t *testing.T) {
+	ethData, err := DecodeFromHexString(testStageNetEthTxHex)
+	require.NoError(t, err)
+	ethTx := new(EthereumTransaction)
+	require.NoError(t, ethTx.DecodeCanonical(ethData))
+
+	txs := signedTestTransfers(t, 4)
+	txs = append(txs, ethTx, &Genesis{})
+	block := &Block{Transactions: txs}
+	require.NoError(t, VerifyBlockTransactionSignatures(block, TestNetScheme, nil))
+
+	// Block without transactions is valid.
+	require.NoError(t, VerifyBlockTransactionSignatures(&Block{}, TestNetScheme, nil))
+
+	// Tampered transactions are detected, the error of the first one is returned.
+	tampered := signedTestTransfers(t, 4)
+	tampered[3].(*TransferWithProofs).Amount = 2
+	tampered[2].(*TransferWithSig).Amount = 2
+	block = &Block{Transactions: append(tampered, ethTx)}
+	err = VerifyBlockTransactionSignatures(block, TestNetScheme, nil)
+	var sigErr TransactionSignatureError
+	require.True(t, errors.As(err, &sigErr))
+	assert.Equal(t, 2, sigErr.Index)
+	assert.Contains(t, err.Error(), "TransferTransaction signature verification failed")
+}
+
+func TestVerifyBlockTransactionSignaturesScriptedSenders(t *testing.T) {
+	_, pk, err := crypto.GenerateKeyPair([]byte("smart account"))
+	require.NoError(t, err)
+	waves := NewOptionalAssetWaves()
+	rcp := NewRecipientFromAddress(MustAddressFromPublicKey(TestNetScheme, pk))
+	// Proofs of transactions from smart accounts are not signatures.
+	scripted := NewUnsignedTransferWithProofs(3, pk, waves, waves, 1000, 1, 100000, rcp, nil)
+	scripted.Proofs = NewProofs()
+	scripted.Proofs.Proofs = append(scripted.Proofs.Proofs, []byte("not a signature"))
+	txs := signedTestTransfers(t, 2)
+	block := &Block{Transactions: []Transaction{txs[0], scripted, txs[1]}}
+
+	err = VerifyBlockTransactionSignatures(block, TestNetScheme, nil)
+	var sigErr TransactionSignatureError
+	require.True(t, errors.As(err, &sigErr))
+	assert.Equal(t, 1, sigErr.Index)
+
+	scriptedAddr, err := NewAddressFromPublicKey(TestNetScheme, scripted.SenderPK)
+	require.NoError(t, err)
+	isScripted := func(addr WavesAddress) (bool, error) { return addr == scriptedAddr, nil }
+	require.NoError(t, VerifyBlockTransactionSignatures(block, TestNetScheme, isScripted))
+
+	notScripted := func(WavesAddress) (bool, error) { return false, nil }
+	require.Error(t, VerifyBlockTransactionSignatures(block, TestNetScheme, notScripted))
+
+	failing := func(WavesAddress) (bool, error) { return false, errors.New("state failure") }
+	err = VerifyBlockTransactionSignatures(block, TestNetScheme, failing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "state failure")
+}
+
+func BenchmarkVerifyBlockTransactionSignatures(b *testing.B) {
+	const blockSize = 6000 // Approximately a block full of transfer transactions
+	block := &Block{Transactions: signedTestTransfers(b, blockSize)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyBlockTransactionSignatures(block, TestNetScheme, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -8,6 +8,18 @@ import (
 	"github.com/umbracle/fastrlp"
 )
 
+const (
+	// minAccessTupleRLPSize is the size of the RLP encoded access tuple without storage keys:
+	// list header, address with its header and empty list of storage keys.
+	minAccessTupleRLPSize = 1 + 1 + EthereumAddressSize + 1
+	// storageKeyRLPSize is the size of the RLP encoded storage key with its header.
+	storageKeyRLPSize = 1 + EthereumHashSize
+	// maxAccessListTuples is the maximum number of access tuples that fit into the transaction of max size.
+	maxAccessListTuples = maxEthereumTxSize / minAccessTupleRLPSize
+	// maxAccessListStorageKeys is the maximum number of storage keys that fit into the transaction of max size.
+	maxAccessListStorageKeys = maxEthereumTxSize / storageKeyRLPSize
+)
+
 // EthereumAccessList is an EIP-2930 access list.
 type EthereumAccessList []EthereumAccessTuple
 
@@ -75,12 +87,20 @@ func unmarshalAccessListFastRLP(value *fastrlp.Value) (EthereumAccessList, error
 	if len(elems) == 0 {
 		return nil, nil
 	}
+	if len(elems) > maxAccessListTuples {
+		return nil, errors.Errorf("too many access tuples %d, max allowed %d", len(elems), maxAccessListTuples)
+	}
 	hashes := make(EthereumAccessList, 0, len(elems))
+	storageKeysCount := 0
 	for _, elem := range elems {
 		var accessTuple EthereumAccessTuple
 		if err := accessTuple.unmarshalFromFastRLP(elem); err != nil {
 			return nil, errors.Wrap(err, "failed to unmarshal EthereumAccessTuple from fastRLP value")
 		}
+		storageKeysCount += len(accessTuple.StorageKeys)
+		if storageKeysCount > maxAccessListStorageKeys {
+			return nil, errors.Errorf("too many storage keys in access list, max allowed %d", maxAccessListStorageKeys)
+		}
 		hashes = append(hashes, accessTuple)
 	}
 	return hashes, nil
@@ -204,6 +224,9 @@ func (tx *EthereumAccessListTx) marshalToFastRLP(arena *fastrlp.Arena) *fastrlp.
 }
 
 func (tx *EthereumAccessListTx) DecodeRLP(rlpData []byte) error {
+	if len(rlpData) > maxEthereumTxSize {
+		return errors.Errorf("RLP data size %d exceeds max transaction size %d", len(rlpData), maxEthereumTxSize)
+	}
 	parser := fastrlp.Parser{}
 	rlpVal, err := parser.Parse(rlpData)
 	if err != nil {
@@ -216,8 +239,9 @@ func (tx *EthereumAccessListTx) DecodeRLP(rlpData []byte) error {
 }
 
 func (tx *EthereumAccessListTx) EncodeRLP(w io.Writer) error {
-	arena := fastrlp.Arena{}
-	rlpVal := tx.marshalToFastRLP(&arena)
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
+	rlpVal := tx.marshalToFastRLP(arena)
 	rlpData := rlpVal.MarshalTo(nil)
 	if _, err := w.Write(rlpData); err != nil {
 		return err
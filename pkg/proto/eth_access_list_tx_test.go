@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/umbracle/fastrlp"
 )
 
 func TestEthereumAccessListTxCanonical(t *testing.T) {
@@ -57,3 +58,32 @@ func TestEthereumAccessListTxCanonical(t *testing.T) {
 		require.Equal(t, inner, ethTx.inner)
 	})
 }
+
+func TestEthereumAccessListRLPBounds(t *testing.T) {
+	decoders := map[string]func([]byte) error{
+		"access list": func(data []byte) error { return new(EthereumAccessListTx).DecodeRLP(data) },
+		"dynamic fee": func(data []byte) error { return new(EthereumDynamicFeeTx).DecodeRLP(data) },
+	}
+	// RLP list header claiming 2^64-1 bytes of content.
+	enormous := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xc0}
+	oversized := make([]byte, maxEthereumTxSize+1)
+	oversized[0] = 0xc0
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, decode(enormous))
+			require.Error(t, decode(oversized))
+		})
+	}
+
+	unmarshal := func(al EthereumAccessList) error {
+		var arena fastrlp.Arena
+		_, err := unmarshalAccessListFastRLP(marshalAccessListToFastRLP(&arena, al))
+		return err
+	}
+	require.NoError(t, unmarshal(make(EthereumAccessList, maxAccessListTuples)))
+	require.Error(t, unmarshal(make(EthereumAccessList, maxAccessListTuples+1)))
+
+	keys := make([]EthereumHash, maxAccessListStorageKeys/2+1)
+	require.NoError(t, unmarshal(EthereumAccessList{{StorageKeys: keys}}))
+	require.Error(t, unmarshal(EthereumAccessList{{StorageKeys: keys}, {StorageKeys: keys}}))
+}
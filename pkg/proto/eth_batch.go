@@ -0,0 +1,104 @@
+package proto
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// EthereumTxIndexedError is an error of decoding or validation of Ethereum transaction at the given index of a batch.
+type EthereumTxIndexedError struct {
+	Index int
+	Err   error
+}
+
+func (e EthereumTxIndexedError) Error() string {
+	return fmt.Sprintf("ethereum transaction #%d: %v", e.Index, e.Err)
+}
+
+func (e EthereumTxIndexedError) Unwrap() error {
+	return e.Err
+}
+
+// EthereumTxBatchError aggregates errors of all failed transactions of a batch, errors are ordered by transaction index.
+type EthereumTxBatchError struct {
+	Errors []EthereumTxIndexedError
+}
+
+func (e *EthereumTxBatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of ethereum transactions are invalid: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// First returns the error of the transaction with the lowest index.
+func (e *EthereumTxBatchError) First() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[0]
+}
+
+// parallelEthereumDecodingThreshold is the minimal number of Ethereum transactions in a block or a microblock
+// that are decoded in parallel by ProtobufConverter.
+const parallelEthereumDecodingThreshold = 16
+
+// DecodeEthereumTransactions decodes the canonical representations of Ethereum transactions and recovers their
+// senders using the given number of goroutines. Transactions are returned in the order of the input data.
+// Senders recovery failures are not reported, the signatures are verified again during the validation of
+// transactions, which reuses the recovered senders. If any transaction fails to decode, the error of type
+// *EthereumTxBatchError is returned, it contains errors of all failed transactions ordered by their indexes.
+func DecodeEthereumTransactions(canonicalData [][]byte, workers int) ([]*EthereumTransaction, error) {
+	if workers <= 0 {
+		return nil, errors.Errorf("invalid number of workers %d", workers)
+	}
+	txs := make([]*EthereumTransaction, len(canonicalData))
+	txErrs := make([]error, len(canonicalData))
+	indexes := make(chan int)
+	wg := new(sync.WaitGroup)
+	for w := 0; w < workers && w < len(canonicalData); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				txs[i], txErrs[i] = decodeEthereumTransaction(canonicalData[i])
+			}
+		}()
+	}
+	for i := range canonicalData {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var batchErr *EthereumTxBatchError
+	for i, err := range txErrs {
+		if err == nil {
+			continue
+		}
+		if batchErr == nil {
+			batchErr = new(EthereumTxBatchError)
+		}
+		batchErr.Errors = append(batchErr.Errors, EthereumTxIndexedError{Index: i, Err: err})
+	}
+	if batchErr != nil {
+		return txs, batchErr
+	}
+	return txs, nil
+}
+
+func decodeEthereumTransaction(data []byte) (*EthereumTransaction, error) {
+	tx := new(EthereumTransaction)
+	if err := tx.DecodeCanonical(data); err != nil {
+		return nil, errors.Wrap(err, "failed to decode")
+	}
+	if err := tx.GenerateID(0); err != nil { // scheme is not used for Ethereum transactions
+		return nil, errors.Wrap(err, "failed to generate ID")
+	}
+	_, _ = tx.Verify() // Invalid signatures are reported by the validation of the transaction
+	return tx, nil
+}
@@ -0,0 +1,110 @@
+package proto
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	g "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+)
+
+const testStageNetEthTxHex = "0xf9011186017ca38c6ddf8502540be4008307a120942ad9f1f20af320b1bb556a4932bf964ed9b4fb0180b8a43e08c2280000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000057361666473000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000081c9a0b5cd5ec65e933de94eddcf49e88441cb8c332e0c8f40439b7405d09e4300e6b4a07ccb30028aa8da93242bdc734860c8209f6f3b8fd0e40419f54fcb28db1d4a1c"
+
+func TestDecodeEthereumTransactions(t *testing.T) {
+	valid, err := DecodeFromHexString(testStageNetEthTxHex)
+	require.NoError(t, err)
+
+	data := [][]byte{valid, valid, {0xf9, 0x01}, valid, nil, valid}
+	for _, workers := range []int{1, 2, runtime.NumCPU()} {
+		t.Run(strconv.Itoa(workers), func(t *testing.T) {
+			txs, err := DecodeEthereumTransactions(data, workers)
+			require.Error(t, err)
+			var batchErr *EthereumTxBatchError
+			require.True(t, errors.As(err, &batchErr))
+			require.Len(t, batchErr.Errors, 2)
+			assert.Equal(t, 2, batchErr.Errors[0].Index)
+			assert.Equal(t, 4, batchErr.Errors[1].Index)
+			assert.Equal(t, batchErr.Errors[0], batchErr.First())
+			require.Len(t, txs, len(data))
+			for i, tx := range txs {
+				if i == 2 || i == 4 {
+					assert.Nil(t, tx)
+					continue
+				}
+				require.NotNil(t, tx)
+				assert.NotNil(t, tx.threadSafeGetSenderPK(), "sender must be recovered")
+			}
+		})
+	}
+
+	txs, err := DecodeEthereumTransactions([][]byte{valid, valid}, 4)
+	require.NoError(t, err)
+	assert.Len(t, txs, 2)
+
+	_, err = DecodeEthereumTransactions([][]byte{valid}, 0)
+	assert.EqualError(t, err, "invalid number of workers 0")
+}
+
+func TestSignedTransactionsParallelEthereumDecoding(t *testing.T) {
+	valid, err := DecodeFromHexString(testStageNetEthTxHex)
+	require.NoError(t, err)
+	transfer := NewUnsignedTransferWithProofs(3, crypto.PublicKey{}, NewOptionalAssetWaves(),
+		NewOptionalAssetWaves(), 1, 1, 100000, NewRecipientFromAddress(WavesAddress{}), nil)
+	transfer.Proofs = NewProofs()
+	pbTransfer, err := transfer.ToProtobufSigned(StageNetScheme)
+	require.NoError(t, err)
+
+	const n = 2 * parallelEthereumDecodingThreshold
+	pbTxs := make([]*g.SignedTransaction, 0, n+1)
+	for i := 0; i < n; i++ {
+		if i == n/2 {
+			pbTxs = append(pbTxs, pbTransfer)
+		}
+		pbTxs = append(pbTxs, &g.SignedTransaction{
+			Transaction: &g.SignedTransaction_EthereumTransaction{EthereumTransaction: valid},
+		})
+	}
+	c := ProtobufConverter{FallbackChainID: StageNetScheme}
+	txs, err := c.SignedTransactions(pbTxs)
+	require.NoError(t, err)
+	require.Len(t, txs, len(pbTxs))
+	for i, tx := range txs {
+		if i == n/2 {
+			assert.IsType(t, &TransferWithProofs{}, tx)
+			continue
+		}
+		require.IsType(t, &EthereumTransaction{}, tx)
+		assert.NotNil(t, tx.(*EthereumTransaction).threadSafeGetSenderPK())
+	}
+
+	pbTxs[3] = &g.SignedTransaction{Transaction: &g.SignedTransaction_EthereumTransaction{
+		EthereumTransaction: []byte{0xf9, 0x01},
+	}}
+	_, err = c.SignedTransactions(pbTxs)
+	assert.ErrorContains(t, err, "failed to unmarshal ethereum transaction #3")
+}
+
+func BenchmarkDecodeEthereumTransactions(b *testing.B) {
+	valid, err := DecodeFromHexString(testStageNetEthTxHex)
+	require.NoError(b, err)
+	const blockSize = 6000 // Approximately a block full of Ethereum transactions
+	data := make([][]byte, blockSize)
+	for i := range data {
+		data[i] = valid
+	}
+	for _, workers := range []int{1, runtime.NumCPU()} {
+		b.Run(strconv.Itoa(workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeEthereumTransactions(data, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -124,6 +124,9 @@ func (tx *EthereumDynamicFeeTx) marshalToFastRLP(arena *fastrlp.Arena) *fastrlp.
 }
 
 func (tx *EthereumDynamicFeeTx) DecodeRLP(rlpData []byte) error {
+	if len(rlpData) > maxEthereumTxSize {
+		return errors.Errorf("RLP data size %d exceeds max transaction size %d", len(rlpData), maxEthereumTxSize)
+	}
 	parser := fastrlp.Parser{}
 	rlpVal, err := parser.Parse(rlpData)
 	if err != nil {
@@ -136,8 +139,9 @@ func (tx *EthereumDynamicFeeTx) DecodeRLP(rlpData []byte) error {
 }
 
 func (tx *EthereumDynamicFeeTx) EncodeRLP(w io.Writer) error {
-	arena := fastrlp.Arena{}
-	rlpVal := tx.marshalToFastRLP(&arena)
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
+	rlpVal := tx.marshalToFastRLP(arena)
 	rlpData := rlpVal.MarshalTo(nil)
 	if _, err := w.Write(rlpData); err != nil {
 		return err
@@ -113,8 +113,9 @@ func (tx *EthereumLegacyTx) DecodeRLP(rlpData []byte) error {
 }
 
 func (tx *EthereumLegacyTx) EncodeRLP(w io.Writer) error {
-	arena := fastrlp.Arena{}
-	rlpVal := tx.marshalToFastRLP(&arena)
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
+	rlpVal := tx.marshalToFastRLP(arena)
 	rlpData := rlpVal.MarshalTo(nil)
 	if _, err := w.Write(rlpData); err != nil {
 		return err
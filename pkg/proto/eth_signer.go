@@ -7,7 +7,6 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/mr-tron/base58/base58"
 	"github.com/pkg/errors"
-	"github.com/umbracle/fastrlp"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 )
 
@@ -230,7 +229,8 @@ func (ls londonSigner) Hash(tx *EthereumTransaction) EthereumHash {
 	if tx.EthereumTxType() != EthereumDynamicFeeTxType {
 		return ls.eip2930Signer.Hash(tx)
 	}
-	arena := &fastrlp.Arena{}
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
 	hashValues := tx.inner.signerHashFastRLP(ls.chainId, arena)
 
 	rlpData := []byte{byte(tx.EthereumTxType())}
@@ -302,7 +302,8 @@ func (es eip2930Signer) Hash(tx *EthereumTransaction) EthereumHash {
 	if tx.EthereumTxType() != EthereumAccessListTxType {
 		return es.eip155Signer.Hash(tx)
 	}
-	arena := &fastrlp.Arena{}
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
 	hashValues := tx.inner.signerHashFastRLP(es.chainId, arena)
 
 	rlpData := []byte{byte(tx.EthereumTxType())}
@@ -385,7 +386,8 @@ func (es eip155Signer) Hash(tx *EthereumTransaction) EthereumHash {
 		//panic("Unsupported transaction type: %d", tx.typ)
 		return EthereumHash{}
 	}
-	arena := &fastrlp.Arena{}
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
 	hashValues := tx.inner.signerHashFastRLP(es.chainId, arena)
 
 	rlpData := hashValues.MarshalTo(nil)
@@ -470,7 +472,8 @@ func (fs FrontierSigner) SignatureValues(tx *EthereumTransaction, sig []byte) (r
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (fs FrontierSigner) Hash(tx *EthereumTransaction) EthereumHash {
-	arena := &fastrlp.Arena{}
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
 	hashValues := tx.inner.signerHashFastRLP(fs.ChainID(), arena)
 
 	var rlpData []byte
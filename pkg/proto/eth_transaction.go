@@ -1,11 +1,13 @@
 package proto
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/pkg/errors"
 	"github.com/umbracle/fastrlp"
 	"go.uber.org/atomic"
@@ -19,6 +21,32 @@ import (
 // EthereumGasPrice is a constant GasPrice which equals 10GWei according to the specification
 const EthereumGasPrice = 10 * ethereumGWei
 
+// maxEthereumTxSize is the maximum size of the canonical encoding of EthereumTransaction (1Mb).
+const maxEthereumTxSize = 1024 * 1024
+
+// Intrinsic gas costs of Ethereum transaction as defined by the Ethereum Yellow Paper, EIP-2028 and EIP-2930.
+const (
+	ethereumTxGas                     = 21000
+	ethereumTxDataZeroGas             = 4
+	ethereumTxDataNonZeroGas          = 16
+	ethereumTxAccessListAddressGas    = 2400
+	ethereumTxAccessListStorageKeyGas = 1900
+)
+
+// Minimal fees in wavelets of Ethereum transfer and dApp invocation, they are equal to the minimal fees of
+// TransferTransaction and InvokeScriptTransaction.
+const (
+	ethereumTransferMinFee = 100000
+	ethereumInvokeMinFee   = 500000
+)
+
+// EthereumFeatures enables optional features of EthereumTransaction validation.
+// The zero value corresponds to the default behavior when only legacy transactions are accepted.
+type EthereumFeatures struct {
+	// DynamicFeeTx allows EIP-1559 dynamic fee transactions.
+	DynamicFeeTx bool
+}
+
 // EthereumTxType is an ethereum transaction type.
 type EthereumTxType byte
 
@@ -234,6 +262,7 @@ type EthereumTransaction struct {
 	inner           EthereumTxData
 	innerBinarySize int
 	senderPK        atomic.Value // *EthereumPublicKey
+	kind            atomic.Value // EthereumTransactionKindType
 	TxKind          EthereumTransactionKind
 	ID              *crypto.Digest
 }
@@ -292,6 +321,43 @@ func (tx *EthereumTransaction) GetTimestamp() uint64 {
 	return tx.Nonce()
 }
 
+// GetProofs returns the synthesized proofs of the transaction. Ethereum transactions have no Waves proofs,
+// the only proof is the 65 bytes of the signature in [R || S || V] form, where V is the recovery ID 0 or 1.
+// The synthesized proof is intended only for displaying, it's longer than the Waves proof and can't be verified
+// as the Waves proof.
+func (tx *EthereumTransaction) GetProofs() (*ProofsV1, error) {
+	v, r, s := tx.RawSignatureValues()
+	if v == nil || r == nil || s == nil {
+		return nil, errors.New("not signed")
+	}
+	recoveryID := new(big.Int).Set(v)
+	if tx.EthereumTxType() == EthereumLegacyTxType { // typed transactions hold the recovery ID in V as is
+		if tx.Protected() {
+			recoveryID.Sub(recoveryID, new(big.Int).Mul(tx.ChainId(), big2))
+			recoveryID.Sub(recoveryID, big.NewInt(35))
+		} else {
+			recoveryID.Sub(recoveryID, big.NewInt(27))
+		}
+	}
+	if !recoveryID.IsUint64() || recoveryID.Uint64() > 1 {
+		return nil, errors.Errorf("invalid signature value V=%s", v.String())
+	}
+	sig, err := NewEthereumSignatureFromVRS(byte(recoveryID.Uint64()), r, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ethereum signature")
+	}
+	return &ProofsV1{Version: proofsVersion, Proofs: []B58Bytes{sig.Bytes()}}, nil
+}
+
+// verificationCacheKey returns the transaction ID used as the key of the shared verification cache.
+// The ID is not stored if it's not generated yet, because Verify can be called concurrently.
+func (tx *EthereumTransaction) verificationCacheKey() (crypto.Digest, error) {
+	if tx.ID != nil {
+		return *tx.ID, nil
+	}
+	return tx.DedupKey()
+}
+
 func (tx *EthereumTransaction) threadSafeGetSenderPK() *EthereumPublicKey {
 	senderPK := tx.senderPK.Load()
 	if senderPK != nil {
@@ -304,75 +370,240 @@ func (tx *EthereumTransaction) threadSafeSetSenderPK(senderPK *EthereumPublicKey
 	tx.senderPK.Store(senderPK)
 }
 
+// resetCaches clears the values derived from the inner transaction: ID, sender's public key and kind.
+// It must be called on every change of the inner transaction.
+func (tx *EthereumTransaction) resetCaches() {
+	tx.senderPK = atomic.Value{}
+	tx.kind = atomic.Value{}
+	tx.TxKind = nil
+	tx.ID = nil
+}
+
+// Reset clears the transaction for reuse, e.g. in pools of transaction objects.
+// The transaction must not be used concurrently during the reset.
+func (tx *EthereumTransaction) Reset() {
+	tx.resetCaches()
+	tx.inner = nil
+	tx.innerBinarySize = 0
+}
+
 // Verify performs ONLY transaction signature verification and calculates EthereumPublicKey of transaction
 // For basic transaction checks use Validate method
 func (tx *EthereumTransaction) Verify() (*EthereumPublicKey, error) {
 	if senderPK := tx.threadSafeGetSenderPK(); senderPK != nil {
 		return senderPK, nil
 	}
+	key, keyErr := tx.verificationCacheKey()
+	if keyErr == nil {
+		if senderPK, ok := ethVerificationCache.get(key); ok {
+			tx.threadSafeSetSenderPK(senderPK)
+			return senderPK, nil
+		}
+	}
 	signer := MakeEthereumSigner(tx.ChainId())
 	senderPK, err := signer.SenderPK(tx)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to verify EthereumTransaction")
 	}
 	tx.threadSafeSetSenderPK(senderPK)
+	if keyErr == nil {
+		ethVerificationCache.add(key, senderPK)
+	}
 	return senderPK, nil
 }
 
+// SigningHash returns the hash signed by the sender of the transaction. It's the Keccak256 hash of the signer payload
+// for the type and chain ID of the transaction, the same hash is used by Verify to recover the sender's public key.
+// Unprotected legacy transactions are signed without chain ID.
+func (tx *EthereumTransaction) SigningHash() (crypto.Digest, error) {
+	if tx.inner == nil {
+		return crypto.Digest{}, errors.New("empty Ethereum transaction")
+	}
+	switch tx.EthereumTxType() {
+	case EthereumLegacyTxType, EthereumAccessListTxType, EthereumDynamicFeeTxType:
+	default:
+		return crypto.Digest{}, ErrTxTypeNotSupported
+	}
+	var signer EthereumSigner = MakeEthereumSigner(tx.ChainId())
+	if !tx.Protected() {
+		signer = HomesteadSigner{}
+	}
+	return crypto.Digest(signer.Hash(tx)), nil
+}
+
+// ethereumValidationStage adapts the check of EthereumTransaction to Validator.
+type ethereumValidationStage func(tx *EthereumTransaction, params TransactionValidationParams) error
+
+func (f ethereumValidationStage) Validate(tx Transaction, params TransactionValidationParams) error {
+	ethTx, ok := tx.(*EthereumTransaction)
+	if !ok {
+		return errors.Errorf("unexpected transaction type '%T', expected '*EthereumTransaction'", tx)
+	}
+	return f(ethTx, params)
+}
+
+// ethereumValidationPipeline is the ordered basic checks of EthereumTransaction.
+var ethereumValidationPipeline = NewValidationPipeline(
+	ethereumValidationStage(validateEthereumChainID),
+	ethereumValidationStage(validateEthereumTxType),
+	ethereumValidationStage(validateEthereumTxSize),
+	ethereumValidationStage(validateEthereumGas),
+	ethereumValidationStage(validateEthereumValue),
+	ethereumValidationStage(validateEthereumGasPrice),
+	ethereumValidationStage(validateEthereumRecipient),
+	ethereumValidationStage(validateEthereumNonce),
+)
+
 // Validate performs basic checks for EthereumTransaction according to the specification
 // This method doesn't include signature verification. Use Verify method for signature verification
 func (tx *EthereumTransaction) Validate(params TransactionValidationParams) (Transaction, error) {
-	// same chainID
+	return ethereumValidationPipeline.Validate(tx, params)
+}
+
+// validateEthereumChainID checks that the transaction belongs to the same network.
+func validateEthereumChainID(tx *EthereumTransaction, params TransactionValidationParams) error {
 	if tx.ChainId().Cmp(big.NewInt(int64(params.Scheme))) != 0 {
 		// TODO: introduce new error type for scheme validation
 		txChainID := tx.ChainId().Uint64()
-		return tx, errs.NewTxValidationError(fmt.Sprintf(
+		return errs.NewTxValidationError(fmt.Sprintf(
 			"Address belongs to another network: expected: %d(%c), actual: %d(%c)",
 			params.Scheme, params.Scheme, txChainID, txChainID,
 		))
 	}
-	// accept only EthereumLegacyTxType (this check doesn't exist in scala)
-	if tx.EthereumTxType() != EthereumLegacyTxType {
-		return tx, errs.NewTxValidationError("the ethereum transaction's type is not legacy tx")
+	return nil
+}
+
+// validateEthereumTxType accepts only EthereumLegacyTxType (this check doesn't exist in scala).
+// EthereumDynamicFeeTxType is accepted if it's enabled by EthereumFeatures.
+func validateEthereumTxType(tx *EthereumTransaction, params TransactionValidationParams) error {
+	switch tx.EthereumTxType() {
+	case EthereumLegacyTxType:
+		return nil
+	case EthereumDynamicFeeTxType:
+		if params.EthereumFeatures.DynamicFeeTx {
+			return nil
+		}
 	}
-	// max size of EthereumTransaction is 1Mb (this check doesn't exist in scala)
-	if tx.innerBinarySize > 1024*1024 {
-		return tx, errs.NewTxValidationError("too big size of transaction")
+	return errs.NewTxValidationError("the ethereum transaction's type is not legacy tx")
+}
+
+// validateEthereumTxSize checks that the size of transaction is not greater than 1Mb
+// (this check doesn't exist in scala).
+func validateEthereumTxSize(tx *EthereumTransaction, _ TransactionValidationParams) error {
+	if tx.innerBinarySize > maxEthereumTxSize {
+		return errs.NewTxValidationError("too big size of transaction")
 	}
-	// insufficient fee
+	return nil
+}
+
+// validateEthereumGas checks that the fee is positive. Intrinsic gas is checked only on UTX pool admission.
+func validateEthereumGas(tx *EthereumTransaction, _ TransactionValidationParams) error {
 	if tx.Gas() <= 0 {
-		return tx, errs.NewFeeValidation("insufficient fee")
+		return errs.NewFeeValidation("insufficient fee")
 	}
+	return nil
+}
+
+// validateEthereumValue checks the amount and that either data or value field is set.
+func validateEthereumValue(tx *EthereumTransaction, _ TransactionValidationParams) error {
 	// too many waves (this check doesn't exist in scala)
 	wavelets, err := EthereumWeiToWavelet(tx.Value())
 	if err != nil {
-		return tx, errs.NewFeeValidation(err.Error())
+		return errs.NewFeeValidation(err.Error())
 	}
 	// non positive amount
 	if wavelets < 0 {
-		return tx, errs.NewNonPositiveAmount(wavelets, "waves")
+		return errs.NewNonPositiveAmount(wavelets, "waves")
 	}
 	// a cancel transaction: value == 0 && data == 0x
 	if tx.Value().Cmp(big0) == 0 && len(tx.Data()) == 0 {
-		return tx, errs.NewTxValidationError("Transaction cancellation is not supported")
+		return errs.NewTxValidationError("Transaction cancellation is not supported")
 	}
 	// either data or value field is set
 	if tx.Value().Cmp(big0) != 0 && len(tx.Data()) != 0 {
-		return tx, errs.NewTxValidationError("Transaction should have either data or value")
+		return errs.NewTxValidationError("Transaction should have either data or value")
+	}
+	return nil
+}
+
+// validateEthereumGasPrice checks that gasPrice == 10GWei. For dynamic fee transactions gasFeeCap == 10GWei
+// and gasTipCap <= gasFeeCap are checked instead, because the gas limit is charged as the fee in wavelets.
+func validateEthereumGasPrice(tx *EthereumTransaction, _ TransactionValidationParams) error {
+	price := new(big.Int).SetUint64(EthereumGasPrice)
+	if tx.EthereumTxType() == EthereumDynamicFeeTxType {
+		tipCap, feeCap := tx.GasTipCap(), tx.GasFeeCap()
+		if tipCap == nil || feeCap == nil || tipCap.Sign() < 0 {
+			return errs.NewTxValidationError("Gas tip cap must be non-negative")
+		}
+		if tipCap.Cmp(feeCap) > 0 {
+			return errs.NewTxValidationError("Gas tip cap must not exceed gas fee cap")
+		}
+		if feeCap.Cmp(price) != 0 {
+			return errs.NewTxValidationError("Gas fee cap must be 10 Gwei")
+		}
+		return nil
 	}
-	// gasPrice == 10GWei
-	if tx.GasPrice().Cmp(new(big.Int).SetUint64(EthereumGasPrice)) != 0 {
-		return tx, errs.NewTxValidationError("Gas price must be 10 Gwei")
+	if tx.GasPrice().Cmp(price) != 0 {
+		return errs.NewTxValidationError("Gas price must be 10 Gwei")
 	}
-	// deny a contract creation transaction (this check doesn't exist in scala)
+	return nil
+}
+
+// validateEthereumRecipient denies a contract creation transaction (this check doesn't exist in scala).
+func validateEthereumRecipient(tx *EthereumTransaction, _ TransactionValidationParams) error {
 	if tx.To() == nil {
-		return tx, errs.NewTxValidationError("Contract creation transaction is not supported")
+		return errs.NewTxValidationError("Contract creation transaction is not supported")
 	}
-	// positive timestamp (this check doesn't exist in scala)
+	return nil
+}
+
+// validateEthereumNonce checks that timestamp is positive (this check doesn't exist in scala).
+func validateEthereumNonce(tx *EthereumTransaction, _ TransactionValidationParams) error {
 	if tx.Nonce() <= 0 {
-		return tx, errs.NewTxValidationError("invalid timestamp")
+		return errs.NewTxValidationError("invalid timestamp")
 	}
-	return tx, nil
+	return nil
+}
+
+// CheckGasSufficient returns an error if the gas limit of the transaction is less than its intrinsic gas.
+// Intrinsic gas is the gas charged before any execution: the base cost of transaction, the cost of every zero
+// and non-zero byte of data and the cost of addresses and storage keys of access list.
+// The check isn't a part of consensus validation, it's applied to the transactions accepted into UTX pool.
+func (tx *EthereumTransaction) CheckGasSufficient() error {
+	if gas, intrinsic := tx.Gas(), tx.intrinsicGas(); gas < intrinsic {
+		return errors.Errorf("insufficient gas: gas limit %d is less than intrinsic gas %d", gas, intrinsic)
+	}
+	return nil
+}
+
+// EstimateEthereumTxGas returns the minimal gas limit of legacy EthereumTransaction with the given data and
+// recipient that is accepted by the node. The node treats gas limit as the fee in wavelets: gas price is fixed
+// to EthereumGasPrice (10 GWei) which is exactly one wavelet in wei, so the estimation is the maximum of the
+// intrinsic gas and the minimal Waves fee. Transactions with empty data are considered as Waves transfers,
+// transactions with data as dApp invocations. ERC20 transfers are accepted with the fee of transfer, but
+// the fee of invocation is returned for them as the asset can't be resolved without state.
+func EstimateEthereumTxGas(data []byte, to *EthereumAddress) uint64 {
+	tx := EthereumTransaction{inner: &EthereumLegacyTx{To: to, Data: data}}
+	gas := tx.intrinsicGas()
+	minFee := uint64(ethereumTransferMinFee)
+	if len(data) != 0 {
+		minFee = ethereumInvokeMinFee
+	}
+	return max(gas, minFee)
+}
+
+// intrinsicGas calculates the intrinsic gas of the transaction. The sizes of data and access list are limited
+// by the size of transaction, so the sum can't overflow.
+func (tx *EthereumTransaction) intrinsicGas() uint64 {
+	gas := uint64(ethereumTxGas)
+	data := tx.Data()
+	zero := uint64(bytes.Count(data, []byte{0}))
+	nonZero := uint64(len(data)) - zero
+	gas += zero*ethereumTxDataZeroGas + nonZero*ethereumTxDataNonZeroGas
+	for _, tuple := range tx.AccessList() {
+		gas += ethereumTxAccessListAddressGas + uint64(len(tuple.StorageKeys))*ethereumTxAccessListStorageKeyGas
+	}
+	return gas
 }
 
 func (tx *EthereumTransaction) GenerateID(_ Scheme) error {
@@ -393,16 +624,67 @@ func (tx *EthereumTransaction) MerkleBytes(_ Scheme) ([]byte, error) {
 	return tx.EncodeCanonical()
 }
 
-func (tx *EthereumTransaction) Sign(_ Scheme, _ crypto.SecretKey) error {
-	return errors.New("Sign method for EthereumTransaction isn't implemented")
+// Sign signs the transaction with the secp256k1 private key built from the given secret key bytes.
+// The chain ID of the signature is the blockchain scheme, typed transactions with a different non-zero chain ID
+// are rejected. The signature values are set to the inner transaction, the cached values derived from it
+// are reset and the ID is regenerated.
+func (tx *EthereumTransaction) Sign(scheme Scheme, sk crypto.SecretKey) error {
+	if tx.inner == nil {
+		return errors.New("empty Ethereum transaction")
+	}
+	chainID := big.NewInt(int64(scheme))
+	switch tx.EthereumTxType() {
+	case EthereumLegacyTxType:
+		// Legacy transactions are signed with EIP155 replay protection, until the signature is set
+		// the V value only encodes the chain ID, so SigningHash picks the right signer.
+		_, r, s := tx.inner.rawSignatureValues()
+		v := new(big.Int).Add(new(big.Int).Mul(chainID, big.NewInt(2)), big.NewInt(35))
+		tx.inner.setSignatureValues(chainID, v, r, s)
+	case EthereumAccessListTxType, EthereumDynamicFeeTxType:
+		id := tx.inner.chainID()
+		if id != nil && id.Sign() != 0 && id.Cmp(chainID) != 0 {
+			return ErrInvalidChainId
+		}
+		// Signers expect the chain ID of typed transaction to be set.
+		v, r, s := tx.inner.rawSignatureValues()
+		tx.inner.setSignatureValues(chainID, v, r, s)
+	default:
+		return ErrTxTypeNotSupported
+	}
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return errors.Wrap(err, "failed to sign EthereumTransaction")
+	}
+	signer := MakeEthereumSigner(chainID)
+	priv, _ := btcec.PrivKeyFromBytes(sk[:])
+	sig, err := crypto.ECDSASign(hash[:], priv)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign EthereumTransaction")
+	}
+	r, s, v, err := signer.SignatureValues(tx, sig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get signature values")
+	}
+	tx.inner.setSignatureValues(chainID, v, r, s)
+	tx.resetCaches()
+	return tx.GenerateID(scheme)
 }
 
+// MarshalBinary returns the canonical binary encoding of the transaction (RLP for legacy transactions and
+// EIP2718 envelope for typed transactions).
 func (tx *EthereumTransaction) MarshalBinary(Scheme) ([]byte, error) {
-	return nil, errors.New("EthereumTransaction does not support 'MarshalBinary' method.")
+	if tx.inner == nil {
+		return nil, errors.New("empty Ethereum transaction")
+	}
+	return tx.EncodeCanonical()
 }
 
-func (tx *EthereumTransaction) UnmarshalBinary(_ []byte, _ Scheme) error {
-	return errors.New("EthereumTransaction does not support 'UnmarshalBinary' method.")
+// UnmarshalBinary decodes the transaction from its canonical binary encoding and generates its ID.
+func (tx *EthereumTransaction) UnmarshalBinary(data []byte, scheme Scheme) error {
+	if err := tx.DecodeCanonical(data); err != nil {
+		return errors.Wrap(err, "failed to unmarshal EthereumTransaction from bytes")
+	}
+	return tx.GenerateID(scheme)
 }
 
 func (tx *EthereumTransaction) BodyMarshalBinary(Scheme) ([]byte, error) {
@@ -410,7 +692,7 @@ func (tx *EthereumTransaction) BodyMarshalBinary(Scheme) ([]byte, error) {
 }
 
 func (tx *EthereumTransaction) BinarySize() int {
-	return 0
+	return tx.innerBinarySize
 }
 
 func (tx *EthereumTransaction) MarshalToProtobuf(_ Scheme) ([]byte, error) {
@@ -437,7 +719,11 @@ func (tx *EthereumTransaction) UnmarshalSignedFromProtobuf(bytes []byte) error {
 			t,
 		)
 	}
-	*tx = *ethTx
+	tx.resetCaches()
+	tx.inner = ethTx.inner
+	tx.innerBinarySize = ethTx.innerBinarySize
+	tx.TxKind = ethTx.TxKind
+	tx.ID = ethTx.ID
 	return nil
 }
 
@@ -568,6 +854,42 @@ func (tx *EthereumTransaction) WavesAddressTo(scheme byte) (WavesAddress, error)
 	return to, nil
 }
 
+// Kind classifies the transaction as Waves transfer, asset transfer or dApp invocation by its call data and
+// recipient without access to the blockchain state. The call data of asset transfer is decoded completely,
+// the call data of invocation could be decoded only with the dApp's script, so only its selector is checked.
+// The result is cached.
+func (tx *EthereumTransaction) Kind(scheme Scheme) (EthereumTransactionKindType, error) {
+	if k, ok := tx.kind.Load().(EthereumTransactionKindType); ok {
+		return k, nil
+	}
+	k, err := tx.classify(scheme)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to determine ethereum transaction kind")
+	}
+	tx.kind.Store(k)
+	return k, nil
+}
+
+func (tx *EthereumTransaction) classify(scheme Scheme) (EthereumTransactionKindType, error) {
+	if _, err := tx.WavesAddressTo(scheme); err != nil {
+		return 0, err
+	}
+	k, err := GuessEthereumTransactionKindType(tx.Data())
+	if err != nil {
+		return 0, err
+	}
+	if k == EthereumTransferAssetsKindType {
+		decoded, err := ethabi.NewErc20MethodsMap().ParseCallDataRide(tx.Data(), true)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to parse erc20 transfer data")
+		}
+		if _, err := ethabi.GetERC20TransferArguments(decoded); err != nil {
+			return 0, err
+		}
+	}
+	return k, nil
+}
+
 // From returns the sender address of the transaction.
 // Returns error if transaction doesn't pass validation.
 func (tx *EthereumTransaction) From() (EthereumAddress, error) {
@@ -578,6 +900,16 @@ func (tx *EthereumTransaction) From() (EthereumAddress, error) {
 	return senderPK.EthereumAddress(), nil
 }
 
+// VerifyFrom reports whether the transaction is signed by the expected sender.
+// The cached sender is compared directly, otherwise the sender is recovered from the signature and cached.
+func (tx *EthereumTransaction) VerifyFrom(expected EthereumAddress) (bool, error) {
+	senderPK, err := tx.Verify()
+	if err != nil {
+		return false, err
+	}
+	return senderPK.EthereumAddress() == expected, nil
+}
+
 // FromPK returns the sender public key of the transaction.
 // Returns error if transaction doesn't pass validation.
 func (tx *EthereumTransaction) FromPK() (*EthereumPublicKey, error) {
@@ -639,6 +971,7 @@ func (tx *EthereumTransaction) DecodeCanonical(canonicalData []byte) error {
 		}
 		tx.inner = inner
 	}
+	tx.resetCaches()
 	tx.innerBinarySize = len(canonicalData)
 	return nil
 }
@@ -647,19 +980,51 @@ func (tx *EthereumTransaction) DecodeCanonical(canonicalData []byte) error {
 // For legacy transactions, it returns the RLP encoding. For EIP-2718 typed
 // transactions, it returns the type and payload.
 func (tx *EthereumTransaction) EncodeCanonical() ([]byte, error) {
-	var (
-		canonical []byte
-		arena     fastrlp.Arena
-	)
+	arena := ethArenaPool.Get()
+	defer ethArenaPool.Put(arena)
+	var canonical []byte
 	if tx.EthereumTxType() == EthereumLegacyTxType {
-		fastrlpTx := tx.inner.marshalToFastRLP(&arena)
+		fastrlpTx := tx.inner.marshalToFastRLP(arena)
 		canonical = fastrlpTx.MarshalTo(nil)
 	} else {
-		canonical = tx.encodeTypedCanonical(&arena)
+		canonical = tx.encodeTypedCanonical(arena)
 	}
 	return canonical, nil
 }
 
+// Equal reports whether transactions have the same canonical encoding.
+// Cached values (ID, sender's public key) and resolved transaction kind are not compared.
+func (tx *EthereumTransaction) Equal(other *EthereumTransaction) bool {
+	if tx == nil || other == nil {
+		return tx == other
+	}
+	if tx.inner == nil || other.inner == nil {
+		return tx.inner == nil && other.inner == nil
+	}
+	a, err := tx.EncodeCanonical()
+	if err != nil {
+		return false
+	}
+	b, err := other.EncodeCanonical()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(a, b)
+}
+
+// DedupKey returns the key which is equal for transactions with the same canonical encoding.
+// The key is comparable and can be used as a map key. Unlike GetID it doesn't use or set the cached ID.
+func (tx *EthereumTransaction) DedupKey() (crypto.Digest, error) {
+	if tx.inner == nil {
+		return crypto.Digest{}, errors.New("empty ethereum transaction")
+	}
+	body, err := tx.EncodeCanonical()
+	if err != nil {
+		return crypto.Digest{}, err
+	}
+	return crypto.Digest(Keccak256EthereumHash(body)), nil
+}
+
 // decodeTypedCanonical decodes a typed transaction from the canonical format.
 func (tx *EthereumTransaction) decodeTypedCanonical(canonicalData []byte) (EthereumTxData, error) {
 	if len(canonicalData) == 0 {
@@ -1,12 +1,17 @@
 package proto
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"math/big"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/mr-tron/base58"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,6 +47,39 @@ func TestEthereumTransaction_GetID(t *testing.T) {
 	}
 }
 
+func TestEthereumTransaction_GetProofs(t *testing.T) {
+	tests := []struct {
+		canonicalTxHex string
+		expectedSigHex string
+	}{
+		{ // dynamic fee tx, V is the recovery ID
+			"0x02f86b010284b6ed1ad4856e3c18e22d82520894b69f3f0f21d129d91fc739e0479196bc7f40707e8080c001a02e9ef96d454f7be05ea62c0eb0fac824b6e6161b748c3331c13d988912359ef4a04981e8f8de5be878fa908f8ab128f630caec9eacfa30a2aa06a6be91a0e7db8c",
+			"0x2e9ef96d454f7be05ea62c0eb0fac824b6e6161b748c3331c13d988912359ef44981e8f8de5be878fa908f8ab128f630caec9eacfa30a2aa06a6be91a0e7db8c01",
+		},
+		{ // legacy EIP-155 tx, V = 37 for chain ID 1
+			"0xf86e82146f8513532f83b3825208949c4c39e3cd2f3d0d930e4c065af5ea4a1fcb4a6e880342e341423780008025a086bd7bec8019f17fe77be36468656c9ede915514f1fc158a4eee8a36264b8315a0205b9fa92365441fd7c06fdce3f9d431007bfeb0253032fc1f6364683bff37c5",
+			"0x86bd7bec8019f17fe77be36468656c9ede915514f1fc158a4eee8a36264b8315205b9fa92365441fd7c06fdce3f9d431007bfeb0253032fc1f6364683bff37c500",
+		},
+	}
+	for _, tc := range tests {
+		canonical, err := DecodeFromHexString(tc.canonicalTxHex)
+		require.NoError(t, err)
+
+		var ethTx EthereumTransaction
+		err = ethTx.DecodeCanonical(canonical)
+		require.NoError(t, err)
+
+		proofs, err := ethTx.GetProofs()
+		require.NoError(t, err)
+		require.Len(t, proofs.Proofs, 1)
+		require.Equal(t, tc.expectedSigHex, EncodeToHexString(proofs.Proofs[0].Bytes()))
+	}
+
+	unsigned := NewEthereumTransaction(&EthereumLegacyTx{}, nil, nil, nil, 0)
+	_, err := unsigned.GetProofs()
+	require.Error(t, err)
+}
+
 func TestEthereumTransaction_MerkleBytes(t *testing.T) {
 	tests := []struct {
 		canonicalTxHex string
@@ -85,6 +123,90 @@ func TestEthereumTransaction_DecodeCanonical_EncodeCanonical(t *testing.T) {
 	require.Equal(t, hexTxData, encodedHexTxData)
 }
 
+func TestEthereumTransaction_MarshalUnmarshalBinary(t *testing.T) {
+	for i, txHex := range []string{
+		"0x02f86b010284b6ed1ad4856e3c18e22d82520894b69f3f0f21d129d91fc739e0479196bc7f40707e8080c001a02e9ef96d454f7be05ea62c0eb0fac824b6e6161b748c3331c13d988912359ef4a04981e8f8de5be878fa908f8ab128f630caec9eacfa30a2aa06a6be91a0e7db8c",     //nolint:lll
+		"0xf86e82146f8513532f83b3825208949c4c39e3cd2f3d0d930e4c065af5ea4a1fcb4a6e880342e341423780008025a086bd7bec8019f17fe77be36468656c9ede915514f1fc158a4eee8a36264b8315a0205b9fa92365441fd7c06fdce3f9d431007bfeb0253032fc1f6364683bff37c5", //nolint:lll
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			canonical, err := DecodeFromHexString(txHex)
+			require.NoError(t, err)
+
+			var decoded EthereumTransaction
+			require.NoError(t, decoded.DecodeCanonical(canonical))
+			require.Equal(t, len(canonical), decoded.BinarySize())
+
+			b, err := decoded.MarshalBinary(TestNetScheme)
+			require.NoError(t, err)
+			require.Equal(t, canonical, b)
+
+			var unmarshalled EthereumTransaction
+			require.NoError(t, unmarshalled.UnmarshalBinary(b, TestNetScheme))
+			require.NotNil(t, unmarshalled.ID)
+			require.Equal(t, len(canonical), unmarshalled.BinarySize())
+			require.True(t, decoded.Equal(&unmarshalled))
+
+			require.NoError(t, decoded.GenerateID(TestNetScheme))
+			require.Equal(t, *decoded.ID, *unmarshalled.ID)
+		})
+	}
+}
+
+func TestEthereumTransaction_EncodeCanonicalConcurrent(t *testing.T) {
+	hexTxs := []string{
+		testStageNetEthTxHex,
+		"0x02f86b010284b6ed1ad4856e3c18e22d82520894b69f3f0f21d129d91fc739e0479196bc7f40707e8080c001a02e9ef96d454f7be05ea62c0eb0fac824b6e6161b748c3331c13d988912359ef4a04981e8f8de5be878fa908f8ab128f630caec9eacfa30a2aa06a6be91a0e7db8c",
+	}
+	txs := make([]EthereumTransaction, len(hexTxs))
+	canonicals := make([][]byte, len(hexTxs))
+	for i, hexTx := range hexTxs {
+		canonical, err := DecodeFromHexString(hexTx)
+		require.NoError(t, err)
+		require.NoError(t, txs[i].DecodeCanonical(canonical))
+		canonicals[i] = canonical
+	}
+	// Encoded bytes must stay intact when pooled arenas are reused by other encodings.
+	const iterations = 100
+	results := make([][][]byte, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for w := range results {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				encoded, err := txs[i%len(txs)].EncodeCanonical()
+				if err != nil {
+					return
+				}
+				results[w] = append(results[w], encoded)
+			}
+		}(w)
+	}
+	wg.Wait()
+	for _, encoded := range results {
+		require.Len(t, encoded, iterations)
+		for i, e := range encoded {
+			assert.Equal(t, canonicals[i%len(canonicals)], e)
+		}
+	}
+}
+
+func BenchmarkEthereumTransaction_EncodeCanonical(b *testing.B) {
+	canonical, err := DecodeFromHexString(testStageNetEthTxHex)
+	require.NoError(b, err)
+	var tx EthereumTransaction
+	require.NoError(b, tx.DecodeCanonical(canonical))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := tx.EncodeCanonical(); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
 func TestEthereumTransactionFromScalaJSON(t *testing.T) {
 	const js = `{
         "type": 18,
@@ -254,3 +376,478 @@ func TestEthereumInvokeScriptTxKind_ValidateCallData(t *testing.T) {
 		})
 	}
 }
+
+func TestEthereumTransaction_EqualAndDedupKey(t *testing.T) {
+	decode := func(t *testing.T) *EthereumTransaction {
+		data, err := DecodeFromHexString(testStageNetEthTxHex)
+		require.NoError(t, err)
+		tx := new(EthereumTransaction)
+		require.NoError(t, tx.DecodeCanonical(data))
+		return tx
+	}
+	a, b := decode(t), decode(t)
+	// populate caches of only one transaction
+	_, err := a.GetID(TestNetScheme)
+	require.NoError(t, err)
+	_, err = a.FromPK()
+	require.NoError(t, err)
+	require.NotNil(t, a.ID)
+	require.Nil(t, b.ID)
+
+	assert.True(t, a.Equal(b))
+	assert.True(t, b.Equal(a))
+	ka, err := a.DedupKey()
+	require.NoError(t, err)
+	kb, err := b.DedupKey()
+	require.NoError(t, err)
+	assert.Equal(t, ka, kb)
+	assert.Nil(t, b.ID, "DedupKey must not set cached ID")
+	id, err := a.GetID(TestNetScheme)
+	require.NoError(t, err)
+	assert.Equal(t, id, ka.Bytes())
+
+	legacy, ok := b.inner.(*EthereumLegacyTx)
+	require.True(t, ok)
+	changed := *legacy
+	changed.Nonce++
+	ct := NewEthereumTransaction(&changed, nil, nil, nil, 0)
+	c := &ct
+	assert.False(t, a.Equal(c))
+	kc, err := c.DedupKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, ka, kc)
+
+	seen := make(map[crypto.Digest]*EthereumTransaction)
+	for _, tx := range []*EthereumTransaction{a, b, c} {
+		k, kErr := tx.DedupKey()
+		require.NoError(t, kErr)
+		if _, ok := seen[k]; !ok {
+			seen[k] = tx
+		}
+	}
+	assert.Len(t, seen, 2)
+
+	var nilTx *EthereumTransaction
+	assert.True(t, nilTx.Equal(nil))
+	assert.False(t, nilTx.Equal(a))
+	assert.False(t, a.Equal(nil))
+	assert.True(t, new(EthereumTransaction).Equal(new(EthereumTransaction)))
+	assert.False(t, new(EthereumTransaction).Equal(a))
+	_, err = new(EthereumTransaction).DedupKey()
+	assert.Error(t, err)
+}
+
+func TestEthereumTransaction_VerifyFrom(t *testing.T) {
+	decode := func(t *testing.T) *EthereumTransaction {
+		data, err := DecodeFromHexString(testStageNetEthTxHex)
+		require.NoError(t, err)
+		tx := new(EthereumTransaction)
+		require.NoError(t, tx.DecodeCanonical(data))
+		return tx
+	}
+	sender, err := decode(t).From()
+	require.NoError(t, err)
+	other := sender
+	other[0] ^= 0xff
+
+	tx := decode(t)
+	require.Nil(t, tx.threadSafeGetSenderPK())
+	ok, err := tx.VerifyFrom(sender)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	require.NotNil(t, tx.threadSafeGetSenderPK(), "sender must be cached after verification")
+	ok, err = tx.VerifyFrom(other)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = decode(t).VerifyFrom(other)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEthereumTransaction_ResetCaches(t *testing.T) {
+	const otherTxHex = "0xf86e82146f8513532f83b3825208949c4c39e3cd2f3d0d930e4c065af5ea4a1fcb4a6e880342e341423780008025a086bd7bec8019f17fe77be36468656c9ede915514f1fc158a4eee8a36264b8315a0205b9fa92365441fd7c06fdce3f9d431007bfeb0253032fc1f6364683bff37c5" //nolint:lll
+	dataA, err := DecodeFromHexString(testStageNetEthTxHex)
+	require.NoError(t, err)
+	dataB, err := DecodeFromHexString(otherTxHex)
+	require.NoError(t, err)
+	var expectedB EthereumTransaction
+	require.NoError(t, expectedB.DecodeCanonical(dataB))
+	senderB, err := expectedB.From()
+	require.NoError(t, err)
+
+	tx := new(EthereumTransaction)
+	require.NoError(t, tx.DecodeCanonical(dataA))
+	senderA, err := tx.From()
+	require.NoError(t, err)
+	require.NoError(t, tx.GenerateID(StageNetScheme))
+	require.NotEqual(t, senderA, senderB)
+
+	require.NoError(t, tx.DecodeCanonical(dataB))
+	assert.Nil(t, tx.ID)
+	sender, err := tx.From()
+	require.NoError(t, err)
+	assert.Equal(t, senderB, sender)
+
+	tx.Reset()
+	assert.Nil(t, tx.ID)
+	assert.Nil(t, tx.threadSafeGetSenderPK())
+	assert.Zero(t, tx.BinarySize())
+	assert.Equal(t, UndefinedTxType, tx.EthereumTxType())
+}
+
+func TestEthereumTransaction_SigningHash(t *testing.T) {
+	t.Run("decoded", func(t *testing.T) {
+		for _, txHex := range []string{
+			testStageNetEthTxHex,
+			"0x02f86b010284b6ed1ad4856e3c18e22d82520894b69f3f0f21d129d91fc739e0479196bc7f40707e8080c001a02e9ef96d454f7be05ea62c0eb0fac824b6e6161b748c3331c13d988912359ef4a04981e8f8de5be878fa908f8ab128f630caec9eacfa30a2aa06a6be91a0e7db8c",
+			"0xf86e82146f8513532f83b3825208949c4c39e3cd2f3d0d930e4c065af5ea4a1fcb4a6e880342e341423780008025a086bd7bec8019f17fe77be36468656c9ede915514f1fc158a4eee8a36264b8315a0205b9fa92365441fd7c06fdce3f9d431007bfeb0253032fc1f6364683bff37c5",
+		} {
+			data, err := DecodeFromHexString(txHex)
+			require.NoError(t, err)
+			var tx EthereumTransaction
+			require.NoError(t, tx.DecodeCanonical(data))
+			hash, err := tx.SigningHash()
+			require.NoError(t, err)
+			proofs, err := tx.GetProofs()
+			require.NoError(t, err)
+			sig, err := NewEthereumSignatureFromBytes(proofs.Proofs[0])
+			require.NoError(t, err)
+			recovered, err := sig.RecoverEthereumPublicKey(hash[:])
+			require.NoError(t, err)
+			expected, err := tx.Verify()
+			require.NoError(t, err)
+			assert.Equal(t, expected.SerializeXYCoordinates(), recovered.SerializeXYCoordinates())
+		}
+	})
+	t.Run("signed", func(t *testing.T) {
+		sk, err := crypto.ECDSANewPrivateKey()
+		require.NoError(t, err)
+		chainID := big.NewInt(int64(TestNetScheme))
+		to := EthereumAddress{1, 2, 3}
+		for _, test := range []struct {
+			inner EthereumTxData
+			v     func(recoveryID byte) *big.Int
+		}{
+			{
+				&EthereumLegacyTx{Nonce: 1, GasPrice: big.NewInt(10), Gas: 100000, To: &to, Value: big.NewInt(1)},
+				func(id byte) *big.Int { return big.NewInt(int64(id) + 35 + 2*chainID.Int64()) },
+			},
+			{
+				&EthereumLegacyTx{Nonce: 2, GasPrice: big.NewInt(10), Gas: 100000, To: &to, Value: big.NewInt(1)},
+				func(id byte) *big.Int { return big.NewInt(int64(id) + 27) },
+			},
+			{
+				&EthereumAccessListTx{ChainID: chainID, Nonce: 3, GasPrice: big.NewInt(10), Gas: 100000, To: &to,
+					Value: big.NewInt(1)},
+				func(id byte) *big.Int { return big.NewInt(int64(id)) },
+			},
+			{
+				&EthereumDynamicFeeTx{ChainID: chainID, Nonce: 4, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(10),
+					Gas: 100000, To: &to, Value: big.NewInt(1)},
+				func(id byte) *big.Int { return big.NewInt(int64(id)) },
+			},
+		} {
+			// Legacy transaction derives its chain ID from V, so V is set before hashing.
+			test.inner.setSignatureValues(chainID, test.v(0), big.NewInt(0), big.NewInt(0))
+			tx := NewEthereumTransaction(test.inner, nil, nil, nil, 0)
+			hash, err := tx.SigningHash()
+			require.NoError(t, err)
+			sig, err := crypto.ECDSASign(hash[:], sk)
+			require.NoError(t, err)
+			r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+			test.inner.setSignatureValues(chainID, test.v(sig[64]), r, s)
+			pk, err := tx.Verify()
+			require.NoError(t, err)
+			assert.Equal(t, (*EthereumPrivateKey)(sk).EthereumPublicKey().SerializeXYCoordinates(),
+				pk.SerializeXYCoordinates())
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		_, err := new(EthereumTransaction).SigningHash()
+		assert.Error(t, err)
+	})
+}
+
+func TestEthereumTransaction_Sign(t *testing.T) {
+	sk, err := crypto.ECDSANewPrivateKey()
+	require.NoError(t, err)
+	var secret crypto.SecretKey
+	sk.Key.PutBytes((*[crypto.SecretKeySize]byte)(&secret))
+	expected := (*EthereumPrivateKey)(sk).EthereumPublicKey().EthereumAddress()
+	to := EthereumAddress{1, 2, 3}
+	for _, test := range []struct {
+		name  string
+		inner EthereumTxData
+	}{
+		{"legacy", &EthereumLegacyTx{Nonce: 1, GasPrice: big.NewInt(10), Gas: 100000, To: &to, Value: big.NewInt(1)}},
+		{"access list", &EthereumAccessListTx{Nonce: 2, GasPrice: big.NewInt(10), Gas: 100000, To: &to,
+			Value: big.NewInt(1), AccessList: EthereumAccessList{{Address: EthereumAddress{4}}}}},
+		{"dynamic fee", &EthereumDynamicFeeTx{ChainID: big.NewInt(int64(TestNetScheme)), Nonce: 3,
+			GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(10), Gas: 100000, To: &to, Value: big.NewInt(1)}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tx := NewEthereumTransaction(test.inner, nil, nil, nil, 0)
+			require.NoError(t, tx.Sign(TestNetScheme, secret))
+			assert.True(t, tx.Protected())
+			assert.Equal(t, big.NewInt(int64(TestNetScheme)), tx.ChainId())
+			v, _, _ := tx.RawSignatureValues()
+			assert.Equal(t, tx.EthereumTxType() == EthereumLegacyTxType, isProtectedV(v))
+			addr, err := tx.From()
+			require.NoError(t, err)
+			assert.Equal(t, expected, addr)
+			id := *tx.ID
+
+			// round trip through the canonical encoding
+			data, err := tx.EncodeCanonical()
+			require.NoError(t, err)
+			decoded := new(EthereumTransaction)
+			require.NoError(t, decoded.DecodeCanonical(data))
+			addr, err = decoded.From()
+			require.NoError(t, err)
+			assert.Equal(t, expected, addr)
+			require.NoError(t, decoded.GenerateID(TestNetScheme))
+			assert.Equal(t, id, *decoded.ID)
+
+			// re-signing resets the cached sender and ID
+			other, err := crypto.ECDSANewPrivateKey()
+			require.NoError(t, err)
+			var otherSecret crypto.SecretKey
+			other.Key.PutBytes((*[crypto.SecretKeySize]byte)(&otherSecret))
+			require.NoError(t, tx.Sign(TestNetScheme, otherSecret))
+			addr, err = tx.From()
+			require.NoError(t, err)
+			assert.Equal(t, (*EthereumPrivateKey)(other).EthereumPublicKey().EthereumAddress(), addr)
+			assert.NotEqual(t, id, *tx.ID)
+		})
+	}
+	t.Run("chain ID mismatch", func(t *testing.T) {
+		tx := NewEthereumTransaction(&EthereumDynamicFeeTx{ChainID: big.NewInt(int64(MainNetScheme)),
+			GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(10), Gas: 100000, To: &to}, nil, nil, nil, 0)
+		assert.ErrorIs(t, tx.Sign(TestNetScheme, secret), ErrInvalidChainId)
+	})
+}
+
+func TestEthereumTransaction_CheckGasSufficient(t *testing.T) {
+	to := EthereumAddress{1, 2, 3}
+	data := []byte{0, 0, 1, 2, 3} // 2 zero and 3 non-zero bytes
+	accessList := EthereumAccessList{
+		{Address: EthereumAddress{4}, StorageKeys: []EthereumHash{{1}, {2}}},
+		{Address: EthereumAddress{5}},
+	}
+	for _, test := range []struct {
+		name      string
+		inner     func(gas uint64) EthereumTxData
+		intrinsic uint64
+	}{
+		{"transfer", func(gas uint64) EthereumTxData {
+			return &EthereumLegacyTx{GasPrice: new(big.Int).SetUint64(EthereumGasPrice), Gas: gas, To: &to, Value: big.NewInt(1)}
+		}, 21000},
+		{"data", func(gas uint64) EthereumTxData {
+			return &EthereumLegacyTx{GasPrice: new(big.Int).SetUint64(EthereumGasPrice), Gas: gas, To: &to, Data: data}
+		}, 21000 + 2*4 + 3*16},
+		{"access list", func(gas uint64) EthereumTxData {
+			return &EthereumAccessListTx{ChainID: big.NewInt(int64(TestNetScheme)), GasPrice: big.NewInt(10),
+				Gas: gas, To: &to, Data: data, AccessList: accessList}
+		}, 21000 + 2*4 + 3*16 + 2*2400 + 2*1900},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			exact := NewEthereumTransaction(test.inner(test.intrinsic), nil, nil, nil, 0)
+			assert.NoError(t, exact.CheckGasSufficient())
+			excessive := NewEthereumTransaction(test.inner(test.intrinsic*10), nil, nil, nil, 0)
+			assert.NoError(t, excessive.CheckGasSufficient())
+			insufficient := NewEthereumTransaction(test.inner(test.intrinsic-1), nil, nil, nil, 0)
+			assert.EqualError(t, insufficient.CheckGasSufficient(), fmt.Sprintf(
+				"insufficient gas: gas limit %d is less than intrinsic gas %d", test.intrinsic-1, test.intrinsic))
+		})
+	}
+	t.Run("validate", func(t *testing.T) {
+		// Intrinsic gas isn't checked by consensus validation, historical transactions with lower gas are valid.
+		params := TransactionValidationParams{Scheme: TestNetScheme, CheckVersion: true}
+		v := big.NewInt(35 + 2*int64(TestNetScheme))
+		inner := &EthereumLegacyTx{Nonce: 1, GasPrice: new(big.Int).SetUint64(EthereumGasPrice), Gas: 20999, To: &to,
+			Value: new(big.Int).SetUint64(waveletToWeiMultiplier), V: v, R: big.NewInt(0), S: big.NewInt(0)}
+		tx := NewEthereumTransaction(inner, nil, nil, nil, 0)
+		require.Error(t, tx.CheckGasSufficient())
+		_, err := tx.Validate(params)
+		assert.NoError(t, err)
+	})
+}
+
+func TestEstimateEthereumTxGas(t *testing.T) {
+	to := EthereumAddress{1, 2, 3}
+	bigData := bytes.Repeat([]byte{1}, 40000) // intrinsic gas exceeds the minimal fee of invocation
+	for _, test := range []struct {
+		name string
+		data []byte
+		gas  uint64
+	}{
+		{"transfer", nil, 100000},
+		{"invocation", []byte{0, 0, 1, 2, 3}, 500000},
+		{"large invocation", bigData, 21000 + 40000*16},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			gas := EstimateEthereumTxGas(test.data, &to)
+			assert.Equal(t, test.gas, gas)
+			value := big.NewInt(0)
+			if len(test.data) == 0 {
+				value.SetUint64(waveletToWeiMultiplier)
+			}
+			inner := &EthereumLegacyTx{Nonce: 1, GasPrice: new(big.Int).SetUint64(EthereumGasPrice), Gas: gas,
+				To: &to, Value: value, Data: test.data, V: big.NewInt(35 + 2*int64(TestNetScheme)),
+				R: big.NewInt(0), S: big.NewInt(0)}
+			tx := NewEthereumTransaction(inner, nil, nil, nil, 0)
+			_, err := tx.Validate(TransactionValidationParams{Scheme: TestNetScheme, CheckVersion: true})
+			assert.NoError(t, err)
+			// Gas limit is the fee in wavelets.
+			assert.Equal(t, gas, tx.GetFee())
+		})
+	}
+}
+
+func TestEthereumTransaction_ValidateDynamicFeeTx(t *testing.T) {
+	to := EthereumAddress{1, 2, 3}
+	price := new(big.Int).SetUint64(EthereumGasPrice)
+	newTx := func(tipCap, feeCap *big.Int) *EthereumTransaction {
+		inner := &EthereumDynamicFeeTx{ChainID: big.NewInt(int64(TestNetScheme)), Nonce: 1, GasTipCap: tipCap,
+			GasFeeCap: feeCap, Gas: 100000, To: &to, Value: new(big.Int).SetUint64(waveletToWeiMultiplier),
+			V: big.NewInt(0), R: big.NewInt(0), S: big.NewInt(0)}
+		tx := NewEthereumTransaction(inner, nil, nil, nil, 0)
+		return &tx
+	}
+	disabled := TransactionValidationParams{Scheme: TestNetScheme, CheckVersion: true}
+	enabled := TransactionValidationParams{Scheme: TestNetScheme, CheckVersion: true,
+		EthereumFeatures: EthereumFeatures{DynamicFeeTx: true}}
+	for _, test := range []struct {
+		name   string
+		tx     *EthereumTransaction
+		params TransactionValidationParams
+		err    string
+	}{
+		{"disabled", newTx(big.NewInt(0), price), disabled, "the ethereum transaction's type is not legacy tx"},
+		{"enabled", newTx(big.NewInt(0), price), enabled, ""},
+		{"enabled with tip", newTx(big.NewInt(1), price), enabled, ""},
+		{"tip exceeds fee cap", newTx(new(big.Int).Add(price, big.NewInt(1)), price), enabled,
+			"Gas tip cap must not exceed gas fee cap"},
+		{"negative tip", newTx(big.NewInt(-1), price), enabled, "Gas tip cap must be non-negative"},
+		{"invalid fee cap", newTx(big.NewInt(0), new(big.Int).Sub(price, big.NewInt(1))), enabled,
+			"Gas fee cap must be 10 Gwei"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.tx.Validate(test.params)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+	t.Run("access list tx", func(t *testing.T) {
+		inner := &EthereumAccessListTx{ChainID: big.NewInt(int64(TestNetScheme)), Nonce: 1, GasPrice: price,
+			Gas: 100000, To: &to, Value: new(big.Int).SetUint64(waveletToWeiMultiplier),
+			V: big.NewInt(0), R: big.NewInt(0), S: big.NewInt(0)}
+		tx := NewEthereumTransaction(inner, nil, nil, nil, 0)
+		_, err := tx.Validate(enabled)
+		assert.EqualError(t, err, "the ethereum transaction's type is not legacy tx")
+	})
+}
+
+func TestEthereumTransaction_VerificationCache(t *testing.T) {
+	require.NoError(t, SetEthereumVerificationCacheSize(10))
+	defer func() {
+		require.NoError(t, SetEthereumVerificationCacheSize(DefaultEthereumVerificationCacheSize))
+	}()
+	decode := func(t *testing.T) *EthereumTransaction {
+		data, err := DecodeFromHexString(testStageNetEthTxHex)
+		require.NoError(t, err)
+		tx := new(EthereumTransaction)
+		require.NoError(t, tx.DecodeCanonical(data))
+		return tx
+	}
+	tx := decode(t)
+	id, err := tx.GetID(TestNetScheme)
+	require.NoError(t, err)
+	key, err := crypto.NewDigestFromBytes(id)
+	require.NoError(t, err)
+	_, ok := ethVerificationCache.get(key)
+	require.False(t, ok)
+	pk, err := tx.Verify()
+	require.NoError(t, err)
+	cached, ok := ethVerificationCache.get(key)
+	require.True(t, ok)
+	assert.Equal(t, pk, cached)
+
+	// replace the cached key to make sure that re-decoded transaction takes it from the cache
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fake := (*EthereumPrivateKey)(sk).EthereumPublicKey()
+	ethVerificationCache.add(key, fake)
+	redecoded := decode(t)
+	rpk, err := redecoded.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, fake, rpk)
+
+	// disabled cache
+	require.NoError(t, SetEthereumVerificationCacheSize(0))
+	redecoded = decode(t)
+	rpk, err = redecoded.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, pk, rpk)
+}
+
+func TestEthereumTransaction_Kind(t *testing.T) {
+	to := EthereumAddress{1, 2, 3}
+	newTx := func(data []byte, to *EthereumAddress) *EthereumTransaction {
+		tx := NewEthereumTransaction(&EthereumLegacyTx{
+			GasPrice: big.NewInt(int64(EthereumGasPrice)),
+			Gas:      100000,
+			To:       to,
+			Value:    big.NewInt(0),
+			Data:     data,
+		}, nil, nil, nil, 0)
+		return &tx
+	}
+	erc20Transfer, err := DecodeFromHexString("0xa9059cbb" +
+		"0000000000000000000000009a1989946ae4249aac19ac7a038d24aab03c3d8c" +
+		"0000000000000000000000000000000000000000000000000000000000003039")
+	require.NoError(t, err)
+	invoke, err := DecodeFromHexString("0xdeadbeef" +
+		"0000000000000000000000000000000000000000000000000000000000000001")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		tx   *EthereumTransaction
+		kind EthereumTransactionKindType
+		err  bool
+	}{
+		{"WavesTransfer", newTx(nil, &to), EthereumTransferWavesKindType, false},
+		{"AssetTransfer", newTx(erc20Transfer, &to), EthereumTransferAssetsKindType, false},
+		{"Invoke", newTx(invoke, &to), EthereumInvokeKindType, false},
+		{"ShortData", newTx([]byte{0xde, 0xad}, &to), 0, true},
+		{"TruncatedAssetTransfer", newTx(erc20Transfer[:ethabi.SelectorSize+10], &to), 0, true},
+		{"NoRecipient", newTx(nil, nil), 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kind, kErr := tc.tx.Kind(TestNetScheme)
+			if tc.err {
+				assert.Error(t, kErr)
+				return
+			}
+			require.NoError(t, kErr)
+			assert.Equal(t, tc.kind, kind)
+		})
+	}
+
+	t.Run("Cached", func(t *testing.T) {
+		tx := newTx(erc20Transfer, &to)
+		kind, kErr := tx.Kind(TestNetScheme)
+		require.NoError(t, kErr)
+		require.Equal(t, EthereumTransferAssetsKindType, kind)
+		tx.inner.(*EthereumLegacyTx).Data = nil
+		kind, kErr = tx.Kind(TestNetScheme)
+		require.NoError(t, kErr)
+		assert.Equal(t, EthereumTransferAssetsKindType, kind)
+	})
+}
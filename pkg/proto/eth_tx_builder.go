@@ -0,0 +1,171 @@
+package proto
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// EthTxBuilder constructs unsigned Ethereum transactions. The type of the transaction is selected by the set
+// fields: dynamic fee transaction if GasTipCap or GasFeeCap is set, access list transaction if AccessList is set,
+// legacy transaction otherwise.
+type EthTxBuilder struct {
+	chainID    *big.Int
+	nonce      uint64
+	to         *EthereumAddress
+	value      *big.Int
+	gas        uint64
+	gasPrice   *big.Int
+	gasTipCap  *big.Int
+	gasFeeCap  *big.Int
+	data       []byte
+	accessList EthereumAccessList
+}
+
+func NewEthTxBuilder() *EthTxBuilder {
+	return &EthTxBuilder{}
+}
+
+func (b *EthTxBuilder) ChainID(chainID int64) *EthTxBuilder {
+	b.chainID = big.NewInt(chainID)
+	return b
+}
+
+func (b *EthTxBuilder) Nonce(nonce uint64) *EthTxBuilder {
+	b.nonce = nonce
+	return b
+}
+
+func (b *EthTxBuilder) To(addr EthereumAddress) *EthTxBuilder {
+	b.to = &addr
+	return b
+}
+
+func (b *EthTxBuilder) Value(value *big.Int) *EthTxBuilder {
+	b.value = value
+	return b
+}
+
+func (b *EthTxBuilder) Gas(gas uint64) *EthTxBuilder {
+	b.gas = gas
+	return b
+}
+
+func (b *EthTxBuilder) GasPrice(gasPrice *big.Int) *EthTxBuilder {
+	b.gasPrice = gasPrice
+	return b
+}
+
+func (b *EthTxBuilder) GasTipCap(gasTipCap *big.Int) *EthTxBuilder {
+	b.gasTipCap = gasTipCap
+	return b
+}
+
+func (b *EthTxBuilder) GasFeeCap(gasFeeCap *big.Int) *EthTxBuilder {
+	b.gasFeeCap = gasFeeCap
+	return b
+}
+
+func (b *EthTxBuilder) Data(data []byte) *EthTxBuilder {
+	b.data = data
+	return b
+}
+
+func (b *EthTxBuilder) AccessList(accessList EthereumAccessList) *EthTxBuilder {
+	b.accessList = accessList
+	return b
+}
+
+func (b *EthTxBuilder) isDynamicFee() bool {
+	return b.gasTipCap != nil || b.gasFeeCap != nil
+}
+
+func (b *EthTxBuilder) validate() error {
+	if b.chainID == nil {
+		return errors.New("chain ID is not set")
+	}
+	if b.to == nil {
+		return errors.New("recipient is not set")
+	}
+	if b.gas == 0 {
+		return errors.New("gas limit is not set")
+	}
+	if b.isDynamicFee() {
+		if b.gasTipCap == nil || b.gasFeeCap == nil {
+			return errors.New("both gas tip cap and gas fee cap must be set for dynamic fee transaction")
+		}
+		if b.gasPrice != nil {
+			return errors.New("gas price can't be set for dynamic fee transaction")
+		}
+		return nil
+	}
+	if b.gasPrice == nil {
+		return errors.New("gas price is not set")
+	}
+	return nil
+}
+
+// Build validates the set fields and returns the unsigned transaction. Signature values of the transaction are
+// zeros, for the legacy transaction V holds the chain ID according to EIP-155.
+func (b *EthTxBuilder) Build() (*EthereumTransaction, error) {
+	if err := b.validate(); err != nil {
+		return nil, errors.Wrap(err, "failed to build ethereum transaction")
+	}
+	value := b.value
+	if value == nil {
+		value = new(big.Int)
+	}
+	var inner EthereumTxData
+	switch {
+	case b.isDynamicFee():
+		inner = &EthereumDynamicFeeTx{
+			ChainID:    new(big.Int).Set(b.chainID),
+			Nonce:      b.nonce,
+			GasTipCap:  new(big.Int).Set(b.gasTipCap),
+			GasFeeCap:  new(big.Int).Set(b.gasFeeCap),
+			Gas:        b.gas,
+			To:         b.to.copy(),
+			Value:      new(big.Int).Set(value),
+			Data:       copyBytes(b.data),
+			AccessList: b.accessList.copy(),
+			V:          new(big.Int),
+			R:          new(big.Int),
+			S:          new(big.Int),
+		}
+	case b.accessList != nil:
+		inner = &EthereumAccessListTx{
+			ChainID:    new(big.Int).Set(b.chainID),
+			Nonce:      b.nonce,
+			GasPrice:   new(big.Int).Set(b.gasPrice),
+			Gas:        b.gas,
+			To:         b.to.copy(),
+			Value:      new(big.Int).Set(value),
+			Data:       copyBytes(b.data),
+			AccessList: b.accessList.copy(),
+			V:          new(big.Int),
+			R:          new(big.Int),
+			S:          new(big.Int),
+		}
+	default:
+		// EIP-155: V = chainID * 2 + 35 for the unsigned transaction.
+		v := new(big.Int).Mul(b.chainID, big.NewInt(2))
+		inner = &EthereumLegacyTx{
+			Nonce:    b.nonce,
+			GasPrice: new(big.Int).Set(b.gasPrice),
+			Gas:      b.gas,
+			To:       b.to.copy(),
+			Value:    new(big.Int).Set(value),
+			Data:     copyBytes(b.data),
+			V:        v.Add(v, big.NewInt(35)),
+			R:        new(big.Int),
+			S:        new(big.Int),
+		}
+	}
+	tx := &EthereumTransaction{inner: inner}
+	canonical, err := tx.EncodeCanonical()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build ethereum transaction")
+	}
+	tx.innerBinarySize = len(canonical)
+	return tx, nil
+}
@@ -0,0 +1,95 @@
+package proto
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthTxBuilder(t *testing.T) {
+	to, err := NewEthereumAddressFromHexString("0x7e5f4552091a69125d5dfcb7b8c2659029395bdf")
+	require.NoError(t, err)
+	data := []byte{0xde, 0xad, 0xbe, 0xef}
+	gasPrice := big.NewInt(int64(EthereumGasPrice))
+	accessList := EthereumAccessList{{Address: to, StorageKeys: []EthereumHash{{0x01}}}}
+	const (
+		chainID = int64('T')
+		nonce   = 1700000000000
+		gas     = 500000
+	)
+
+	for _, test := range []struct {
+		name     string
+		builder  *EthTxBuilder
+		inner    EthereumTxData
+		expected EthereumTxType
+	}{
+		{
+			name:    "legacy",
+			builder: NewEthTxBuilder().ChainID(chainID).Nonce(nonce).To(to).Gas(gas).GasPrice(gasPrice).Data(data),
+			inner: &EthereumLegacyTx{
+				Nonce: nonce, GasPrice: gasPrice, Gas: gas, To: &to, Value: big.NewInt(0), Data: data,
+				V: big.NewInt(chainID*2 + 35), R: big.NewInt(0), S: big.NewInt(0),
+			},
+			expected: EthereumLegacyTxType,
+		},
+		{
+			name: "access list",
+			builder: NewEthTxBuilder().ChainID(chainID).Nonce(nonce).To(to).Value(big.NewInt(100)).Gas(gas).
+				GasPrice(gasPrice).AccessList(accessList),
+			inner: &EthereumAccessListTx{
+				ChainID: big.NewInt(chainID), Nonce: nonce, GasPrice: gasPrice, Gas: gas, To: &to,
+				Value: big.NewInt(100), AccessList: accessList, V: big.NewInt(0), R: big.NewInt(0), S: big.NewInt(0),
+			},
+			expected: EthereumAccessListTxType,
+		},
+		{
+			name: "dynamic fee",
+			builder: NewEthTxBuilder().ChainID(chainID).Nonce(nonce).To(to).Gas(gas).GasTipCap(gasPrice).
+				GasFeeCap(gasPrice).Data(data),
+			inner: &EthereumDynamicFeeTx{
+				ChainID: big.NewInt(chainID), Nonce: nonce, GasTipCap: gasPrice, GasFeeCap: gasPrice, Gas: gas,
+				To: &to, Value: big.NewInt(0), Data: data, V: big.NewInt(0), R: big.NewInt(0), S: big.NewInt(0),
+			},
+			expected: EthereumDynamicFeeTxType,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tx, bErr := test.builder.Build()
+			require.NoError(t, bErr)
+			assert.Equal(t, test.expected, tx.EthereumTxType())
+			assert.Equal(t, big.NewInt(chainID), tx.ChainId())
+
+			hand := NewEthereumTransaction(test.inner, nil, nil, nil, 0)
+			expected, eErr := hand.EncodeCanonical()
+			require.NoError(t, eErr)
+			actual, eErr := tx.EncodeCanonical()
+			require.NoError(t, eErr)
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, len(expected), tx.innerBinarySize)
+		})
+	}
+}
+
+func TestEthTxBuilderValidation(t *testing.T) {
+	to := EthereumAddress{0x01}
+	price := big.NewInt(int64(EthereumGasPrice))
+	for _, test := range []struct {
+		builder *EthTxBuilder
+		err     string
+	}{
+		{NewEthTxBuilder().To(to).Gas(1).GasPrice(price), "chain ID is not set"},
+		{NewEthTxBuilder().ChainID(1).Gas(1).GasPrice(price), "recipient is not set"},
+		{NewEthTxBuilder().ChainID(1).To(to).GasPrice(price), "gas limit is not set"},
+		{NewEthTxBuilder().ChainID(1).To(to).Gas(1), "gas price is not set"},
+		{NewEthTxBuilder().ChainID(1).To(to).Gas(1).GasTipCap(price),
+			"both gas tip cap and gas fee cap must be set for dynamic fee transaction"},
+		{NewEthTxBuilder().ChainID(1).To(to).Gas(1).GasTipCap(price).GasFeeCap(price).GasPrice(price),
+			"gas price can't be set for dynamic fee transaction"},
+	} {
+		_, err := test.builder.Build()
+		assert.EqualError(t, err, "failed to build ethereum transaction: "+test.err)
+	}
+}
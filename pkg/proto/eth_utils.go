@@ -14,6 +14,11 @@ const (
 	waveletToWeiMultiplier        = ethereumEther / PriceConstant // ethereum numbers are represented in 10^18, waves 10^8
 )
 
+// ethArenaPool is the pool of RLP arenas used to encode Ethereum transactions.
+// Values allocated in the arena are reused after the arena is returned to the pool, so the encoded bytes
+// must be copied out of the values with MarshalTo before putting the arena back.
+var ethArenaPool fastrlp.ArenaPool
+
 func WaveletToEthereumWei(waveletAmount uint64) *big.Int {
 	return new(big.Int).Mul(
 		new(big.Int).SetUint64(waveletAmount),
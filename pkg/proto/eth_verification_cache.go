@@ -0,0 +1,77 @@
+package proto
+
+import (
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+// DefaultEthereumVerificationCacheSize is the default number of recovered public keys kept in the cache.
+const DefaultEthereumVerificationCacheSize = 10000
+
+// ethereumVerificationCache maps the ID of EthereumTransaction to the recovered public key of the sender.
+// Unlike the cache of the transaction instance it survives the re-decoding of the transaction, for example on
+// rollback, so the signature of the same transaction is not recovered again.
+type ethereumVerificationCache struct {
+	mu    sync.RWMutex
+	cache *lru.Cache // nil if caching is disabled
+}
+
+var ethVerificationCache = newEthereumVerificationCache(DefaultEthereumVerificationCacheSize)
+
+func newEthereumVerificationCache(size int) *ethereumVerificationCache {
+	c := new(ethereumVerificationCache)
+	if err := c.resize(size); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (c *ethereumVerificationCache) resize(size int) error {
+	if size < 0 {
+		return errors.Errorf("invalid ethereum verification cache size %d", size)
+	}
+	var cache *lru.Cache
+	if size > 0 {
+		var err error
+		cache, err = lru.New(size)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ethereum verification cache")
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = cache
+	return nil
+}
+
+func (c *ethereumVerificationCache) get(key crypto.Digest) (*EthereumPublicKey, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.cache == nil {
+		return nil, false
+	}
+	v, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*EthereumPublicKey), true
+}
+
+func (c *ethereumVerificationCache) add(key crypto.Digest, pk *EthereumPublicKey) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.cache != nil {
+		c.cache.Add(key, pk)
+	}
+}
+
+// SetEthereumVerificationCacheSize sets the maximum number of recovered public keys of Ethereum transactions
+// senders shared between all instances of EthereumTransaction. Zero size disables the cache.
+// All cached keys are dropped.
+func SetEthereumVerificationCacheSize(size int) error {
+	return ethVerificationCache.resize(size)
+}
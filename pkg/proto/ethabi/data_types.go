@@ -28,6 +28,11 @@ func (List) ethABIDataTypeMarker()   {}
 const abiSlotSize = 32
 
 func (i Int) encodeToABISlot() (slot [abiSlotSize]byte) {
+	if i < 0 { // sign extension of the negative value
+		for j := range slot[:abiSlotSize-8] {
+			slot[j] = 0xff
+		}
+	}
 	binary.BigEndian.PutUint64(slot[abiSlotSize-8:], uint64(i))
 	return slot
 }
@@ -46,19 +51,27 @@ func (b Bool) EncodeToABI() []byte {
 }
 
 func (s String) EncodeToABI() []byte {
-	l := len(s)
-	strSlots := l / abiSlotSize
-	if l%abiSlotSize != 0 { // doesn't fit in strSlots
-		strSlots += 1 // add slot
+	return encodeDynamicBytesToABI([]byte(s))
+}
+
+func (b Bytes) EncodeToABI() []byte {
+	return encodeDynamicBytesToABI(b)
+}
+
+func encodeDynamicBytesToABI(b []byte) []byte {
+	l := len(b)
+	dataSlots := l / abiSlotSize
+	if l%abiSlotSize != 0 { // doesn't fit in dataSlots
+		dataSlots += 1 // add slot
 	}
 	var (
 		offset = Int(abiSlotSize).encodeToABISlot()
 		size   = Int(l).encodeToABISlot()
 	)
-	outSize := (2 + strSlots) * abiSlotSize // offset slot + size slot + string slots
+	outSize := (2 + dataSlots) * abiSlotSize // offset slot + size slot + data slots
 	out := make([]byte, 0, outSize)
 	out = append(out, offset[:]...)
 	out = append(out, size[:]...)
-	out = append(out, s[:]...)
+	out = append(out, b...)
 	return out[:outSize]
 }
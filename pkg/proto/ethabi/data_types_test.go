@@ -33,3 +33,27 @@ func TestString_EncodeToABI(t *testing.T) {
 	}
 
 }
+
+func TestInt_EncodeToABI(t *testing.T) {
+	tests := []struct {
+		data   Int
+		hexABI string
+	}{
+		{
+			data:   255,
+			hexABI: "00000000000000000000000000000000000000000000000000000000000000ff",
+		},
+		{
+			data:   -1,
+			hexABI: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		},
+		{
+			data:   -256,
+			hexABI: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff00",
+		},
+	}
+	for _, tc := range tests {
+		actual := tc.data.EncodeToABI()
+		require.Equal(t, tc.hexABI, hex.EncodeToString(actual))
+	}
+}
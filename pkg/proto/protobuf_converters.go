@@ -1,6 +1,8 @@
 package proto
 
 import (
+	"runtime"
+
 	"github.com/pkg/errors"
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
@@ -1688,7 +1690,13 @@ func (c *ProtobufConverter) Block(block *g.Block) (Block, error) {
 
 func (c *ProtobufConverter) SignedTransactions(txs []*g.SignedTransaction) ([]Transaction, error) {
 	res := make([]Transaction, len(txs))
+	if err := c.ethereumTransactions(txs, res); err != nil {
+		return nil, err
+	}
 	for i, stx := range txs {
+		if res[i] != nil {
+			continue
+		}
 		tx, err := c.SignedTransaction(stx)
 		if err != nil {
 			return nil, err
@@ -1698,6 +1706,37 @@ func (c *ProtobufConverter) SignedTransactions(txs []*g.SignedTransaction) ([]Tr
 	return res, nil
 }
 
+// ethereumTransactions decodes Ethereum transactions of the block in parallel if there are many of them and puts
+// them to the same positions of res, other positions are left empty.
+func (c *ProtobufConverter) ethereumTransactions(txs []*g.SignedTransaction, res []Transaction) error {
+	var (
+		indexes []int
+		data    [][]byte
+	)
+	for i, stx := range txs {
+		if wrappedTx, ok := stx.GetTransaction().(*g.SignedTransaction_EthereumTransaction); ok {
+			indexes = append(indexes, i)
+			data = append(data, wrappedTx.EthereumTransaction)
+		}
+	}
+	if len(data) < parallelEthereumDecodingThreshold {
+		return nil
+	}
+	ethTxs, err := DecodeEthereumTransactions(data, runtime.NumCPU())
+	if err != nil {
+		var batchErr *EthereumTxBatchError
+		if errors.As(err, &batchErr) {
+			first := batchErr.Errors[0]
+			return errors.Wrapf(first.Err, "failed to unmarshal ethereum transaction #%d", indexes[first.Index])
+		}
+		return err
+	}
+	for i, tx := range ethTxs {
+		res[indexes[i]] = tx
+	}
+	return nil
+}
+
 func (c *ProtobufConverter) features(features []uint32) []int16 {
 	r := make([]int16, len(features))
 	for i, f := range features {
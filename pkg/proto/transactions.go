@@ -159,8 +159,9 @@ func (a TransactionTypeInfo) String() string {
 
 // TransactionValidationParams contains parameters for transaction validation.
 type TransactionValidationParams struct {
-	Scheme       Scheme
-	CheckVersion bool
+	Scheme           Scheme
+	CheckVersion     bool
+	EthereumFeatures EthereumFeatures
 }
 
 // Transaction is a set of common transaction functions.
@@ -180,6 +181,9 @@ type Transaction interface {
 	GetFee() uint64
 	GetFeeAsset() OptionalAsset
 	GetTimestamp() uint64
+	// GetProofs returns the proofs of the transaction in the common form. For transactions with signature
+	// the signature is returned as the single proof. Error is returned for unsigned transaction.
+	GetProofs() (*ProofsV1, error)
 
 	// Validate checks that all transaction fields are valid.
 	// This includes ranges checks, and sanity checks specific for each transaction type:
@@ -450,6 +454,11 @@ func (tx Genesis) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+// GetProofs returns empty proofs, Genesis transaction is not signed.
+func (tx Genesis) GetProofs() (*ProofsV1, error) {
+	return NewProofs(), nil
+}
+
 func (tx Genesis) GetType() TransactionType {
 	return tx.Type
 }
@@ -723,6 +732,13 @@ func (tx Payment) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx Payment) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx Payment) GetType() TransactionType {
 	return tx.Type
 }
@@ -1140,6 +1140,40 @@ func TestTransferWithSigToJSON(t *testing.T) {
 	}
 }
 
+func TestTransactionGetProofs(t *testing.T) {
+	seed, err := base58.Decode("3TUPTbbpiM5UmZDhMmzdsKKNgMvyHwZQncKWfJrxk3bc")
+	require.NoError(t, err)
+	sk, pk, err := crypto.GenerateKeyPair(seed)
+	require.NoError(t, err)
+	adr, err := NewAddressFromString("3PDgLyMzNLkHF2cV1y7NhpmyS2HQjd57SWu")
+	require.NoError(t, err)
+	rcp := NewRecipientFromAddress(adr)
+	ts := uint64(time.Now().Unix() * 1000)
+	w := NewOptionalAssetWaves()
+
+	txs := NewUnsignedTransferWithSig(pk, w, w, ts, 100000000, 100000, rcp, Attachment{})
+	_, err = txs.GetProofs()
+	assert.Error(t, err)
+	require.NoError(t, txs.Sign(TestNetScheme, sk))
+	proofs, err := txs.GetProofs()
+	require.NoError(t, err)
+	require.Len(t, proofs.Proofs, 1)
+	assert.Equal(t, txs.Signature.Bytes(), proofs.Proofs[0].Bytes())
+
+	txp := NewUnsignedTransferWithProofs(2, pk, w, w, ts, 100000000, 100000, rcp, Attachment{})
+	_, err = txp.GetProofs()
+	assert.Error(t, err)
+	require.NoError(t, txp.Sign(TestNetScheme, sk))
+	proofs, err = txp.GetProofs()
+	require.NoError(t, err)
+	assert.Equal(t, txp.Proofs, proofs)
+
+	genesis := NewUnsignedGenesis(adr, 100000000, ts)
+	proofs, err = genesis.GetProofs()
+	require.NoError(t, err)
+	assert.Empty(t, proofs.Proofs)
+}
+
 func TestTransferWithProofsValidations(t *testing.T) {
 	var (
 		w      = NewOptionalAssetWaves()
@@ -135,6 +135,13 @@ func (tx IssueWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx IssueWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx IssueWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -407,10 +414,6 @@ func (tx TransferWithProofs) BinarySize() int {
 	return 3 + tx.Proofs.BinarySize() + tx.Transfer.BinarySize()
 }
 
-func (tx TransferWithProofs) GetProofs() *ProofsV1 {
-	return tx.Proofs
-}
-
 func (tx *TransferWithProofs) MarshalToProtobuf(scheme Scheme) ([]byte, error) {
 	return MarshalTxDeterministic(tx, scheme)
 }
@@ -475,6 +478,13 @@ func (tx TransferWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx TransferWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx TransferWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -795,6 +805,13 @@ func (tx ReissueWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx ReissueWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx ReissueWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -1050,6 +1067,13 @@ func (tx BurnWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx BurnWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx BurnWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -1331,6 +1355,13 @@ func (tx ExchangeWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx ExchangeWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx ExchangeWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -1969,6 +2000,13 @@ func (tx LeaseWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx LeaseWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx LeaseWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -2206,6 +2244,13 @@ func (tx LeaseCancelWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx LeaseCancelWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx LeaseCancelWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -2469,6 +2514,13 @@ func (tx CreateAliasWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx CreateAliasWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx CreateAliasWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -2753,6 +2805,13 @@ func (tx MassTransferWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx MassTransferWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx MassTransferWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -3154,6 +3213,13 @@ func (tx DataWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx DataWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx DataWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -3545,6 +3611,13 @@ func (tx SetScriptWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx SetScriptWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx SetScriptWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -3848,6 +3921,13 @@ func (tx SponsorshipWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx SponsorshipWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx SponsorshipWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -4144,6 +4224,13 @@ func (tx SetAssetScriptWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx SetAssetScriptWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx SetAssetScriptWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -4477,6 +4564,13 @@ func (tx InvokeScriptWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx InvokeScriptWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx InvokeScriptWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -4863,6 +4957,13 @@ func (tx UpdateAssetInfoWithProofs) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx UpdateAssetInfoWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx UpdateAssetInfoWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -5090,6 +5191,13 @@ func (tx InvokeExpressionTransactionWithProofs) GetTypeInfo() TransactionTypeInf
 	return TransactionTypeInfo{tx.Type, Proof}
 }
 
+func (tx InvokeExpressionTransactionWithProofs) GetProofs() (*ProofsV1, error) {
+	if tx.Proofs == nil {
+		return nil, errors.New("not signed")
+	}
+	return tx.Proofs, nil
+}
+
 func (tx InvokeExpressionTransactionWithProofs) GetType() TransactionType {
 	return tx.Type
 }
@@ -62,6 +62,13 @@ func (tx IssueWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx IssueWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx IssueWithSig) GetType() TransactionType {
 	return tx.Type
 }
@@ -278,10 +285,6 @@ type TransferWithSig struct {
 	Transfer
 }
 
-func (tx TransferWithSig) GetProofs() *ProofsV1 {
-	return NewProofsFromSignature(tx.Signature)
-}
-
 func (tx *TransferWithSig) Validate(params TransactionValidationParams) (Transaction, error) {
 	if tx.Version != 1 {
 		return tx, errors.Errorf("unexpected version %d for TransferWithSig", tx.Version)
@@ -301,6 +304,13 @@ func (tx TransferWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx TransferWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx TransferWithSig) GetType() TransactionType {
 	return tx.Type
 }
@@ -601,6 +611,13 @@ func (tx ReissueWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx ReissueWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx ReissueWithSig) GetType() TransactionType {
 	return tx.Type
 }
@@ -842,6 +859,13 @@ func (tx BurnWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx BurnWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx BurnWithSig) GetType() TransactionType {
 	return tx.Type
 }
@@ -1068,6 +1092,13 @@ func (tx ExchangeWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx ExchangeWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx ExchangeWithSig) GetType() TransactionType {
 	return tx.Type
 }
@@ -1557,6 +1588,13 @@ func (tx LeaseWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx LeaseWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx LeaseWithSig) GetType() TransactionType {
 	return tx.Type
 }
@@ -1796,6 +1834,13 @@ func (tx LeaseCancelWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx LeaseCancelWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx LeaseCancelWithSig) GetType() TransactionType {
 	return tx.Type
 }
@@ -2027,6 +2072,13 @@ func (tx CreateAliasWithSig) GetTypeInfo() TransactionTypeInfo {
 	return TransactionTypeInfo{tx.Type, Signature}
 }
 
+func (tx CreateAliasWithSig) GetProofs() (*ProofsV1, error) {
+	if tx.Signature == nil {
+		return nil, errors.New("not signed")
+	}
+	return NewProofsFromSignature(tx.Signature), nil
+}
+
 func (tx CreateAliasWithSig) GetType() TransactionType {
 	return tx.Type
 }
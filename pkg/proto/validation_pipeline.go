@@ -0,0 +1,56 @@
+package proto
+
+// Validator is a single stage of ValidationPipeline.
+type Validator interface {
+	Validate(tx Transaction, params TransactionValidationParams) error
+}
+
+// ValidatorFunc is an adapter to use ordinary functions as Validator.
+type ValidatorFunc func(tx Transaction, params TransactionValidationParams) error
+
+func (f ValidatorFunc) Validate(tx Transaction, params TransactionValidationParams) error {
+	return f(tx, params)
+}
+
+// TransactionDataValidator is the built-in stage that performs the basic checks of transaction data
+// implemented by the Validate method of transaction.
+var TransactionDataValidator Validator = ValidatorFunc(
+	func(tx Transaction, params TransactionValidationParams) error {
+		_, err := tx.Validate(params)
+		return err
+	},
+)
+
+// ValidationPipeline passes transactions through the ordered stages of validation.
+// Validation stops on the first stage that returns an error.
+type ValidationPipeline struct {
+	stages []Validator
+}
+
+// NewValidationPipeline creates the pipeline of the given stages.
+func NewValidationPipeline(stages ...Validator) *ValidationPipeline {
+	return &ValidationPipeline{stages: stages}
+}
+
+// DefaultValidationPipeline creates the pipeline of the built-in stages.
+func DefaultValidationPipeline() *ValidationPipeline {
+	return NewValidationPipeline(TransactionDataValidator)
+}
+
+// With returns a new pipeline with the given stages appended after the stages of the pipeline.
+func (p *ValidationPipeline) With(stages ...Validator) *ValidationPipeline {
+	res := make([]Validator, 0, len(p.stages)+len(stages))
+	res = append(res, p.stages...)
+	res = append(res, stages...)
+	return &ValidationPipeline{stages: res}
+}
+
+// Validate runs the stages of the pipeline in order and returns the error of the first failed stage.
+func (p *ValidationPipeline) Validate(tx Transaction, params TransactionValidationParams) (Transaction, error) {
+	for _, s := range p.stages {
+		if err := s.Validate(tx, params); err != nil {
+			return tx, err
+		}
+	}
+	return tx, nil
+}
@@ -0,0 +1,79 @@
+package proto
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationPipeline(t *testing.T) {
+	params := TransactionValidationParams{Scheme: TestNetScheme, CheckVersion: true}
+	to := EthereumAddress{1, 2, 3}
+	newTx := func(value uint64) *EthereumTransaction {
+		inner := &EthereumLegacyTx{Nonce: 1, GasPrice: new(big.Int).SetUint64(EthereumGasPrice), Gas: 100000,
+			To: &to, Value: new(big.Int).SetUint64(value * waveletToWeiMultiplier),
+			V: big.NewInt(35 + 2*int64(TestNetScheme)), R: big.NewInt(0), S: big.NewInt(0)}
+		tx := NewEthereumTransaction(inner, nil, nil, nil, 0)
+		return &tx
+	}
+	errPolicy := errors.New("amount exceeds policy limit")
+	var calls []string
+	stage := func(name string, err error) Validator {
+		return ValidatorFunc(func(Transaction, TransactionValidationParams) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+	// policy rejects Ethereum transfers of more than 100 wavelets
+	policy := ValidatorFunc(func(tx Transaction, _ TransactionValidationParams) error {
+		calls = append(calls, "policy")
+		ethTx, ok := tx.(*EthereumTransaction)
+		if !ok {
+			return nil
+		}
+		if ethTx.Value().Cmp(new(big.Int).SetUint64(100*waveletToWeiMultiplier)) > 0 {
+			return errPolicy
+		}
+		return nil
+	})
+
+	t.Run("custom stage", func(t *testing.T) {
+		calls = nil
+		p := DefaultValidationPipeline().With(policy, stage("last", nil))
+		_, err := p.Validate(newTx(100), params)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"policy", "last"}, calls)
+
+		calls = nil
+		_, err = p.Validate(newTx(101), params)
+		assert.ErrorIs(t, err, errPolicy)
+		assert.Equal(t, []string{"policy"}, calls)
+	})
+	t.Run("built-in stage first", func(t *testing.T) {
+		calls = nil
+		tx := newTx(0) // cancellation is not supported
+		p := DefaultValidationPipeline().With(policy)
+		_, err := p.Validate(tx, params)
+		assert.EqualError(t, err, "Transaction cancellation is not supported")
+		assert.Empty(t, calls)
+	})
+	t.Run("ordering", func(t *testing.T) {
+		calls = nil
+		errSecond := errors.New("second")
+		p := NewValidationPipeline(stage("first", nil), stage("second", errSecond), stage("third", nil))
+		_, err := p.Validate(newTx(1), params)
+		assert.ErrorIs(t, err, errSecond)
+		assert.Equal(t, []string{"first", "second"}, calls)
+	})
+	t.Run("With doesn't modify pipeline", func(t *testing.T) {
+		calls = nil
+		p := NewValidationPipeline(stage("first", nil))
+		_ = p.With(stage("second", nil))
+		_, err := p.Validate(newTx(1), params)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first"}, calls)
+	})
+}
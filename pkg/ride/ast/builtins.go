@@ -0,0 +1,81 @@
+package ast
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	extractNativePrefix = "@extrNative("
+	extractUserPrefix   = "@extrUser("
+	extractSuffix       = ")"
+)
+
+// NormalizeBuiltinName returns the name of the built-in function wrapped by the value extracting function,
+// for example, "1050" for "@extrNative(1050)" and "getInteger" for "@extrUser(getInteger)". Other names are
+// returned as is.
+func NormalizeBuiltinName(name string) string {
+	for _, prefix := range [...]string{extractNativePrefix, extractUserPrefix} {
+		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, extractSuffix) {
+			return name[len(prefix) : len(name)-len(extractSuffix)]
+		}
+	}
+	return name
+}
+
+// UsedBuiltins returns the sorted names of built-in functions called by the tree. Native functions are named
+// by their IDs (for example, "1050"), library user functions by their names (for example, "addressFromString").
+// Value extracting functions are named after the functions they wrap (see NormalizeBuiltinName).
+// Functions declared by the script itself are not included.
+func UsedBuiltins(tree *Tree) []string {
+	c := &builtinsCollector{
+		declared: make(map[string]struct{}),
+		called:   make(map[string]struct{}),
+	}
+	for _, n := range tree.Declarations {
+		c.walk(n)
+	}
+	for _, n := range tree.Functions {
+		c.walk(n)
+	}
+	if tree.Verifier != nil {
+		c.walk(tree.Verifier)
+	}
+	res := make([]string, 0, len(c.called))
+	for name := range c.called {
+		if _, ok := c.declared[name]; ok {
+			continue
+		}
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+type builtinsCollector struct {
+	declared map[string]struct{}
+	called   map[string]struct{}
+}
+
+func (c *builtinsCollector) walk(node Node) {
+	switch n := node.(type) {
+	case *AssignmentNode:
+		c.walk(n.Expression)
+		c.walk(n.Block)
+	case *FunctionDeclarationNode:
+		c.declared[n.Name] = struct{}{}
+		c.walk(n.Body)
+		c.walk(n.Block)
+	case *ConditionalNode:
+		c.walk(n.Condition)
+		c.walk(n.TrueExpression)
+		c.walk(n.FalseExpression)
+	case *PropertyNode:
+		c.walk(n.Object)
+	case *FunctionCallNode:
+		c.called[NormalizeBuiltinName(n.Function.Name())] = struct{}{}
+		for _, arg := range n.Arguments {
+			c.walk(arg)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ast
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/meta"
+)
+
+// CallableArgument is an argument of a callable function with its type in RIDE notation.
+type CallableArgument struct {
+	Name string
+	Type string
+}
+
+// Callable is the signature of a callable function of a dApp.
+type Callable struct {
+	Name                string
+	InvocationParameter string
+	Arguments           []CallableArgument
+}
+
+// Callables returns the signatures of callable functions of the dApp in the order of the script meta. Argument types
+// are taken from the meta, argument names and the invocation parameter are taken from the function declarations.
+// Names replaced by the compaction are resolved to the original ones with the abbreviations of the meta.
+func Callables(tree *Tree) ([]Callable, error) {
+	if !tree.IsDApp() {
+		return nil, errors.New("script is not a dApp")
+	}
+	declarations := make(map[string]*FunctionDeclarationNode, len(tree.Functions))
+	reserved := make(map[string]struct{}, len(tree.Functions))
+	for _, n := range tree.Functions {
+		f, ok := n.(*FunctionDeclarationNode)
+		if !ok {
+			return nil, errors.Errorf("unexpected callable function node type %T", n)
+		}
+		declarations[f.Name] = f
+		reserved[f.Name] = struct{}{}
+	}
+	originals := tree.Meta.Abbreviations.CompactToOriginalNames(reserved)
+	original := func(name string) string {
+		if o, ok := originals[name]; ok {
+			return o
+		}
+		return name
+	}
+	res := make([]Callable, len(tree.Meta.Functions))
+	for i, f := range tree.Meta.Functions {
+		decl, ok := declarations[f.Name]
+		if !ok {
+			return nil, errors.Errorf("declaration of callable '%s' not found", f.Name)
+		}
+		if len(decl.Arguments) != len(f.Arguments) {
+			return nil, errors.Errorf("inconsistent number of arguments of callable '%s'", f.Name)
+		}
+		args := make([]CallableArgument, len(f.Arguments))
+		for j, t := range f.Arguments {
+			tn, err := meta.TypeName(t)
+			if err != nil {
+				return nil, errors.Wrapf(err, "callable '%s'", f.Name)
+			}
+			args[j] = CallableArgument{Name: original(decl.Arguments[j]), Type: tn}
+		}
+		res[i] = Callable{Name: f.Name, InvocationParameter: original(decl.InvocationParameter), Arguments: args}
+	}
+	return res, nil
+}
@@ -0,0 +1,46 @@
+package compiler
+
+import (
+	stderrs "errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+// CallableParameter describes a parameter of callable function.
+type CallableParameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// CallableABI is a signature of dApp's callable function.
+// RIDE has no default or optional arguments, so all parameters are required in invocation.
+type CallableABI struct {
+	Name       string              `json:"name"`
+	Parameters []CallableParameter `json:"parameters"`
+}
+
+// CallableABIs compiles the dApp source code and returns signatures of its callable functions
+// in the order of declaration.
+func CallableABIs(src string) ([]CallableABI, error) {
+	tree, errs := CompileToTree(src)
+	if len(errs) > 0 {
+		return nil, stderrs.Join(errs...)
+	}
+	return callableABIs(tree)
+}
+
+func callableABIs(tree *ast.Tree) ([]CallableABI, error) {
+	callables, err := ast.Callables(tree)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]CallableABI, len(callables))
+	for i, c := range callables {
+		params := make([]CallableParameter, len(c.Arguments))
+		for j, a := range c.Arguments {
+			params[j] = CallableParameter{Name: a.Name, Type: a.Type}
+		}
+		res[i] = CallableABI{Name: c.Name, Parameters: params}
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package compiler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallableABIs(t *testing.T) {
+	const src = `
+{-# STDLIB_VERSION 5 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+func helper(a: Int) = a + 1
+
+@Callable(i)
+func deposit() = []
+
+@Callable(i)
+func transfer(recipient: String, amount: Int, memo: ByteVector, confirm: Boolean) = []
+
+@Callable(inv)
+func batch(amounts: List[Int], key: Int|String) = []
+
+@Verifier(tx)
+func verify() = true
+`
+	abis, err := CallableABIs(src)
+	require.NoError(t, err)
+	expected := []CallableABI{
+		{Name: "deposit", Parameters: []CallableParameter{}},
+		{Name: "transfer", Parameters: []CallableParameter{
+			{Name: "recipient", Type: "String"},
+			{Name: "amount", Type: "Int"},
+			{Name: "memo", Type: "ByteVector"},
+			{Name: "confirm", Type: "Boolean"},
+		}},
+		{Name: "batch", Parameters: []CallableParameter{
+			{Name: "amounts", Type: "List[Int]"},
+			{Name: "key", Type: "Int|String"},
+		}},
+	}
+	assert.Equal(t, expected, abis)
+
+	js, err := json.Marshal(abis[2])
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"name":"batch","parameters":[{"name":"amounts","type":"List[Int]"},{"name":"key","type":"Int|String"}]}`,
+		string(js),
+	)
+
+	_, err = CallableABIs(`
+{-# STDLIB_VERSION 5 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+true
+`)
+	assert.EqualError(t, err, "script is not a dApp")
+
+	_, err = CallableABIs(`
+{-# STDLIB_VERSION 5 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func f(a: Unknown) = []
+`)
+	assert.Error(t, err)
+}
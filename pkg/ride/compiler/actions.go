@@ -0,0 +1,265 @@
+package compiler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+const (
+	consFunctionID   = "1100"
+	appendFunctionID = "1101"
+	concatFunctionID = "1102"
+	tuple2FunctionID = "1300"
+	issueFunctionID  = "1090"
+	issueNFunctionID = "1091"
+)
+
+// actionsByConstructor maps names of action constructors to the representatives of the corresponding script actions.
+// Representatives are used only to determine the group of the action during static counting.
+var actionsByConstructor = map[string]proto.ScriptAction{
+	"DataEntry":      &proto.DataEntryScriptAction{},
+	"BinaryEntry":    &proto.DataEntryScriptAction{},
+	"BooleanEntry":   &proto.DataEntryScriptAction{},
+	"DeleteEntry":    &proto.DataEntryScriptAction{},
+	"IntegerEntry":   &proto.DataEntryScriptAction{},
+	"StringEntry":    &proto.DataEntryScriptAction{},
+	"ScriptTransfer": &proto.TransferScriptAction{},
+	"Lease":          &proto.LeaseScriptAction{},
+	"LeaseCancel":    &proto.LeaseCancelScriptAction{},
+	"Issue":          &proto.IssueScriptAction{},
+	"Reissue":        &proto.ReissueScriptAction{},
+	"Burn":           &proto.BurnScriptAction{},
+	"SponsorFee":     &proto.SponsorshipScriptAction{},
+}
+
+// ActionsCount is the number of actions produced by the callable, computed statically.
+// Min and Max are the bounds over the results of the callable which length is known at compile time.
+// If length of any result could be determined only in runtime the count is dynamic.
+type ActionsCount struct {
+	Min     int
+	Max     int
+	Dynamic bool
+}
+
+func (c ActionsCount) String() string {
+	switch {
+	case c.Dynamic:
+		return "dynamic"
+	case c.Min == c.Max:
+		return strconv.Itoa(c.Min)
+	default:
+		return fmt.Sprintf("%d..%d", c.Min, c.Max)
+	}
+}
+
+// CallableActionsCounts returns the static counts of actions of all callables of the tree by the callable names.
+func CallableActionsCounts(tree *ast.Tree) map[string]ActionsCount {
+	res := make(map[string]ActionsCount, len(tree.Functions))
+	for _, node := range tree.Functions {
+		if f, ok := node.(*ast.FunctionDeclarationNode); ok {
+			res[f.Name] = callableActionsCount(f)
+		}
+	}
+	return res
+}
+
+func callableActionsCount(f *ast.FunctionDeclarationNode) ActionsCount {
+	var (
+		c     ActionsCount
+		known bool
+	)
+	for _, res := range callableResults(f.Body) {
+		n, ok := staticListLength(res)
+		if !ok {
+			c.Dynamic = true
+			continue
+		}
+		if !known {
+			c.Min, c.Max, known = n, n, true
+			continue
+		}
+		c.Min = min(c.Min, n)
+		c.Max = max(c.Max, n)
+	}
+	if !known {
+		c.Dynamic = true
+	}
+	return c
+}
+
+// maxCallableActions returns the total number of actions of all groups that callable is allowed to produce.
+func maxCallableActions(libVersion ast.LibraryVersion) int {
+	switch {
+	case libVersion < ast.LibV5:
+		return proto.MaxScriptActionsV1 + proto.MaxDataEntryScriptActions
+	case libVersion == ast.LibV5:
+		return proto.MaxScriptActionsV2 + proto.MaxDataEntryScriptActions
+	default:
+		return proto.MaxBalanceScriptActionsV3 + proto.MaxAssetScriptActionsV3 + proto.MaxDataEntryScriptActions
+	}
+}
+
+// checkCallableActions validates the number of actions of every statically known result of the callable
+// against the limits of the script's library version. If the kinds of actions are unknown, but the number
+// of actions exceeds the total limit of all kinds, the warning is reported.
+func (p *astParser) checkCallableActions(node *node32, f *ast.FunctionDeclarationNode) {
+	for _, res := range callableResults(f.Body) {
+		actions, ok := staticActions(res)
+		if !ok {
+			continue
+		}
+		validator := proto.NewScriptActionsCountValidator()
+		for _, a := range actions {
+			if err := validator.CountAction(a, p.tree.LibVersion, true); err != nil {
+				p.addError(node.token32, "Callable '%s' statically exceeds actions limit: %v", f.Name, err)
+				return
+			}
+		}
+	}
+	// Maximum is reported even if some results are dynamic, because at least one result reaches it.
+	if c, limit := callableActionsCount(f), maxCallableActions(p.tree.LibVersion); c.Max > limit {
+		p.addWarning(node.token32, "Callable '%s' may produce up to %d actions, more than allowed %d",
+			f.Name, c.Max, limit)
+	}
+}
+
+// callableResults returns all expressions that could be returned by the callable body.
+// Declarations are skipped and both branches of conditional expressions are taken into account.
+func callableResults(node ast.Node) []ast.Node {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		return callableResults(n.Block)
+	case *ast.FunctionDeclarationNode:
+		return callableResults(n.Block)
+	case *ast.ConditionalNode:
+		return append(callableResults(n.TrueExpression), callableResults(n.FalseExpression)...)
+	case *ast.FunctionCallNode:
+		switch n.Function.Name() {
+		case tuple2FunctionID: // (actions, result) tuple, only actions are interesting
+			return callableResults(n.Arguments[0])
+		case "ScriptResult": // ScriptResult(WriteSet, TransferSet) of RIDE V3
+			var res []ast.Node
+			for _, arg := range n.Arguments {
+				res = append(res, callableResults(arg)...)
+			}
+			return []ast.Node{concatNodes(res)}
+		case "WriteSet", "TransferSet":
+			if len(n.Arguments) == 1 {
+				return []ast.Node{n.Arguments[0]}
+			}
+		}
+		return []ast.Node{n}
+	case nil:
+		return nil
+	default:
+		return []ast.Node{n}
+	}
+}
+
+// concatNodes joins the list expressions into one list concatenation expression.
+func concatNodes(nodes []ast.Node) ast.Node {
+	var res ast.Node = ast.NewReferenceNode("nil")
+	for i := len(nodes) - 1; i >= 0; i-- {
+		res = ast.NewFunctionCallNode(ast.NativeFunction(concatFunctionID), []ast.Node{nodes[i], res})
+	}
+	return res
+}
+
+// staticActions returns the list of actions of statically built list expression.
+// The second return value is false if the list or any of its elements could be determined only in runtime.
+func staticActions(node ast.Node) ([]proto.ScriptAction, bool) {
+	switch n := node.(type) {
+	case *ast.ReferenceNode:
+		if n.Name == "nil" {
+			return nil, true
+		}
+		return nil, false
+	case *ast.FunctionCallNode:
+		if len(n.Arguments) != 2 {
+			return nil, false
+		}
+		switch n.Function.Name() {
+		case consFunctionID:
+			action, ok := staticAction(n.Arguments[0])
+			if !ok {
+				return nil, false
+			}
+			tail, ok := staticActions(n.Arguments[1])
+			if !ok {
+				return nil, false
+			}
+			return append([]proto.ScriptAction{action}, tail...), true
+		case appendFunctionID:
+			head, ok := staticActions(n.Arguments[0])
+			if !ok {
+				return nil, false
+			}
+			action, ok := staticAction(n.Arguments[1])
+			if !ok {
+				return nil, false
+			}
+			return append(head, action), true
+		case concatFunctionID:
+			head, ok := staticActions(n.Arguments[0])
+			if !ok {
+				return nil, false
+			}
+			tail, ok := staticActions(n.Arguments[1])
+			if !ok {
+				return nil, false
+			}
+			return append(head, tail...), true
+		}
+	}
+	return nil, false
+}
+
+// staticListLength returns the number of elements of statically built list expression.
+// Unlike staticActions the elements themselves could be unknown at compile time.
+func staticListLength(node ast.Node) (int, bool) {
+	switch n := node.(type) {
+	case *ast.ReferenceNode:
+		return 0, n.Name == "nil"
+	case *ast.FunctionCallNode:
+		if len(n.Arguments) != 2 {
+			return 0, false
+		}
+		switch n.Function.Name() {
+		case consFunctionID:
+			tail, ok := staticListLength(n.Arguments[1])
+			return tail + 1, ok
+		case appendFunctionID:
+			head, ok := staticListLength(n.Arguments[0])
+			return head + 1, ok
+		case concatFunctionID:
+			head, ok := staticListLength(n.Arguments[0])
+			if !ok {
+				return 0, false
+			}
+			tail, ok := staticListLength(n.Arguments[1])
+			return head + tail, ok
+		}
+	}
+	return 0, false
+}
+
+func staticAction(node ast.Node) (proto.ScriptAction, bool) {
+	call, ok := node.(*ast.FunctionCallNode)
+	if !ok {
+		return nil, false
+	}
+	switch fn := call.Function.(type) {
+	case ast.UserFunction:
+		action, ok := actionsByConstructor[fn.Name()]
+		return action, ok
+	case ast.NativeFunction: // Issue functions with default arguments
+		switch fn.Name() {
+		case issueFunctionID, issueNFunctionID:
+			return &proto.IssueScriptAction{}, true
+		}
+	}
+	return nil, false
+}
@@ -32,6 +32,29 @@ const (
 	libraryValueName = "LIBRARY"
 )
 
+// ScriptKind is the kind of the script declared by the CONTENT_TYPE directive.
+type ScriptKind byte
+
+const (
+	DAppScriptKind ScriptKind = iota + 1
+	ExpressionScriptKind
+	// LibraryScriptKind is the kind of the script which contains only declarations to be imported by other scripts.
+	LibraryScriptKind
+)
+
+func (k ScriptKind) String() string {
+	switch k {
+	case DAppScriptKind:
+		return dappValueName
+	case ExpressionScriptKind:
+		return expressionValueName
+	case LibraryScriptKind:
+		return libraryValueName
+	default:
+		return "UNKNOWN"
+	}
+}
+
 type scriptType byte
 
 const (
@@ -68,17 +91,29 @@ type astParser struct {
 	tree   *ast.Tree
 	buffer []rune
 
-	errorsList []error
-	stack      *stack
+	errorsList   []error
+	warningsList []error
+	stack        *stack
 
 	stdFuncs   s.FunctionsSignatures
 	stdObjects s.ObjectsSignatures
 	stdTypes   map[string]s.Type
+	reserved   map[string]struct{}
+	builtins   []Builtin
 
 	scriptType  scriptType
+	kind        ScriptKind
 	importPaths []importPath
+	imports     []ManifestFile
 	isLibrary   bool
 	fileName    string
+
+	maxNestingDepth int
+	maxCallables    int
+	maxLiteralSize  int
+
+	invokeCallSites []InvokeCallSite
+	recursion       recursionChecker
 }
 
 func newASTParser(node *node32, buffer []rune) astParser {
@@ -95,10 +130,12 @@ func newASTParser(node *node32, buffer []rune) astParser {
 				Abbreviations: meta.Abbreviations{},
 			},
 		},
-		buffer:     buffer,
-		errorsList: []error{},
-		stack:      newStack(),
-		scriptType: accountScript,
+		buffer:       buffer,
+		errorsList:   []error{},
+		warningsList: []error{},
+		stack:        newStack(),
+		scriptType:   accountScript,
+		kind:         DAppScriptKind,
 	}
 }
 
@@ -106,14 +143,64 @@ func (p *astParser) parse() {
 	switch p.node.pegRule {
 	case ruleCode:
 		p.ruleCodeHandler(p.node.up)
+		p.checkNesting(p.node.up)
+		p.checkRecursion()
 	}
 }
 
 func (p *astParser) addError(token token32, format string, args ...any) {
-	p.errorsList = append(p.errorsList,
+	msg := fmt.Sprintf(format, args...)
+	if origin := unionOrigin(args); origin != "" {
+		msg += ", " + origin
+	}
+	p.errorsList = append(p.errorsList, newASTError(msg, token, p.buffer, p.fileName))
+}
+
+// unionOrigin returns the origin of the first union type among the types reported in the error,
+// it explains where the unexpected union type came from.
+func unionOrigin(args []any) string {
+	for _, a := range args {
+		switch t := a.(type) {
+		case s.UnionType:
+			if t.Origin != "" {
+				return t.Origin
+			}
+		case argsTypesList:
+			for _, tt := range t {
+				if u, ok := tt.(s.UnionType); ok && u.Origin != "" {
+					return u.Origin
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// addWarning reports the issue which doesn't prevent the compilation, but most likely is a mistake.
+func (p *astParser) addWarning(token token32, format string, args ...any) {
+	p.warningsList = append(p.warningsList,
 		newASTError(fmt.Sprintf(format, args...), token, p.buffer, p.fileName))
 }
 
+// declareVariable pushes the variable declared by user at the token to the stack.
+func (p *astParser) declareVariable(token token32, v s.Variable) {
+	p.stack.pushDeclaredVariable(v, &declaration{token: token, buffer: p.buffer, fileName: p.fileName})
+}
+
+// checkShadowing warns if the binding hides the user variable of the same name declared in the enclosing scope.
+func (p *astParser) checkShadowing(token token32, name string) {
+	if decl, ok := p.stack.declaration(name); ok {
+		p.addWarning(token, "Variable '%s' shadows the variable declared at %s", name, decl.position())
+	}
+}
+
+// tokenPosition returns the textual position of the beginning of the token.
+func (p *astParser) tokenPosition(token token32) string {
+	begin := int(token.begin)
+	pos := translatePositions(p.buffer, []int{begin})[begin]
+	return fmt.Sprintf("%d:%d", pos.line, pos.symbol)
+}
+
 func (p *astParser) loadBuildInVarsToStackByVersion() {
 	resVars := make(map[string]s.Variable)
 	ver := int(p.tree.LibVersion)
@@ -166,9 +253,10 @@ func (p *astParser) ruleDAppRootHandler(node *node32) {
 		curNode = p.parseDirectives(curNode)
 	}
 	if !p.isLibrary {
-		p.stdFuncs = s.FuncsByVersion()[p.tree.LibVersion]
+		p.stdFuncs = extendFunctions(s.FuncsByVersion()[p.tree.LibVersion], p.builtins)
 		p.stdObjects = s.ObjectsByVersion()[p.tree.LibVersion]
 		p.stdTypes = s.DefaultTypes()[p.tree.LibVersion]
+		p.reserved = reservedIdentifiers(p.tree.LibVersion, p.tree.IsDApp())
 		p.loadBuildInVarsToStackByVersion()
 	}
 	p.loadImport()
@@ -177,16 +265,68 @@ func (p *astParser) ruleDAppRootHandler(node *node32) {
 		curNode = p.parseDeclarations(curNode)
 	}
 	curNode = skipToNextRule(curNode)
-	if !p.isLibrary {
-		if isRule(curNode, ruleAnnotatedFunc) {
+	if isRule(curNode, ruleAnnotatedFunc) {
+		if p.isLibraryCode() {
+			p.checkLibraryAnnotatedFuncs(curNode)
+		} else {
 			p.parseAnnotatedFunc(curNode)
+			p.checkCallablesCount(curNode)
+		}
+	}
+}
+
+// isLibraryCode reports whether the code is a library, either imported or compiled on its own.
+func (p *astParser) isLibraryCode() bool {
+	return p.isLibrary || p.kind == LibraryScriptKind
+}
+
+// checkLibraryAnnotatedFuncs reports callable functions and verifier found in the library.
+// Library can export only declarations.
+func (p *astParser) checkLibraryAnnotatedFuncs(node *node32) {
+	curNode := node
+	for {
+		if isRule(curNode, ruleAnnotatedFunc) {
+			annotationNode := curNode.up.up
+			switch name := p.nodeValue(annotationNode.up); name {
+			case "Callable":
+				p.addError(annotationNode.token32, "Library can't contain callable functions")
+			case "Verifier":
+				p.addError(annotationNode.token32, "Library can't contain verifier function")
+			default:
+				p.addError(annotationNode.token32, "Undefined annotation '%s'", name)
+			}
+			curNode = curNode.next
 		}
+		curNode = skipToNextRule(curNode)
+		if curNode == nil || (curNode.pegRule != rule_ && curNode.pegRule != ruleAnnotatedFunc) {
+			break
+		}
+	}
+}
+
+// checkCallablesCount reports the callable function that exceeds the limit of the number of callables.
+func (p *astParser) checkCallablesCount(node *node32) {
+	if p.maxCallables <= 0 {
+		return
+	}
+	var callables []*node32
+	for curNode := node; isRule(curNode, ruleAnnotatedFunc); {
+		if annotationNode := curNode.up.up; p.nodeValue(annotationNode.up) == "Callable" {
+			callables = append(callables, annotationNode)
+		}
+		curNode = skipToNextRule(curNode.next)
+	}
+	if len(callables) > p.maxCallables {
+		p.addError(callables[p.maxCallables].token32, "Number of callable functions %d exceeds the limit %d",
+			len(callables), p.maxCallables)
 	}
 }
 
 func (p *astParser) loadLib(lib *astParser) {
 	p.tree.Declarations = append(p.tree.Declarations, lib.tree.Declarations...)
 	p.errorsList = append(p.errorsList, lib.errorsList...)
+	p.warningsList = append(p.warningsList, lib.warningsList...)
+	p.imports = append(p.imports, lib.imports...)
 }
 
 func (p *astParser) loadImport() {
@@ -203,6 +343,7 @@ func (p *astParser) loadImport() {
 			p.addError(path.node.token32, "File '%s' not readable: %v", path.path, err)
 			continue
 		}
+		p.imports = append(p.imports, newManifestFile(path.path, buffer))
 		rawP := Parser{Buffer: string(buffer)}
 		err = rawP.Init()
 		if err != nil {
@@ -227,14 +368,15 @@ func (p *astParser) loadImport() {
 					Abbreviations: meta.Abbreviations{},
 				},
 			},
-			buffer:     rawP.buffer,
-			errorsList: []error{},
-			stack:      p.stack,
-			stdFuncs:   p.stdFuncs,
-			stdObjects: p.stdObjects,
-			stdTypes:   p.stdTypes,
-			isLibrary:  true,
-			fileName:   path.path,
+			buffer:       rawP.buffer,
+			errorsList:   []error{},
+			warningsList: []error{},
+			stack:        p.stack,
+			stdFuncs:     p.stdFuncs,
+			stdObjects:   p.stdObjects,
+			stdTypes:     p.stdTypes,
+			isLibrary:    true,
+			fileName:     path.path,
 		}
 		parser.parse()
 		p.loadLib(&parser)
@@ -247,17 +389,23 @@ func (p *astParser) ruleScriptRootHandler(node *node32) {
 		curNode = p.parseDirectives(curNode)
 	}
 	if !p.isLibrary {
-		p.stdFuncs = s.FuncsByVersion()[p.tree.LibVersion]
+		p.stdFuncs = extendFunctions(s.FuncsByVersion()[p.tree.LibVersion], p.builtins)
 		p.stdObjects = s.ObjectsByVersion()[p.tree.LibVersion]
 		p.stdTypes = s.DefaultTypes()[p.tree.LibVersion]
+		p.reserved = reservedIdentifiers(p.tree.LibVersion, p.tree.IsDApp())
 		p.loadBuildInVarsToStackByVersion()
 	}
 	p.loadImport()
-	var decls []ast.Node
+	var (
+		decls []ast.Node
+		r     reachability
+	)
 	for {
 		curNode = skipToNextRule(curNode)
 		if isRule(curNode, ruleDeclaration) {
+			r.visit(p, curNode)
 			expr, _ := p.ruleDeclarationHandler(curNode.up, true)
+			r.declared(curNode.up, expr)
 			decls = append(decls, expr...)
 			curNode = curNode.next
 		}
@@ -265,6 +413,11 @@ func (p *astParser) ruleScriptRootHandler(node *node32) {
 			break
 		}
 	}
+	if p.isLibraryCode() {
+		p.addError(curNode.token32, "Library can't contain expression, only declarations are allowed")
+		return
+	}
+	r.visit(p, curNode)
 	block, varType := p.ruleExprHandler(curNode)
 	if block == nil {
 		p.addError(curNode.token32, "No expression defined")
@@ -348,11 +501,14 @@ func (p *astParser) ruleDirectiveHandler(node *node32, directiveCnt map[string]i
 		dirValue := p.nodeValue(curNode)
 		switch dirValue {
 		case dappValueName:
+			p.kind = DAppScriptKind
 			p.tree.ContentType = ast.ContentTypeApplication
 		case expressionValueName:
+			p.kind = ExpressionScriptKind
 			p.tree.ContentType = ast.ContentTypeExpression
 		case libraryValueName:
-			break
+			p.kind = LibraryScriptKind
+			p.tree.ContentType = ast.ContentTypeApplication
 		default:
 			p.addError(dirNameNode.token32, "Illegal value '%s' of directive '%s'", dirValue, contentTypeDirectiveName)
 		}
@@ -487,20 +643,23 @@ func (p *astParser) ruleVariableHandler(node *node32) ([]ast.Node, []s.Type) {
 }
 
 func (p *astParser) simpleVariableDeclaration(node *node32) (ast.Node, s.Type) {
-	curNode := skipToNextRule(node.up)
+	nameNode := skipToNextRule(node.up)
 	// get Variable Name
-	varName := p.nodeValue(curNode)
-	curNode = skipToNextRule(curNode.next)
+	varName := p.nodeValue(nameNode)
+	curNode := skipToNextRule(nameNode.next)
 	expr, varType := p.ruleExprHandler(curNode)
 	if expr == nil {
 		return nil, nil
 	}
+	if !p.checkReservedIdentifier(nameNode.token32, varName) {
+		return nil, nil
+	}
 	if _, ok := p.stack.variable(varName); ok {
 		p.addError(curNode.token32, "Variable '%s' already declared", varName)
 		return nil, nil
 	}
 	expr = ast.NewAssignmentNode(varName, expr, nil)
-	p.stack.pushVariable(s.Variable{
+	p.declareVariable(nameNode.token32, s.Variable{
 		Name: varName,
 		Type: varType,
 	})
@@ -510,16 +669,21 @@ func (p *astParser) simpleVariableDeclaration(node *node32) (ast.Node, s.Type) {
 func (p *astParser) tupleRefDeclaration(node *node32) ([]ast.Node, []s.Type) {
 	curNode := skipToNextRule(node.up)
 	var varNames []string
+	var varTokens []token32
 	tupleRefNode := curNode.up
 	for {
 		tupleRefNode = skipToNextRule(tupleRefNode)
 		if tupleRefNode != nil && tupleRefNode.pegRule == ruleIdentifier {
 			name := p.nodeValue(tupleRefNode)
+			if !p.checkReservedIdentifier(tupleRefNode.token32, name) {
+				return nil, nil
+			}
 			if _, ok := p.stack.variable(name); ok {
 				p.addError(tupleRefNode.token32, "Variable '%s' already declared", name)
 				return nil, nil
 			}
 			varNames = append(varNames, name)
+			varTokens = append(varTokens, tupleRefNode.token32)
 			tupleRefNode = tupleRefNode.next
 		}
 		if tupleRefNode == nil {
@@ -583,7 +747,7 @@ func (p *astParser) tupleRefDeclaration(node *node32) ([]ast.Node, []s.Type) {
 		})
 		itemType := getTupleItemTypeByIndex(varType, i)
 		resTypes = append(resTypes, itemType)
-		p.stack.pushVariable(s.Variable{
+		p.declareVariable(varTokens[i], s.Variable{
 			Name: name,
 			Type: itemType,
 		})
@@ -619,7 +783,7 @@ func (p *astParser) ruleExprHandler(node *node32) (ast.Node, s.Type) {
 	}
 	for {
 		if !varType.Equal(s.BooleanType) {
-			p.addError(node.up.up.token32, "Unexpected type, required 'Boolean', but '%s' found", varType.String())
+			p.addError(node.up.up.token32, "Unexpected type, required 'Boolean', but '%s' found", varType)
 		}
 		curNode = skipToNextRule(curNode)
 		curNode = skipToNextRule(curNode.next) // skip orOp
@@ -648,7 +812,7 @@ func (p *astParser) ruleAndOpAtomHandler(node *node32) (ast.Node, s.Type) {
 	}
 	for {
 		if !varType.Equal(s.BooleanType) {
-			p.addError(node.up.up.token32, "Unexpected type, required 'Boolean', but '%s' found", varType.String())
+			p.addError(node.up.up.token32, "Unexpected type, required 'Boolean', but '%s' found", varType)
 		}
 		curNode = skipToNextRule(curNode)
 		curNode = skipToNextRule(curNode.next) // skip andOp
@@ -689,7 +853,7 @@ func (p *astParser) ruleEqualityGroupOpAtomHandler(node *node32) (ast.Node, s.Ty
 			return nil, nil
 		}
 		if !nextExprVarType.EqualWithEntry(varType) && !varType.EqualWithEntry(nextExprVarType) {
-			p.addError(curNode.token32, "Unexpected type, required '%s', but '%s' found", varType.String(), nextExprVarType.String())
+			p.addError(curNode.token32, "Unexpected type, required '%s', but '%s' found", varType, nextExprVarType)
 		}
 		expr = ast.NewFunctionCallNode(funcId, []ast.Node{expr, nextExpr})
 		varType = s.BooleanType
@@ -712,7 +876,7 @@ func (p *astParser) ruleCompareGroupOpAtomHandler(node *node32) (ast.Node, s.Typ
 		return expr, varType
 	}
 	if !s.BigIntType.Equal(varType) && !s.IntType.Equal(varType) {
-		p.addError(node.up.up.token32, "Unexpected type, required 'BigInt' or 'Int', but '%s' found", varType.String())
+		p.addError(node.up.up.token32, "Unexpected type, required 'BigInt' or 'Int', but '%s' found", varType)
 	}
 	for {
 		curNode = skipToNextRule(curNode)
@@ -728,14 +892,14 @@ func (p *astParser) ruleCompareGroupOpAtomHandler(node *node32) (ast.Node, s.Typ
 				gltFun = "319"
 				gleFun = "320"
 			} else {
-				p.addError(curNode.token32, "Unexpected type, required 'BigInt', but '%s' found", nextExprVarType.String())
+				p.addError(curNode.token32, "Unexpected type, required 'BigInt', but '%s' found", nextExprVarType)
 			}
 		} else if s.IntType.Equal(varType) {
 			if s.IntType.Equal(nextExprVarType) {
 				gltFun = "102"
 				gleFun = "103"
 			} else {
-				p.addError(curNode.token32, "Unexpected type, required 'Int', but '%s' found", nextExprVarType.String())
+				p.addError(curNode.token32, "Unexpected type, required 'Int', but '%s' found", nextExprVarType)
 			}
 		}
 		switch operator {
@@ -784,7 +948,7 @@ func (p *astParser) ruleSumGroupOpAtomHandler(node *node32) (ast.Node, s.Type) {
 			} else if varType.Equal(s.ByteVectorType) && nextExprVarType.Equal(s.ByteVectorType) {
 				funcId = "203"
 			} else {
-				p.addError(node.token32, "Unexpected types for '+' operator '%s' and '%s'", varType.String(), nextExprVarType.String())
+				p.addError(node.token32, "Unexpected types for '+' operator '%s' and '%s'", varType, nextExprVarType)
 			}
 		case ruleSubOp:
 			if varType.Equal(s.IntType) && nextExprVarType.Equal(s.IntType) {
@@ -792,9 +956,12 @@ func (p *astParser) ruleSumGroupOpAtomHandler(node *node32) (ast.Node, s.Type) {
 			} else if varType.Equal(s.BigIntType) && nextExprVarType.Equal(s.BigIntType) {
 				funcId = "312"
 			} else {
-				p.addError(node.token32, "Unexpected types for '-' operator '%s' and '%s'", varType.String(), nextExprVarType.String())
+				p.addError(node.token32, "Unexpected types for '-' operator '%s' and '%s'", varType, nextExprVarType)
 			}
 		}
+		if funcId == sumFunctionID || funcId == subFunctionID {
+			p.checkIntOverflow(node, funcId, expr, nextExpr)
+		}
 		expr = ast.NewFunctionCallNode(ast.NativeFunction(funcId), []ast.Node{expr, nextExpr})
 		curNode = curNode.next
 		if curNode == nil {
@@ -933,6 +1100,7 @@ func (p *astParser) ruleMultGroupOpAtomHandler(node *node32) (ast.Node, s.Type)
 	}
 	for {
 		curNode = skipToNextRule(curNode)
+		operatorNode := curNode
 		operator := curNode.up.pegRule
 		curNode = skipToNextRule(curNode.next)
 		nextExpr, nextExprVarType := p.ruleAtomExprHandler(curNode)
@@ -944,7 +1112,7 @@ func (p *astParser) ruleMultGroupOpAtomHandler(node *node32) (ast.Node, s.Type)
 			} else if varType.Equal(s.BigIntType) && nextExprVarType.Equal(s.BigIntType) {
 				funcId = "313"
 			} else {
-				p.addError(node.token32, "Unexpected types for '*' operator '%s' and '%s'", varType.String(), nextExprVarType.String())
+				p.addError(node.token32, "Unexpected types for '*' operator '%s' and '%s'", varType, nextExprVarType)
 			}
 		case ruleDivOp:
 			if varType.Equal(s.IntType) && nextExprVarType.Equal(s.IntType) {
@@ -952,7 +1120,7 @@ func (p *astParser) ruleMultGroupOpAtomHandler(node *node32) (ast.Node, s.Type)
 			} else if varType.Equal(s.BigIntType) && nextExprVarType.Equal(s.BigIntType) {
 				funcId = "314"
 			} else {
-				p.addError(node.token32, "Unexpected types for '/' operator '%s' and '%s'", varType.String(), nextExprVarType.String())
+				p.addError(node.token32, "Unexpected types for '/' operator '%s' and '%s'", varType, nextExprVarType)
 			}
 		case ruleModOp:
 			if varType.Equal(s.IntType) && nextExprVarType.Equal(s.IntType) {
@@ -960,9 +1128,15 @@ func (p *astParser) ruleMultGroupOpAtomHandler(node *node32) (ast.Node, s.Type)
 			} else if varType.Equal(s.BigIntType) && nextExprVarType.Equal(s.BigIntType) {
 				funcId = "315"
 			} else {
-				p.addError(node.token32, "Unexpected types for '%%' operator '%s' and '%s'", varType.String(), nextExprVarType.String())
+				p.addError(node.token32, "Unexpected types for '%%' operator '%s' and '%s'", varType, nextExprVarType)
 			}
 		}
+		switch funcId {
+		case mulFunctionID:
+			p.checkIntOverflow(node, funcId, expr, nextExpr)
+		case divFunctionID, modFunctionID, bigIntDivFunctionID, bigIntModFunctionID:
+			p.checkDivisionByZero(operatorNode, nextExpr)
+		}
 		expr = ast.NewFunctionCallNode(ast.NativeFunction(funcId), []ast.Node{expr, nextExpr})
 		curNode = curNode.next
 		if curNode == nil {
@@ -1005,17 +1179,17 @@ func (p *astParser) ruleAtomExprHandler(node *node32) (ast.Node, s.Type) {
 		} else if varType.Equal(s.BigIntType) {
 			expr = ast.NewFunctionCallNode(ast.NativeFunction("318"), []ast.Node{expr})
 		} else {
-			p.addError(curNode.token32, "Unexpected types for unary '-' operator, required 'Int' or 'BigInt', but '%s' found", varType.String())
+			p.addError(curNode.token32, "Unexpected types for unary '-' operator, required 'Int' or 'BigInt', but '%s' found", varType)
 		}
 	case ruleNotOp:
 		if varType.Equal(s.BooleanType) {
 			expr = ast.NewFunctionCallNode(ast.UserFunction("!"), []ast.Node{expr})
 		} else {
-			p.addError(curNode.token32, "Unexpected types for unary '!' operator, required 'Boolean', but '%s' found", varType.String())
+			p.addError(curNode.token32, "Unexpected types for unary '!' operator, required 'Boolean', but '%s' found", varType)
 		}
 	case rulePositiveOp:
 		if !varType.Equal(s.IntType) && !varType.Equal(s.BigIntType) {
-			p.addError(curNode.token32, "Unexpected types for unary '+' operator, required 'Int' or 'BigInt', but %s found", varType.String())
+			p.addError(curNode.token32, "Unexpected types for unary '+' operator, required 'Int' or 'BigInt', but %s found", varType)
 		}
 	}
 	return expr, varType
@@ -1093,6 +1267,7 @@ func (p *astParser) ruleStringHandler(node *node32) (ast.Node, s.Type) {
 		}
 		curNode = curNode.next
 	}
+	p.checkLiteralSize(node.token32, "String", len(res))
 	return ast.NewStringNode(res), s.StringType
 }
 
@@ -1117,6 +1292,7 @@ func (p *astParser) ruleByteVectorHandler(node *node32) (ast.Node, s.Type) {
 	if err != nil {
 		p.addError(node.token32, "Failed to parse 'ByteVector' value: %v", err)
 	}
+	p.checkLiteralSize(node.token32, "ByteVector", len(value))
 	return ast.NewBytesNode(value), s.ByteVectorType
 }
 
@@ -1193,6 +1369,7 @@ func (p *astParser) ruleIfWithErrorHandler(node *node32) (ast.Node, s.Type) {
 		p.addError(curNode.token32, "Expression must be 'Boolean' but got '%s'", condType)
 	}
 	curNode = skipToNextRule(curNode.next)
+	thenToken := curNode.token32
 	var thenExpr ast.Node
 	var thenType s.Type
 	switch curNode.pegRule {
@@ -1202,6 +1379,7 @@ func (p *astParser) ruleIfWithErrorHandler(node *node32) (ast.Node, s.Type) {
 		thenExpr, thenType = p.ruleBlockHandler(curNode)
 	}
 	curNode = skipToNextRule(curNode.next)
+	elseToken := curNode.token32
 	var elseExpr ast.Node
 	var elseType s.Type
 	switch curNode.pegRule {
@@ -1215,6 +1393,18 @@ func (p *astParser) ruleIfWithErrorHandler(node *node32) (ast.Node, s.Type) {
 		return nil, nil
 	}
 	resType = s.JoinTypes(thenType, elseType)
+	if u, ok := resType.(s.UnionType); ok {
+		if len(u.Types) == 0 { // both branches throw
+			return ast.NewConditionalNode(cond, thenExpr, elseExpr), s.ThrowType
+		}
+		if !thenType.Equal(s.ThrowType) && !elseType.Equal(s.ThrowType) {
+			// The type of branches can't be unified, the union of them is used where a single type is required.
+			u.Origin = fmt.Sprintf("the types of if-else branches at %s can't be unified: '%s' at %s and '%s' at %s",
+				p.tokenPosition(node.token32), thenType, p.tokenPosition(thenToken), elseType, p.tokenPosition(elseToken),
+			)
+			resType = u
+		}
+	}
 	return ast.NewConditionalNode(cond, thenExpr, elseExpr), resType
 }
 
@@ -1402,7 +1592,10 @@ func (p *astParser) ruleParExprHandler(node *node32) (ast.Node, s.Type) {
 	return p.ruleExprHandler(curNode)
 }
 
-func listArgsToString(l []s.Type) string {
+// argsTypesList formats the types of the function arguments in error messages.
+type argsTypesList []s.Type
+
+func (l argsTypesList) String() string {
 	res := ""
 	for i, t := range l {
 		if t == nil {
@@ -1417,6 +1610,17 @@ func listArgsToString(l []s.Type) string {
 	return res
 }
 
+// addDeprecationWarning reports the usage of the deprecated function along with its replacement.
+func (p *astParser) addDeprecationWarning(token token32, funcName string, d s.Deprecation) {
+	if d.Removed != 0 {
+		p.addWarning(token, "Function '%s' is deprecated since STDLIB_VERSION %d and removed in STDLIB_VERSION %d, "+
+			"use '%s' instead", funcName, d.Since, d.Removed, d.Replacement)
+		return
+	}
+	p.addWarning(token, "Function '%s' is deprecated since STDLIB_VERSION %d, use '%s' instead",
+		funcName, d.Since, d.Replacement)
+}
+
 func (p *astParser) ruleFunctionCallHandler(node *node32, firstArg ast.Node, firstArgType s.Type) (ast.Node, s.Type) {
 	curNode := node.up
 	funcName := p.nodeValue(curNode)
@@ -1428,14 +1632,31 @@ func (p *astParser) ruleFunctionCallHandler(node *node32, firstArg ast.Node, fir
 		argsNodes = append([]ast.Node{firstArg}, argsNodes...)
 		argsTypes = append([]s.Type{firstArgType}, argsTypes...)
 	}
+	for _, t := range argsTypes {
+		if t == nil { // Error in argument is already reported, overloads can't be resolved without its type
+			return nil, nil
+		}
+	}
 	var funcSign s.FunctionParams
 	funcSign, ok := p.stack.function(funcName)
 	if !ok {
 		funcSign, ok = p.stdFuncs.Get(funcName, argsTypes)
-		if !ok {
+		if ok {
+			p.recordInvokeCallSite(nameNode.token32, funcSign.ID, argsNodes)
+			if funcName == fractionFunctionName {
+				p.checkFractionDivisor(nameNode, argsNodes)
+			}
+			if d, deprecated := p.stdFuncs.IsDeprecated(funcName); deprecated {
+				p.addDeprecationWarning(nameNode.token32, funcName, d)
+			}
+		} else {
 			funcSign, ok = p.stdObjects.GetConstruct(funcName, argsTypes)
 			if !ok {
-				p.addError(nameNode.token32, "Undefined function '%s(%s)'", funcName, listArgsToString(argsTypes))
+				if p.checkRecursiveCall(funcName, nameNode.token32) {
+					return nil, nil
+				}
+				p.addError(nameNode.token32, "Undefined function '%s(%s)'", funcName, argsTypesList(argsTypes))
+				p.recordUndefinedCall(funcName, nameNode.token32)
 				return nil, nil
 			}
 		}
@@ -1444,6 +1665,7 @@ func (p *astParser) ruleFunctionCallHandler(node *node32, firstArg ast.Node, fir
 		}
 		return ast.NewFunctionCallNode(funcSign.ID, argsNodes), funcSign.ReturnType
 	}
+	p.recordUserCall(funcName)
 	if len(argsNodes) != len(funcSign.Arguments) {
 		p.addError(curNode.token32, "Function '%s' requires %d arguments, but %d are provided", funcName, len(funcSign.Arguments), len(argsNodes))
 		return nil, funcSign.ReturnType
@@ -1500,11 +1722,16 @@ func (p *astParser) ruleIdentifierAccessHandler(node *node32, obj ast.Node, objT
 func (p *astParser) ruleBlockHandler(node *node32) (ast.Node, s.Type) {
 	p.stack.addFrame()
 	curNode := node.up
-	var decls []ast.Node
+	var (
+		decls []ast.Node
+		r     reachability
+	)
 	for {
 		curNode = skipToNextRule(curNode)
 		if isRule(curNode, ruleDeclaration) {
+			r.visit(p, curNode)
 			expr, _ := p.ruleDeclarationHandler(curNode.up, true)
+			r.declared(curNode.up, expr)
 			decls = append(decls, expr...)
 			curNode = curNode.next
 		}
@@ -1512,6 +1739,7 @@ func (p *astParser) ruleBlockHandler(node *node32) (ast.Node, s.Type) {
 			break
 		}
 	}
+	r.visit(p, curNode)
 	block, varType := p.ruleExprHandler(curNode)
 	expr := block
 	for i := len(decls) - 1; i >= 0; i-- {
@@ -1536,12 +1764,15 @@ func (p *astParser) ruleFuncHandler(node *node32) (ast.Node, s.Type, []s.Type) {
 	p.stack.addFrame()
 	curNode := skipToNextRule(node.up)
 	funcName := p.nodeValue(curNode)
+	p.checkReservedIdentifier(curNode.token32, funcName)
 	if _, ok := p.stack.function(funcName); ok {
 		p.addError(curNode.token32, "Function '%s' already exists", funcName)
 	}
 	if ok := p.stdFuncs.Check(funcName); ok {
 		p.addError(curNode.token32, "Function '%s' exists in standard library", funcName)
 	}
+	p.beginFuncDeclaration(funcName, curNode.token32)
+	defer p.endFuncDeclaration()
 	curNode = curNode.next
 	var argsNode *node32
 	for {
@@ -1616,7 +1847,9 @@ func (p *astParser) ruleFuncArgHandler(node *node32) (string, s.Type) {
 	if argType == nil {
 		return "", nil
 	}
-	p.stack.pushVariable(s.Variable{
+	p.checkReservedIdentifier(node.up.token32, argName)
+	p.checkShadowing(node.up.token32, argName)
+	p.declareVariable(node.up.token32, s.Variable{
 		Name: argName,
 		Type: argType,
 	})
@@ -1707,51 +1940,79 @@ func (p *astParser) parseAnnotatedFunc(node *node32) {
 	}
 }
 
-func (p *astParser) loadMeta(name string, argsTypes []s.Type) error {
+// loadMeta adds the meta information of the callable function to the tree. Arguments of types that can't be passed
+// in the invoke transaction are reported at the positions of the corresponding argument nodes. The error is returned
+// if there is no node for such argument, so the caller can report it at the position of the function.
+func (p *astParser) loadMeta(name string, argsTypes []s.Type, argsNodes []*node32) error {
 	if int(p.tree.LibVersion) <= 3 {
 		p.tree.Meta.Version = 1
 	} else {
 		p.tree.Meta.Version = 2
 	}
-	switch p.tree.LibVersion {
-	case ast.LibV1, ast.LibV2, ast.LibV3, ast.LibV4, ast.LibV5:
-		return p.loadMetaBeforeV6(name, argsTypes)
-	case ast.LibV6, ast.LibV7, ast.LibV8:
-		return p.loadMetaV6(name, argsTypes)
+	metaType := metaTypeBeforeV6
+	if p.tree.LibVersion >= ast.LibV6 {
+		metaType = metaTypeV6
 	}
-	return nil
-}
-
-func (p *astParser) loadMetaV6(name string, argsTypes []s.Type) error {
 	res := meta.Function{
 		Name:      name,
 		Arguments: []meta.Type{},
 	}
-	for _, t := range argsTypes {
-		switch T := t.(type) {
-		case s.SimpleType:
-			metaT, err := getMetaType(t)
-			if err != nil {
-				return err
-			}
-			res.Arguments = append(res.Arguments, metaT)
-		case s.ListType:
-			if _, ok := T.Type.(s.SimpleType); !ok {
-				return errors.Errorf("Unexpected type in callable args '%s'", t.String())
-			}
-			metaT, err := getMetaType(T.Type)
-			if err != nil {
+	ok := true
+	for i, t := range argsTypes {
+		metaT, err := metaType(t)
+		if err != nil {
+			if i >= len(argsNodes) {
 				return err
 			}
-			res.Arguments = append(res.Arguments, meta.ListType{Inner: metaT})
-		default:
-			return errors.Errorf("Unexpected type in callable args '%s'", t.String())
+			ok = false
+			p.addError(argsNodes[i].token32, "%v", err.Error())
+			continue
 		}
+		res.Arguments = append(res.Arguments, metaT)
+	}
+	if ok {
+		p.tree.Meta.Functions = append(p.tree.Meta.Functions, res)
 	}
-	p.tree.Meta.Functions = append(p.tree.Meta.Functions, res)
 	return nil
 }
 
+// funcArgNodes returns the nodes of arguments of the function declaration node.
+func funcArgNodes(funcNode *node32) []*node32 {
+	var res []*node32
+	for n := funcNode.up; n != nil; n = n.next {
+		if isRule(n, ruleFuncArgSeq) {
+			for seq := n; seq != nil; {
+				res = append(res, seq.up)
+				next := skipToNextRule(seq.up.next)
+				for next != nil && !isRule(next, ruleFuncArgSeq) {
+					next = skipToNextRule(next.next)
+				}
+				seq = next
+			}
+			break
+		}
+	}
+	return res
+}
+
+func metaTypeV6(t s.Type) (meta.Type, error) {
+	switch T := t.(type) {
+	case s.SimpleType:
+		return getMetaType(t)
+	case s.ListType:
+		if _, ok := T.Type.(s.SimpleType); !ok {
+			return nil, errors.Errorf("Unexpected type in callable args '%s'", t.String())
+		}
+		metaT, err := getMetaType(T.Type)
+		if err != nil {
+			return nil, err
+		}
+		return meta.ListType{Inner: metaT}, nil
+	default:
+		return nil, errors.Errorf("Unexpected type in callable args '%s'", t.String())
+	}
+}
+
 func getMetaType(t s.Type) (meta.SimpleType, error) {
 	if simpleType, ok := t.(s.SimpleType); ok {
 		switch simpleType.Type {
@@ -1768,54 +2029,44 @@ func getMetaType(t s.Type) (meta.SimpleType, error) {
 	return meta.SimpleType(byte(0)), errors.Errorf("Unexpected type in callable args '%s'", t.String())
 }
 
-func (p *astParser) loadMetaBeforeV6(name string, argsTypes []s.Type) error {
-	res := meta.Function{
-		Name:      name,
-		Arguments: []meta.Type{},
+func getMetaUnionType(t s.UnionType) (meta.UnionType, error) {
+	var res []meta.SimpleType
+	for _, unionT := range t.Types {
+		metaT, err := getMetaType(unionT)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, metaT)
 	}
-	for _, t := range argsTypes {
-		switch T := t.(type) {
+	return res, nil
+}
+
+func metaTypeBeforeV6(t s.Type) (meta.Type, error) {
+	switch T := t.(type) {
+	case s.SimpleType:
+		return getMetaType(t)
+	case s.ListType:
+		switch u := T.Type.(type) {
 		case s.SimpleType:
-			metaT, err := getMetaType(t)
+			metaT, err := getMetaType(T.Type)
 			if err != nil {
-				return err
-			}
-			res.Arguments = append(res.Arguments, metaT)
-		case s.ListType:
-			switch u := T.Type.(type) {
-			case s.SimpleType:
-				metaT, err := getMetaType(T.Type)
-				if err != nil {
-					return err
-				}
-				res.Arguments = append(res.Arguments, meta.ListType{Inner: metaT})
-			case s.UnionType:
-				var resType []meta.SimpleType
-				for _, unionT := range u.Types {
-					metaT, err := getMetaType(unionT)
-					if err != nil {
-						return err
-					}
-					resType = append(resType, metaT)
-				}
-				res.Arguments = append(res.Arguments, meta.ListType{Inner: meta.UnionType(resType)})
+				return nil, err
 			}
+			return meta.ListType{Inner: metaT}, nil
 		case s.UnionType:
-			var resType []meta.SimpleType
-			for _, unionT := range T.Types {
-				metaT, err := getMetaType(unionT)
-				if err != nil {
-					return err
-				}
-				resType = append(resType, metaT)
+			metaT, err := getMetaUnionType(u)
+			if err != nil {
+				return nil, err
 			}
-			res.Arguments = append(res.Arguments, meta.UnionType(resType))
+			return meta.ListType{Inner: metaT}, nil
 		default:
-			return errors.Errorf("Unexpected type in callable args '%s'", t.String())
+			return nil, errors.Errorf("Unexpected type in callable args '%s'", t.String())
 		}
+	case s.UnionType:
+		return getMetaUnionType(T)
+	default:
+		return nil, errors.Errorf("Unexpected type in callable args '%s'", t.String())
 	}
-	p.tree.Meta.Functions = append(p.tree.Meta.Functions, res)
-	return nil
 }
 
 func (p *astParser) ruleAnnotatedFunc(node *node32) {
@@ -1826,6 +2077,7 @@ func (p *astParser) ruleAnnotatedFunc(node *node32) {
 		return
 	}
 	curNode = skipToNextRule(curNode.next)
+	firstCallSite := len(p.invokeCallSites)
 	expr, retType, types := p.ruleFuncHandler(curNode)
 	if expr == nil || retType == nil {
 		return
@@ -1834,9 +2086,11 @@ func (p *astParser) ruleAnnotatedFunc(node *node32) {
 	f.InvocationParameter = annotationParameter
 	switch annotation {
 	case "Callable":
+		for i := firstCallSite; i < len(p.invokeCallSites); i++ {
+			p.invokeCallSites[i].Callable = f.Name
+		}
 		p.tree.Functions = append(p.tree.Functions, expr)
-		err := p.loadMeta(f.Name, types)
-		if err != nil {
+		if err := p.loadMeta(f.Name, types, funcArgNodes(curNode)); err != nil {
 			p.addError(curNode.token32, "%v", err.Error())
 		}
 		switch p.tree.LibVersion {
@@ -1853,6 +2107,7 @@ func (p *astParser) ruleAnnotatedFunc(node *node32) {
 				p.addError(curNode.token32, "CallableFunc must return %s, but return %s", s.CallableRetV5.String(), retType.String())
 			}
 		}
+		p.checkCallableActions(curNode, f)
 	case "Verifier":
 		if p.tree.Verifier != nil {
 			p.addError(curNode.token32, "More than one Verifier")
@@ -1878,6 +2133,7 @@ func (p *astParser) ruleAnnotationSeqHandler(node *node32) (string, string) {
 	}
 	annotationNode = skipToNextRule(annotationNode)
 	annotationNode = annotationNode.next.up
+	varToken := annotationNode.token32
 	varName := p.nodeValue(annotationNode)
 	annotationNode = annotationNode.next
 	if annotationNode != nil {
@@ -1887,17 +2143,20 @@ func (p *astParser) ruleAnnotationSeqHandler(node *node32) (string, string) {
 	if curNode != nil {
 		p.addError(curNode.token32, "More then one annotation")
 	}
+	p.checkReservedIdentifier(varToken, varName)
 
 	switch name {
 	case "Callable":
-		p.stack.pushVariable(s.Variable{
+		p.checkShadowing(varToken, varName)
+		p.declareVariable(varToken, s.Variable{
 			Name: varName,
 			Type: s.SimpleType{Type: "Invocation"},
 		})
 	case "Verifier":
 		txType := p.stdTypes["Transaction"].(s.UnionType)
 		txType.AppendType(s.SimpleType{Type: "Order"})
-		p.stack.pushVariable(s.Variable{
+		p.checkShadowing(varToken, varName)
+		p.declareVariable(varToken, s.Variable{
 			Name: varName,
 			Type: txType,
 		})
@@ -2048,7 +2307,7 @@ func (p *astParser) ruleValuePatternHandler(node *node32, matchName string, poss
 		if _, ok := p.stack.variable(name); ok {
 			p.addError(nameNode.token32, "Variable '%s' already exists", name)
 		}
-		p.stack.pushVariable(s.Variable{
+		p.declareVariable(nameNode.token32, s.Variable{
 			Name: name,
 			Type: t,
 		})
@@ -2185,7 +2444,7 @@ func (p *astParser) ruleObjectFieldsPatternHandler(node *node32, matchName strin
 			p.addError(curNode.token32, "Variable '%s' already exists", name)
 			return nil, nil, curNode
 		}
-		p.stack.pushVariable(s.Variable{
+		p.declareVariable(curNode.token32, s.Variable{
 			Name: name,
 			Type: t,
 		})
@@ -2310,7 +2569,8 @@ func (p *astParser) ruleTupleValuesPatternHandler(node *node32, matchName string
 		expr = ast.NewFunctionCallNode(ast.NativeFunction("1"), []ast.Node{ast.NewPropertyNode("_"+strconv.Itoa(cnt+1), ast.NewReferenceNode(matchName)), ast.NewStringNode(varType.String())})
 		if nameNode.pegRule != rulePlaceholder {
 			name := p.nodeValue(nameNode)
-			p.stack.pushVariable(s.Variable{
+			p.checkShadowing(nameNode.token32, name)
+			p.declareVariable(nameNode.token32, s.Variable{
 				Name: name,
 				Type: varType,
 			})
@@ -2328,7 +2588,8 @@ func (p *astParser) ruleTupleValuesPatternHandler(node *node32, matchName string
 			}
 		}
 
-		p.stack.pushVariable(s.Variable{
+		p.checkShadowing(curNode.token32, name)
+		p.declareVariable(curNode.token32, s.Variable{
 			Name: name,
 			Type: varType,
 		})
@@ -2379,9 +2640,14 @@ func (p *astParser) ruleFoldMacroHandler(node *node32) (ast.Node, s.Type) {
 	funcName := p.nodeValue(curNode)
 	funcSign, ok := p.stack.function(funcName)
 	if !ok {
+		if p.checkRecursiveCall(funcName, curNode.token32) {
+			return nil, nil
+		}
 		p.addError(curNode.token32, "Undefined function '%s'", funcName)
+		p.recordUndefinedCall(funcName, curNode.token32)
 		return nil, nil
 	}
+	p.recordUserCall(funcName)
 	if len(funcSign.Arguments) != 2 {
 		p.addError(curNode.token32, "Function '%s' must have 2 arguments", funcName)
 	} else {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"embed"
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -17,6 +19,7 @@ import (
 
 	"github.com/wavesplatform/gowaves/pkg/client"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	s "github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
 	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
 )
 
@@ -232,7 +235,7 @@ let b = a == 10`,
 		{`
 let a = if true then 1 else unit
 let b = a >= 10`,
-			true, "(6:9, 6:10): Unexpected type, required 'BigInt' or 'Int', but 'Int|Unit' found"},
+			true, "(6:9, 6:10): Unexpected type, required 'BigInt' or 'Int', but 'Int|Unit' found, the types of if-else branches at 5:9 can't be unified: 'Int' at 5:22 and 'Unit' at 5:29"},
 		{`let a = [1, 2] :+ "a"`, false, "BgICCAIBAAFhCQDNCAIJAMwIAgABCQDMCAIAAgUDbmlsAgFhAAAmqjlN"},
 		{`let a = [1, 2] ++ nil`, false, "BgICCAIBAAFhCQDOCAIJAMwIAgABCQDMCAIAAgUDbmlsBQNuaWwAAOmqp9I="},
 		{`let a = "a" :: [1, 2]`, false, "BgICCAIBAAFhCQDMCAICAWEJAMwIAgABCQDMCAIAAgUDbmlsAADcsh9u"},
@@ -648,7 +651,7 @@ func test(a: Int|String) = {
 	([StringEntry("a", "a")], unit)
 }
 `,
-			true, "(7:1, 10:0): Unexpected type in callable args 'Int|String'"},
+			true, "(7:11, 7:24): Unexpected type in callable args 'Int|String'"},
 		{`
 {-# STDLIB_VERSION 6 #-}
 {-# CONTENT_TYPE DAPP #-}
@@ -659,7 +662,7 @@ func test(a: List[Int|String]) = {
 	([StringEntry("a", "a")], unit)
 }
 `,
-			true, "(7:1, 10:0): Unexpected type in callable args 'List[Int|String]'"},
+			true, "(7:11, 7:30): Unexpected type in callable args 'List[Int|String]'"},
 		{`
 {-# STDLIB_VERSION 6 #-}
 {-# CONTENT_TYPE DAPP #-}
@@ -703,7 +706,7 @@ func test(a: Int|List[Int]) = {
 	([StringEntry("a", "a")], unit)
 }
 `,
-			true, "(7:1, 10:0): Unexpected type in callable args 'List[Int]'"},
+			true, "(7:11, 7:27): Unexpected type in callable args 'List[Int]'"},
 		{`
 {-# STDLIB_VERSION 5 #-}
 {-# CONTENT_TYPE DAPP #-}
@@ -751,6 +754,764 @@ func fn() = nil
 	}
 }
 
+func TestCallableArgumentTypes(t *testing.T) {
+	for i, test := range []struct {
+		version  int
+		args     string
+		errorMsg string
+	}{
+		{6, "a: Int, b: ByteVector, c: Boolean, d: String, e: List[Int]", ""},
+		{5, "a: Int|String, b: List[ByteVector|Boolean]", ""},
+		{6, "a: Int, b: (Int, String)", "(7:19, 7:35): Unexpected type in callable args '(Int, String)'"},
+		{5, "a: List[List[Int]]", "(7:11, 7:29): Unexpected type in callable args 'List[List[Int]]'"},
+		{6, "a: Int, b: Address", "(7:19, 7:29): Unexpected type in callable args 'Address'"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := fmt.Sprintf(`
+{-# STDLIB_VERSION %d #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func test(%s) = nil
+`, test.version, test.args)
+			tree, errs := CompileToTree(code)
+			if test.errorMsg == "" {
+				require.Empty(t, errs)
+				require.Len(t, tree.Meta.Functions, 1)
+				assert.Len(t, tree.Meta.Functions[0].Arguments, strings.Count(test.args, ":"))
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.EqualError(t, errs[0], test.errorMsg)
+		})
+	}
+}
+
+func TestLoadMetaWithoutArgumentNodes(t *testing.T) {
+	p := newASTParser(nil, nil)
+	p.tree.LibVersion = ast.LibV6
+	err := p.loadMeta("test", []s.Type{s.IntType, s.SimpleType{Type: "Address"}}, nil)
+	assert.EqualError(t, err, "Unexpected type in callable args 'Address'")
+	assert.Empty(t, p.tree.Meta.Functions)
+	assert.Empty(t, p.errorsList)
+}
+
+func TestCallableActionsLimit(t *testing.T) {
+	for i, test := range []struct {
+		code     string
+		errorMsg string
+	}{
+		{`
+{-# STDLIB_VERSION 4 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func test() = [1, 2]
+`, "(7:1, 8:0): CallableFunc must return List[BinaryEntry|BooleanEntry|Burn|DeleteEntry|IntegerEntry|Issue|Reissue|ScriptTransfer|SponsorFee|StringEntry],but return List[Int]"},
+		{`
+{-# STDLIB_VERSION 4 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func test() = {
+	let t = ScriptTransfer(i.caller, 1, unit)
+	[t, t, t, t, t, t, t, t, t, t, t]
+}
+`, ""},
+		{`
+{-# STDLIB_VERSION 4 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func test() = {
+	let t = ScriptTransfer(i.caller, 1, unit)
+	[ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit),
+	 ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit),
+	 ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit),
+	 ScriptTransfer(i.caller, 1, unit)] :+ t
+}
+`, ""},
+		{`
+{-# STDLIB_VERSION 4 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func test() = if (i.caller == this) then [IntegerEntry("a", 1)] else
+	[Issue("A", "", 1, 0, false), Issue("B", "", 1, 0, false), Issue("C", "", 1, 0, false),
+	 Issue("D", "", 1, 0, false), Issue("E", "", 1, 0, false), Issue("F", "", 1, 0, false)] ++
+	[ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit),
+	 ScriptTransfer(i.caller, 1, unit), ScriptTransfer(i.caller, 1, unit)]
+`, "(7:1, 12:0): Callable 'test' statically exceeds actions limit: number of actions (11) produced by script is more than allowed 10"},
+		{`
+{-# STDLIB_VERSION 5 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func test() = ([IntegerEntry("a", 1), IntegerEntry("b", 2)], 42)
+`, ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			_, errs := CompileToTree(test.code)
+			if test.errorMsg == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.NotEmpty(t, errs)
+			assert.Equal(t, test.errorMsg, errs[0].Error())
+		})
+	}
+}
+
+func TestCallableActionsCounts(t *testing.T) {
+	const code = `
+{-# STDLIB_VERSION 5 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func fixed() = [ScriptTransfer(i.caller, 1, unit), IntegerEntry("a", 1)]
+
+@Callable(i)
+func branches() = {
+	let t = ScriptTransfer(i.caller, 1, unit)
+	if (i.caller == this) then [t] else [t, t] :+ t
+}
+
+@Callable(i)
+func dynamic(n: Int) = {
+	let entries = if (n > 0) then [IntegerEntry("a", n)] else nil
+	entries ++ [IntegerEntry("b", n)]
+}
+`
+	tree, errs, warnings := CompileToTreeWithOptions(code, Options{})
+	require.Empty(t, errs)
+	require.Empty(t, warnings)
+	counts := CallableActionsCounts(tree)
+	require.Len(t, counts, 3)
+	assert.Equal(t, "2", counts["fixed"].String())
+	assert.Equal(t, "1..3", counts["branches"].String())
+	assert.Equal(t, "dynamic", counts["dynamic"].String())
+}
+
+func TestCallableActionsLimitWarning(t *testing.T) {
+	for i, test := range []struct {
+		n       int
+		warning string
+	}{
+		{maxCallableActions(ast.LibV4), ""},
+		{maxCallableActions(ast.LibV4) + 1, "(7:1, 11:0): Callable 'test' may produce up to 111 actions, more than allowed 110"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 4 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func test() = {
+	let t = IntegerEntry("a", 1)
+	[t` + strings.Repeat(", t", test.n-1) + `]
+}
+`
+			_, errs, warnings := CompileToTreeWithWarnings(code)
+			require.Empty(t, errs)
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Equal(t, test.warning, warnings[0].Error())
+			}
+		})
+	}
+}
+
+func TestCallablesLimit(t *testing.T) {
+	const code = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func a() = []
+
+@Callable(i)
+func b() = []
+
+@Callable(i)
+func c() = []
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)
+`
+	for i, test := range []struct {
+		limit    int
+		errorMsg string
+	}{
+		{0, ""},
+		{3, ""},
+		{10, ""},
+		{2, "(12:1, 13:0): Number of callable functions 3 exceeds the limit 2"},
+		{1, "(9:1, 10:0): Number of callable functions 3 exceeds the limit 1"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			_, errs, _ := CompileToTreeWithOptions(code, Options{MaxCallables: test.limit})
+			if test.errorMsg == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.EqualError(t, errs[0], test.errorMsg)
+		})
+	}
+}
+
+func TestIfElseBranchTypes(t *testing.T) {
+	for i, test := range []struct {
+		code     string
+		errorMsg string
+	}{
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let t = if (height > 0) then (1, "a") else (1, "a", true)
+match t {
+	case p: (Int, String) => p._2 == "a"
+	case q: (Int, String, Boolean) => q._3
+}
+`, ""},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let t = if (height > 0) then (1, "a") else (1, "a", true)
+t._3
+`, "(6:3, 7:0): Tuple index must be less then 2"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let v = if (height > 0) then 1 else "a"
+match v {
+	case i: Int => i == 1
+	case s: String => s == "a"
+}
+`, ""},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let (a, b) = if (height > 0) then (1, "a") else ("b", 2)
+a != b
+`, ""},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let v = if (height > 0) then 1 else "a"
+v + 1 > 0
+`, "(6:1, 6:6): Unexpected types for '+' operator 'Int|String' and 'Int', the types of if-else branches at 5:9 " +
+			"can't be unified: 'Int' at 5:30 and 'String' at 5:37"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func call(flag: Boolean) = {
+    let v = if (flag) then 1 else unit
+    [IntegerEntry("k", v)]
+}
+`, "(8:6, 8:18): Undefined function 'IntegerEntry(String, Int|Unit)', the types of if-else branches at 7:13 " +
+			"can't be unified: 'Int' at 7:28 and 'Unit' at 7:35"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+func fail(code: Int) = if (code > 0) then throw("positive") else throw("negative")
+height > 0 || fail(height)
+`, ""},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let v = if (height > 0) then throw("a") else throw("b")
+v == 1
+`, "(6:6, 7:0): Unexpected type, required 'Unknown', but 'Int' found"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			_, errs := CompileToTree(test.code)
+			if test.errorMsg == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.NotEmpty(t, errs)
+			assert.Equal(t, test.errorMsg, errs[0].Error())
+		})
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	for i, test := range []struct {
+		expr    string
+		err     string
+		warning string
+	}{
+		{"9223372036854775807 + 1", "Integer overflow in constant expression: add: integer overflow/underflow", ""},
+		{"-9223372036854775807 - 10", "Integer overflow in constant expression: sub: integer overflow/underflow", ""},
+		{"(3037000500 * 3037000500) + 1", "Integer overflow in constant expression: mul: integer overflow/underflow", ""},
+		{"1000000 * 1000000 * 1000000", "", ""},
+		{"height * 100 + 9223372036854775", "", ""},
+		{"height * 10000000000", "", "Possible integer overflow: operation with large constant 10000000000"},
+		{"height + 9223372036854775000", "", "Possible integer overflow: operation with large constant 9223372036854775000"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let a = ` + test.expr + `
+a > 0
+`
+			_, errs, warnings := CompileToTreeWithOptions(code, Options{})
+			if test.err == "" {
+				require.Empty(t, errs)
+			} else {
+				require.NotEmpty(t, errs)
+				assert.Contains(t, errs[0].Error(), test.err)
+			}
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.NotEmpty(t, warnings)
+				assert.Contains(t, warnings[0].Error(), test.warning)
+			}
+		})
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	for i, test := range []struct {
+		expr string
+		err  string
+	}{
+		{"10 / 0", "(5:12, 5:13): Division by zero in constant expression"},
+		{"10 % 0", "(5:12, 5:13): Division by zero in constant expression"},
+		{"height / (5 - 5)", "(5:16, 5:17): Division by zero in constant expression"},
+		{"height / (1 / 2)", "(5:16, 5:17): Division by zero in constant expression"},
+		{"toBigInt(height) / toBigInt(0) == toBigInt(0)", "Division by zero in constant expression"},
+		{"fraction(height, 5, 0)", "(5:9, 5:17): Division by zero in constant expression: denominator of 'fraction' is zero"},
+		{"fraction(height, 5, 1 - 1, HALFUP)", "denominator of 'fraction' is zero"},
+		{"fraction(toBigInt(height), toBigInt(5), toBigInt(0)) == toBigInt(0)", "denominator of 'fraction' is zero"},
+		{"fraction(height, 0, 5)", ""},
+		{"0 / height", ""},
+		{"height / (2 / 1)", ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let a = ` + test.expr + `
+a == a
+`
+			_, errs := CompileToTree(code)
+			if test.err == "" {
+				assert.Empty(t, errs)
+			} else {
+				require.NotEmpty(t, errs)
+				assert.Contains(t, errs[0].Error(), test.err)
+			}
+		})
+	}
+}
+
+func TestDeprecatedFunctions(t *testing.T) {
+	const (
+		extract = "Function 'extract' is deprecated since STDLIB_VERSION 3 and removed in STDLIB_VERSION 4, " +
+			"use 'value' instead"
+		checkMerkleProof = "Function 'checkMerkleProof' is deprecated since STDLIB_VERSION 3 and removed in " +
+			"STDLIB_VERSION 4, use 'createMerkleRoot' instead"
+	)
+	for i, test := range []struct {
+		version int
+		expr    string
+		warning string
+		err     string
+	}{
+		{1, "0 < extract(x)", "", ""},
+		{2, "0 < extract(x)", "", ""},
+		{3, "0 < extract(x)", "(6:5, 6:12): " + extract, ""},
+		{3, "0 < x.extract()", extract, ""},
+		{3, "0 < value(x)", "", ""},
+		{3, "checkMerkleProof(base58'', base58'', base58'')", "(6:1, 6:17): " + checkMerkleProof, ""},
+		{4, "0 < extract(x)", "", "Undefined function 'extract(Int|Unit)'"},
+		{4, "createMerkleRoot([], base58'', 0) == base58''", "", ""},
+		{5, "0 < value(x)", "", ""},
+		{6, "0 < value(x)", "", ""},
+		{7, "0 < value(x)", "", ""},
+		{8, "0 < value(x)", "", ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION ` + strconv.Itoa(test.version) + ` #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let x = if (height > 0) then 1 else unit
+` + test.expr + `
+`
+			_, errs, warnings := CompileToTreeWithOptions(code, Options{})
+			if test.err != "" {
+				require.NotEmpty(t, errs)
+				assert.Contains(t, errs[0].Error(), test.err)
+				return
+			}
+			require.Empty(t, errs)
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Contains(t, warnings[0].Error(), test.warning)
+			}
+		})
+	}
+}
+
+func TestDeprecationTable(t *testing.T) {
+	expected := map[ast.LibraryVersion][]string{
+		ast.LibV3: {"checkMerkleProof", "extract"},
+	}
+	for v, funcs := range s.FuncsByVersion() {
+		names := make([]string, 0, len(funcs.Deprecated))
+		for name := range funcs.Deprecated {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		assert.Equal(t, len(expected[v]), len(names), v)
+		if len(expected[v]) > 0 {
+			assert.Equal(t, expected[v], names, v)
+		}
+		for name, d := range funcs.Deprecated {
+			assert.True(t, funcs.Check(name), "deprecated function %s is not available in version %d", name, v)
+			assert.LessOrEqual(t, d.Since, v, name)
+			replacementVersion := v
+			if d.Removed != 0 {
+				removed := s.FuncsByVersion()[d.Removed]
+				assert.False(t, removed.Check(name), name)
+				replacementVersion = d.Removed
+			}
+			replacementFuncs := s.FuncsByVersion()[replacementVersion]
+			assert.True(t, replacementFuncs.Check(d.Replacement),
+				"replacement %s of %s is not available in version %d", d.Replacement, name, replacementVersion)
+		}
+	}
+}
+
+func TestNestingDepthWarning(t *testing.T) {
+	nestedIfs := func(n int) string {
+		return strings.Repeat("if (height > 0) then ", n) + "1" + strings.Repeat(" else 0", n)
+	}
+	elseIfs := func(n int) string {
+		return strings.Repeat("if (height > 0) then 0 else ", n) + "1"
+	}
+	nestedMatches := func(n int) string {
+		return strings.Repeat("match height { case _ => ", n) + "1" + strings.Repeat(" }", n)
+	}
+	for i, test := range []struct {
+		expr    string
+		limit   int
+		warning string
+	}{
+		{nestedIfs(DefaultMaxNestingDepth), DefaultMaxNestingDepth, ""},
+		{nestedIfs(DefaultMaxNestingDepth + 1), DefaultMaxNestingDepth,
+			"(5:9, 6:0)"},
+		{nestedIfs(DefaultMaxNestingDepth + 1), DefaultMaxNestingDepth,
+			"Nesting depth 6 of conditional expressions exceeds the limit 5"},
+		{nestedMatches(DefaultMaxNestingDepth), DefaultMaxNestingDepth, ""},
+		{nestedMatches(DefaultMaxNestingDepth + 1), DefaultMaxNestingDepth,
+			"Nesting depth 6 of conditional expressions exceeds the limit 5"},
+		{elseIfs(2 * DefaultMaxNestingDepth), DefaultMaxNestingDepth, ""},
+		{"if (height > 0) then " + nestedMatches(2) + " else 0", 2,
+			"Nesting depth 3 of conditional expressions exceeds the limit 2"},
+		{nestedIfs(DefaultMaxNestingDepth + 1), 0, ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let a = ` + test.expr + `
+a > 0
+`
+			_, errs, warnings := CompileToTreeWithOptions(code, Options{MaxNestingDepth: test.limit})
+			require.Empty(t, errs)
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Contains(t, warnings[0].Error(), test.warning)
+			}
+		})
+	}
+}
+
+func TestNonASCIIIdentifiers(t *testing.T) {
+	for i, test := range []struct {
+		expr    string
+		warning string
+	}{
+		{"let amount = 1", ""},
+		{"let s = \"сумма\" # комментарий", ""},
+		{"let \u0430mount = 1", "(5:5, 5:11): Identifier '\u0430mount' contains non-ASCII characters"},
+		{"let amount = 1 + p\u0430yment", "(5:18, 6:0): Identifier 'p\u0430yment' contains non-ASCII characters"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+` + test.expr + `
+true
+`
+			_, errs, warnings := CompileToTreeWithOptions(code, Options{})
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.NotEmpty(t, errs, "non-ASCII identifiers are not allowed by grammar")
+				require.Len(t, warnings, 1)
+				assert.Contains(t, warnings[0].Error(), test.warning)
+			}
+		})
+	}
+}
+
+func TestReservedIdentifiers(t *testing.T) {
+	for i, test := range []struct {
+		expr string
+		err  string
+	}{
+		{"let height = 1", "(5:5, 5:11): Identifier 'height' is reserved in STDLIB_VERSION 6"},
+		{"let Address = 1", "(5:5, 5:12): Identifier 'Address' is reserved in STDLIB_VERSION 6"},
+		{"func tx() = 1", "(5:6, 5:8): Identifier 'tx' is reserved in STDLIB_VERSION 6"},
+		{"func f(height: Int) = height", "(5:8, 5:14): Identifier 'height' is reserved in STDLIB_VERSION 6"},
+		{"let heightValue = 1", ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+` + test.expr + `
+true
+`
+			_, errs := CompileToTree(code)
+			if test.err == "" {
+				assert.Empty(t, errs)
+			} else {
+				require.NotEmpty(t, errs)
+				assert.Contains(t, errs[0].Error(), test.err)
+			}
+		})
+	}
+}
+
+func TestReservedAnnotationIdentifiers(t *testing.T) {
+	for i, test := range []struct {
+		annotation string
+		err        string
+	}{
+		{"@Callable(this)", "(5:11, 5:15): Identifier 'this' is reserved in STDLIB_VERSION 6"},
+		{"@Callable(lastBlock)", "(5:11, 5:20): Identifier 'lastBlock' is reserved in STDLIB_VERSION 6"},
+		{"@Verifier(height)", "(5:11, 5:17): Identifier 'height' is reserved in STDLIB_VERSION 6"},
+		{"@Verifier(tx)", ""},
+		{"@Callable(i)", ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+` + test.annotation + `
+func f() = true
+`
+			if strings.HasPrefix(test.annotation, "@Callable") {
+				code = strings.Replace(code, "= true", "= []", 1)
+			}
+			_, errs := CompileToTree(code)
+			if test.err == "" {
+				assert.Empty(t, errs)
+			} else {
+				require.NotEmpty(t, errs)
+				assert.Contains(t, errs[0].Error(), test.err)
+			}
+		})
+	}
+}
+
+func TestShadowingWarning(t *testing.T) {
+	for i, test := range []struct {
+		expr    string
+		warning string
+	}{
+		{`func f() = {
+  let x = 1
+  func g(x: Int) = x + 1
+  g(x)
+}`, "(7:10, 7:11): Variable 'x' shadows the variable declared at 6:7"},
+		{`func f(a: Int) = {
+  func g(a: Int) = a * 2
+  g(a)
+}`, "(6:10, 6:11): Variable 'a' shadows the variable declared at 5:8"},
+		{`func f() = {
+  let x = 1
+  x
+}
+func g(x: Int) = x + 1`, ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+` + test.expr + `
+true
+`
+			_, errs, warnings := CompileToTreeWithOptions(code, Options{})
+			require.Empty(t, errs)
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Contains(t, warnings[0].Error(), test.warning)
+			}
+		})
+	}
+}
+
+func TestLargeLiteralWarning(t *testing.T) {
+	for i, test := range []struct {
+		expr    string
+		limit   int
+		warning string
+	}{
+		{`let s = "` + strings.Repeat("a", DefaultMaxLiteralSize) + `"`, DefaultMaxLiteralSize, ""},
+		{`let s = "` + strings.Repeat("a", DefaultMaxLiteralSize+1) + `"`, DefaultMaxLiteralSize,
+			"Size 1025 bytes of String literal exceeds the limit 1024"},
+		{`let s = "` + strings.Repeat("я", 3) + `"`, 5, "(5:9, 6:0): Size 6 bytes of String literal exceeds the limit 5"},
+		{`let b = base16'` + strings.Repeat("ff", 16) + `'`, 16, ""},
+		{`let b = base16'` + strings.Repeat("ff", 17) + `'`, 16,
+			"Size 17 bytes of ByteVector literal exceeds the limit 16"},
+		{`let b = base64'` + base64.StdEncoding.EncodeToString(make([]byte, 100)) + `'`, 64,
+			"Size 100 bytes of ByteVector literal exceeds the limit 64"},
+		{`let s = "` + strings.Repeat("a", DefaultMaxLiteralSize+1) + `"`, 0, ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+` + test.expr + `
+true
+`
+			_, errs, warnings := CompileToTreeWithOptions(code, Options{MaxLiteralSize: test.limit})
+			require.Empty(t, errs)
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Contains(t, warnings[0].Error(), test.warning)
+			}
+		})
+	}
+}
+
+func TestUnreachableCodeWarning(t *testing.T) {
+	for i, test := range []struct {
+		code    string
+		warning string
+	}{
+		{`
+func f(a: Int) = {
+  strict x = throw("error")
+  a + 1
+}
+f(1) == 2`, "(7:3, 8:0): Unreachable code: the preceding strict variable always throws"},
+		{`
+func f(a: Int) = {
+  strict x = throw()
+  let y = a * 2
+  strict z = throw("again")
+  y
+}
+f(1) == 2`, "(7:3, 8:3): Unreachable code: the preceding strict variable always throws"},
+		{`
+strict x = if (height > 0) then throw("positive") else throw("non-positive")
+true`, "(6:1, 6:5): Unreachable code: the preceding strict variable always throws"},
+		{`
+strict x = {
+  let a = 1
+  throw("block")
+}
+true`, "(9:1, 9:5): Unreachable code: the preceding strict variable always throws"},
+		{`
+strict x = if (height > 0) then throw("positive") else 1
+x == 1`, ""},
+		{`
+let x = throw("lazy")
+true`, ""},
+		{`
+func f(a: Int) = {
+  strict x = if (a > 0) then a else throw("negative")
+  x + 1
+}
+f(1) == 2`, ""},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}` + test.code
+			_, errs, warnings := CompileToTreeWithOptions(code, Options{})
+			require.Empty(t, errs)
+			if test.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Equal(t, test.warning, warnings[0].Error())
+			}
+		})
+	}
+}
+
+func TestFunctionCallWithUndefinedArgument(t *testing.T) {
+	for i, test := range []struct {
+		code string
+		err  string
+	}{
+		{`toString(x) == ""`, "(5:10, 5:11): Variable 'x' doesn't exist"},
+		{`size(x) == 1`, "(5:6, 5:7): Variable 'x' doesn't exist"},
+		{`x.toString() == ""`, "(5:1, 5:2): Variable 'x' doesn't exist"},
+		{`func f(a: Int) = a
+f(x) == 1`, "(6:3, 6:4): Variable 'x' doesn't exist"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+` + test.code
+			_, errs := CompileToTree(code)
+			require.NotEmpty(t, errs)
+			assert.Equal(t, test.err, errs[0].Error())
+		})
+	}
+}
+
 func TestStrict(t *testing.T) {
 	for _, test := range []struct {
 		code     string
@@ -857,6 +1618,86 @@ baz != 10
 	}
 }
 
+func TestLibraryScripts(t *testing.T) {
+	t.Run("valid library", func(t *testing.T) {
+		code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE LIBRARY #-}
+
+let baz = 5
+func foo(a: Int) = a + baz
+`
+		tree, errs := CompileLibrary(code)
+		require.Empty(t, errs)
+		require.NotNil(t, tree)
+		assert.Len(t, tree.Declarations, 2)
+		assert.Empty(t, tree.Functions)
+		assert.Nil(t, tree.Verifier)
+	})
+	t.Run("library with callable", func(t *testing.T) {
+		code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE LIBRARY #-}
+
+func foo(a: Int) = a + 1
+
+@Callable(i)
+func call() = []
+`
+		_, errs := CompileLibrary(code)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "(7:1, 8:0): Library can't contain callable functions")
+	})
+	t.Run("library with verifier", func(t *testing.T) {
+		code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE LIBRARY #-}
+
+@Verifier(tx)
+func verify() = true
+`
+		_, errs := CompileToTree(code)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "(5:1, 6:0): Library can't contain verifier function")
+	})
+	t.Run("library with expression", func(t *testing.T) {
+		code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE LIBRARY #-}
+
+let a = 1
+a == 1
+`
+		_, errs := CompileLibrary(code)
+		require.NotEmpty(t, errs)
+		assert.Contains(t, errs[0].Error(), "Library can't contain expression")
+	})
+	t.Run("not a library", func(t *testing.T) {
+		code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+
+func foo(a: Int) = a + 1
+`
+		_, errs := CompileLibrary(code)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "script of kind DAPP is not a library")
+	})
+	t.Run("import of library with callable", func(t *testing.T) {
+		code := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+{-# IMPORT lib_test_scripts/lib_callable.ride #-}
+
+foo(10) == 11
+`
+		_, errs := CompileToTree(code)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "lib_test_scripts/lib_callable.ride(6:1, 7:0): Library can't contain callable functions")
+	})
+}
+
 func TestAnyAndThrowTypes(t *testing.T) {
 	tests := []struct {
 		code     string
@@ -904,7 +1745,7 @@ func TestBuiltInVarsWithCompaction(t *testing.T) {
 func f(height: Any) = height
 
 let a = height.f()`,
-			false, "BgIQCAIiAWYiBmhlaWdodCIBYQIBAWEBAWIFAWIAAWMJAQFhAQUGaGVpZ2h0AAAPvLXS"},
+			true, "(6:8, 6:14): Identifier 'height' is reserved in STDLIB_VERSION 6"},
 		{`
 {-# STDLIB_VERSION 6 #-}
 {-# CONTENT_TYPE DAPP #-}
@@ -913,7 +1754,7 @@ let a = height.f()`,
 func f(unit: Any) = unit
 
 let a = unit.f()`,
-			false, "BgIOCAIiAWYiBHVuaXQiAWECAQFhAQFiBQFiAAFjCQEBYQEFBHVuaXQAALdQWqE="},
+			true, "(6:8, 6:12): Identifier 'unit' is reserved in STDLIB_VERSION 6"},
 	}
 	for i, test := range tests {
 		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
@@ -1130,3 +1971,44 @@ let a = Order("".toBytes(), "".toBytes(), AssetPair("".toBytes(), "".toBytes()),
 		})
 	}
 }
+
+func TestRecursiveFunctions(t *testing.T) {
+	const header = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+`
+	for _, test := range []struct {
+		name   string
+		code   string
+		errors []string
+	}{
+		{"direct", `func f(x: Int) = f(x - 1)`, []string{
+			"(6:18, 6:19): Recursive functions are not allowed, functions form a cycle: f (6:6) -> f (6:6)",
+		}},
+		{"nested", `func f(x: Int) = {
+  func g(y: Int) = f(y)
+  g(x)
+}`, []string{
+			"(7:20, 7:21): Recursive functions are not allowed, functions form a cycle: f (6:6) -> g (7:8) -> f (6:6)",
+		}},
+		{"mutual", `func a(x: Int) = b(x)
+func b(x: Int) = a(x)`, []string{
+			"(6:18, 6:19): Recursive functions are not allowed, functions form a cycle: a (6:6) -> b (7:6) -> a (6:6)",
+			"(7:18, 7:19): Recursive functions are not allowed, functions form a cycle: b (7:6) -> a (6:6) -> b (7:6)",
+		}},
+		{"undefined", `func a(x: Int) = c(x)
+func b(x: Int) = a(x)`, []string{
+			"(6:18, 6:19): Undefined function 'c(Int)'",
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, errs := CompileToTree(header + test.code)
+			require.GreaterOrEqual(t, len(errs), len(test.errors))
+			for i, msg := range test.errors {
+				assert.EqualError(t, errs[i], msg)
+			}
+		})
+	}
+}
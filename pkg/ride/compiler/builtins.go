@@ -0,0 +1,124 @@
+package compiler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	s "github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
+)
+
+// Builtin is the definition of an additional built-in function available to the compiled scripts.
+type Builtin struct {
+	// Name is the name of the function in the source code.
+	Name string `json:"name"`
+	// ID is the identifier of the function in the compiled tree. Numeric ID makes the function native,
+	// otherwise it's a user function with the given name. If empty the Name is used.
+	ID         string   `json:"id,omitempty"`
+	Arguments  []string `json:"arguments"`
+	ReturnType string   `json:"return_type"`
+	Complexity int      `json:"complexity"`
+}
+
+func (b Builtin) function() ast.Function {
+	id := b.ID
+	if id == "" {
+		id = b.Name
+	}
+	if _, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return ast.NativeFunction(id)
+	}
+	return ast.UserFunction(id)
+}
+
+func (b Builtin) validate() error {
+	if b.Name == "" {
+		return errors.New("empty function name")
+	}
+	if b.Complexity < 0 {
+		return errors.Errorf("negative complexity %d", b.Complexity)
+	}
+	if err := validateType(b.ReturnType); err != nil {
+		return errors.Wrap(err, "invalid return type")
+	}
+	for i, a := range b.Arguments {
+		if err := validateType(a); err != nil {
+			return errors.Wrapf(err, "invalid type of argument %d", i)
+		}
+	}
+	return nil
+}
+
+func validateType(t string) error {
+	if t == "" {
+		return errors.New("empty type")
+	}
+	p := s.Types{Buffer: t}
+	if err := p.Init(); err != nil {
+		return err
+	}
+	return p.Parse()
+}
+
+// LoadBuiltins reads the JSON array of built-in functions definitions from the file.
+func LoadBuiltins(path string) ([]Builtin, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read built-in functions file")
+	}
+	return ParseBuiltins(data)
+}
+
+// ParseBuiltins parses and validates the JSON array of built-in functions definitions.
+func ParseBuiltins(data []byte) ([]Builtin, error) {
+	var builtins []Builtin
+	if err := json.Unmarshal(data, &builtins); err != nil {
+		return nil, errors.Wrap(err, "failed to parse built-in functions")
+	}
+	for i, b := range builtins {
+		if err := b.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid built-in function %d '%s'", i, b.Name)
+		}
+	}
+	return builtins, nil
+}
+
+// BuiltinsComplexities returns the complexities of built-in functions by their IDs in the compiled tree.
+// The result can be used to estimate the tree compiled with the built-in functions.
+func BuiltinsComplexities(builtins []Builtin) map[string]int {
+	r := make(map[string]int, len(builtins))
+	for _, b := range builtins {
+		r[b.function().Name()] = b.Complexity
+	}
+	return r
+}
+
+// extendFunctions returns the copy of standard functions signatures with built-in functions added.
+// Built-in function is added as an overload if the standard function with the same name exists.
+func extendFunctions(std s.FunctionsSignatures, builtins []Builtin) s.FunctionsSignatures {
+	if len(builtins) == 0 {
+		return std
+	}
+	funcs := make(map[string][]s.FunctionParams, len(std.Funcs)+len(builtins))
+	for name, over := range std.Funcs {
+		funcs[name] = over
+	}
+	for _, b := range builtins {
+		args := make([]s.Type, len(b.Arguments))
+		for i, a := range b.Arguments {
+			args[i] = s.ParseType(a)
+		}
+		over := make([]s.FunctionParams, 0, len(funcs[b.Name])+1)
+		over = append(over, funcs[b.Name]...)
+		funcs[b.Name] = append(over, s.FunctionParams{
+			ID:         b.function(),
+			Arguments:  args,
+			ReturnType: s.ParseType(b.ReturnType),
+		})
+	}
+	return s.FunctionsSignatures{Funcs: funcs, Deprecated: std.Deprecated}
+}
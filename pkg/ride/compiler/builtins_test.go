@@ -0,0 +1,66 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+func TestCompileWithBuiltins(t *testing.T) {
+	builtins, err := ParseBuiltins([]byte(`[
+		{"name": "randomInt", "arguments": ["Int"], "return_type": "Int", "complexity": 100},
+		{"name": "oracle", "id": "5000", "arguments": ["String", "ByteVector|Unit"], "return_type": "List[Int]",
+		 "complexity": 50}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"randomInt": 100, "5000": 50}, BuiltinsComplexities(builtins))
+
+	const src = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func roll() = {
+  let r = randomInt(size(oracle("prices", unit)))
+  [IntegerEntry("roll", r)]
+}
+`
+	_, errs := CompileToTree(src)
+	assert.NotEmpty(t, errs) // unknown functions without built-ins
+
+	tree, errs, _ := CompileToTreeWithOptions(src, Options{Builtins: builtins})
+	require.Empty(t, errs)
+	fn := tree.Functions[0].(*ast.FunctionDeclarationNode)
+	let := fn.Body.(*ast.AssignmentNode)
+	call := let.Expression.(*ast.FunctionCallNode)
+	assert.Equal(t, ast.UserFunction("randomInt"), call.Function)
+	size := call.Arguments[0].(*ast.FunctionCallNode)
+	assert.Equal(t, ast.NativeFunction("5000"), size.Arguments[0].(*ast.FunctionCallNode).Function)
+
+	_, errs, _ = CompileToTreeWithBuiltins(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+randomInt("10") > 5
+`, builtins)
+	assert.NotEmpty(t, errs) // argument type mismatch
+
+	res, errs, _ := CompileWithBuiltins(src, false, false, builtins)
+	require.Empty(t, errs)
+	assert.NotEmpty(t, res)
+
+	for _, invalid := range []string{
+		`[{"name": "", "arguments": [], "return_type": "Int"}]`,
+		`[{"name": "f", "arguments": [], "return_type": ""}]`,
+		`[{"name": "f", "arguments": ["Int"], "return_type": "Int", "complexity": -1}]`,
+		`[{"name": "f", "arguments": ["Int|"], "return_type": "Int"}]`,
+		`{"name": "f"}`,
+	} {
+		_, err = ParseBuiltins([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
@@ -6,19 +6,6 @@ import (
 	"golang.org/x/exp/maps"
 )
 
-const (
-	charRange    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	lenCharRange = 52
-)
-
-func idxToName(n int, seed string) string {
-	if n < lenCharRange {
-		return string(charRange[n]) + seed
-	} else {
-		return idxToName(n/lenCharRange-1, string(charRange[n%lenCharRange])+seed)
-	}
-}
-
 type Compaction struct {
 	tree          *ast.Tree
 	counter       int
@@ -76,11 +63,16 @@ func (c *Compaction) Compact() {
 	c.tree.Meta.Abbreviations = meta.NewAbbreviations(c.resAbrList)
 }
 
+// Names returns the table of the original names to the compact ones.
+func (c *Compaction) Names() map[string]string {
+	return maps.Clone(c.originalNames)
+}
+
 func (c *Compaction) replaceName(oldName string) string {
 	if compName, ok := c.originalNames[oldName]; ok {
 		return compName
 	}
-	compName := idxToName(c.counter, "")
+	compName := meta.CompactName(c.counter)
 	if c.hasConflict(compName) {
 		c.counter += 1
 		return c.replaceName(oldName)
@@ -1,45 +1,269 @@
 package compiler
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
 )
 
 //go:generate peg -output=parser.peg.go ride.peg
 
+// Options control the compilation of the script. Zero value corresponds to the plain compilation without
+// transformations of the tree, additional built-in functions, limits and optional warnings.
+type Options struct {
+	// Compact replaces the names of dApp's user-defined functions and variables with short ones.
+	Compact bool
+	// RemoveUnused removes the dApp's declarations that are not used by callable functions and verifier.
+	RemoveUnused bool
+	// Optimize deduplicates the repeated pure subexpressions, see Optimize.
+	Optimize bool
+	// Builtins extend the standard library functions of the script's version.
+	Builtins []Builtin
+	// MaxCallables is the maximal number of dApp's callable functions, zero means no limit.
+	MaxCallables int
+	// MaxNestingDepth enables the warning about conditional expressions nested deeper than the limit,
+	// zero disables the warning. DefaultMaxNestingDepth is the recommended limit.
+	MaxNestingDepth int
+	// MaxLiteralSize enables the warning about string and byte vector literals larger than the limit in bytes,
+	// zero disables the warning. DefaultMaxLiteralSize is the recommended limit.
+	MaxLiteralSize int
+	// CheckVerifier enables the warning about account scripts without an explicit verifier or with the verifier
+	// that always returns false, see CheckVerifier. Asset scripts are not checked.
+	CheckVerifier bool
+}
+
 func CompileToTree(code string) (*ast.Tree, []error) {
-	pp := Parser{Buffer: code}
-	err := pp.Init()
+	tree, errs, _ := CompileToTreeWithOptions(code, Options{})
+	return tree, errs
+}
+
+// CompileToTreeWithOptions compiles the code and returns the tree along with the list of warnings.
+// Warnings are issues that don't prevent the compilation, but most likely are mistakes in the code.
+func CompileToTreeWithOptions(code string, opts Options) (*ast.Tree, []error, []error) {
+	tree, _, errs, warnings := compileToTree(code, opts)
+	return tree, errs, warnings
+}
+
+// CompileToTreeWithWarnings compiles the code and returns the tree along with the list of warnings.
+// It's a shorthand for CompileToTreeWithOptions with the zero Options.
+func CompileToTreeWithWarnings(code string) (*ast.Tree, []error, []error) {
+	return CompileToTreeWithOptions(code, Options{})
+}
+
+// CompileToTreeWithBuiltins compiles the code with the standard library extended by the given built-in functions.
+func CompileToTreeWithBuiltins(code string, builtins []Builtin) (*ast.Tree, []error, []error) {
+	return CompileToTreeWithOptions(code, Options{Builtins: builtins})
+}
+
+// compileToTree compiles the code and transforms the tree according to the options. The table of the original
+// names to the compact ones is returned alongside, it's nil if the compaction is not performed.
+func compileToTree(code string, opts Options) (*ast.Tree, map[string]string, []error, []error) {
+	ap, err := parseAST(code, opts)
 	if err != nil {
-		return nil, []error{err}
+		return nil, nil, []error{err}, nonASCIIIdentifiersWarnings(code)
+	}
+	if len(ap.errorsList) > 0 {
+		return nil, nil, ap.errorsList, ap.warningsList
+	}
+	tree := ap.tree
+	warnings := ap.warningsList
+	if opts.CheckVerifier && ap.scriptType == accountScript && ap.kind != LibraryScriptKind {
+		if err := CheckVerifier(tree); err != nil {
+			warnings = append(warnings, err)
+		}
+	}
+	if opts.RemoveUnused && tree.IsDApp() {
+		removeUnusedCode(tree)
+	}
+	if opts.Optimize {
+		Optimize(tree)
 	}
-	err = pp.Parse()
+	var names map[string]string
+	if opts.Compact && tree.IsDApp() {
+		comp := NewCompaction(tree)
+		comp.Compact()
+		names = comp.Names()
+	}
+	return tree, names, nil, warnings
+}
+
+// CompileLibrary compiles the library script, the resulting tree contains only declarations of the library.
+// Error is returned if the script isn't declared as a library with CONTENT_TYPE directive.
+func CompileLibrary(code string) (*ast.Tree, []error) {
+	ap, err := parseAST(code, Options{})
 	if err != nil {
 		return nil, []error{err}
 	}
-	ap := newASTParser(pp.AST(), pp.buffer)
-	ap.parse()
+	if ap.kind != LibraryScriptKind {
+		return nil, []error{errors.Errorf("script of kind %s is not a library", ap.kind)}
+	}
 	if len(ap.errorsList) > 0 {
 		return nil, ap.errorsList
 	}
 	return ap.tree, nil
 }
 
+// parseAST parses the code and builds the tree, errors of the tree building are collected in the returned parser.
+// Warnings about non-ASCII identifiers are collected even if the code is parsed successfully.
+// Only the built-in functions and limits of the options are taken into account, the tree is not transformed.
+func parseAST(code string, opts Options) (*astParser, error) {
+	pp := Parser{Buffer: code}
+	if err := pp.Init(); err != nil {
+		return nil, err
+	}
+	if err := pp.Parse(); err != nil {
+		return nil, err
+	}
+	ap := newASTParser(pp.AST(), pp.buffer)
+	ap.builtins = opts.Builtins
+	ap.maxNestingDepth = opts.MaxNestingDepth
+	ap.maxCallables = opts.MaxCallables
+	ap.maxLiteralSize = opts.MaxLiteralSize
+	ap.warningsList = append(ap.warningsList, nonASCIIIdentifiersWarnings(code)...)
+	ap.parse()
+	return &ap, nil
+}
+
 func Compile(code string, compact, removeUnused bool) ([]byte, []error) {
-	tree, errs := CompileToTree(code)
+	res, _, errs, _ := CompileWithOptions(code, Options{Compact: compact, RemoveUnused: removeUnused})
+	return res, errs
+}
+
+// CompileWithWarnings compiles and serializes the code, the list of warnings is returned alongside the errors.
+func CompileWithWarnings(code string, compact, removeUnused bool) ([]byte, []error, []error) {
+	res, _, errs, warnings := CompileWithOptions(code, Options{Compact: compact, RemoveUnused: removeUnused})
+	return res, errs, warnings
+}
+
+// CompileWithBuiltins compiles and serializes the code with the standard library extended by the given
+// built-in functions. Use BuiltinsComplexities to estimate the resulting tree.
+func CompileWithBuiltins(code string, compact, removeUnused bool, builtins []Builtin) ([]byte, []error, []error) {
+	opts := Options{Compact: compact, RemoveUnused: removeUnused, Builtins: builtins}
+	res, _, errs, warnings := CompileWithOptions(code, opts)
+	return res, errs, warnings
+}
+
+// CompileWithCompaction compiles and serializes the code, if compact is set the names of user-defined functions
+// and variables of dApp are replaced with short ones. The table of the original names to the compact ones is
+// returned alongside, it's nil if the compaction is not performed.
+func CompileWithCompaction(code string, compact bool) ([]byte, map[string]string, []error) {
+	res, names, errs, _ := CompileWithOptions(code, Options{Compact: compact})
+	return res, names, errs
+}
+
+// CompileWithOptions compiles and serializes the code according to the options. The table of the original names
+// to the compact ones and the list of warnings are returned alongside the errors. The table is nil if the compaction
+// is not performed. Use BuiltinsComplexities to estimate the tree compiled with additional built-in functions.
+func CompileWithOptions(code string, opts Options) ([]byte, map[string]string, []error, []error) {
+	tree, names, errs, warnings := compileToTree(code, opts)
 	if len(errs) > 0 {
-		return nil, errs
+		return nil, nil, errs, warnings
 	}
-	if removeUnused && tree.IsDApp() {
-		removeUnusedCode(tree)
+	res, err := serialization.SerializeTree(tree)
+	if err != nil {
+		return nil, nil, []error{err}, warnings
 	}
-	if compact && tree.IsDApp() {
-		comp := NewCompaction(tree)
-		comp.Compact()
+	return res, names, nil, warnings
+}
+
+// VerifierComplexityKey is the key of the verifier complexity in the complexities returned by CompileWithComplexity.
+// It never clashes with the names of callables because it's not a valid identifier.
+const VerifierComplexityKey = "@Verifier"
+
+// Estimator estimates the tree returning the complexities of callables by names and the complexity of the verifier.
+// The tree estimators are implemented in the ride package, so the estimator is supplied by the caller.
+type Estimator func(tree *ast.Tree) (callables map[string]int, verifier int, err error)
+
+// CompileWithComplexity compiles and serializes the code and estimates the produced tree, see Complexities.
+// Use estimation.CompileWithComplexity to estimate with the latest estimator of the ride package.
+func CompileWithComplexity(code string, estimate Estimator) ([]byte, map[string]int, []error) {
+	res, errs := Compile(code, false, false)
+	if len(errs) > 0 {
+		return nil, nil, errs
 	}
-	res, err := serialization.SerializeTree(tree)
+	complexities, err := Complexities(res, estimate)
 	if err != nil {
-		return nil, []error{err}
+		return nil, nil, []error{err}
+	}
+	return res, complexities, nil
+}
+
+// Complexities estimates the compiled script. The complexities of callables are returned by names, the complexity
+// of the verifier is returned by VerifierComplexityKey if the script has one.
+func Complexities(script []byte, estimate Estimator) (map[string]int, error) {
+	tree, err := serialization.Parse(script)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse compiled script")
+	}
+	callables, verifier, err := estimate(tree)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to estimate script")
+	}
+	complexities := make(map[string]int, len(callables)+1)
+	for name, c := range callables {
+		complexities[name] = c
+	}
+	if tree.HasVerifier() {
+		complexities[VerifierComplexityKey] = verifier
+	}
+	return complexities, nil
+}
+
+// CheckStdlibVersion returns an error if the STDLIB version of the compiled script differs from the required one.
+// The version of the script is the one declared with the STDLIB_VERSION directive or the default one.
+func CheckStdlibVersion(tree *ast.Tree, required ast.LibraryVersion) error {
+	if tree.LibVersion != required {
+		return errors.Errorf("script declares STDLIB_VERSION %d, but version %d is required",
+			tree.LibVersion, required)
+	}
+	return nil
+}
+
+// CheckVerifier returns an error if transactions of the account with the compiled script are not verified by
+// an explicit verifier. It's the case of a dApp without @Verifier function, transactions of such account are
+// verified with the default check of the account's signature. Also, the error is returned if the verifier of
+// expression or dApp script always evaluates to false, because the account becomes unable to send transactions.
+// The check is meant for account scripts, an asset script with the constant false is a valid way to freeze the asset.
+func CheckVerifier(tree *ast.Tree) error {
+	if tree.IsDApp() && !tree.HasVerifier() {
+		return errors.New("dApp has no @Verifier function, transactions of the account are verified " +
+			"with the default signature check")
+	}
+	body := tree.Verifier
+	if f, ok := body.(*ast.FunctionDeclarationNode); ok {
+		body = f.Body
+	}
+	if v, ok := constantBoolean(body); ok && !v {
+		return errors.New("verifier always returns false, the account will be unable to send transactions")
+	}
+	return nil
+}
+
+// constantBoolean returns the value of the expression if it evaluates to the same boolean regardless of the
+// transaction and the state. Logical operators are compiled into conditional expressions, so they are covered too.
+func constantBoolean(node ast.Node) (bool, bool) {
+	switch n := node.(type) {
+	case *ast.BooleanNode:
+		return n.Value, true
+	case *ast.AssignmentNode:
+		return constantBoolean(n.Block)
+	case *ast.FunctionDeclarationNode:
+		return constantBoolean(n.Block)
+	case *ast.ConditionalNode:
+		if c, ok := constantBoolean(n.Condition); ok {
+			if c {
+				return constantBoolean(n.TrueExpression)
+			}
+			return constantBoolean(n.FalseExpression)
+		}
+		t, tok := constantBoolean(n.TrueExpression)
+		f, fok := constantBoolean(n.FalseExpression)
+		if tok && fok && t == f {
+			return t, true
+		}
+		return false, false
+	default:
+		return false, false
 	}
-	return res, nil
 }
@@ -0,0 +1,257 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+)
+
+func TestCheckStdlibVersion(t *testing.T) {
+	for i, test := range []struct {
+		code     string
+		required ast.LibraryVersion
+		err      string
+	}{
+		{"{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\ntrue", ast.LibV6, ""},
+		{"{-# STDLIB_VERSION 5 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\ntrue", ast.LibV5, ""},
+		{"{-# STDLIB_VERSION 5 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\ntrue", ast.LibV6,
+			"script declares STDLIB_VERSION 5, but version 6 is required"},
+		{"{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\ntrue", ast.LibV4,
+			"script declares STDLIB_VERSION 6, but version 4 is required"},
+	} {
+		tree, errs := CompileToTree(test.code)
+		require.Empty(t, errs, i)
+		err := CheckStdlibVersion(tree, test.required)
+		if test.err == "" {
+			assert.NoError(t, err, i)
+		} else {
+			assert.EqualError(t, err, test.err, i)
+		}
+	}
+}
+
+func TestCheckVerifier(t *testing.T) {
+	for i, test := range []struct {
+		code string
+		err  string
+	}{
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)`, ""},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func call() = []`,
+			"dApp has no @Verifier function, transactions of the account are verified with the default signature check"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Verifier(tx)
+func verify() = false`, "verifier always returns false, the account will be unable to send transactions"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)`, ""},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+false`, "verifier always returns false, the account will be unable to send transactions"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let x = false
+sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey) && false`,
+			"verifier always returns false, the account will be unable to send transactions"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Verifier(tx)
+func verify() = if (true) then false else sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)`,
+			"verifier always returns false, the account will be unable to send transactions"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey) || false`, ""},
+	} {
+		tree, errs := CompileToTree(test.code)
+		require.Empty(t, errs, i)
+		err := CheckVerifier(tree)
+		if test.err == "" {
+			assert.NoError(t, err, i)
+		} else {
+			assert.EqualError(t, err, test.err, i)
+		}
+	}
+}
+
+func TestCheckVerifierOption(t *testing.T) {
+	for i, test := range []struct {
+		code     string
+		warnings []string
+	}{
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func call() = []`,
+			[]string{"dApp has no @Verifier function, transactions of the account are verified with the default signature check"}},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+false`, []string{"verifier always returns false, the account will be unable to send transactions"}},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ASSET #-}
+false`, nil},
+	} {
+		_, errs, warnings := CompileToTreeWithOptions(test.code, Options{CheckVerifier: true})
+		require.Empty(t, errs, i)
+		var msgs []string
+		for _, w := range warnings {
+			msgs = append(msgs, w.Error())
+		}
+		assert.Equal(t, test.warnings, msgs, i)
+		_, _, warnings = CompileToTreeWithOptions(test.code, Options{})
+		assert.Empty(t, warnings, i)
+	}
+}
+
+func TestCompileWithCompaction(t *testing.T) {
+	const code = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let multiplier = 10
+
+func calculateAmount(value: Int) = value * multiplier
+
+func amountKey(address: String) = "amount_" + address
+
+@Callable(invocation)
+func deposit(depositAmount: Int) = {
+    let key = amountKey(invocation.caller.toString())
+    [IntegerEntry(key, calculateAmount(depositAmount))]
+}
+
+@Callable(invocation)
+func clear() = [DeleteEntry(amountKey(invocation.caller.toString()))]
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)
+`
+	plain, names, errs := CompileWithCompaction(code, false)
+	require.Empty(t, errs)
+	assert.Nil(t, names)
+	compacted, names, errs := CompileWithCompaction(code, true)
+	require.Empty(t, errs)
+	assert.Less(t, len(compacted), len(plain))
+	res, errs, _ := CompileWithWarnings(code, true, false)
+	require.Empty(t, errs)
+	assert.Equal(t, compacted, res)
+
+	for _, n := range []string{"multiplier", "calculateAmount", "value", "amountKey", "address", "invocation",
+		"depositAmount", "key", "tx"} {
+		short, ok := names[n]
+		require.True(t, ok, n)
+		assert.Less(t, len(short), len(n), n)
+	}
+	for _, n := range []string{"deposit", "clear"} {
+		assert.NotContains(t, names, n, "names of callables must be preserved")
+	}
+
+	plainTree := parseScript(t, plain)
+	compactedTree := parseScript(t, compacted)
+	require.Len(t, compactedTree.Declarations, len(plainTree.Declarations))
+	for i, d := range plainTree.Declarations {
+		assert.Equal(t, names[declarationName(d)], declarationName(compactedTree.Declarations[i]))
+	}
+	require.Len(t, compactedTree.Functions, len(plainTree.Functions))
+	for i, f := range plainTree.Functions {
+		expected := f.(*ast.FunctionDeclarationNode).Name
+		assert.Equal(t, expected, compactedTree.Functions[i].(*ast.FunctionDeclarationNode).Name)
+	}
+}
+
+func TestCompileWithComplexity(t *testing.T) {
+	const code = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func light() = [IntegerEntry("light", 1)]
+
+@Callable(i)
+func heavy(data: ByteVector) = {
+    let h = sha256_16Kb(keccak256_16Kb(blake2b256_16Kb(data)))
+    [BinaryEntry("hash", h)]
+}
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)
+`
+	estimate := func(tree *ast.Tree) (map[string]int, int, error) {
+		est, err := ride.EstimateTree(tree, 4)
+		if err != nil {
+			return nil, 0, err
+		}
+		return est.Functions, est.Verifier, nil
+	}
+	res, complexities, errs := CompileWithComplexity(code, estimate)
+	require.Empty(t, errs)
+	plain, errs := Compile(code, false, false)
+	require.Empty(t, errs)
+	assert.Equal(t, plain, res)
+
+	require.Len(t, complexities, 3)
+	assert.Positive(t, complexities["light"])
+	assert.Greater(t, complexities["heavy"], complexities["light"])
+	assert.Positive(t, complexities[VerifierComplexityKey])
+
+	_, complexities, errs = CompileWithComplexity("{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\ntrue",
+		estimate)
+	require.Empty(t, errs)
+	require.Len(t, complexities, 1)
+	assert.Contains(t, complexities, VerifierComplexityKey)
+
+	_, complexities, errs = CompileWithComplexity(code+"\nlet x = ", estimate)
+	assert.NotEmpty(t, errs)
+	assert.Nil(t, complexities)
+}
+
+func parseScript(t *testing.T, script []byte) *ast.Tree {
+	tree, err := serialization.Parse(script)
+	require.NoError(t, err)
+	return tree
+}
+
+func declarationName(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		return n.Name
+	case *ast.FunctionDeclarationNode:
+		return n.Name
+	default:
+		return ""
+	}
+}
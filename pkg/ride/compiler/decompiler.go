@@ -0,0 +1,537 @@
+package compiler
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
+	"github.com/wavesplatform/gowaves/pkg/ride/meta"
+)
+
+const decompilerIndent = "    "
+
+// binaryOperators maps the IDs of functions produced by the compiler for binary operators to the operators.
+var binaryOperators = map[string]string{
+	"0":    "==",
+	"!=":   "!=",
+	"100":  "+",
+	"101":  "-",
+	"102":  ">",
+	"103":  ">=",
+	"104":  "*",
+	"105":  "/",
+	"106":  "%",
+	"203":  "+",
+	"300":  "+",
+	"311":  "+",
+	"312":  "-",
+	"313":  "*",
+	"314":  "/",
+	"315":  "%",
+	"319":  ">",
+	"320":  ">=",
+	"1100": "::",
+	"1101": ":+",
+	"1102": "++",
+}
+
+// unaryOperators maps the IDs of functions produced by the compiler for unary operators to the operators.
+var unaryOperators = map[string]string{
+	"-":   "-",
+	"!":   "!",
+	"318": "-",
+}
+
+// operandTypes maps the IDs of functions produced by the compiler for operators to the types of operands.
+var operandTypes = map[string]stdlib.Type{
+	"100": stdlib.IntType,
+	"101": stdlib.IntType,
+	"102": stdlib.IntType,
+	"103": stdlib.IntType,
+	"104": stdlib.IntType,
+	"105": stdlib.IntType,
+	"106": stdlib.IntType,
+	"203": stdlib.ByteVectorType,
+	"300": stdlib.StringType,
+	"311": stdlib.BigIntType,
+	"312": stdlib.BigIntType,
+	"313": stdlib.BigIntType,
+	"314": stdlib.BigIntType,
+	"315": stdlib.BigIntType,
+	"318": stdlib.BigIntType,
+	"319": stdlib.BigIntType,
+	"320": stdlib.BigIntType,
+	"-":   stdlib.IntType,
+	"!":   stdlib.BooleanType,
+}
+
+const (
+	getElementFunctionID = "401"
+	firstTupleFunctionID = 1300
+	lastTupleFunctionID  = 1320
+)
+
+type decompiler struct {
+	tree       *ast.Tree
+	names      map[string]string
+	signatures map[string][]stdlib.FunctionParams
+	argTypes   map[string][]string
+}
+
+// Decompile reconstructs the RIDE source code of the compiled script. The source is semantically equivalent
+// to the original one, but the syntactic sugar like pattern matching, FOLD macros or strict variables is
+// represented by the expressions it was compiled into. The tree doesn't keep the types of user functions
+// arguments and the type of the script, so the types of arguments are inferred from their usage and
+// the script is declared as the account one.
+func Decompile(tree *ast.Tree) (string, error) {
+	if tree == nil {
+		return "", errors.New("empty tree")
+	}
+	d := &decompiler{
+		tree:       tree,
+		names:      make(map[string]string),
+		signatures: make(map[string][]stdlib.FunctionParams),
+		argTypes:   make(map[string][]string),
+	}
+	for name, overloads := range stdlib.FuncsByVersion()[tree.LibVersion].Funcs {
+		for _, f := range overloads {
+			d.names[f.ID.Name()] = name
+			d.signatures[f.ID.Name()] = append(d.signatures[f.ID.Name()], f)
+		}
+	}
+	for name, info := range stdlib.ObjectsByVersion()[tree.LibVersion].Obj {
+		if info.NotConstruct {
+			continue
+		}
+		constructor := stdlib.FunctionParams{ID: ast.UserFunction(name)}
+		for _, f := range info.Fields {
+			constructor.Arguments = append(constructor.Arguments, f.Type)
+		}
+		d.signatures[name] = append(d.signatures[name], constructor)
+	}
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "{-# %s %d #-}\n", stdlibVersionDirectiveName, tree.LibVersion)
+	if tree.IsDApp() {
+		fmt.Fprintf(sb, "{-# %s %s #-}\n", contentTypeDirectiveName, dappValueName)
+	} else {
+		fmt.Fprintf(sb, "{-# %s %s #-}\n", contentTypeDirectiveName, expressionValueName)
+	}
+	fmt.Fprintf(sb, "{-# %s %s #-}\n", scriptTypeDirectiveName, accountValueName)
+	if !tree.IsDApp() {
+		body, err := d.block(tree.Verifier, 0)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(sb, "\n%s\n", body)
+		return sb.String(), nil
+	}
+	for _, n := range tree.Declarations {
+		decl, err := d.declaration(n, 0)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(sb, "\n%s\n", decl)
+	}
+	for _, n := range tree.Functions {
+		f, ok := n.(*ast.FunctionDeclarationNode)
+		if !ok {
+			return "", errors.Errorf("unexpected callable node %T", n)
+		}
+		callable, err := d.callable(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(sb, "\n@Callable(%s)\n%s\n", f.InvocationParameter, callable)
+	}
+	if tree.HasVerifier() {
+		f, ok := tree.Verifier.(*ast.FunctionDeclarationNode)
+		if !ok {
+			return "", errors.Errorf("unexpected verifier node %T", tree.Verifier)
+		}
+		body, err := d.expression(f.Body, 0)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(sb, "\n@Verifier(%s)\nfunc %s() = %s\n", f.InvocationParameter, f.Name, body)
+	}
+	return sb.String(), nil
+}
+
+// callable returns the declaration of the callable function with the types of arguments taken from the meta.
+func (d *decompiler) callable(f *ast.FunctionDeclarationNode) (string, error) {
+	var signature *meta.Function
+	for i := range d.tree.Meta.Functions {
+		if d.tree.Meta.Functions[i].Name == f.Name {
+			signature = &d.tree.Meta.Functions[i]
+			break
+		}
+	}
+	args := make([]string, len(f.Arguments))
+	for i, a := range f.Arguments {
+		args[i] = a
+		if signature == nil || i >= len(signature.Arguments) {
+			continue
+		}
+		t, err := meta.TypeName(signature.Arguments[i])
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid type of argument '%s' of callable '%s'", a, f.Name)
+		}
+		args[i] = a + ": " + t
+	}
+	body, err := d.expression(f.Body, 0)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("func %s(%s) = %s", f.Name, strings.Join(args, ", "), body), nil
+}
+
+func (d *decompiler) declaration(node ast.Node, indent int) (string, error) {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		expr, err := d.expression(n.Expression, indent)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("let %s = %s", n.Name, expr), nil
+	case *ast.FunctionDeclarationNode:
+		body, err := d.expression(n.Body, indent)
+		if err != nil {
+			return "", err
+		}
+		types := d.argumentTypes(n)
+		d.argTypes[n.Name] = types
+		args := make([]string, len(n.Arguments))
+		for i, a := range n.Arguments {
+			args[i] = a + ": " + types[i]
+		}
+		return fmt.Sprintf("func %s(%s) = %s", n.Name, strings.Join(args, ", "), body), nil
+	default:
+		return "", errors.Errorf("unexpected declaration node %T", node)
+	}
+}
+
+// argumentTypes infers the types of arguments of the user function. The argument passed directly to a function
+// gets the type of the corresponding parameter of the function. Arguments, which types can't be inferred,
+// are declared as Any.
+func (d *decompiler) argumentTypes(f *ast.FunctionDeclarationNode) []string {
+	types := make([]string, len(f.Arguments))
+	index := make(map[string]int, len(f.Arguments))
+	for i, a := range f.Arguments {
+		index[a] = i
+	}
+	d.inferTypes(f.Body, index, types)
+	for i := range types {
+		if types[i] == "" {
+			types[i] = stdlib.AnyType.String()
+		}
+	}
+	return types
+}
+
+// inferTypes walks the expression and sets the types of the arguments from the index, which types are not set yet.
+// The index maps the names of arguments and local variables holding their values to the positions of arguments.
+func (d *decompiler) inferTypes(node ast.Node, index map[string]int, types []string) {
+	infer := func(n ast.Node, t string) {
+		for _, j := range yielded(n, index) {
+			if types[j] == "" {
+				types[j] = t
+			}
+		}
+	}
+	switch n := node.(type) {
+	case *ast.FunctionCallNode:
+		for i, a := range n.Arguments {
+			infer(a, d.parameterType(n.Function, i))
+			d.inferTypes(a, index, types)
+		}
+	case *ast.ConditionalNode:
+		infer(n.Condition, stdlib.BooleanType.String())
+		d.inferTypes(n.Condition, index, types)
+		d.inferTypes(n.TrueExpression, index, types)
+		d.inferTypes(n.FalseExpression, index, types)
+	case *ast.PropertyNode:
+		d.inferTypes(n.Object, index, types)
+	case *ast.AssignmentNode:
+		d.inferTypes(n.Expression, index, types)
+		inner := shadow(index, n.Name)
+		if js := yielded(n.Expression, index); len(js) == 1 {
+			inner = maps.Clone(inner)
+			inner[n.Name] = js[0]
+		}
+		d.inferTypes(n.Block, inner, types)
+	case *ast.FunctionDeclarationNode:
+		d.inferTypes(n.Body, shadow(index, n.Arguments...), types)
+		d.inferTypes(n.Block, shadow(index, n.Name), types)
+	}
+}
+
+// yielded returns the positions of arguments from the index, which values the expression could result in.
+func yielded(node ast.Node, index map[string]int) []int {
+	switch n := node.(type) {
+	case *ast.ReferenceNode:
+		if j, ok := index[n.Name]; ok {
+			return []int{j}
+		}
+	case *ast.ConditionalNode:
+		return append(yielded(n.TrueExpression, index), yielded(n.FalseExpression, index)...)
+	}
+	return nil
+}
+
+// parameterType returns the type of the i-th parameter of the function or an empty string if the type
+// is unknown or is not specific enough to be declared.
+func (d *decompiler) parameterType(f ast.Function, i int) string {
+	if _, user := f.(ast.UserFunction); user {
+		if types, ok := d.argTypes[f.Name()]; ok {
+			if i < len(types) && types[i] != stdlib.AnyType.String() {
+				return types[i]
+			}
+			return ""
+		}
+	}
+	if t, ok := operandTypes[f.Name()]; ok {
+		return t.String()
+	}
+	var t string
+	for _, s := range d.signatures[f.Name()] {
+		if i >= len(s.Arguments) {
+			return ""
+		}
+		st := s.Arguments[i].String()
+		if t != "" && t != st {
+			return "" // overloads take different types
+		}
+		t = st
+	}
+	if t == stdlib.AnyType.String() {
+		return ""
+	}
+	return t
+}
+
+// shadow returns the index without the names, which are redeclared in the nested scope.
+func shadow(index map[string]int, names ...string) map[string]int {
+	var res map[string]int
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			continue
+		}
+		if res == nil {
+			res = maps.Clone(index)
+		}
+		delete(res, name)
+	}
+	if res == nil {
+		return index
+	}
+	return res
+}
+
+// block returns the lines of declarations followed by the resulting expression of the block.
+func (d *decompiler) block(node ast.Node, indent int) (string, error) {
+	var lines []string
+	prefix := strings.Repeat(decompilerIndent, indent)
+	for {
+		var next ast.Node
+		switch n := node.(type) {
+		case *ast.AssignmentNode:
+			next = n.Block
+		case *ast.FunctionDeclarationNode:
+			next = n.Block
+		}
+		if next == nil {
+			break
+		}
+		decl, err := d.declaration(node, indent)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, prefix+decl)
+		node = next
+	}
+	expr, err := d.expression(node, indent)
+	if err != nil {
+		return "", err
+	}
+	lines = append(lines, prefix+expr)
+	return strings.Join(lines, "\n"), nil
+}
+
+func (d *decompiler) expression(node ast.Node, indent int) (string, error) {
+	switch n := node.(type) {
+	case *ast.LongNode:
+		return strconv.FormatInt(n.Value, 10), nil
+	case *ast.BooleanNode:
+		return strconv.FormatBool(n.Value), nil
+	case *ast.StringNode:
+		return quoteString(n.Value), nil
+	case *ast.BytesNode:
+		return "base58'" + base58.Encode(n.Value) + "'", nil
+	case *ast.ReferenceNode:
+		return n.Name, nil
+	case *ast.PropertyNode:
+		obj, err := d.operand(n.Object, indent)
+		if err != nil {
+			return "", err
+		}
+		return obj + "." + n.Name, nil
+	case *ast.ConditionalNode:
+		return d.conditional(n, indent)
+	case *ast.FunctionCallNode:
+		return d.call(n, indent)
+	case *ast.AssignmentNode, *ast.FunctionDeclarationNode:
+		body, err := d.block(node, indent+1)
+		if err != nil {
+			return "", err
+		}
+		return "{\n" + body + "\n" + strings.Repeat(decompilerIndent, indent) + "}", nil
+	default:
+		return "", errors.Errorf("unexpected expression node %T", node)
+	}
+}
+
+func (d *decompiler) conditional(n *ast.ConditionalNode, indent int) (string, error) {
+	// Logical operators are compiled into conditional expressions
+	op, right := "", ast.Node(nil)
+	if b, ok := n.FalseExpression.(*ast.BooleanNode); ok && !b.Value {
+		op, right = "&&", n.TrueExpression
+	} else if b, ok := n.TrueExpression.(*ast.BooleanNode); ok && b.Value {
+		op, right = "||", n.FalseExpression
+	}
+	if right != nil {
+		l, err := d.operand(n.Condition, indent)
+		if err != nil {
+			return "", err
+		}
+		r, err := d.operand(right, indent)
+		if err != nil {
+			return "", err
+		}
+		return l + " " + op + " " + r, nil
+	}
+	cond, err := d.expression(n.Condition, indent)
+	if err != nil {
+		return "", err
+	}
+	t, err := d.expression(n.TrueExpression, indent)
+	if err != nil {
+		return "", err
+	}
+	f, err := d.expression(n.FalseExpression, indent)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("if (%s) then %s else %s", cond, t, f), nil
+}
+
+func (d *decompiler) call(n *ast.FunctionCallNode, indent int) (string, error) {
+	id := n.Function.Name()
+	if op, ok := binaryOperators[id]; ok && len(n.Arguments) == 2 {
+		l, err := d.operand(n.Arguments[0], indent)
+		if err != nil {
+			return "", err
+		}
+		r, err := d.operand(n.Arguments[1], indent)
+		if err != nil {
+			return "", err
+		}
+		return l + " " + op + " " + r, nil
+	}
+	if op, ok := unaryOperators[id]; ok && len(n.Arguments) == 1 {
+		arg, err := d.operand(n.Arguments[0], indent)
+		if err != nil {
+			return "", err
+		}
+		return op + arg, nil
+	}
+	if id == getElementFunctionID && len(n.Arguments) == 2 {
+		list, err := d.operand(n.Arguments[0], indent)
+		if err != nil {
+			return "", err
+		}
+		index, err := d.expression(n.Arguments[1], indent)
+		if err != nil {
+			return "", err
+		}
+		return list + "[" + index + "]", nil
+	}
+	args := make([]string, len(n.Arguments))
+	for i, a := range n.Arguments {
+		arg, err := d.expression(a, indent)
+		if err != nil {
+			return "", err
+		}
+		args[i] = arg
+	}
+	if i, err := strconv.Atoi(id); err == nil && i >= firstTupleFunctionID && i <= lastTupleFunctionID {
+		return "(" + strings.Join(args, ", ") + ")", nil
+	}
+	name, ok := d.names[id]
+	if !ok {
+		if _, native := n.Function.(ast.NativeFunction); native {
+			name = "Native" + id
+		} else {
+			name = id // user function or constructor
+		}
+	}
+	return name + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+// operand returns the expression enclosed in parentheses if it could change the meaning of the enclosing operator.
+func (d *decompiler) operand(node ast.Node, indent int) (string, error) {
+	expr, err := d.expression(node, indent)
+	if err != nil {
+		return "", err
+	}
+	parenthesize := false
+	switch n := node.(type) {
+	case *ast.ConditionalNode:
+		parenthesize = true
+	case *ast.LongNode:
+		parenthesize = n.Value < 0
+	case *ast.FunctionCallNode:
+		_, binary := binaryOperators[n.Function.Name()]
+		_, unary := unaryOperators[n.Function.Name()]
+		parenthesize = binary || unary
+	}
+	if parenthesize {
+		return "(" + expr + ")", nil
+	}
+	return expr, nil
+}
+
+// quoteString returns the string literal, the backslash and control characters are written as unicode escapes.
+func quoteString(s string) string {
+	sb := new(strings.Builder)
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r == '\\' || r < ' ' {
+				fmt.Fprintf(sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,118 @@
+package compiler
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+)
+
+func TestDecompileRoundTrip(t *testing.T) {
+	for i, test := range []struct {
+		code     string
+		fragment string
+	}{
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let prefix = "key_\"q\""
+let limit = -10
+
+func total() = {
+    let a = 1
+    let b = base58'3xz'
+    a + size(b) * 2
+}
+
+@Callable(i)
+func call(amount: Int, ids: List[String]) = {
+    let k = prefix + ids[0]
+    if (amount > limit && amount <= 100 || i.caller == this) then [IntegerEntry(k, amount - (-1))] else []
+}
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)
+`, "@Callable(i)\nfunc call(amount: Int, ids: List[String]) = {\n    let k = prefix + ids[0]\n"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let t = (1, "line\nnext")
+let s = "\u005c"
+!(t._1 > 2) && size(t._2 + s) == 10
+`, `let s = "\u005c"`},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			compiled, errs := Compile(test.code, false, false)
+			require.Empty(t, errs)
+			tree, err := serialization.Parse(compiled)
+			require.NoError(t, err)
+			src, err := Decompile(tree)
+			require.NoError(t, err)
+			assert.Contains(t, src, test.fragment)
+			recompiled, errs := Compile(src, false, false)
+			require.Empty(t, errs, src)
+			assert.Equal(t, compiled, recompiled, src)
+		})
+	}
+}
+
+func TestDecompileFunctionArguments(t *testing.T) {
+	for i, test := range []struct {
+		code     string
+		fragment string
+	}{
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+func double(x: Int) = x * 2
+double(height) > 10
+`, "func double(x: Int) = x * 2\n"},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+func key(prefix: String, id: ByteVector) = prefix + toBase58String(id)
+
+func entry(k: String, v: Int, flag: Boolean, items: List[Int]) = {
+    let x = if (flag) then v else size(items)
+    IntegerEntry(key(k, base58'3xz'), x)
+}
+
+func same(a: Int, b: Int) = a == b
+
+@Callable(i)
+func call(amount: Int) = if (same(amount, 1)) then [entry("a", amount, true, [1, 2])] else []
+`, "func entry(k: String, v: Int, flag: Boolean, items: List[Any]) = "},
+		{`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+func f(v: Int) = {
+    func g(v: String) = size(v)
+    g("abc") + v
+}
+f(1) == 4
+`, "func f(v: Int) = {\n    func g(v: String) = size(v)\n"},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			compiled, errs := Compile(test.code, false, false)
+			require.Empty(t, errs)
+			tree, err := serialization.Parse(compiled)
+			require.NoError(t, err)
+			src, err := Decompile(tree)
+			require.NoError(t, err)
+			assert.Contains(t, src, test.fragment)
+			recompiled, errs := Compile(src, false, false)
+			require.Empty(t, errs, src)
+			assert.Equal(t, compiled, recompiled, src)
+		})
+	}
+}
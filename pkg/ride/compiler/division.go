@@ -0,0 +1,39 @@
+package compiler
+
+import (
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+const (
+	intToBigIntFunctionID = "310"
+	bigIntDivFunctionID   = "314"
+	bigIntModFunctionID   = "315"
+
+	fractionFunctionName = "fraction"
+	// fractionDivisorPosition is the position of the denominator in all overloads of 'fraction' function.
+	fractionDivisorPosition = 2
+)
+
+// isConstZero reports whether the Int or BigInt expression is the constant zero.
+func isConstZero(node ast.Node) bool {
+	if call, ok := node.(*ast.FunctionCallNode); ok && len(call.Arguments) == 1 &&
+		call.Function == ast.NativeFunction(intToBigIntFunctionID) {
+		node = call.Arguments[0]
+	}
+	v, ok := constIntValue(node)
+	return ok && v == 0
+}
+
+// checkDivisionByZero reports an error if the divisor of '/' or '%' operator is a constant zero.
+func (p *astParser) checkDivisionByZero(operator *node32, divisor ast.Node) {
+	if isConstZero(divisor) {
+		p.addError(operator.token32, "Division by zero in constant expression")
+	}
+}
+
+// checkFractionDivisor reports an error if the denominator of 'fraction' function call is a constant zero.
+func (p *astParser) checkFractionDivisor(name *node32, args []ast.Node) {
+	if len(args) > fractionDivisorPosition && isConstZero(args[fractionDivisorPosition]) {
+		p.addError(name.token32, "Division by zero in constant expression: denominator of 'fraction' is zero")
+	}
+}
@@ -0,0 +1,29 @@
+// Package estimation compiles RIDE scripts and estimates their complexities with the latest tree estimator.
+// It's separated from the compiler package because the tests of the ride package, where the estimators are
+// implemented, depend on the compiler.
+package estimation
+
+import (
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+)
+
+// CompileWithComplexity compiles and serializes the code and estimates the produced tree with the latest estimator.
+// The complexities of callables are returned by names, the complexity of the verifier is returned by
+// compiler.VerifierComplexityKey if the script has one.
+func CompileWithComplexity(src string) ([]byte, map[string]int, []error) {
+	return compiler.CompileWithComplexity(src, Estimator(nil))
+}
+
+// Estimator returns the estimator of the latest version with the catalogue of functions extended by the given
+// builtins, see ride.EstimateTreeWithBuiltins.
+func Estimator(builtins map[string]int) compiler.Estimator {
+	return func(tree *ast.Tree) (map[string]int, int, error) {
+		est, err := ride.EstimateTreeWithBuiltins(tree, ride.LatestEstimatorVersion, builtins)
+		if err != nil {
+			return nil, 0, err
+		}
+		return est.Functions, est.Verifier, nil
+	}
+}
@@ -0,0 +1,44 @@
+package estimation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/compiler"
+)
+
+func TestCompileWithComplexity(t *testing.T) {
+	const code = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func light() = [IntegerEntry("light", 1)]
+
+@Callable(i)
+func heavy(data: ByteVector) = {
+    let h = sha256_16Kb(keccak256_16Kb(blake2b256_16Kb(data)))
+    [BinaryEntry("hash", h)]
+}
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)
+`
+	res, complexities, errs := CompileWithComplexity(code)
+	require.Empty(t, errs)
+	plain, errs := compiler.Compile(code, false, false)
+	require.Empty(t, errs)
+	assert.Equal(t, plain, res)
+
+	require.Len(t, complexities, 3)
+	assert.Positive(t, complexities["light"])
+	assert.Greater(t, complexities["heavy"], complexities["light"])
+	assert.Positive(t, complexities[compiler.VerifierComplexityKey])
+
+	_, complexities, errs = CompileWithComplexity(code + "\nlet x = ")
+	assert.NotEmpty(t, errs)
+	assert.Nil(t, complexities)
+}
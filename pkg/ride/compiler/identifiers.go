@@ -0,0 +1,49 @@
+package compiler
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// nonASCIIIdentifiersWarnings scans the source code for identifiers containing non-ASCII letters or digits.
+// The grammar allows only ASCII identifiers, but identifiers with homoglyphs (like Cyrillic 'а' instead of
+// Latin 'a') look exactly as valid ones, so on the parsing failure the warnings point to its real cause.
+// Strings literals and comments are skipped.
+func nonASCIIIdentifiersWarnings(code string) []error {
+	buffer := []rune(code)
+	var warnings []error
+	for i := 0; i < len(buffer); {
+		switch r := buffer[i]; {
+		case r == '#':
+			for i < len(buffer) && buffer[i] != '\n' {
+				i++
+			}
+		case r == '"':
+			for i++; i < len(buffer) && buffer[i] != '"'; i++ {
+				if buffer[i] == '\\' {
+					i++
+				}
+			}
+			i++
+		case isIdentifierRune(r):
+			begin, ascii := i, true
+			for ; i < len(buffer) && isIdentifierRune(buffer[i]); i++ {
+				if buffer[i] > unicode.MaxASCII {
+					ascii = false
+				}
+			}
+			if !ascii {
+				msg := fmt.Sprintf("Identifier '%s' contains non-ASCII characters", string(buffer[begin:i]))
+				token := token32{begin: uint32(begin), end: uint32(i)}
+				warnings = append(warnings, newASTError(msg, token, buffer, ""))
+			}
+		default:
+			i++
+		}
+	}
+	return warnings
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
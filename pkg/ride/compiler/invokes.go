@@ -0,0 +1,99 @@
+package compiler
+
+import (
+	stderrs "errors"
+
+	"github.com/mr-tron/base58"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+const (
+	invokeFunctionID          = "1020"
+	reentrantInvokeFunctionID = "1021"
+)
+
+// InvokeCallSite describes a call of another dApp with `invoke` or `reentrantInvoke` function.
+type InvokeCallSite struct {
+	// Line and Column are the position of the call in the source code.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	// Reentrant is true for calls of `reentrantInvoke`.
+	Reentrant bool `json:"reentrant"`
+	// Callable is the name of the callable function containing the call,
+	// it's empty if the call is made inside a user function.
+	Callable string `json:"callable,omitempty"`
+	// Target is the dApp address in Base58, "alias:<name>" or "this" if it's known statically, otherwise it's empty.
+	Target string `json:"target,omitempty"`
+	// Function is the name of the called function if it's known statically, otherwise it's empty.
+	Function string `json:"function,omitempty"`
+}
+
+// InvokeCallSites compiles the dApp source code and returns all calls of `invoke` and `reentrantInvoke` functions
+// in the order of appearance.
+func InvokeCallSites(src string) ([]InvokeCallSite, error) {
+	ap, err := parseAST(src, Options{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ap.errorsList) > 0 {
+		return nil, stderrs.Join(ap.errorsList...)
+	}
+	return ap.invokeCallSites, nil
+}
+
+func (p *astParser) recordInvokeCallSite(token token32, id ast.Function, args []ast.Node) {
+	if _, ok := id.(ast.NativeFunction); !ok {
+		return
+	}
+	var reentrant bool
+	switch id.Name() {
+	case invokeFunctionID:
+		reentrant = false
+	case reentrantInvokeFunctionID:
+		reentrant = true
+	default:
+		return
+	}
+	begin := int(token.begin)
+	pos := translatePositions(p.buffer, []int{begin})[begin]
+	cs := InvokeCallSite{Line: pos.line, Column: pos.symbol, Reentrant: reentrant}
+	if len(args) > 0 {
+		cs.Target = staticInvokeTarget(args[0])
+	}
+	if len(args) > 1 {
+		switch n := args[1].(type) {
+		case *ast.StringNode:
+			cs.Function = n.Value
+		case *ast.ReferenceNode:
+			if n.Name == "unit" {
+				cs.Function = "default"
+			}
+		}
+	}
+	p.invokeCallSites = append(p.invokeCallSites, cs)
+}
+
+func staticInvokeTarget(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.ReferenceNode:
+		if n.Name == "this" {
+			return "this"
+		}
+	case *ast.FunctionCallNode:
+		if len(n.Arguments) != 1 {
+			return ""
+		}
+		switch n.Function.Name() {
+		case "Address":
+			if b, ok := n.Arguments[0].(*ast.BytesNode); ok {
+				return base58.Encode(b.Value)
+			}
+		case "Alias":
+			if s, ok := n.Arguments[0].(*ast.StringNode); ok {
+				return "alias:" + s.Value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvokeCallSites(t *testing.T) {
+	const src = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+func callUnknown(dApp: Address) = invoke(dApp, "unknown", [], [])
+
+@Callable(i)
+func swap(amount: Int) = {
+  strict a = invoke(Address(base58'3MzRLn6ofv5Ygy2QmPq8AzMA8vS2kJBCXAg'), "deposit", [amount], [])
+  strict b = reentrantInvoke(Alias("pool"), unit, [], [])
+  strict c = callUnknown(i.caller)
+  []
+}
+
+@Callable(i)
+func loop() = {
+  strict r = reentrantInvoke(this, "loop", [], [])
+  []
+}
+`
+	sites, err := InvokeCallSites(src)
+	require.NoError(t, err)
+	expected := []InvokeCallSite{
+		{Line: 6, Column: 35, Reentrant: false, Function: "unknown"},
+		{Line: 10, Column: 14, Reentrant: false, Callable: "swap",
+			Target: "3MzRLn6ofv5Ygy2QmPq8AzMA8vS2kJBCXAg", Function: "deposit"},
+		{Line: 11, Column: 14, Reentrant: true, Callable: "swap", Target: "alias:pool", Function: "default"},
+		{Line: 18, Column: 14, Reentrant: true, Callable: "loop", Target: "this", Function: "loop"},
+	}
+	assert.Equal(t, expected, sites)
+
+	sites, err = InvokeCallSites(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func f() = []
+`)
+	require.NoError(t, err)
+	assert.Empty(t, sites)
+
+	_, err = InvokeCallSites(`
+{-# STDLIB_VERSION 4 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func f() = {
+  strict r = invoke(this, "f", [], [])
+  []
+}
+`)
+	assert.Error(t, err) // invoke is not available before V5
+}
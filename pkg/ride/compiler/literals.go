@@ -0,0 +1,14 @@
+package compiler
+
+// DefaultMaxLiteralSize is the default size in bytes of string or byte vector literal above which the compiler
+// reports a warning.
+const DefaultMaxLiteralSize = 1024
+
+// checkLiteralSize reports the warning if the literal is larger than the limit. Large constants bloat the script
+// and usually are the data that should be kept in the state. Zero or negative limit disables the check.
+func (p *astParser) checkLiteralSize(token token32, kind string, size int) {
+	if p.maxLiteralSize <= 0 || size <= p.maxLiteralSize {
+		return
+	}
+	p.addWarning(token, "Size %d bytes of %s literal exceeds the limit %d", size, kind, p.maxLiteralSize)
+}
@@ -0,0 +1,48 @@
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	stderrs "errors"
+)
+
+// ManifestFile is a file used in the compilation along with the hex encoded SHA-256 hash of its content.
+type ManifestFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+func newManifestFile(path string, content []byte) ManifestFile {
+	h := sha256.Sum256(content)
+	return ManifestFile{Path: path, SHA256: hex.EncodeToString(h[:])}
+}
+
+// Manifest lists the compiled script and all the files included into it with IMPORT directive, including
+// the files imported by other libraries. Given the manifest, a reviewer can confirm exactly what was compiled.
+type Manifest struct {
+	Script   ManifestFile   `json:"script"`
+	Includes []ManifestFile `json:"includes"`
+}
+
+// BuildManifest compiles the script with the given built-in functions and returns its manifest, the path is
+// recorded as the path of the script. Includes are listed in the order they were loaded, a file imported several
+// times is listed once.
+func BuildManifest(path, src string, builtins []Builtin) (*Manifest, error) {
+	ap, err := parseAST(src, Options{Builtins: builtins})
+	if err != nil {
+		return nil, err
+	}
+	if len(ap.errorsList) > 0 {
+		return nil, stderrs.Join(ap.errorsList...)
+	}
+	includes := make([]ManifestFile, 0, len(ap.imports))
+	seen := make(map[ManifestFile]struct{}, len(ap.imports))
+	for _, f := range ap.imports {
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		includes = append(includes, f)
+	}
+	return &Manifest{Script: newManifestFile(path, []byte(src)), Includes: includes}, nil
+}
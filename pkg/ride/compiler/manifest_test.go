@@ -0,0 +1,54 @@
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildManifest(t *testing.T) {
+	hash := func(t *testing.T, path string) string {
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		h := sha256.Sum256(b)
+		return hex.EncodeToString(h[:])
+	}
+	src := `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+{-# IMPORT lib_test_scripts/lib-bar-nested.ride, lib_test_scripts/lib-baz-1.ride #-}
+
+@Callable(i)
+func call() = [IntegerEntry("bar", bar(baz))]
+`
+	m, err := BuildManifest("dapp.ride", src, nil)
+	require.NoError(t, err)
+	srcHash := sha256.Sum256([]byte(src))
+	assert.Equal(t, ManifestFile{Path: "dapp.ride", SHA256: hex.EncodeToString(srcHash[:])}, m.Script)
+	var expected []ManifestFile
+	for _, path := range []string{
+		"lib_test_scripts/lib-bar-nested.ride",
+		"lib_test_scripts/lib-foo-1.ride", // imported by lib-bar-nested.ride
+		"lib_test_scripts/lib-baz-1.ride",
+	} {
+		expected = append(expected, ManifestFile{Path: path, SHA256: hash(t, path)})
+	}
+	assert.Equal(t, expected, m.Includes)
+
+	m, err = BuildManifest("expr.ride", "{-# STDLIB_VERSION 6 #-}\ntrue", nil)
+	require.NoError(t, err)
+	assert.Empty(t, m.Includes)
+
+	_, err = BuildManifest("missing.ride", `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+{-# IMPORT lib_test_scripts/missing.ride #-}
+`, nil)
+	assert.Error(t, err)
+}
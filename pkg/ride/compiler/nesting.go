@@ -0,0 +1,87 @@
+package compiler
+
+// DefaultMaxNestingDepth is the default limit of nesting of conditional expressions (if and match)
+// above which the compiler reports a warning.
+const DefaultMaxNestingDepth = 5
+
+// checkNesting reports the warning for every outermost conditional expression which nesting depth exceeds
+// the limit. Chains of 'else if' are not considered nested. Zero or negative limit disables the check.
+func (p *astParser) checkNesting(node *node32) {
+	if p.maxNestingDepth <= 0 {
+		return
+	}
+	for n := node; n != nil; n = n.next {
+		if n.pegRule == ruleIfWithError || n.pegRule == ruleMatch {
+			if d := nestingDepth(n); d > p.maxNestingDepth {
+				p.addWarning(n.token32, "Nesting depth %d of conditional expressions exceeds the limit %d",
+					d, p.maxNestingDepth)
+			}
+			continue // nested expressions are not deeper than the outermost one
+		}
+		p.checkNesting(n.up)
+	}
+}
+
+// nestingDepth returns the maximal number of conditional expressions nested into each other in the node's subtree.
+func nestingDepth(node *node32) int {
+	switch node.pegRule {
+	case ruleIfWithError:
+		return ifNestingDepth(node)
+	case ruleMatch:
+		return 1 + childrenNestingDepth(node)
+	default:
+		return childrenNestingDepth(node)
+	}
+}
+
+func childrenNestingDepth(node *node32) int {
+	depth := 0
+	for n := node.up; n != nil; n = n.next {
+		depth = max(depth, nestingDepth(n))
+	}
+	return depth
+}
+
+func ifNestingDepth(node *node32) int {
+	ifNode := node.up
+	var elseNode *node32
+	if isRule(ifNode, ruleIf) {
+		for n := ifNode.up; n != nil; n = n.next {
+			if n.pegRule != rule_ {
+				elseNode = n
+			}
+		}
+	}
+	depth, chained := 0, 0
+	for n := ifNode.up; n != nil; n = n.next {
+		if n == elseNode {
+			if elseIf := chainedIf(n); elseIf != nil {
+				chained = ifNestingDepth(elseIf)
+				continue
+			}
+		}
+		depth = max(depth, nestingDepth(n))
+	}
+	return max(depth+1, chained)
+}
+
+// chainedIf returns the conditional expression if it's the only content of the node, like in 'else if' chain.
+func chainedIf(node *node32) *node32 {
+	for n := node; n != nil; {
+		if n.pegRule == ruleIfWithError {
+			return n
+		}
+		var child *node32
+		for c := n.up; c != nil; c = c.next {
+			if c.pegRule == rule_ {
+				continue
+			}
+			if child != nil {
+				return nil
+			}
+			child = c
+		}
+		n = child
+	}
+	return nil
+}
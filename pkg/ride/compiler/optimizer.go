@@ -0,0 +1,433 @@
+package compiler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+const (
+	// csePrefix is the prefix of the names of variables introduced by the optimizer, the prefix can't be used
+	// in the script code, so the names never clash with the user's ones.
+	csePrefix = "$cse"
+
+	extrNativePrefix = "@extrNative("
+	extrUserPrefix   = "@extrUser("
+)
+
+// impureNativeFunctions are the native functions which results depend on the blockchain state or which have
+// side effects. The state can be changed by the invocations of other dApps during the script evaluation,
+// so the results of such functions can't be reused.
+var impureNativeFunctions = map[string]struct{}{
+	"2":    {}, // throw
+	"1000": {}, // transactionByID
+	"1001": {}, // transactionHeightByID
+	"1003": {}, // assetBalance
+	"1004": {}, // assetInfo
+	"1005": {}, // blockInfoByHeight
+	"1006": {}, // transferByID
+	"1007": {}, // wavesBalance
+	"1008": {}, // assetBalance
+	"1009": {}, // scriptHash
+	"1020": {}, // invoke
+	"1021": {}, // reentrantInvoke
+	"1050": {}, // getInteger
+	"1051": {}, // getBoolean
+	"1052": {}, // getBinary
+	"1053": {}, // getString
+	"1054": {}, // isDataStorageUntouched
+	"1055": {}, // getInteger of this
+	"1056": {}, // getBoolean of this
+	"1057": {}, // getBinary of this
+	"1058": {}, // getString of this
+	"1060": {}, // addressFromRecipient
+}
+
+// impureUserFunctions are the user functions of the standard library which results depend on the blockchain
+// state or which have side effects.
+var impureUserFunctions = map[string]struct{}{
+	"throw":        {},
+	"wavesBalance": {},
+}
+
+// optimizer deduplicates identical pure subexpressions of the tree by hoisting them into shared variables.
+// Variables of RIDE are lazy, the hoisted expression is evaluated on the first reference which is the place of
+// its first occurrence in the original code, so the order of evaluation doesn't change.
+type optimizer struct {
+	functions  map[string][]*ast.FunctionDeclarationNode
+	purity     map[string]bool
+	inProgress map[string]struct{}
+	counter    int
+	hoisted    int
+}
+
+// Optimize replaces the repeated pure subexpressions of the tree with the references to shared variables and
+// returns the number of introduced variables. Only the calls of functions without side effects and independent
+// of the blockchain state are deduplicated.
+func Optimize(tree *ast.Tree) int {
+	o := &optimizer{
+		functions:  make(map[string][]*ast.FunctionDeclarationNode),
+		purity:     make(map[string]bool),
+		inProgress: make(map[string]struct{}),
+	}
+	for _, d := range tree.Declarations {
+		o.collectFunctions(d)
+	}
+	for _, f := range tree.Functions {
+		o.collectFunctions(f)
+	}
+	if tree.Verifier != nil {
+		o.collectFunctions(tree.Verifier)
+	}
+	for _, d := range tree.Declarations {
+		switch n := d.(type) {
+		case *ast.AssignmentNode:
+			n.Expression = o.optimize(n.Expression)
+		case *ast.FunctionDeclarationNode:
+			n.Body = o.optimize(n.Body)
+		}
+	}
+	for _, f := range tree.Functions {
+		fn := f.(*ast.FunctionDeclarationNode)
+		fn.Body = o.optimize(fn.Body)
+	}
+	switch v := tree.Verifier.(type) {
+	case nil:
+	case *ast.FunctionDeclarationNode:
+		if tree.IsDApp() {
+			v.Body = o.optimize(v.Body)
+		} else {
+			tree.Verifier = o.optimize(v)
+		}
+	default:
+		tree.Verifier = o.optimize(v)
+	}
+	return o.hoisted
+}
+
+func (o *optimizer) collectFunctions(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.FunctionDeclarationNode:
+		o.functions[n.Name] = append(o.functions[n.Name], n)
+		o.collectFunctions(n.Body)
+		o.collectFunctions(n.Block)
+	case *ast.AssignmentNode:
+		o.collectFunctions(n.Expression)
+		o.collectFunctions(n.Block)
+	case *ast.ConditionalNode:
+		o.collectFunctions(n.Condition)
+		o.collectFunctions(n.TrueExpression)
+		o.collectFunctions(n.FalseExpression)
+	case *ast.FunctionCallNode:
+		for _, a := range n.Arguments {
+			o.collectFunctions(a)
+		}
+	case *ast.PropertyNode:
+		o.collectFunctions(n.Object)
+	}
+}
+
+// optimize deduplicates the subexpressions of the region and then of its nested regions. The region is the
+// expression at the top of which the shared variables are declared.
+func (o *optimizer) optimize(region ast.Node) ast.Node {
+	region = o.optimizeRegion(region)
+	o.optimizeNested(region)
+	return region
+}
+
+func (o *optimizer) optimizeNested(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		n.Expression = o.optimize(n.Expression)
+		n.Block = o.optimize(n.Block)
+	case *ast.FunctionDeclarationNode:
+		n.Body = o.optimize(n.Body)
+		n.Block = o.optimize(n.Block)
+	case *ast.ConditionalNode:
+		o.optimizeNested(n.Condition)
+		o.optimizeNested(n.TrueExpression)
+		o.optimizeNested(n.FalseExpression)
+	case *ast.FunctionCallNode:
+		for _, a := range n.Arguments {
+			o.optimizeNested(a)
+		}
+	case *ast.PropertyNode:
+		o.optimizeNested(n.Object)
+	}
+}
+
+// optimizeRegion hoists repeated subexpressions to the top of the region, the largest expressions go first.
+func (o *optimizer) optimizeRegion(region ast.Node) ast.Node {
+	bound := make(map[string]struct{})
+	boundNames(region, bound)
+	for {
+		c := newCandidates()
+		o.collectCandidates(region, bound, c)
+		key, ok := c.best()
+		if !ok {
+			return region
+		}
+		name := o.freshName()
+		// Introduced variable is declared at the top of the region, so expressions referencing it can't be hoisted.
+		bound[name] = struct{}{}
+		expression := c.nodes[key]
+		region = o.replaceExpression(region, key, name)
+		region = ast.NewAssignmentNode(name, expression, region)
+		o.hoisted++
+	}
+}
+
+func (o *optimizer) freshName() string {
+	name := csePrefix + strconv.Itoa(o.counter)
+	o.counter++
+	return name
+}
+
+// candidates counts the occurrences of the subexpressions by their keys.
+type candidates struct {
+	counts map[string]int
+	sizes  map[string]int
+	nodes  map[string]ast.Node
+	order  []string
+}
+
+func newCandidates() *candidates {
+	return &candidates{
+		counts: make(map[string]int),
+		sizes:  make(map[string]int),
+		nodes:  make(map[string]ast.Node),
+	}
+}
+
+func (c *candidates) add(key string, size int, node ast.Node) {
+	if _, ok := c.counts[key]; !ok {
+		c.order = append(c.order, key)
+		c.sizes[key] = size
+		c.nodes[key] = node
+	}
+	c.counts[key]++
+}
+
+// best returns the key of the largest expression which occurs more than once. The first one in the order of
+// appearance is selected among the expressions of the same size to make the result deterministic.
+func (c *candidates) best() (string, bool) {
+	best, found := "", false
+	for _, key := range c.order {
+		if c.counts[key] < 2 {
+			continue
+		}
+		if !found || c.sizes[key] > c.sizes[best] {
+			best, found = key, true
+		}
+	}
+	return best, found
+}
+
+// collectCandidates registers the pure function calls of the region. Expressions referencing the names bound
+// inside the region are skipped, because such names can refer to different values in different places.
+func (o *optimizer) collectCandidates(node ast.Node, bound map[string]struct{}, c *candidates) {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		o.collectCandidates(n.Expression, bound, c)
+		o.collectCandidates(n.Block, bound, c)
+		return
+	case *ast.FunctionDeclarationNode:
+		o.collectCandidates(n.Body, bound, c)
+		o.collectCandidates(n.Block, bound, c)
+		return
+	case *ast.ConditionalNode:
+		o.collectCandidates(n.Condition, bound, c)
+		o.collectCandidates(n.TrueExpression, bound, c)
+		o.collectCandidates(n.FalseExpression, bound, c)
+	case *ast.FunctionCallNode:
+		for _, a := range n.Arguments {
+			o.collectCandidates(a, bound, c)
+		}
+	case *ast.PropertyNode:
+		o.collectCandidates(n.Object, bound, c)
+	default:
+		return
+	}
+	if _, ok := node.(*ast.FunctionCallNode); !ok {
+		return
+	}
+	key, size, ok := o.expressionKey(node, bound)
+	if ok {
+		c.add(key, size, node)
+	}
+}
+
+// expressionKey returns the structural key and the size of the pure expression. False is returned if the
+// expression is impure, declares names or references the names from the given set.
+func (o *optimizer) expressionKey(node ast.Node, bound map[string]struct{}) (string, int, bool) {
+	switch n := node.(type) {
+	case *ast.LongNode:
+		return "L" + strconv.FormatInt(n.Value, 10), 1, true
+	case *ast.BytesNode:
+		return "B" + hex.EncodeToString(n.Value), 1, true
+	case *ast.StringNode:
+		return "S" + strconv.Quote(n.Value), 1, true
+	case *ast.BooleanNode:
+		return "T" + strconv.FormatBool(n.Value), 1, true
+	case *ast.ReferenceNode:
+		if _, ok := bound[n.Name]; ok {
+			return "", 0, false
+		}
+		return "R" + n.Name, 1, true
+	case *ast.PropertyNode:
+		key, size, ok := o.expressionKey(n.Object, bound)
+		if !ok {
+			return "", 0, false
+		}
+		return fmt.Sprintf("P%s(%s)", n.Name, key), size + 1, true
+	case *ast.ConditionalNode:
+		keys := make([]string, 0, 3)
+		size := 1
+		for _, e := range []ast.Node{n.Condition, n.TrueExpression, n.FalseExpression} {
+			key, s, ok := o.expressionKey(e, bound)
+			if !ok {
+				return "", 0, false
+			}
+			keys = append(keys, key)
+			size += s
+		}
+		return "I(" + strings.Join(keys, ",") + ")", size, true
+	case *ast.FunctionCallNode:
+		if _, ok := bound[n.Function.Name()]; ok || !o.isPure(n.Function) {
+			return "", 0, false
+		}
+		keys := make([]string, 0, len(n.Arguments))
+		size := 1
+		for _, a := range n.Arguments {
+			key, s, ok := o.expressionKey(a, bound)
+			if !ok {
+				return "", 0, false
+			}
+			keys = append(keys, key)
+			size += s
+		}
+		return fmt.Sprintf("C%s:%s(%s)", n.Function.Type(), n.Function.Name(), strings.Join(keys, ",")), size, true
+	default:
+		return "", 0, false
+	}
+}
+
+// isPure checks that the function has no side effects and doesn't depend on the blockchain state. The functions
+// declared in the script are pure if all their declarations call only pure functions.
+func (o *optimizer) isPure(fn ast.Function) bool {
+	name := fn.Name()
+	if _, ok := fn.(ast.NativeFunction); ok {
+		_, impure := impureNativeFunctions[name]
+		return !impure
+	}
+	if strings.HasPrefix(name, extrNativePrefix) {
+		return o.isPure(ast.NativeFunction(strings.TrimSuffix(strings.TrimPrefix(name, extrNativePrefix), ")")))
+	}
+	if strings.HasPrefix(name, extrUserPrefix) {
+		return o.isPure(ast.UserFunction(strings.TrimSuffix(strings.TrimPrefix(name, extrUserPrefix), ")")))
+	}
+	declarations, ok := o.functions[name]
+	if !ok {
+		_, impure := impureUserFunctions[name]
+		return !impure
+	}
+	if pure, ok := o.purity[name]; ok {
+		return pure
+	}
+	if _, ok := o.inProgress[name]; ok {
+		return false
+	}
+	o.inProgress[name] = struct{}{}
+	pure := true
+	for _, d := range declarations {
+		if !o.callsOnlyPure(d.Body) {
+			pure = false
+			break
+		}
+	}
+	delete(o.inProgress, name)
+	o.purity[name] = pure
+	return pure
+}
+
+func (o *optimizer) callsOnlyPure(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		return o.callsOnlyPure(n.Expression) && o.callsOnlyPure(n.Block)
+	case *ast.FunctionDeclarationNode:
+		return o.callsOnlyPure(n.Body) && o.callsOnlyPure(n.Block)
+	case *ast.ConditionalNode:
+		return o.callsOnlyPure(n.Condition) && o.callsOnlyPure(n.TrueExpression) &&
+			o.callsOnlyPure(n.FalseExpression)
+	case *ast.FunctionCallNode:
+		if !o.isPure(n.Function) {
+			return false
+		}
+		for _, a := range n.Arguments {
+			if !o.callsOnlyPure(a) {
+				return false
+			}
+		}
+		return true
+	case *ast.PropertyNode:
+		return o.callsOnlyPure(n.Object)
+	default:
+		return true
+	}
+}
+
+// boundNames collects the names of variables, functions and function arguments declared in the expression.
+func boundNames(node ast.Node, names map[string]struct{}) {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		names[n.Name] = struct{}{}
+		boundNames(n.Expression, names)
+		boundNames(n.Block, names)
+	case *ast.FunctionDeclarationNode:
+		names[n.Name] = struct{}{}
+		for _, a := range n.Arguments {
+			names[a] = struct{}{}
+		}
+		boundNames(n.Body, names)
+		boundNames(n.Block, names)
+	case *ast.ConditionalNode:
+		boundNames(n.Condition, names)
+		boundNames(n.TrueExpression, names)
+		boundNames(n.FalseExpression, names)
+	case *ast.FunctionCallNode:
+		for _, a := range n.Arguments {
+			boundNames(a, names)
+		}
+	case *ast.PropertyNode:
+		boundNames(n.Object, names)
+	}
+}
+
+// replaceExpression replaces all occurrences of the expression with the given key by the reference to the variable.
+func (o *optimizer) replaceExpression(node ast.Node, key, name string) ast.Node {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		n.Expression = o.replaceExpression(n.Expression, key, name)
+		n.Block = o.replaceExpression(n.Block, key, name)
+	case *ast.FunctionDeclarationNode:
+		n.Body = o.replaceExpression(n.Body, key, name)
+		n.Block = o.replaceExpression(n.Block, key, name)
+	case *ast.ConditionalNode:
+		n.Condition = o.replaceExpression(n.Condition, key, name)
+		n.TrueExpression = o.replaceExpression(n.TrueExpression, key, name)
+		n.FalseExpression = o.replaceExpression(n.FalseExpression, key, name)
+	case *ast.FunctionCallNode:
+		if k, _, ok := o.expressionKey(n, nil); ok && k == key {
+			return ast.NewReferenceNode(name)
+		}
+		for i, a := range n.Arguments {
+			n.Arguments[i] = o.replaceExpression(a, key, name)
+		}
+	case *ast.PropertyNode:
+		n.Object = o.replaceExpression(n.Object, key, name)
+	}
+	return node
+}
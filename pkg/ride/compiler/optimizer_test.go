@@ -0,0 +1,69 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+func TestOptimize(t *testing.T) {
+	const header = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+`
+	for _, test := range []struct {
+		name    string
+		code    string
+		hoisted int
+	}{
+		{"pure", `
+let s = "abcdefghij"
+let a = size(s + s) * 2
+let b = size(s + s) + 1
+func f(x: Int) = x * size(s + s)
+a + b + f(1) == 81 && sha256(toBytes(a)) == sha256(toBytes(a))`, 2},
+		{"state", `getInteger(this, "key") == getInteger(this, "key")`, 0},
+		{"impure argument", `value(getInteger(this, "key")) == value(getInteger(this, "key"))`, 0},
+		{"pure user function", `
+func g(x: Int) = x + 1
+g(2) + g(2) == 6`, 1},
+		{"impure user function", `
+func h() = getIntegerValue(this, "key")
+h() + h() == 2`, 0},
+		{"shadowing", `
+let x = 1
+func f(x: Int) = x * 10
+f(2) + x * 10 == 30`, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tree, errs := CompileToTree(header + test.code)
+			require.Empty(t, errs)
+			assert.Equal(t, test.hoisted, Optimize(tree))
+		})
+	}
+}
+
+func TestOptimizeHoistsToRegionTop(t *testing.T) {
+	tree, errs := CompileToTree(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+size("abc" + "def") + size("abc" + "def") == 12`)
+	require.Empty(t, errs)
+	require.Equal(t, 1, Optimize(tree))
+	let, ok := tree.Verifier.(*ast.AssignmentNode)
+	require.True(t, ok)
+	assert.Equal(t, "$cse0", let.Name)
+	call, ok := let.Expression.(*ast.FunctionCallNode)
+	require.True(t, ok)
+	assert.Equal(t, ast.NativeFunction("305"), call.Function)
+	eq, ok := let.Block.(*ast.FunctionCallNode)
+	require.True(t, ok)
+	sum, ok := eq.Arguments[0].(*ast.FunctionCallNode)
+	require.True(t, ok)
+	assert.Equal(t, []ast.Node{ast.NewReferenceNode("$cse0"), ast.NewReferenceNode("$cse0")}, sum.Arguments)
+}
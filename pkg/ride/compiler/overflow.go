@@ -0,0 +1,116 @@
+package compiler
+
+import (
+	stdmath "math"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/math"
+	"github.com/wavesplatform/gowaves/pkg/util/common"
+)
+
+const (
+	sumFunctionID = "100"
+	subFunctionID = "101"
+	mulFunctionID = "104"
+	divFunctionID = "105"
+	modFunctionID = "106"
+
+	// riskyMultiplierThreshold is the absolute value of the constant multiplier starting from which
+	// multiplication by a value known only in runtime is considered overflow-prone.
+	riskyMultiplierThreshold = 1 << 32
+	// riskySummandThreshold is the absolute value of the constant summand starting from which
+	// addition or subtraction of a value known only in runtime is considered overflow-prone.
+	riskySummandThreshold = 1 << 62
+)
+
+// constIntValue returns the value of constant integer expression built from literals with '+', '-', '*', '/' and '%'
+// operators.
+// The second result is false if the expression isn't constant or its evaluation overflows.
+func constIntValue(node ast.Node) (int64, bool) {
+	switch n := node.(type) {
+	case *ast.LongNode:
+		return n.Value, true
+	case *ast.FunctionCallNode:
+		if len(n.Arguments) != 2 {
+			return 0, false
+		}
+		if _, ok := n.Function.(ast.NativeFunction); !ok {
+			return 0, false
+		}
+		a, ok := constIntValue(n.Arguments[0])
+		if !ok {
+			return 0, false
+		}
+		b, ok := constIntValue(n.Arguments[1])
+		if !ok {
+			return 0, false
+		}
+		v, err := foldIntOperation(n.Function.Name(), a, b)
+		if err != nil {
+			return 0, false
+		}
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func foldIntOperation(id string, a, b int64) (int64, error) {
+	switch id {
+	case sumFunctionID:
+		return common.AddInt(a, b)
+	case subFunctionID:
+		return common.SubInt(a, b)
+	case mulFunctionID:
+		return common.MulInt(a, b)
+	case divFunctionID:
+		if b == 0 {
+			return 0, errors.New("division by zero")
+		}
+		if a == stdmath.MinInt64 && b == -1 {
+			return 0, errors.New("div: integer overflow")
+		}
+		return math.FloorDiv(a, b), nil
+	case modFunctionID:
+		if b == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return math.ModDivision(a, b), nil
+	default:
+		return 0, errors.Errorf("unsupported operation '%s'", id)
+	}
+}
+
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
+
+// checkIntOverflow reports an error if the integer operation on constant operands certainly overflows and
+// a warning if one of the operands is a large constant and another one is known only in runtime.
+func (p *astParser) checkIntOverflow(node *node32, op string, left, right ast.Node) {
+	a, aConst := constIntValue(left)
+	b, bConst := constIntValue(right)
+	switch {
+	case aConst && bConst:
+		if _, err := foldIntOperation(op, a, b); err != nil {
+			p.addError(node.token32, "Integer overflow in constant expression: %v", err)
+		}
+	case aConst || bConst:
+		c := a
+		if bConst {
+			c = b
+		}
+		threshold := uint64(riskySummandThreshold)
+		if op == mulFunctionID {
+			threshold = riskyMultiplierThreshold
+		}
+		if abs(c) >= threshold {
+			p.addWarning(node.token32, "Possible integer overflow: operation with large constant %d", c)
+		}
+	}
+}
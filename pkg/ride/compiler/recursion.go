@@ -0,0 +1,119 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// undefinedCall is the call of a function which is not declared at the place of the call. Such call is a part of
+// recursion if the called function is declared later and calls the caller back.
+type undefinedCall struct {
+	caller     string
+	callee     string
+	token      token32
+	errorIndex int
+}
+
+// recursionChecker collects the call graph of user functions to detect recursive definitions.
+type recursionChecker struct {
+	declaring []string
+	declared  map[string]token32
+	calls     map[string][]string
+	undefined []undefinedCall
+}
+
+func (p *astParser) beginFuncDeclaration(name string, token token32) {
+	if p.recursion.declared == nil {
+		p.recursion.declared = make(map[string]token32)
+		p.recursion.calls = make(map[string][]string)
+	}
+	p.recursion.declaring = append(p.recursion.declaring, name)
+	if _, ok := p.recursion.declared[name]; !ok {
+		p.recursion.declared[name] = token
+	}
+}
+
+func (p *astParser) endFuncDeclaration() {
+	p.recursion.declaring = p.recursion.declaring[:len(p.recursion.declaring)-1]
+}
+
+func (p *astParser) currentFunc() (string, bool) {
+	if len(p.recursion.declaring) == 0 {
+		return "", false
+	}
+	return p.recursion.declaring[len(p.recursion.declaring)-1], true
+}
+
+// recordUserCall adds the call of the user function to the call graph.
+func (p *astParser) recordUserCall(callee string) {
+	if caller, ok := p.currentFunc(); ok {
+		p.recursion.calls[caller] = append(p.recursion.calls[caller], callee)
+	}
+}
+
+// checkRecursiveCall reports an error if the function calls itself directly or from a nested function.
+func (p *astParser) checkRecursiveCall(callee string, token token32) bool {
+	for i, name := range p.recursion.declaring {
+		if name == callee {
+			cycle := append(append([]string{}, p.recursion.declaring[i:]...), callee)
+			p.addError(token, "%s", p.recursionMessage(cycle))
+			return true
+		}
+	}
+	return false
+}
+
+// recordUndefinedCall remembers the undefined function call for which the error was just reported.
+// The call is added to the call graph too, because the caller itself stays undefined after the error.
+func (p *astParser) recordUndefinedCall(callee string, token token32) {
+	if caller, ok := p.currentFunc(); ok {
+		p.recursion.calls[caller] = append(p.recursion.calls[caller], callee)
+		p.recursion.undefined = append(p.recursion.undefined, undefinedCall{
+			caller:     caller,
+			callee:     callee,
+			token:      token,
+			errorIndex: len(p.errorsList) - 1,
+		})
+	}
+}
+
+// checkRecursion replaces the errors about undefined functions with the errors about recursion if the function
+// declared later calls the caller back directly or indirectly.
+func (p *astParser) checkRecursion() {
+	for _, c := range p.recursion.undefined {
+		if _, ok := p.recursion.declared[c.callee]; !ok {
+			continue
+		}
+		path, ok := p.callPath(c.callee, c.caller, make(map[string]struct{}))
+		if !ok {
+			continue
+		}
+		cycle := append([]string{c.caller}, path...)
+		p.errorsList[c.errorIndex] = newASTError(p.recursionMessage(cycle), c.token, p.buffer, p.fileName)
+	}
+}
+
+// callPath returns the chain of calls from one function to another.
+func (p *astParser) callPath(from, to string, visited map[string]struct{}) ([]string, bool) {
+	if from == to {
+		return []string{to}, true
+	}
+	if _, ok := visited[from]; ok {
+		return nil, false
+	}
+	visited[from] = struct{}{}
+	for _, next := range p.recursion.calls[from] {
+		if path, ok := p.callPath(next, to, visited); ok {
+			return append([]string{from}, path...), true
+		}
+	}
+	return nil, false
+}
+
+func (p *astParser) recursionMessage(cycle []string) string {
+	members := make([]string, len(cycle))
+	for i, name := range cycle {
+		members[i] = fmt.Sprintf("%s (%s)", name, p.tokenPosition(p.recursion.declared[name]))
+	}
+	return "Recursive functions are not allowed, functions form a cycle: " + strings.Join(members, " -> ")
+}
@@ -0,0 +1,202 @@
+package compiler
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+// ReportSchemaVersion is the version of the report's JSON schema. It is incremented on every incompatible change
+// of the schema, new fields could be added without the change of the version.
+const ReportSchemaVersion = 1
+
+// Report is the machine-readable summary of the dApp's resource usage.
+type Report struct {
+	SchemaVersion int `json:"schemaVersion"`
+	StdlibVersion int `json:"stdlibVersion"`
+	// Complexity is the maximal complexity of the callables and verifier.
+	Complexity int              `json:"complexity"`
+	Callables  []CallableReport `json:"callables"`
+	Verifier   *VerifierReport  `json:"verifier,omitempty"`
+}
+
+// CallableReport describes the resource usage of the callable function.
+type CallableReport struct {
+	Name       string         `json:"name"`
+	Complexity int            `json:"complexity"`
+	Actions    ActionsReport  `json:"actions"`
+	DataKeys   DataKeysReport `json:"dataKeys"`
+}
+
+// VerifierReport describes the resource usage of the verifier function.
+type VerifierReport struct {
+	Complexity int            `json:"complexity"`
+	DataKeys   DataKeysReport `json:"dataKeys"`
+}
+
+// ActionsReport is the static count of the callable's actions, see ActionsCount.
+type ActionsReport struct {
+	Min     int  `json:"min"`
+	Max     int  `json:"max"`
+	Dynamic bool `json:"dynamic"`
+}
+
+// DataKeysReport lists the keys of data entries read from the blockchain state and written by the function.
+// Only the keys given as string literals are listed, if any key is computed in runtime the corresponding
+// dynamic flag is set.
+type DataKeysReport struct {
+	Reads         []string `json:"reads"`
+	Writes        []string `json:"writes"`
+	DynamicReads  bool     `json:"dynamicReads"`
+	DynamicWrites bool     `json:"dynamicWrites"`
+}
+
+// stateGetterKeyArgument maps the IDs of native functions reading data entries from the blockchain state
+// to the positions of the key argument.
+var stateGetterKeyArgument = map[string]int{
+	"1050": 1, // getInteger(addressOrAlias, key)
+	"1051": 1, // getBoolean(addressOrAlias, key)
+	"1052": 1, // getBinary(addressOrAlias, key)
+	"1053": 1, // getString(addressOrAlias, key)
+	"1055": 0, // getInteger(key) of this
+	"1056": 0, // getBoolean(key) of this
+	"1057": 0, // getBinary(key) of this
+	"1058": 0, // getString(key) of this
+}
+
+// NewReport builds the report of the dApp tree. The complexities of callables by names and the complexity of
+// the verifier are the results of the tree estimation.
+func NewReport(tree *ast.Tree, callables map[string]int, verifier int) (*Report, error) {
+	if !tree.IsDApp() {
+		return nil, errors.New("report is available only for dApp scripts")
+	}
+	c := newKeysCollector(tree)
+	r := &Report{
+		SchemaVersion: ReportSchemaVersion,
+		StdlibVersion: int(tree.LibVersion),
+		Callables:     make([]CallableReport, 0, len(tree.Functions)),
+	}
+	for _, node := range tree.Functions {
+		f, ok := node.(*ast.FunctionDeclarationNode)
+		if !ok {
+			continue
+		}
+		complexity, ok := callables[f.Name]
+		if !ok {
+			return nil, errors.Errorf("no complexity of callable '%s'", f.Name)
+		}
+		ac := callableActionsCount(f)
+		r.Callables = append(r.Callables, CallableReport{
+			Name:       f.Name,
+			Complexity: complexity,
+			Actions:    ActionsReport{Min: ac.Min, Max: ac.Max, Dynamic: ac.Dynamic},
+			DataKeys:   c.collect(f.Body),
+		})
+		r.Complexity = max(r.Complexity, complexity)
+	}
+	if tree.HasVerifier() {
+		var body ast.Node = tree.Verifier
+		if f, ok := tree.Verifier.(*ast.FunctionDeclarationNode); ok {
+			body = f.Body
+		}
+		r.Verifier = &VerifierReport{Complexity: verifier, DataKeys: c.collect(body)}
+		r.Complexity = max(r.Complexity, verifier)
+	}
+	return r, nil
+}
+
+// keysCollector collects the keys of data entries accessed by the expression and by the global user functions
+// called from it.
+type keysCollector struct {
+	functions map[string]*ast.FunctionDeclarationNode
+	visited   map[string]struct{}
+	reads     map[string]struct{}
+	writes    map[string]struct{}
+	report    DataKeysReport
+}
+
+func newKeysCollector(tree *ast.Tree) *keysCollector {
+	functions := make(map[string]*ast.FunctionDeclarationNode)
+	for _, d := range tree.Declarations {
+		if f, ok := d.(*ast.FunctionDeclarationNode); ok {
+			functions[f.Name] = f
+		}
+	}
+	return &keysCollector{functions: functions}
+}
+
+func (c *keysCollector) collect(node ast.Node) DataKeysReport {
+	c.visited = make(map[string]struct{})
+	c.reads = make(map[string]struct{})
+	c.writes = make(map[string]struct{})
+	c.report = DataKeysReport{}
+	c.walk(node)
+	c.report.Reads = sortedKeys(c.reads)
+	c.report.Writes = sortedKeys(c.writes)
+	return c.report
+}
+
+func (c *keysCollector) walk(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.AssignmentNode:
+		c.walk(n.Expression)
+		c.walk(n.Block)
+	case *ast.FunctionDeclarationNode:
+		c.walk(n.Body)
+		c.walk(n.Block)
+	case *ast.ConditionalNode:
+		c.walk(n.Condition)
+		c.walk(n.TrueExpression)
+		c.walk(n.FalseExpression)
+	case *ast.PropertyNode:
+		c.walk(n.Object)
+	case *ast.FunctionCallNode:
+		c.call(n)
+		for _, arg := range n.Arguments {
+			c.walk(arg)
+		}
+	}
+}
+
+func (c *keysCollector) call(n *ast.FunctionCallNode) {
+	name := n.Function.Name()
+	if strings.HasPrefix(name, extrNativePrefix) {
+		name = strings.TrimSuffix(strings.TrimPrefix(name, extrNativePrefix), ")")
+	}
+	if i, ok := stateGetterKeyArgument[name]; ok && i < len(n.Arguments) {
+		c.report.DynamicReads = !addKey(c.reads, n.Arguments[i]) || c.report.DynamicReads
+		return
+	}
+	if _, ok := actionsByConstructor[name]; ok && strings.HasSuffix(name, "Entry") && len(n.Arguments) > 0 {
+		c.report.DynamicWrites = !addKey(c.writes, n.Arguments[0]) || c.report.DynamicWrites
+		return
+	}
+	if f, ok := c.functions[name]; ok {
+		if _, seen := c.visited[name]; !seen {
+			c.visited[name] = struct{}{}
+			c.walk(f.Body)
+		}
+	}
+}
+
+// addKey adds the key to the set if it's a string literal, false is returned otherwise.
+func addKey(keys map[string]struct{}, node ast.Node) bool {
+	s, ok := node.(*ast.StringNode)
+	if !ok {
+		return false
+	}
+	keys[s.Value] = struct{}{}
+	return true
+}
+
+func sortedKeys(keys map[string]struct{}) []string {
+	res := make([]string, 0, len(keys))
+	for k := range keys {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,89 @@
+package compiler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReport(t *testing.T) {
+	const src = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+func counter() = this.getInteger("counter").valueOrElse(0)
+
+@Callable(i)
+func deposit(amount: Int) = {
+	let owner = getStringValue("owner")
+	if (amount > 100) then
+		[IntegerEntry("counter", counter() + 1), IntegerEntry("deposit", amount), StringEntry("owner", owner)]
+	else
+		[IntegerEntry("counter", counter() + 1)]
+}
+
+@Callable(i)
+func store(key: String, value: Int) = [IntegerEntry(key, value)] ++ [IntegerEntry("total", this.getIntegerValue(key))]
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey) && !getBooleanValue("locked")
+`
+	tree, errs := CompileToTree(src)
+	require.Empty(t, errs)
+
+	r, err := NewReport(tree, map[string]int{"deposit": 130, "store": 60}, 215)
+	require.NoError(t, err)
+
+	js, err := json.Marshal(r)
+	require.NoError(t, err)
+	expected := `{
+		"schemaVersion": 1,
+		"stdlibVersion": 6,
+		"complexity": 215,
+		"callables": [
+			{
+				"name": "deposit",
+				"complexity": 130,
+				"actions": {"min": 1, "max": 3, "dynamic": false},
+				"dataKeys": {
+					"reads": ["counter", "owner"],
+					"writes": ["counter", "deposit", "owner"],
+					"dynamicReads": false,
+					"dynamicWrites": false
+				}
+			},
+			{
+				"name": "store",
+				"complexity": 60,
+				"actions": {"min": 2, "max": 2, "dynamic": false},
+				"dataKeys": {
+					"reads": [],
+					"writes": ["total"],
+					"dynamicReads": true,
+					"dynamicWrites": true
+				}
+			}
+		],
+		"verifier": {
+			"complexity": 215,
+			"dataKeys": {
+				"reads": ["locked"],
+				"writes": [],
+				"dynamicReads": false,
+				"dynamicWrites": false
+			}
+		}
+	}`
+	assert.JSONEq(t, expected, string(js))
+
+	_, err = NewReport(tree, map[string]int{"deposit": 130}, 215)
+	assert.EqualError(t, err, "no complexity of callable 'store'")
+
+	expr, errs := CompileToTree("{-# STDLIB_VERSION 6 #-}\n{-# CONTENT_TYPE EXPRESSION #-}\ntrue")
+	require.Empty(t, errs)
+	_, err = NewReport(expr, nil, 0)
+	assert.Error(t, err)
+}
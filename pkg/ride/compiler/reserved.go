@@ -0,0 +1,39 @@
+package compiler
+
+import (
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	s "github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
+)
+
+// reservedIdentifiers returns the identifiers reserved by the standard library of the given version: the names
+// of built-in variables available in the script context and the names of built-in types.
+func reservedIdentifiers(version ast.LibraryVersion, dApp bool) map[string]struct{} {
+	res := make(map[string]struct{})
+	for i := 0; i < int(version); i++ {
+		for _, v := range s.Vars().Vars[i].Append {
+			res[v.Name] = struct{}{}
+		}
+		for _, name := range s.Vars().Vars[i].Remove {
+			delete(res, name)
+		}
+	}
+	if !dApp {
+		res["tx"] = struct{}{}
+	}
+	if version >= ast.LibV4 {
+		res["this"] = struct{}{}
+	}
+	for name := range s.DefaultTypes()[version] {
+		res[name] = struct{}{}
+	}
+	return res
+}
+
+// checkReservedIdentifier reports an error if the declared name is reserved by the standard library.
+func (p *astParser) checkReservedIdentifier(token token32, name string) bool {
+	if _, ok := p.reserved[name]; ok {
+		p.addError(token, "Identifier '%s' is reserved in STDLIB_VERSION %d", name, p.tree.LibVersion)
+		return false
+	}
+	return true
+}
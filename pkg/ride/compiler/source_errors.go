@@ -0,0 +1,55 @@
+package compiler
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Position is the position of a symbol in the source code, lines and columns are counted from one.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// SourceError is the compilation error with the span of the source code it refers to.
+// File is set for errors in the imported libraries. Errors that don't refer to the source code have zero positions.
+type SourceError struct {
+	Message string   `json:"message"`
+	File    string   `json:"file,omitempty"`
+	Start   Position `json:"start"`
+	End     Position `json:"end"`
+}
+
+// NewSourceError extracts the message and the position span from the error returned by the compiler.
+func NewSourceError(err error) SourceError {
+	var ae *astError
+	if errors.As(err, &ae) {
+		return SourceError{
+			Message: ae.msg,
+			File:    ae.prefix,
+			Start:   Position{Line: ae.begin.line, Column: ae.begin.symbol},
+			End:     Position{Line: ae.end.line, Column: ae.end.symbol},
+		}
+	}
+	var pe *parseError
+	if errors.As(err, &pe) {
+		begin, end := int(pe.max.begin), int(pe.max.end)
+		translations := translatePositions(pe.p.buffer, []int{begin, end})
+		return SourceError{
+			Message: strings.TrimSpace(err.Error()),
+			Start:   Position{Line: translations[begin].line, Column: translations[begin].symbol},
+			End:     Position{Line: translations[end].line, Column: translations[end].symbol},
+		}
+	}
+	return SourceError{Message: err.Error()}
+}
+
+// NewSourceErrors converts the list of errors returned by the compiler, see NewSourceError.
+func NewSourceErrors(errs []error) []SourceError {
+	r := make([]SourceError, len(errs))
+	for i, err := range errs {
+		r[i] = NewSourceError(err)
+	}
+	return r
+}
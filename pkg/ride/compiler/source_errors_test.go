@@ -0,0 +1,37 @@
+package compiler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceErrors(t *testing.T) {
+	code := DappV6Directive + `let a = "a" + 1
+let b = "\a"
+`
+	_, errs := Compile(code, false, false)
+	require.Len(t, errs, 2)
+	js, err := json.Marshal(NewSourceErrors(errs))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+  {
+    "message": "Unexpected types for '+' operator 'String' and 'Int'",
+    "start": {"line": 4, "column": 9},
+    "end": {"line": 5, "column": 0}
+  },
+  {
+    "message": "Unknown escaped symbol: '\\a'. The valid are \\b, \\f, \\n, \\r, \\t, \\\"",
+    "start": {"line": 5, "column": 10},
+    "end": {"line": 5, "column": 12}
+  }
+]`, string(js))
+
+	_, errs = Compile(DappV6Directive+"let = 1", false, false)
+	require.Len(t, errs, 1)
+	se := NewSourceError(errs[0])
+	assert.Contains(t, se.Message, "parse error near")
+	assert.Equal(t, 4, se.Start.Line)
+}
@@ -1,6 +1,7 @@
 package compiler
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/wavesplatform/gowaves/pkg/ride/compiler/stdlib"
@@ -10,9 +11,27 @@ type frame struct {
 	varsIndex  int
 	funcsIndex int
 }
+
+// declaration is the place in the source code where the user variable was declared.
+type declaration struct {
+	token    token32
+	buffer   []rune
+	fileName string
+}
+
+func (d *declaration) position() string {
+	begin := int(d.token.begin)
+	pos := translatePositions(d.buffer, []int{begin})[begin]
+	if d.fileName != "" {
+		return fmt.Sprintf("%d:%d in %s", pos.line, pos.symbol, d.fileName)
+	}
+	return fmt.Sprintf("%d:%d", pos.line, pos.symbol)
+}
+
 type stack struct {
 	frames []frame
 	vars   []stdlib.Variable
+	decls  []*declaration
 	funcs  []stdlib.FunctionParams
 }
 
@@ -20,6 +39,7 @@ func newStack() *stack {
 	return &stack{
 		frames: []frame{{}},
 		vars:   make([]stdlib.Variable, 0),
+		decls:  make([]*declaration, 0),
 		funcs:  make([]stdlib.FunctionParams, 0),
 	}
 }
@@ -37,12 +57,19 @@ func (s *stack) dropFrame() {
 		var f frame
 		f, s.frames = s.frames[len(s.frames)-1], s.frames[:len(s.frames)-1]
 		s.vars = s.vars[:f.varsIndex]
+		s.decls = s.decls[:f.varsIndex]
 		s.funcs = s.funcs[:f.funcsIndex]
 	}
 }
 
 func (s *stack) pushVariable(variable stdlib.Variable) {
+	s.pushDeclaredVariable(variable, nil)
+}
+
+// pushDeclaredVariable pushes the variable declared by user, built-in and service variables have no declaration.
+func (s *stack) pushDeclaredVariable(variable stdlib.Variable, decl *declaration) {
 	s.vars = append(s.vars, variable)
+	s.decls = append(s.decls, decl)
 }
 
 func (s *stack) pushFunc(f stdlib.FunctionParams) {
@@ -58,6 +85,17 @@ func (s *stack) variable(name string) (stdlib.Variable, bool) {
 	return stdlib.Variable{}, false
 }
 
+// declaration returns the declaration of the visible variable with the given name,
+// it returns false if there is no such variable or it wasn't declared by user.
+func (s *stack) declaration(name string) (*declaration, bool) {
+	for i := len(s.vars) - 1; i >= 0; i-- {
+		if name == s.vars[i].Name {
+			return s.decls[i], s.decls[i] != nil
+		}
+	}
+	return nil, false
+}
+
 func (s *stack) topMatchName() (string, bool) {
 	for i := len(s.vars) - 1; i >= 0; i-- {
 		if strings.HasPrefix(s.vars[i].Name, "$match") {
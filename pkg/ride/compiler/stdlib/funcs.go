@@ -14,7 +14,16 @@ func FuncsByVersion() map[ast.LibraryVersion]FunctionsSignatures {
 }
 
 type FunctionsSignatures struct {
-	Funcs map[string][]FunctionParams
+	Funcs      map[string][]FunctionParams
+	Deprecated map[string]Deprecation
+}
+
+// Deprecation describes the function that is still available but not recommended for use.
+// Removed is the version that no longer has the function, it's zero if the function is not removed yet.
+type Deprecation struct {
+	Since       ast.LibraryVersion
+	Removed     ast.LibraryVersion
+	Replacement string
 }
 
 type FunctionParams struct {
@@ -57,13 +66,20 @@ func (sig *FunctionsSignatures) Check(name string) bool {
 	return ok
 }
 
+// IsDeprecated returns deprecation info if the function with given name is deprecated in the current version.
+func (sig *FunctionsSignatures) IsDeprecated(name string) (Deprecation, bool) {
+	d, ok := sig.Deprecated[name]
+	return d, ok
+}
+
 type FunctionsSignaturesJson struct {
 	Versions []FunctionsInVersions `json:"versions"`
 }
 
 type FunctionsInVersions struct {
-	New    map[string][]FunctionParamsJson `json:"new"`
-	Remove []string                        `json:"remove"`
+	New        map[string][]FunctionParamsJson `json:"new"`
+	Remove     []string                        `json:"remove"`
+	Deprecated map[string]string               `json:"deprecated"`
 }
 
 type FunctionParamsJson struct {
@@ -84,16 +100,25 @@ func mustLoadFuncs() map[ast.LibraryVersion]FunctionsSignatures {
 	res := map[ast.LibraryVersion]FunctionsSignatures{}
 	for v, funcs := range s.Versions {
 		funcsInVersion := FunctionsSignatures{
-			Funcs: map[string][]FunctionParams{},
+			Funcs:      map[string][]FunctionParams{},
+			Deprecated: map[string]Deprecation{},
 		}
 		if v > 0 {
 			// copy prev version
-			for name, over := range res[ast.LibraryVersion(byte(v))].Funcs {
+			prev := res[ast.LibraryVersion(byte(v))]
+			for name, over := range prev.Funcs {
 				funcsInVersion.Funcs[name] = over
 			}
+			for name, d := range prev.Deprecated {
+				funcsInVersion.Deprecated[name] = d
+			}
 		}
 		for _, name := range funcs.Remove {
 			delete(funcsInVersion.Funcs, name)
+			delete(funcsInVersion.Deprecated, name)
+		}
+		for name, replacement := range funcs.Deprecated {
+			funcsInVersion.Deprecated[name] = Deprecation{Since: ast.LibraryVersion(byte(v + 1)), Replacement: replacement}
 		}
 		for name, over := range funcs.New {
 			var funcsParams []FunctionParams
@@ -118,9 +143,25 @@ func mustLoadFuncs() map[ast.LibraryVersion]FunctionsSignatures {
 		}
 		res[ast.LibraryVersion(byte(v+1))] = funcsInVersion
 	}
+	setRemovalVersions(res, len(s.Versions))
 	return res
 }
 
+// setRemovalVersions sets the version of removal of deprecated functions that are removed in later versions.
+func setRemovalVersions(res map[ast.LibraryVersion]FunctionsSignatures, versions int) {
+	for v := 1; v <= versions; v++ {
+		for name, d := range res[ast.LibraryVersion(byte(v))].Deprecated {
+			for later := v + 1; later <= versions; later++ {
+				if _, ok := res[ast.LibraryVersion(byte(later))].Funcs[name]; !ok {
+					d.Removed = ast.LibraryVersion(byte(later))
+					res[ast.LibraryVersion(byte(v))].Deprecated[name] = d
+					break
+				}
+			}
+		}
+	}
+}
+
 // handleTemplateFuncs
 
 func getGenericFuncsSign(name string, args []Type, findFuncPar FunctionParams) FunctionParams {
@@ -227,6 +227,8 @@ func (t SimpleType) String() string {
 
 type UnionType struct {
 	Types []Type
+	// Origin describes the expression the union was made of, it's used to explain type errors.
+	Origin string
 }
 
 func (t UnionType) Equal(other Type) bool {
@@ -0,0 +1,54 @@
+package compiler
+
+import (
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+const (
+	throwFunctionID   = "2"
+	throwFunctionName = "throw"
+)
+
+// alwaysThrows reports whether the evaluation of the expression unconditionally ends with an exception.
+// The analysis is conservative, the expression is considered throwing only if all its branches end with a call
+// of 'throw' function or the condition of the branching throws itself.
+func alwaysThrows(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.FunctionCallNode:
+		return n.Function == ast.NativeFunction(throwFunctionID) || n.Function == ast.UserFunction(throwFunctionName)
+	case *ast.ConditionalNode:
+		return alwaysThrows(n.Condition) || (alwaysThrows(n.TrueExpression) && alwaysThrows(n.FalseExpression))
+	case *ast.AssignmentNode:
+		return alwaysThrows(n.Block)
+	case *ast.FunctionDeclarationNode:
+		return alwaysThrows(n.Block)
+	default:
+		return false
+	}
+}
+
+// reachability tracks the declarations of a block to report the code following the strict variable which value
+// always throws. Unlike the regular variables, strict variable is evaluated at the place of declaration,
+// so nothing after it could be reached.
+type reachability struct {
+	throws   bool
+	reported bool
+}
+
+// declared is called with the expressions built from the declaration node.
+func (r *reachability) declared(node *node32, decls []ast.Node) {
+	if r.throws || node.pegRule != ruleStrictVariable || len(decls) == 0 {
+		return
+	}
+	if a, ok := decls[0].(*ast.AssignmentNode); ok {
+		r.throws = alwaysThrows(a.Expression)
+	}
+}
+
+// visit reports the first declaration or expression of the block which is unreachable.
+func (r *reachability) visit(p *astParser, node *node32) {
+	if r.throws && !r.reported {
+		p.addWarning(node.token32, "Unreachable code: the preceding strict variable always throws")
+		r.reported = true
+	}
+}
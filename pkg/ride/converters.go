@@ -1301,6 +1301,26 @@ func ethereumInvocationToObject(rideVersion ast.LibraryVersion, scheme proto.Sch
 	}
 }
 
+// ethereumCallInvocationToObject creates the invocation of the read-only call made by Ethereum address.
+// Since there is no transaction and the public key of the caller is unknown, the transaction ID and the public key
+// are empty, no payments are attached and the fee is zero.
+func ethereumCallInvocationToObject(rideVersion ast.LibraryVersion, caller proto.WavesAddress) rideType {
+	var (
+		emptyBytes = rideByteVector{}
+		wavesAsset = optionalAsset(proto.NewOptionalAssetWaves())
+	)
+	switch rideVersion {
+	case ast.LibV1, ast.LibV2, ast.LibV3:
+		return newRideInvocationV3(rideUnit{}, emptyBytes, wavesAsset, emptyBytes, rideAddress(caller), 0)
+	case ast.LibV4:
+		return newRideInvocationV4(rideList{}, emptyBytes, wavesAsset, emptyBytes, rideAddress(caller), 0)
+	default:
+		return newRideInvocationV5(rideAddress(caller), rideList{}, emptyBytes, wavesAsset, emptyBytes, emptyBytes,
+			rideAddress(caller), 0,
+		)
+	}
+}
+
 func recipientToObject(recipient proto.Recipient) rideType {
 	if addr := recipient.Address(); addr != nil {
 		return rideAddress(*addr)
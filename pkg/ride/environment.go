@@ -1281,6 +1281,11 @@ func (e *EvaluationEnvironment) SetEthereumInvoke(tx *proto.EthereumTransaction,
 	return nil
 }
 
+// SetEthereumCall sets the invocation for the read-only call of callable function by the given caller.
+func (e *EvaluationEnvironment) SetEthereumCall(v ast.LibraryVersion, caller proto.WavesAddress) {
+	e.inv = ethereumCallInvocationToObject(v, caller)
+}
+
 func (e *EvaluationEnvironment) SetLimit(limit uint32) {
 	e.cc.setLimit(limit)
 }
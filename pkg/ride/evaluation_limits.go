@@ -0,0 +1,60 @@
+package ride
+
+import (
+	"github.com/pkg/errors"
+)
+
+// maxByteVectorSize is the maximum size of byte vector on chain (DataTxMaxProtoBytes in Scala implementation).
+const maxByteVectorSize = 165947
+
+// EvaluationLimits bounds the sizes of values produced during the evaluation of a script.
+// Limits are intended for off-chain evaluation of untrusted scripts and arguments, zero value of a limit
+// means that the size is not checked.
+type EvaluationLimits struct {
+	MaxListSize       int
+	MaxByteVectorSize int
+	MaxStringSize     int
+}
+
+// DefaultEvaluationLimits returns the limits that mirror the limits of on-chain evaluation.
+func DefaultEvaluationLimits() EvaluationLimits {
+	return EvaluationLimits{
+		MaxListSize:       maxListSize,
+		MaxByteVectorSize: maxByteVectorSize,
+		MaxStringSize:     maxMessageLength,
+	}
+}
+
+// check verifies the size of the value, elements of lists are not checked.
+func (l EvaluationLimits) check(v rideType) error {
+	switch tv := v.(type) {
+	case rideList:
+		if l.MaxListSize > 0 && len(tv) > l.MaxListSize {
+			return errors.Errorf("list size %d exceeds the limit %d", len(tv), l.MaxListSize)
+		}
+	case rideByteVector:
+		if l.MaxByteVectorSize > 0 && len(tv) > l.MaxByteVectorSize {
+			return errors.Errorf("byte vector size %d exceeds the limit %d", len(tv), l.MaxByteVectorSize)
+		}
+	case rideString:
+		if l.MaxStringSize > 0 && len(tv) > l.MaxStringSize {
+			return errors.Errorf("string size %d exceeds the limit %d", len(tv), l.MaxStringSize)
+		}
+	}
+	return nil
+}
+
+// checkDeep verifies the size of the value and all elements of lists.
+func (l EvaluationLimits) checkDeep(v rideType) error {
+	if err := l.check(v); err != nil {
+		return err
+	}
+	if list, ok := v.(rideList); ok {
+		for _, item := range list {
+			if err := l.checkDeep(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package ride
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	ridec "github.com/wavesplatform/gowaves/pkg/ride/compiler"
+)
+
+func TestEvaluationLimits(t *testing.T) {
+	tree, errs := ridec.CompileToTree(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let a = [1, 2, 3, 4, 5, 6, 7, 8, 9, 10]
+let b = a ++ a ++ a ++ a
+let s = "abcdefghij" + "abcdefghij"
+size(b) == 40 && size(s) == 20
+`)
+	require.Empty(t, errs)
+	env := newTestEnv(t).withLibVersion(ast.LibV6).withComplexityLimit(2000).withRideV6Activated().toEnv()
+
+	for _, limits := range []EvaluationLimits{{}, DefaultEvaluationLimits()} {
+		r, err := CallVerifierWithLimits(env, tree, limits)
+		require.NoError(t, err)
+		assert.True(t, r.Result())
+	}
+
+	_, err := CallVerifierWithLimits(env, tree, EvaluationLimits{MaxListSize: 30})
+	require.Error(t, err)
+	assert.Equal(t, EvaluationFailure, GetEvaluationErrorType(err))
+	assert.ErrorContains(t, err, "list size 40 exceeds the limit 30")
+
+	_, err = CallVerifierWithLimits(env, tree, EvaluationLimits{MaxStringSize: 15})
+	assert.ErrorContains(t, err, "string size 20 exceeds the limit 15")
+
+	limits := EvaluationLimits{MaxListSize: 2, MaxByteVectorSize: 2}
+	assert.NoError(t, limits.checkDeep(rideList{rideByteVector{1, 2}, rideList{rideInt(1)}}))
+	assert.Error(t, limits.checkDeep(rideList{rideList{rideByteVector{1, 2, 3}}}))
+	assert.NoError(t, limits.check(rideList{rideList{rideByteVector{1, 2, 3}}})) // elements are not checked
+}
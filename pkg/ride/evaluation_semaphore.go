@@ -0,0 +1,40 @@
+package ride
+
+// EvaluationSemaphore bounds the number of scripts evaluated concurrently. Evaluations beyond the limit wait
+// for a free slot instead of running in parallel. Nil semaphore doesn't limit evaluations.
+type EvaluationSemaphore struct {
+	slots chan struct{}
+}
+
+// NewEvaluationSemaphore creates a semaphore that allows at most n concurrent evaluations.
+// Nil is returned if n is not positive, that means evaluations are not limited.
+func NewEvaluationSemaphore(n int) *EvaluationSemaphore {
+	if n <= 0 {
+		return nil
+	}
+	return &EvaluationSemaphore{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot for evaluation becomes available.
+func (s *EvaluationSemaphore) Acquire() {
+	if s == nil {
+		return
+	}
+	s.slots <- struct{}{}
+}
+
+// Release frees the slot taken by Acquire.
+func (s *EvaluationSemaphore) Release() {
+	if s == nil {
+		return
+	}
+	<-s.slots
+}
+
+// Limit returns the maximum number of concurrent evaluations, zero means no limit.
+func (s *EvaluationSemaphore) Limit() int {
+	if s == nil {
+		return 0
+	}
+	return cap(s.slots)
+}
@@ -0,0 +1,60 @@
+package ride
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluationSemaphoreCap(t *testing.T) {
+	const (
+		limit    = 3
+		requests = 50
+	)
+	s := NewEvaluationSemaphore(limit)
+	assert.Equal(t, limit, s.Limit())
+
+	var (
+		wg      sync.WaitGroup
+		running atomic.Int32
+		maxSeen atomic.Int32
+		done    atomic.Int32
+	)
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Acquire()
+			defer s.Release()
+			n := running.Add(1)
+			for {
+				m := maxSeen.Load()
+				if n <= m || maxSeen.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond) // Imitate evaluation to let other goroutines pile up on the semaphore.
+			running.Add(-1)
+			done.Add(1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(requests), done.Load())
+	assert.Equal(t, int32(limit), maxSeen.Load())
+}
+
+func TestEvaluationSemaphoreUnlimited(t *testing.T) {
+	s := NewEvaluationSemaphore(0)
+	assert.Nil(t, s)
+	assert.Equal(t, 0, s.Limit())
+	// Nil semaphore never blocks.
+	for range 10 {
+		s.Acquire()
+	}
+	for range 10 {
+		s.Release()
+	}
+}
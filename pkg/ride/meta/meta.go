@@ -1,6 +1,8 @@
 package meta
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 	g "github.com/wavesplatform/gowaves/pkg/ride/meta/generated"
 )
@@ -54,6 +56,43 @@ type DApp struct {
 	Abbreviations Abbreviations
 }
 
+// TypeName returns the RIDE notation of the type, for example "Int|String" or "List[ByteVector]".
+func TypeName(t Type) (string, error) {
+	switch tt := t.(type) {
+	case SimpleType:
+		switch tt {
+		case Int:
+			return "Int", nil
+		case Bytes:
+			return "ByteVector", nil
+		case Boolean:
+			return "Boolean", nil
+		case String:
+			return "String", nil
+		default:
+			return "", errors.Errorf("unexpected simple type %d", tt)
+		}
+	case UnionType:
+		names := make([]string, len(tt))
+		for i, st := range tt {
+			n, err := TypeName(st)
+			if err != nil {
+				return "", err
+			}
+			names[i] = n
+		}
+		return strings.Join(names, "|"), nil
+	case ListType:
+		inner, err := TypeName(tt.Inner)
+		if err != nil {
+			return "", err
+		}
+		return "List[" + inner + "]", nil
+	default:
+		return "", errors.Errorf("unexpected type %T", t)
+	}
+}
+
 type pair struct {
 	compact  string
 	original string
@@ -80,6 +119,45 @@ func (a *Abbreviations) CompactToOriginal(compact string) (string, error) {
 	return "", errors.Errorf("short name '%s' not found", compact)
 }
 
+// CompactToOriginalNames returns the table of compact names to the original ones. Compact names of the list of
+// original names are generated in order by CompactName, skipping the reserved names. The reserved names are
+// the names of callable functions, which are not replaced by the compaction.
+func (a *Abbreviations) CompactToOriginalNames(reserved map[string]struct{}) map[string]string {
+	r := make(map[string]string, len(a.compact2original)+len(a.names))
+	for c, o := range a.compact2original {
+		r[c] = o
+	}
+	n := 0
+	for _, o := range a.names {
+		c := CompactName(n)
+		for _, ok := reserved[c]; ok; _, ok = reserved[c] {
+			n++
+			c = CompactName(n)
+		}
+		r[c] = o
+		n++
+	}
+	return r
+}
+
+const (
+	compactNameChars    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	compactNameCharsNum = len(compactNameChars)
+)
+
+// CompactName returns the n-th name in the sequence of short names used by the compaction of dApp scripts:
+// "a", "b", ..., "Z", "aa", "ba", ...
+func CompactName(n int) string {
+	return compactName(n, "")
+}
+
+func compactName(n int, seed string) string {
+	if n < compactNameCharsNum {
+		return string(compactNameChars[n]) + seed
+	}
+	return compactName(n/compactNameCharsNum-1, string(compactNameChars[n%compactNameCharsNum])+seed)
+}
+
 func Convert(meta *g.DAppMeta) (DApp, error) {
 	v := int(meta.GetVersion())
 	abbreviations := convertAbbreviations(meta.GetCompactNameAndOriginalNamePairList(), meta.GetOriginalNames())
@@ -0,0 +1,596 @@
+// Package mockstate provides an implementation of the blockchain state for RIDE scripts backed by user-supplied
+// data. It allows to evaluate dApps off-chain without a node, for example, in unit tests or with ride-eval tool.
+package mockstate
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/types"
+)
+
+// defaultEstimatorVersion is the version of the complexity estimator reported by State by default.
+const defaultEstimatorVersion = 4
+
+var errNotFound = errors.New("not found in mock state")
+
+var _ types.SmartState = (*State)(nil)
+
+// State is an implementation of types.SmartState that serves blockchain queries of scripts from user-supplied
+// data or functions. It allows to evaluate dApps off-chain, for example, in unit tests.
+// If a function is set for some query, it takes precedence over the data, otherwise the data is looked up.
+// Absent data is reported as not found, so the corresponding built-in functions return `unit` or zero balances.
+type State struct {
+	Height            proto.Height
+	Estimator         int
+	DataEntries       map[proto.WavesAddress]map[string]proto.DataEntry
+	WavesBalances     map[proto.WavesAddress]uint64
+	AssetBalances     map[proto.WavesAddress]map[crypto.Digest]uint64
+	Aliases           map[string]proto.WavesAddress
+	Assets            map[crypto.Digest]*proto.FullAssetInfo
+	Transactions      map[crypto.Digest]proto.Transaction
+	TxHeights         map[crypto.Digest]proto.Height
+	Blocks            map[proto.Height]*proto.BlockInfo
+	AccountScripts    map[proto.WavesAddress]*ast.Tree
+	LeasesInfo        map[crypto.Digest]*proto.LeaseInfo
+	DataEntryFn       func(addr proto.WavesAddress, key string) (proto.DataEntry, error)
+	WavesBalanceFn    func(addr proto.WavesAddress) (uint64, error)
+	AssetBalanceFn    func(addr proto.WavesAddress, assetID crypto.Digest) (uint64, error)
+	TransactionByIDFn func(id crypto.Digest) (proto.Transaction, error)
+}
+
+// New creates an empty State with the given height.
+func New(height proto.Height) *State {
+	return &State{
+		Height:         height,
+		Estimator:      defaultEstimatorVersion,
+		DataEntries:    make(map[proto.WavesAddress]map[string]proto.DataEntry),
+		WavesBalances:  make(map[proto.WavesAddress]uint64),
+		AssetBalances:  make(map[proto.WavesAddress]map[crypto.Digest]uint64),
+		Aliases:        make(map[string]proto.WavesAddress),
+		Assets:         make(map[crypto.Digest]*proto.FullAssetInfo),
+		Transactions:   make(map[crypto.Digest]proto.Transaction),
+		TxHeights:      make(map[crypto.Digest]proto.Height),
+		Blocks:         make(map[proto.Height]*proto.BlockInfo),
+		AccountScripts: make(map[proto.WavesAddress]*ast.Tree),
+		LeasesInfo:     make(map[crypto.Digest]*proto.LeaseInfo),
+	}
+}
+
+// SetDataEntry puts the data entry to the storage of the account.
+func (s *State) SetDataEntry(addr proto.WavesAddress, entry proto.DataEntry) {
+	entries, ok := s.DataEntries[addr]
+	if !ok {
+		entries = make(map[string]proto.DataEntry)
+		s.DataEntries[addr] = entries
+	}
+	entries[entry.GetKey()] = entry
+}
+
+// SetAssetBalance sets the balance of the asset on the account.
+func (s *State) SetAssetBalance(addr proto.WavesAddress, assetID crypto.Digest, balance uint64) {
+	balances, ok := s.AssetBalances[addr]
+	if !ok {
+		balances = make(map[crypto.Digest]uint64)
+		s.AssetBalances[addr] = balances
+	}
+	balances[assetID] = balance
+}
+
+type stateJSON struct {
+	Height        proto.Height                      `json:"height"`
+	Data          map[string]proto.DataEntries      `json:"data"`
+	WavesBalances map[string]uint64                 `json:"balances"`
+	AssetBalances map[string]map[string]uint64      `json:"assetBalances"`
+	Aliases       map[string]string                 `json:"aliases"`
+	Blocks        map[proto.Height]*proto.BlockInfo `json:"blocks"`
+	TxHeights     map[string]proto.Height           `json:"transactionHeights"`
+	LeasesInfo    map[string]*proto.LeaseInfo       `json:"leases"`
+}
+
+// UnmarshalJSON reads the mock data from JSON. Addresses, asset and transaction IDs are encoded in Base58.
+// Assets, transactions and account scripts can't be set from JSON.
+func (s *State) UnmarshalJSON(data []byte) error {
+	var m stateJSON
+	if err := json.Unmarshal(data, &m); err != nil {
+		return errors.Wrap(err, "failed to unmarshal mock state")
+	}
+	*s = *New(m.Height)
+	for a, entries := range m.Data {
+		addr, err := proto.NewAddressFromString(a)
+		if err != nil {
+			return errors.Wrapf(err, "invalid address '%s'", a)
+		}
+		for _, e := range entries {
+			s.SetDataEntry(addr, e)
+		}
+	}
+	for a, b := range m.WavesBalances {
+		addr, err := proto.NewAddressFromString(a)
+		if err != nil {
+			return errors.Wrapf(err, "invalid address '%s'", a)
+		}
+		s.WavesBalances[addr] = b
+	}
+	for a, balances := range m.AssetBalances {
+		addr, err := proto.NewAddressFromString(a)
+		if err != nil {
+			return errors.Wrapf(err, "invalid address '%s'", a)
+		}
+		for id, b := range balances {
+			assetID, err := crypto.NewDigestFromBase58(id)
+			if err != nil {
+				return errors.Wrapf(err, "invalid asset ID '%s'", id)
+			}
+			s.SetAssetBalance(addr, assetID, b)
+		}
+	}
+	for alias, a := range m.Aliases {
+		addr, err := proto.NewAddressFromString(a)
+		if err != nil {
+			return errors.Wrapf(err, "invalid address '%s'", a)
+		}
+		s.Aliases[alias] = addr
+	}
+	for h, info := range m.Blocks {
+		s.Blocks[h] = info
+	}
+	for id, h := range m.TxHeights {
+		txID, err := crypto.NewDigestFromBase58(id)
+		if err != nil {
+			return errors.Wrapf(err, "invalid transaction ID '%s'", id)
+		}
+		s.TxHeights[txID] = h
+	}
+	for id, info := range m.LeasesInfo {
+		leaseID, err := crypto.NewDigestFromBase58(id)
+		if err != nil {
+			return errors.Wrapf(err, "invalid lease ID '%s'", id)
+		}
+		s.LeasesInfo[leaseID] = info
+	}
+	return nil
+}
+
+func (s *State) AddingBlockHeight() (uint64, error) {
+	return s.Height, nil
+}
+
+func (s *State) NewestRecipientToAddress(recipient proto.Recipient) (proto.WavesAddress, error) {
+	if addr := recipient.Address(); addr != nil {
+		return *addr, nil
+	}
+	return s.NewestAddrByAlias(*recipient.Alias())
+}
+
+func (s *State) NewestAddrByAlias(alias proto.Alias) (proto.WavesAddress, error) {
+	addr, ok := s.Aliases[alias.Alias]
+	if !ok {
+		return proto.WavesAddress{}, errors.Wrapf(errNotFound, "alias '%s'", alias.Alias)
+	}
+	return addr, nil
+}
+
+func (s *State) NewestScriptPKByAddr(addr proto.WavesAddress) (crypto.PublicKey, error) {
+	return crypto.PublicKey{}, errors.Wrapf(errNotFound, "script public key of '%s'", addr.String())
+}
+
+func (s *State) NewestTransactionByID(id []byte) (proto.Transaction, error) {
+	d, err := crypto.NewDigestFromBytes(id)
+	if err != nil {
+		return nil, errors.Wrap(errNotFound, err.Error())
+	}
+	if s.TransactionByIDFn != nil {
+		return s.TransactionByIDFn(d)
+	}
+	tx, ok := s.Transactions[d]
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "transaction '%s'", d.String())
+	}
+	return tx, nil
+}
+
+func (s *State) NewestTransactionHeightByID(id []byte) (uint64, error) {
+	d, err := crypto.NewDigestFromBytes(id)
+	if err != nil {
+		return 0, errors.Wrap(errNotFound, err.Error())
+	}
+	h, ok := s.TxHeights[d]
+	if !ok {
+		return 0, errors.Wrapf(errNotFound, "height of transaction '%s'", d.String())
+	}
+	return h, nil
+}
+
+func (s *State) NewestScriptByAccount(account proto.Recipient) (*ast.Tree, error) {
+	addr, err := s.NewestRecipientToAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	tree, ok := s.AccountScripts[addr]
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "script of '%s'", addr.String())
+	}
+	return tree, nil
+}
+
+func (s *State) NewestScriptBytesByAccount(account proto.Recipient) (proto.Script, error) {
+	return nil, errors.Wrapf(errNotFound, "script bytes of '%s'", account.String())
+}
+
+func (s *State) NewestLeasingInfo(id crypto.Digest) (*proto.LeaseInfo, error) {
+	info, ok := s.LeasesInfo[id]
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "lease '%s'", id.String())
+	}
+	return info, nil
+}
+
+func (s *State) IsStateUntouched(account proto.Recipient) (bool, error) {
+	addr, err := s.NewestRecipientToAddress(account)
+	if err != nil {
+		return false, err
+	}
+	return len(s.DataEntries[addr]) == 0, nil
+}
+
+func (s *State) NewestAssetBalance(account proto.Recipient, assetID crypto.Digest) (uint64, error) {
+	addr, err := s.NewestRecipientToAddress(account)
+	if err != nil {
+		return 0, err
+	}
+	if s.AssetBalanceFn != nil {
+		return s.AssetBalanceFn(addr, assetID)
+	}
+	return s.AssetBalances[addr][assetID], nil
+}
+
+func (s *State) NewestWavesBalance(account proto.Recipient) (uint64, error) {
+	addr, err := s.NewestRecipientToAddress(account)
+	if err != nil {
+		return 0, err
+	}
+	if s.WavesBalanceFn != nil {
+		return s.WavesBalanceFn(addr)
+	}
+	return s.WavesBalances[addr], nil
+}
+
+func (s *State) NewestFullWavesBalance(account proto.Recipient) (*proto.FullWavesBalance, error) {
+	b, err := s.NewestWavesBalance(account)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.FullWavesBalance{Regular: b, Generating: b, Available: b, Effective: b}, nil
+}
+
+func (s *State) dataEntry(account proto.Recipient, key string) (proto.DataEntry, error) {
+	addr, err := s.NewestRecipientToAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	if s.DataEntryFn != nil {
+		return s.DataEntryFn(addr, key)
+	}
+	e, ok := s.DataEntries[addr][key]
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "data entry '%s' of '%s'", key, addr.String())
+	}
+	return e, nil
+}
+
+func (s *State) RetrieveNewestIntegerEntry(account proto.Recipient, key string) (*proto.IntegerDataEntry, error) {
+	e, err := s.dataEntry(account, key)
+	if err != nil {
+		return nil, err
+	}
+	ie, ok := e.(*proto.IntegerDataEntry)
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "integer data entry '%s'", key)
+	}
+	return ie, nil
+}
+
+func (s *State) RetrieveNewestBooleanEntry(account proto.Recipient, key string) (*proto.BooleanDataEntry, error) {
+	e, err := s.dataEntry(account, key)
+	if err != nil {
+		return nil, err
+	}
+	be, ok := e.(*proto.BooleanDataEntry)
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "boolean data entry '%s'", key)
+	}
+	return be, nil
+}
+
+func (s *State) RetrieveNewestStringEntry(account proto.Recipient, key string) (*proto.StringDataEntry, error) {
+	e, err := s.dataEntry(account, key)
+	if err != nil {
+		return nil, err
+	}
+	se, ok := e.(*proto.StringDataEntry)
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "string data entry '%s'", key)
+	}
+	return se, nil
+}
+
+func (s *State) RetrieveNewestBinaryEntry(account proto.Recipient, key string) (*proto.BinaryDataEntry, error) {
+	e, err := s.dataEntry(account, key)
+	if err != nil {
+		return nil, err
+	}
+	be, ok := e.(*proto.BinaryDataEntry)
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "binary data entry '%s'", key)
+	}
+	return be, nil
+}
+
+func (s *State) NewestAssetIsSponsored(assetID crypto.Digest) (bool, error) {
+	info, err := s.NewestFullAssetInfo(assetID)
+	if err != nil {
+		return false, err
+	}
+	return info.SponsorshipCost > 0, nil
+}
+
+func (s *State) NewestAssetConstInfo(assetID proto.AssetID) (*proto.AssetConstInfo, error) {
+	for id, info := range s.Assets {
+		if proto.AssetIDFromDigest(id) == assetID {
+			return &info.AssetInfo.AssetConstInfo, nil
+		}
+	}
+	return nil, errors.Wrapf(errNotFound, "asset '%s'", assetID.String())
+}
+
+func (s *State) NewestAssetInfo(assetID crypto.Digest) (*proto.AssetInfo, error) {
+	info, err := s.NewestFullAssetInfo(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return &info.AssetInfo, nil
+}
+
+func (s *State) NewestFullAssetInfo(assetID crypto.Digest) (*proto.FullAssetInfo, error) {
+	info, ok := s.Assets[assetID]
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "asset '%s'", assetID.String())
+	}
+	return info, nil
+}
+
+func (s *State) NewestScriptByAsset(assetID crypto.Digest) (*ast.Tree, error) {
+	return nil, errors.Wrapf(errNotFound, "script of asset '%s'", assetID.String())
+}
+
+func (s *State) NewestBlockInfoByHeight(height proto.Height) (*proto.BlockInfo, error) {
+	info, ok := s.Blocks[height]
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "block at height %d", height)
+	}
+	return info, nil
+}
+
+func (s *State) EstimatorVersion() (int, error) {
+	return s.Estimator, nil
+}
+
+func (s *State) IsNotFound(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
+// Invocation describes an invocation of dApp's callable function evaluated with State.
+type Invocation struct {
+	DApp      proto.WavesAddress
+	CallerPK  crypto.PublicKey
+	Call      proto.FunctionCall
+	Payments  proto.ScriptPayments
+	Fee       uint64
+	Timestamp uint64
+}
+
+// NewEnvironment creates an environment to evaluate the invocation of the dApp script with State.
+// All features are considered activated. Invocations of other dApps are not supported.
+func NewEnvironment(
+	scheme proto.Scheme, state *State, tree *ast.Tree, inv Invocation,
+) (*ride.EvaluationEnvironment, error) {
+	env, err := ride.NewEnvironment(scheme, state, 0, 0, true, true, true, true, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create mock environment")
+	}
+	env.SetThisFromAddress(inv.DApp)
+	env.ChooseSizeCheck(tree.LibVersion)
+	env.ChooseTakeString(true)
+	env.ChooseMaxDataEntriesSize(true)
+	env.SetTimestamp(inv.Timestamp)
+	if info, ok := state.Blocks[state.Height]; ok {
+		if err := env.SetLastBlockFromBlockInfo(info); err != nil {
+			return nil, errors.Wrap(err, "failed to create mock environment")
+		}
+	}
+	limit, err := ride.MaxChainInvokeComplexityByVersion(tree.LibVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create mock environment")
+	}
+	env.SetLimit(limit)
+	tx := proto.NewUnsignedInvokeScriptWithProofs(2, inv.CallerPK, proto.NewRecipientFromAddress(inv.DApp),
+		inv.Call, inv.Payments, proto.NewOptionalAssetWaves(), inv.Fee, inv.Timestamp)
+	tx.ID = &crypto.Digest{}
+	tx.Proofs = proto.NewProofs()
+	if err := env.SetTransaction(tx); err != nil {
+		return nil, errors.Wrap(err, "failed to create mock environment")
+	}
+	if err := env.SetInvoke(tx, tree.LibVersion); err != nil {
+		return nil, errors.Wrap(err, "failed to create mock environment")
+	}
+	return env, nil
+}
+
+// EvaluationReport describes the completed evaluation of the invocation with State.
+// Complexity is the complexity consumed by the taken execution path, it is accounted the same way as on-chain.
+// Estimation is the static estimation of the callable function with the latest estimator, it covers the most
+// expensive path. Limit is the maximum complexity of the invocation for the script's version.
+// Actions are the actions emitted by the callable function in the order of emission, Snapshot is the resulting
+// change of the state.
+type EvaluationReport struct {
+	Actions    Actions  `json:"actions"`
+	Snapshot   Snapshot `json:"snapshot"`
+	Complexity int      `json:"complexity"`
+	Estimation int      `json:"estimation"`
+	Limit      int      `json:"limit"`
+}
+
+// Actions is the ordered list of actions emitted by the invocation evaluated with State.
+type Actions []proto.ScriptAction
+
+type actionJSON struct {
+	Type   string             `json:"type"`
+	Action proto.ScriptAction `json:"action"`
+}
+
+// MarshalJSON writes the actions as the list of objects holding the type of the action and the action itself.
+func (a Actions) MarshalJSON() ([]byte, error) {
+	r := make([]actionJSON, len(a))
+	for i, action := range a {
+		var t string
+		switch action.(type) {
+		case *proto.DataEntryScriptAction:
+			t = "data"
+		case *proto.AttachedPaymentScriptAction:
+			t = "payment"
+		case *proto.TransferScriptAction:
+			t = "transfer"
+		case *proto.IssueScriptAction:
+			t = "issue"
+		case *proto.ReissueScriptAction:
+			t = "reissue"
+		case *proto.BurnScriptAction:
+			t = "burn"
+		case *proto.SponsorshipScriptAction:
+			t = "sponsorship"
+		case *proto.LeaseScriptAction:
+			t = "lease"
+		case *proto.LeaseCancelScriptAction:
+			t = "leaseCancel"
+		default:
+			return nil, errors.Errorf("unexpected action type %T", action)
+		}
+		r[i] = actionJSON{Type: t, Action: action}
+	}
+	return json.Marshal(r)
+}
+
+// Snapshot describes the change of the state made by the invocation evaluated with State.
+// Data holds the data entries written by accounts, balances are the signed changes of balances that include
+// the attached payments. Addresses and asset IDs are encoded in Base58. Leases are not reflected.
+type Snapshot struct {
+	Data          map[string]proto.DataEntries `json:"data"`
+	WavesBalances map[string]int64             `json:"balances"`
+	AssetBalances map[string]map[string]int64  `json:"assetBalances"`
+}
+
+func newSnapshot() Snapshot {
+	return Snapshot{
+		Data:          make(map[string]proto.DataEntries),
+		WavesBalances: make(map[string]int64),
+		AssetBalances: make(map[string]map[string]int64),
+	}
+}
+
+func (s Snapshot) addBalance(addr proto.WavesAddress, asset proto.OptionalAsset, amount int64) {
+	if !asset.Present {
+		s.WavesBalances[addr.String()] += amount
+		return
+	}
+	balances, ok := s.AssetBalances[addr.String()]
+	if !ok {
+		balances = make(map[string]int64)
+		s.AssetBalances[addr.String()] = balances
+	}
+	balances[asset.ID.String()] += amount
+}
+
+// buildSnapshot builds the snapshot of the invocation from its payments and actions.
+func buildSnapshot(
+	scheme proto.Scheme, state *State, inv Invocation, actions []proto.ScriptAction,
+) (Snapshot, error) {
+	s := newSnapshot()
+	if len(inv.Payments) > 0 {
+		caller, err := proto.NewAddressFromPublicKey(scheme, inv.CallerPK)
+		if err != nil {
+			return Snapshot{}, errors.Wrap(err, "failed to build mock snapshot")
+		}
+		for _, p := range inv.Payments {
+			s.addBalance(caller, p.Asset, -int64(p.Amount))
+			s.addBalance(inv.DApp, p.Asset, int64(p.Amount))
+		}
+	}
+	for _, action := range actions {
+		sender := inv.DApp
+		if pk := action.SenderPK(); pk != nil {
+			addr, err := proto.NewAddressFromPublicKey(scheme, *pk)
+			if err != nil {
+				return Snapshot{}, errors.Wrap(err, "failed to build mock snapshot")
+			}
+			sender = addr
+		}
+		switch a := action.(type) {
+		case *proto.DataEntryScriptAction:
+			s.Data[sender.String()] = append(s.Data[sender.String()], a.Entry)
+		case *proto.TransferScriptAction:
+			recipient, err := state.NewestRecipientToAddress(a.Recipient)
+			if err != nil {
+				return Snapshot{}, errors.Wrapf(err, "failed to resolve recipient '%s'", a.Recipient.String())
+			}
+			s.addBalance(sender, a.Asset, -a.Amount)
+			s.addBalance(recipient, a.Asset, a.Amount)
+		case *proto.IssueScriptAction:
+			s.addBalance(sender, *proto.NewOptionalAssetFromDigest(a.ID), a.Quantity)
+		case *proto.ReissueScriptAction:
+			s.addBalance(sender, *proto.NewOptionalAssetFromDigest(a.AssetID), a.Quantity)
+		case *proto.BurnScriptAction:
+			s.addBalance(sender, *proto.NewOptionalAssetFromDigest(a.AssetID), -a.Quantity)
+		}
+	}
+	return s, nil
+}
+
+// Evaluate evaluates the invocation of the dApp script with State and reports the emitted actions,
+// the resulting snapshot and the consumed complexity. If the evaluation fails the report holds the complexity
+// spent before the failure.
+func Evaluate(
+	scheme proto.Scheme, state *State, tree *ast.Tree, inv Invocation,
+) (EvaluationReport, error) {
+	const estimatorVersion = 4
+	est, err := ride.EstimateTree(tree, estimatorVersion)
+	if err != nil {
+		return EvaluationReport{}, errors.Wrap(err, "failed to estimate script")
+	}
+	limit, err := ride.MaxChainInvokeComplexityByVersion(tree.LibVersion)
+	if err != nil {
+		return EvaluationReport{}, err
+	}
+	report := EvaluationReport{Estimation: est.Functions[inv.Call.Name()], Limit: int(limit)}
+	env, err := NewEnvironment(scheme, state, tree, inv)
+	if err != nil {
+		return EvaluationReport{}, err
+	}
+	res, err := ride.CallFunction(env, tree, inv.Call)
+	if err != nil {
+		report.Complexity = ride.EvaluationErrorSpentComplexity(err)
+		return report, err
+	}
+	report.Actions = res.ScriptActions()
+	report.Complexity = res.Complexity()
+	report.Snapshot, err = buildSnapshot(scheme, state, inv, report.Actions)
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
@@ -0,0 +1,214 @@
+package mockstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	ridec "github.com/wavesplatform/gowaves/pkg/ride/compiler"
+)
+
+func TestStateDApp(t *testing.T) {
+	const src = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func deposit(amount: Int) = {
+	let counter = this.getInteger("counter").valueOrElse(0)
+	let balance = wavesBalance(i.caller).available
+	let owner = addressFromRecipient(Alias("owner"))
+	if (balance < amount) then throw("insufficient balance") else
+	[
+		IntegerEntry("counter", counter + 1),
+		IntegerEntry("deposit", amount),
+		StringEntry("owner", owner.toString())
+	]
+}
+`
+	tree, errs := ridec.CompileToTree(src)
+	require.Empty(t, errs)
+
+	_, dAppPK, err := crypto.GenerateKeyPair([]byte("dApp"))
+	require.NoError(t, err)
+	dApp, err := proto.NewAddressFromPublicKey(proto.TestNetScheme, dAppPK)
+	require.NoError(t, err)
+	_, callerPK, err := crypto.GenerateKeyPair([]byte("caller"))
+	require.NoError(t, err)
+	caller, err := proto.NewAddressFromPublicKey(proto.TestNetScheme, callerPK)
+	require.NoError(t, err)
+
+	js := fmt.Sprintf(`{
+		"height": 100,
+		"data": {"%[1]s": [{"key": "counter", "type": "integer", "value": 41}]},
+		"balances": {"%[2]s": 1000},
+		"aliases": {"owner": "%[1]s"}
+	}`, dApp.String(), caller.String())
+	state := new(State)
+	require.NoError(t, json.Unmarshal([]byte(js), state))
+
+	call := func(amount int64) (ride.Result, error) {
+		inv := Invocation{
+			DApp:     dApp,
+			CallerPK: callerPK,
+			Call:     proto.NewFunctionCall("deposit", proto.Arguments{proto.NewIntegerArgument(amount)}),
+		}
+		env, err := NewEnvironment(proto.TestNetScheme, state, tree, inv)
+		require.NoError(t, err)
+		return ride.CallFunction(env, tree, inv.Call)
+	}
+
+	res, err := call(500)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []proto.ScriptAction{
+		&proto.DataEntryScriptAction{Entry: &proto.IntegerDataEntry{Key: "counter", Value: 42}},
+		&proto.DataEntryScriptAction{Entry: &proto.IntegerDataEntry{Key: "deposit", Value: 500}},
+		&proto.DataEntryScriptAction{Entry: &proto.StringDataEntry{Key: "owner", Value: dApp.String()}},
+	}, res.ScriptActions())
+
+	_, err = call(5000)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient balance")
+
+	// Functions take precedence over data.
+	state.WavesBalanceFn = func(_ proto.WavesAddress) (uint64, error) { return 10000, nil }
+	state.DataEntryFn = func(_ proto.WavesAddress, key string) (proto.DataEntry, error) {
+		return &proto.IntegerDataEntry{Key: key, Value: 1}, nil
+	}
+	res, err = call(5000)
+	require.NoError(t, err)
+	assert.Contains(t, res.ScriptActions(),
+		&proto.DataEntryScriptAction{Entry: &proto.IntegerDataEntry{Key: "counter", Value: 2}})
+}
+
+func TestEvaluateComplexity(t *testing.T) {
+	const src = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func check(heavy: Boolean) = {
+	if (heavy) then {
+		let h = sha256_16Kb(keccak256_16Kb(blake2b256_16Kb(base58'')))
+		[BinaryEntry("hash", h)]
+	} else [IntegerEntry("light", 1)]
+}
+`
+	tree, errs := ridec.CompileToTree(src)
+	require.Empty(t, errs)
+
+	_, dAppPK, err := crypto.GenerateKeyPair([]byte("dApp"))
+	require.NoError(t, err)
+	dApp, err := proto.NewAddressFromPublicKey(proto.TestNetScheme, dAppPK)
+	require.NoError(t, err)
+	_, callerPK, err := crypto.GenerateKeyPair([]byte("caller"))
+	require.NoError(t, err)
+
+	est, err := ride.EstimateTree(tree, 4)
+	require.NoError(t, err)
+
+	evaluate := func(heavy bool) EvaluationReport {
+		inv := Invocation{
+			DApp:     dApp,
+			CallerPK: callerPK,
+			Call:     proto.NewFunctionCall("check", proto.Arguments{&proto.BooleanArgument{Value: heavy}}),
+		}
+		report, rErr := Evaluate(proto.TestNetScheme, New(1), tree, inv)
+		require.NoError(t, rErr)
+		return report
+	}
+	light := evaluate(false)
+	heavy := evaluate(true)
+
+	assert.Equal(t, est.Functions["check"], light.Estimation)
+	assert.Equal(t, est.Functions["check"], heavy.Estimation)
+	assert.Equal(t, 52000, light.Limit)
+	assert.Len(t, light.Actions, 1)
+	assert.Len(t, heavy.Actions, 1)
+
+	// Consumed complexity reflects the taken branch, the estimation covers the most expensive one.
+	assert.Positive(t, light.Complexity)
+	assert.Less(t, light.Complexity, heavy.Complexity)
+	assert.LessOrEqual(t, heavy.Complexity, heavy.Estimation)
+	assert.Less(t, light.Complexity, light.Estimation)
+}
+
+func TestEvaluateActions(t *testing.T) {
+	const src = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+@Callable(i)
+func pay(amount: Int) = [
+	IntegerEntry("paid", amount),
+	ScriptTransfer(i.caller, amount, unit),
+	StringEntry("last", i.caller.toString()),
+	ScriptTransfer(i.caller, 1, i.payments[0].assetId)
+]
+`
+	tree, errs := ridec.CompileToTree(src)
+	require.Empty(t, errs)
+
+	_, dAppPK, err := crypto.GenerateKeyPair([]byte("dApp"))
+	require.NoError(t, err)
+	dApp, err := proto.NewAddressFromPublicKey(proto.TestNetScheme, dAppPK)
+	require.NoError(t, err)
+	_, callerPK, err := crypto.GenerateKeyPair([]byte("caller"))
+	require.NoError(t, err)
+	caller, err := proto.NewAddressFromPublicKey(proto.TestNetScheme, callerPK)
+	require.NoError(t, err)
+	asset := crypto.MustDigestFromBase58("5ZmhsYdJ1M2rHXrhqwBPbKgHR73ZGDFPAF8CCVSydRyM")
+
+	state := New(1)
+	state.WavesBalances[dApp] = 1000
+	state.SetAssetBalance(caller, asset, 10)
+	inv := Invocation{
+		DApp:     dApp,
+		CallerPK: callerPK,
+		Call:     proto.NewFunctionCall("pay", proto.Arguments{proto.NewIntegerArgument(300)}),
+		Payments: proto.ScriptPayments{{Amount: 5, Asset: *proto.NewOptionalAssetFromDigest(asset)}},
+	}
+	report, err := Evaluate(proto.TestNetScheme, state, tree, inv)
+	require.NoError(t, err)
+
+	assert.Equal(t, Actions{
+		&proto.DataEntryScriptAction{Entry: &proto.IntegerDataEntry{Key: "paid", Value: 300}},
+		&proto.TransferScriptAction{
+			Recipient: proto.NewRecipientFromAddress(caller), Amount: 300, Asset: proto.NewOptionalAssetWaves(),
+		},
+		&proto.DataEntryScriptAction{Entry: &proto.StringDataEntry{Key: "last", Value: caller.String()}},
+		&proto.TransferScriptAction{
+			Recipient: proto.NewRecipientFromAddress(caller), Amount: 1, Asset: *proto.NewOptionalAssetFromDigest(asset),
+		},
+	}, report.Actions)
+
+	assert.Equal(t, map[string]proto.DataEntries{dApp.String(): {
+		&proto.IntegerDataEntry{Key: "paid", Value: 300},
+		&proto.StringDataEntry{Key: "last", Value: caller.String()},
+	}}, report.Snapshot.Data)
+	assert.Equal(t, map[string]int64{dApp.String(): -300, caller.String(): 300}, report.Snapshot.WavesBalances)
+	assert.Equal(t, map[string]map[string]int64{
+		dApp.String():   {asset.String(): 4},
+		caller.String(): {asset.String(): -4},
+	}, report.Snapshot.AssetBalances)
+
+	js, err := json.Marshal(report.Actions)
+	require.NoError(t, err)
+	var types []struct {
+		Type string `json:"type"`
+	}
+	require.NoError(t, json.Unmarshal(js, &types))
+	require.Len(t, types, 4)
+	for i, typ := range []string{"data", "transfer", "data", "transfer"} {
+		assert.Equal(t, typ, types[i].Type)
+	}
+}
@@ -1,6 +1,11 @@
 package ride
 
-import "github.com/wavesplatform/gowaves/pkg/proto"
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/proto/ethabi"
+)
 
 type Result interface {
 	Result() bool
@@ -52,3 +57,22 @@ func (r DAppResult) ScriptActions() []proto.ScriptAction {
 func (r DAppResult) Complexity() int {
 	return r.complexity
 }
+
+// EncodeResultToEthereumABI encodes the value returned by the callable function into Ethereum ABI.
+// Only the values of primitive types are supported, Unit or absence of the value is encoded as empty bytes.
+func EncodeResultToEthereumABI(r Result) ([]byte, error) {
+	switch v := r.userResult().(type) {
+	case nil, rideUnit:
+		return nil, nil
+	case rideInt:
+		return ethabi.Int(v).EncodeToABI(), nil
+	case rideBoolean:
+		return ethabi.Bool(v).EncodeToABI(), nil
+	case rideString:
+		return ethabi.String(v).EncodeToABI(), nil
+	case rideByteVector:
+		return ethabi.Bytes(v).EncodeToABI(), nil
+	default:
+		return nil, errors.Errorf("unsupported type '%s' of result for Ethereum ABI encoding", v.instanceOf())
+	}
+}
@@ -0,0 +1,56 @@
+package serialization
+
+import (
+	"bytes"
+	sh256 "crypto/sha256"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// Envelope protects the serialized tree from corruption while it's stored or transmitted outside the network.
+// It consists of the magic bytes, the envelope version, the length of the tree bytes, the tree bytes and
+// the SHA-256 hash of the tree bytes. The envelope is not a part of the consensus encoding of the script,
+// it has to be removed with UnwrapWithChecksum before the script is submitted to the network.
+const (
+	envelopeVersion      byte = 1
+	envelopeHeaderSize        = len(envelopeMagic) + 1 + 4
+	envelopeChecksumSize      = sh256.Size
+)
+
+var envelopeMagic = [4]byte{'R', 'I', 'D', 'E'}
+
+// WrapWithChecksum returns the serialized tree wrapped into the envelope with its length and checksum.
+func WrapWithChecksum(tree []byte) []byte {
+	res := make([]byte, 0, envelopeHeaderSize+len(tree)+envelopeChecksumSize)
+	res = append(res, envelopeMagic[:]...)
+	res = append(res, envelopeVersion)
+	res = binary.BigEndian.AppendUint32(res, uint32(len(tree)))
+	res = append(res, tree...)
+	checksum := sh256.Sum256(tree)
+	return append(res, checksum[:]...)
+}
+
+// UnwrapWithChecksum verifies the envelope created by WrapWithChecksum and returns the serialized tree.
+// An error is returned if the envelope is malformed or the checksum doesn't match the tree bytes.
+func UnwrapWithChecksum(envelope []byte) ([]byte, error) {
+	if len(envelope) < envelopeHeaderSize+envelopeChecksumSize {
+		return nil, errors.Errorf("invalid envelope size %d", len(envelope))
+	}
+	if !bytes.Equal(envelope[:len(envelopeMagic)], envelopeMagic[:]) {
+		return nil, errors.New("invalid envelope magic bytes")
+	}
+	if v := envelope[len(envelopeMagic)]; v != envelopeVersion {
+		return nil, errors.Errorf("unsupported envelope version %d", v)
+	}
+	size := binary.BigEndian.Uint32(envelope[len(envelopeMagic)+1 : envelopeHeaderSize])
+	if uint64(len(envelope)) != uint64(envelopeHeaderSize)+uint64(size)+envelopeChecksumSize {
+		return nil, errors.Errorf("envelope size %d doesn't match tree size %d", len(envelope), size)
+	}
+	tree := envelope[envelopeHeaderSize : envelopeHeaderSize+int(size)]
+	checksum := sh256.Sum256(tree)
+	if !bytes.Equal(checksum[:], envelope[envelopeHeaderSize+int(size):]) {
+		return nil, errors.New("invalid envelope checksum")
+	}
+	return tree, nil
+}
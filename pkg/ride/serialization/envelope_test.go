@@ -0,0 +1,40 @@
+package serialization
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapWithChecksum(t *testing.T) {
+	tree, err := base64.StdEncoding.DecodeString("AwQAAAABeAAAAAAAAAAAAQbtAkXn") // V3: let x = 1; true
+	require.NoError(t, err)
+	envelope := WrapWithChecksum(tree)
+	assert.Len(t, envelope, envelopeHeaderSize+len(tree)+envelopeChecksumSize)
+
+	unwrapped, err := UnwrapWithChecksum(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, tree, unwrapped)
+	_, err = Parse(unwrapped)
+	require.NoError(t, err)
+
+	// Corruption of any byte of the envelope is detected.
+	for i := range envelope {
+		corrupted := append([]byte(nil), envelope...)
+		corrupted[i] ^= 0xff
+		_, err := UnwrapWithChecksum(corrupted)
+		assert.Error(t, err, "corrupted byte %d", i)
+	}
+
+	_, err = UnwrapWithChecksum(envelope[:len(envelope)-1])
+	assert.EqualError(t, err, "envelope size 61 doesn't match tree size 21")
+	_, err = UnwrapWithChecksum(append(envelope, 0))
+	assert.EqualError(t, err, "envelope size 63 doesn't match tree size 21")
+	_, err = UnwrapWithChecksum(envelope[:envelopeHeaderSize])
+	assert.EqualError(t, err, "invalid envelope size 9")
+	// Tree bytes without envelope are rejected.
+	_, err = UnwrapWithChecksum(append(tree, make([]byte, envelopeChecksumSize)...))
+	assert.EqualError(t, err, "invalid envelope magic bytes")
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 )
 
+// LatestEstimatorVersion is the version of the most recent tree estimator.
+const LatestEstimatorVersion = 4
+
 type TreeEstimation struct {
 	Estimation int            `cbor:"0,keyasint"`
 	Verifier   int            `cbor:"1,keyasint,omitempty"`
@@ -14,12 +17,19 @@ type TreeEstimation struct {
 }
 
 func EstimateTree(tree *ast.Tree, v int) (TreeEstimation, error) {
+	return EstimateTreeWithBuiltins(tree, v, nil)
+}
+
+// EstimateTreeWithBuiltins estimates the tree with the catalogue of functions extended by the given builtins.
+// Builtins are the complexities of functions by their IDs, they override the standard ones with the same IDs.
+func EstimateTreeWithBuiltins(tree *ast.Tree, v int, builtins map[string]int) (TreeEstimation, error) {
 	switch v {
 	case 1:
 		te, err := newTreeEstimatorV1(tree)
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
 		}
+		te.scope.builtin = extendCatalogue(te.scope.builtin, builtins)
 		max, verifier, functions, err := te.estimate()
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
@@ -50,6 +60,7 @@ func EstimateTree(tree *ast.Tree, v int) (TreeEstimation, error) {
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
 		}
+		te.scope.builtin = extendCatalogue(te.scope.builtin, builtins)
 		max, verifier, functions, err := te.estimate()
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
@@ -60,6 +71,7 @@ func EstimateTree(tree *ast.Tree, v int) (TreeEstimation, error) {
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
 		}
+		te.scope.builtin = extendCatalogue(te.scope.builtin, builtins)
 		max, verifier, functions, err := te.estimate()
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
@@ -70,6 +82,7 @@ func EstimateTree(tree *ast.Tree, v int) (TreeEstimation, error) {
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
 		}
+		te.scope.builtin = extendCatalogue(te.scope.builtin, builtins)
 		max, verifier, functions, err := te.estimate()
 		if err != nil {
 			return TreeEstimation{}, errors.Wrapf(err, "failed to estimate with tree estimator V%d", v)
@@ -79,3 +92,17 @@ func EstimateTree(tree *ast.Tree, v int) (TreeEstimation, error) {
 		return TreeEstimation{}, errors.Errorf("unsupported version of tree estimator '%d'", v)
 	}
 }
+
+func extendCatalogue(catalogue, builtins map[string]int) map[string]int {
+	if len(builtins) == 0 {
+		return catalogue
+	}
+	r := make(map[string]int, len(catalogue)+len(builtins))
+	for id, c := range catalogue {
+		r[id] = c
+	}
+	for id, c := range builtins {
+		r[id] = c
+	}
+	return r
+}
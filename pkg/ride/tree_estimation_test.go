@@ -510,3 +510,21 @@ func TestScope(t *testing.T) {
 		}
 	}
 }
+
+func TestEstimateTreeWithBuiltins(t *testing.T) {
+	builtins := []ridec.Builtin{{Name: "randomInt", Arguments: []string{"Int"}, ReturnType: "Int", Complexity: 100}}
+	tree, errs, _ := ridec.CompileToTreeWithOptions(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+randomInt(10) > 5
+`, ridec.Options{Builtins: builtins})
+	require.Empty(t, errs)
+
+	est, err := EstimateTreeWithBuiltins(tree, 4, ridec.BuiltinsComplexities(builtins))
+	require.NoError(t, err)
+	assert.Equal(t, 101, est.Estimation)
+
+	_, err = EstimateTree(tree, 4)
+	assert.Error(t, err) // unknown function without built-ins
+}
@@ -7,14 +7,28 @@ import (
 )
 
 func CallVerifier(env environment, tree *ast.Tree) (Result, error) {
+	return CallVerifierWithLimits(env, tree, EvaluationLimits{})
+}
+
+// CallVerifierWithLimits evaluates the verifier failing if sizes of produced values exceed the limits.
+func CallVerifierWithLimits(env environment, tree *ast.Tree, limits EvaluationLimits) (Result, error) {
 	e, err := treeVerifierEvaluator(env, tree)
 	if err != nil {
 		return nil, RuntimeError.Wrap(err, "failed to call verifier")
 	}
+	e.limits = limits
 	return e.evaluate()
 }
 
 func CallFunction(env environment, tree *ast.Tree, fc proto.FunctionCall) (Result, error) {
+	return CallFunctionWithLimits(env, tree, fc, EvaluationLimits{})
+}
+
+// CallFunctionWithLimits calls the callable function failing if sizes of arguments or values produced
+// by the function exceed the limits. Limits are not applied to the functions of other dApps invoked by the call.
+func CallFunctionWithLimits(
+	env environment, tree *ast.Tree, fc proto.FunctionCall, limits EvaluationLimits,
+) (Result, error) {
 	var (
 		name = fc.Name()
 		args = fc.Arguments()
@@ -23,10 +37,16 @@ func CallFunction(env environment, tree *ast.Tree, fc proto.FunctionCall) (Resul
 	if err != nil {
 		return nil, EvaluationFailure.Wrapf(err, "failed to call function '%s'", name)
 	}
+	for i, a := range arguments {
+		if lErr := limits.checkDeep(a); lErr != nil {
+			return nil, EvaluationFailure.Wrapf(lErr, "argument %d of function '%s'", i+1, name)
+		}
+	}
 	e, err := treeFunctionEvaluator(env, tree, name, arguments)
 	if err != nil {
 		return nil, EvaluationFailure.Wrapf(err, "failed to call function '%s'", name)
 	}
+	e.limits = limits
 	// After that instruction script/function is executed,
 	// so result of the execution and spent complexity should be considered outside.
 	rideResult, err := e.evaluate()
@@ -6408,3 +6408,39 @@ func TestUpdateGeneratingBalanceDuringScriptExecutionAfterLightNode(t *testing.T
 		assert.True(t, bool(isBalanceUpdated))
 	})
 }
+
+func TestOptimizedTreeEvaluation(t *testing.T) {
+	const src = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+let s = "abcdefghij"
+let a = size(s + s) * 2
+let b = size(s + s) + 1
+func f(x: Int) = x * size(s + s)
+a + b + f(1) == 81 && sha256(toBytes(a)) == sha256(toBytes(a))
+`
+	tree, errs := ridec.CompileToTree(src)
+	require.Empty(t, errs)
+	optimized, errs := ridec.CompileToTree(src)
+	require.Empty(t, errs)
+	require.Equal(t, 2, ridec.Optimize(optimized))
+
+	est, err := EstimateTree(tree, 4)
+	require.NoError(t, err)
+	optimizedEst, err := EstimateTree(optimized, 4)
+	require.NoError(t, err)
+	assert.Less(t, optimizedEst.Estimation, est.Estimation)
+
+	// Complexity is accumulated in the environment, so each tree is evaluated in its own one.
+	newEnv := func() *mockRideEnvironment {
+		return newTestEnv(t).withLibVersion(ast.LibV6).withComplexityLimit(2000).withRideV6Activated().toEnv()
+	}
+	res, err := CallVerifier(newEnv(), tree)
+	require.NoError(t, err)
+	optimizedRes, err := CallVerifier(newEnv(), optimized)
+	require.NoError(t, err)
+	assert.True(t, res.Result())
+	assert.Equal(t, res.Result(), optimizedRes.Result())
+	assert.Less(t, optimizedRes.Complexity(), res.Complexity())
+}
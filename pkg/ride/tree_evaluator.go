@@ -190,10 +190,11 @@ func selectConstantNames(v ast.LibraryVersion) ([]string, error) {
 }
 
 type treeEvaluator struct {
-	dapp bool
-	f    ast.Node
-	s    evaluationScope
-	env  environment
+	dapp   bool
+	f      ast.Node
+	s      evaluationScope
+	env    environment
+	limits EvaluationLimits
 }
 
 func (e *treeEvaluator) complexity() int {
@@ -284,6 +285,9 @@ func (e *treeEvaluator) evaluateNativeFunction(name string, arguments []ast.Node
 	if err != nil {
 		return nil, EvaluationErrorPushf(err, "failed to call system function '%s'", name)
 	}
+	if lErr := e.limits.check(r); lErr != nil {
+		return nil, EvaluationFailure.Wrapf(lErr, "result of system function '%s'", name)
+	}
 	return r, nil
 }
 
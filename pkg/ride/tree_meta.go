@@ -0,0 +1,59 @@
+package ride
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+// ArgumentMeta describes an argument of a callable function.
+type ArgumentMeta struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// CallableMeta describes a callable function of a dApp: its name, the name of the invocation parameter given
+// in the @Callable annotation and the arguments in the order of declaration.
+type CallableMeta struct {
+	Name                string         `json:"name"`
+	InvocationParameter string         `json:"invocationParameter"`
+	Arguments           []ArgumentMeta `json:"arguments"`
+}
+
+// Meta is the metadata of a deployed dApp. Version is the version of meta stored in the script, zero version
+// means that the script carries no meta and Callables is empty. Verifier is the name of the @Verifier function,
+// it is empty if the dApp has no verifier.
+type Meta struct {
+	Version   int            `json:"version"`
+	Callables []CallableMeta `json:"callables"`
+	Verifier  string         `json:"verifier,omitempty"`
+}
+
+// TreeMeta returns the metadata of the decoded dApp tree. Argument types are taken from the meta of the script and
+// given in RIDE notation, for example "Int" or "List[ByteVector|String]", argument names are taken from
+// declarations of callable functions, see ast.Callables.
+func TreeMeta(tree *ast.Tree) (*Meta, error) {
+	callables, err := ast.Callables(tree)
+	if err != nil {
+		return nil, err
+	}
+	res := &Meta{
+		Version:   tree.Meta.Version,
+		Callables: make([]CallableMeta, len(callables)),
+	}
+	for i, c := range callables {
+		args := make([]ArgumentMeta, len(c.Arguments))
+		for j, a := range c.Arguments {
+			args[j] = ArgumentMeta{Name: a.Name, Type: a.Type}
+		}
+		res.Callables[i] = CallableMeta{Name: c.Name, InvocationParameter: c.InvocationParameter, Arguments: args}
+	}
+	if tree.Verifier != nil {
+		v, ok := tree.Verifier.(*ast.FunctionDeclarationNode)
+		if !ok {
+			return nil, errors.Errorf("unexpected verifier node type %T", tree.Verifier)
+		}
+		res.Verifier = v.Name
+	}
+	return res, nil
+}
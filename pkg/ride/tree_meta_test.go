@@ -0,0 +1,96 @@
+package ride
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	ridec "github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+)
+
+func TestTreeMeta(t *testing.T) {
+	for _, test := range []struct {
+		comment string
+		source  string
+		meta    *Meta
+	}{
+		{`V3: no meta`, "AAIDAAAAAAAAAAAAAAABAQAAABFnZXRQcmV2aW91c0Fuc3dlcgAAAAEAAAAHYWRkcmVzcwUAAAAHYWRkcmVzcwAAAAIAAAABaQEAAAAGdGVsbG1lAAAAAQAAAAhxdWVzdGlvbgQAAAAGYW5zd2VyCQEAAAARZ2V0UHJldmlvdXNBbnN3ZXIAAAABBQAAAAhxdWVzdGlvbgkBAAAACFdyaXRlU2V0AAAAAQkABEwAAAACCQEAAAAJRGF0YUVudHJ5AAAAAgkAASwAAAACBQAAAAZhbnN3ZXICAAAAAl9xBQAAAAhxdWVzdGlvbgkABEwAAAACCQEAAAAJRGF0YUVudHJ5AAAAAgkAASwAAAACBQAAAAZhbnN3ZXICAAAAAl9hBQAAAAZhbnN3ZXIFAAAAA25pbAAAAAppbnZvY2F0aW9uAQAAAAdkZWZhdWx0AAAAAAQAAAAHc2VuZGVyMAgIBQAAAAppbnZvY2F0aW9uAAAABmNhbGxlcgAAAAVieXRlcwkBAAAACFdyaXRlU2V0AAAAAQkABEwAAAACCQEAAAAJRGF0YUVudHJ5AAAAAgIAAAABYQIAAAABYgkABEwAAAACCQEAAAAJRGF0YUVudHJ5AAAAAgIAAAAGc2VuZGVyBQAAAAdzZW5kZXIwBQAAAANuaWwAAAABAAAAAnR4AQAAAAZ2ZXJpZnkAAAAACQAAAAAAAAIJAQAAABFnZXRQcmV2aW91c0Fuc3dlcgAAAAEJAAQlAAAAAQgFAAAAAnR4AAAABnNlbmRlcgIAAAABMcP91gY=",
+			&Meta{Version: 0, Callables: []CallableMeta{}, Verifier: "verify"}},
+		// Test 0 from meta_test.go
+		{`V3: unions`, "AAIDAAAAAAAAAA0IARIECgIKBRIDCgEPAAAAAAAAAAIAAAABaQEAAAAFY2FsbDEAAAACAAAAAWEAAAABYgkBAAAADFNjcmlwdFJlc3VsdAAAAAIJAQAAAAhXcml0ZVNldAAAAAEFAAAAA25pbAkBAAAAC1RyYW5zZmVyU2V0AAAAAQUAAAADbmlsAAAAAWkBAAAABWNhbGwyAAAAAQAAAAFhCQEAAAAMU2NyaXB0UmVzdWx0AAAAAgkBAAAACFdyaXRlU2V0AAAAAQUAAAADbmlsCQEAAAALVHJhbnNmZXJTZXQAAAABBQAAAANuaWwAAAABAAAAAnR4AQAAAAZ2ZXJpZnkAAAAABg8tLpI=",
+			&Meta{Version: 1, Verifier: "verify", Callables: []CallableMeta{
+				{Name: "call1", InvocationParameter: "i", Arguments: []ArgumentMeta{
+					{Name: "a", Type: "ByteVector|String"}, {Name: "b", Type: "Int|Boolean"},
+				}},
+				{Name: "call2", InvocationParameter: "i", Arguments: []ArgumentMeta{
+					{Name: "a", Type: "Int|ByteVector|Boolean|String"},
+				}},
+			}}},
+		// Test 1 from meta_test.go
+		{`V5: lists`, "AAIFAAAAAAAAABEIAhIGCgQBBAgCEgUKAxEaGAAAAAAAAAACAAAAAWkBAAAABWNhbGwxAAAABAAAAAFuAAAAAWIAAAABcwAAAAFhBQAAAANuaWwAAAABaQEAAAAFY2FsbDIAAAADAAAAAmxpAAAAA2xzYgAAAAJzbAUAAAADbmlsAAAAAQAAAAJ0eAEAAAAGdmVyaWZ5AAAAAAaVNfq8",
+			&Meta{Version: 2, Verifier: "verify", Callables: []CallableMeta{
+				{Name: "call1", InvocationParameter: "i", Arguments: []ArgumentMeta{
+					{Name: "n", Type: "Int"}, {Name: "b", Type: "Boolean"}, {Name: "s", Type: "String"},
+					{Name: "a", Type: "ByteVector"},
+				}},
+				{Name: "call2", InvocationParameter: "i", Arguments: []ArgumentMeta{
+					{Name: "li", Type: "List[Int]"}, {Name: "lsb", Type: "List[ByteVector|String]"},
+					{Name: "sl", Type: "List[String]"},
+				}},
+			}}},
+	} {
+		src, err := base64.StdEncoding.DecodeString(test.source)
+		require.NoError(t, err, test.comment)
+		tree, err := serialization.Parse(src)
+		require.NoError(t, err, test.comment)
+
+		m, err := TreeMeta(tree)
+		require.NoError(t, err, test.comment)
+		assert.Equal(t, test.meta, m, test.comment)
+	}
+}
+
+func TestTreeMetaNotDApp(t *testing.T) {
+	src, err := base64.StdEncoding.DecodeString("AwZd0cYf") // {-# STDLIB_VERSION 3 #-} true
+	require.NoError(t, err)
+	tree, err := serialization.Parse(src)
+	require.NoError(t, err)
+
+	_, err = TreeMeta(tree)
+	assert.EqualError(t, err, "script is not a dApp")
+}
+
+func TestTreeMetaCompacted(t *testing.T) {
+	const code = `
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let multiplier = 10
+
+@Callable(inv)
+func a(amount: Int, note: String) = [IntegerEntry(note, amount * multiplier)]
+
+@Callable(inv)
+func deposit(recipients: List[String]) = [StringEntry("last", recipients[0])]`
+	src, errs := ridec.Compile(code, true, false)
+	require.Empty(t, errs)
+	tree, err := serialization.Parse(src)
+	require.NoError(t, err)
+	require.NotEqual(t, "amount", tree.Functions[0].(*ast.FunctionDeclarationNode).Arguments[0])
+
+	m, err := TreeMeta(tree)
+	require.NoError(t, err)
+	assert.Equal(t, &Meta{Version: 2, Callables: []CallableMeta{
+		{Name: "a", InvocationParameter: "inv", Arguments: []ArgumentMeta{
+			{Name: "amount", Type: "Int"}, {Name: "note", Type: "String"},
+		}},
+		{Name: "deposit", InvocationParameter: "inv", Arguments: []ArgumentMeta{
+			{Name: "recipients", Type: "List[String]"},
+		}},
+	}}, m)
+}
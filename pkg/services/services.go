@@ -57,4 +57,7 @@ type Services struct {
 	InternalChannel chan messages.InternalMessage
 	MinPeersMining  int
 	SkipMessageList *messages.SkipMessageList
+	// TxValidation is the pipeline used to validate transactions received from peers or API,
+	// the default pipeline is used if it's nil.
+	TxValidation *proto.ValidationPipeline
 }
@@ -244,10 +244,35 @@ func (s *accountsDataStorage) retrieveEntries(addr proto.Address) ([]proto.DataE
 	if err != nil {
 		return nil, err
 	}
+	var entries []proto.DataEntry
+	err = s.iterateEntriesByNum(addrNum, func(entry proto.DataEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// iterateEntries calls fn for every current data entry of the account in the order of entries keys.
+// Nothing is called for the account without data, iteration stops on the first error returned by fn.
+func (s *accountsDataStorage) iterateEntries(addr proto.Address, fn func(entry proto.DataEntry) error) error {
+	addrNum, err := s.addrToNum(addr)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil // no data was saved for the address
+		}
+		return err
+	}
+	return s.iterateEntriesByNum(addrNum, fn)
+}
+
+func (s *accountsDataStorage) iterateEntriesByNum(addrNum uint64, fn func(entry proto.DataEntry) error) error {
 	key := accountsDataStorKey{addrNum: addrNum}
 	iter, err := s.hs.newTopEntryIteratorByPrefix(key.accountPrefix())
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer func() {
 		iter.Release()
@@ -256,30 +281,68 @@ func (s *accountsDataStorage) retrieveEntries(addr proto.Address) ([]proto.DataE
 		}
 	}()
 
-	var entries []proto.DataEntry
 	for iter.Next() {
 		entryKeyBytes := keyvalue.SafeKey(iter)
 		recordBytes := keyvalue.SafeValue(iter)
 		var record dataEntryRecord
 		if err := record.unmarshalBinary(recordBytes); err != nil {
-			return nil, err
+			return err
 		}
 		var entryKey accountsDataStorKey
 		if err := entryKey.unmarshal(entryKeyBytes); err != nil {
-			return nil, err
+			return err
 		}
 		entry, err := proto.NewDataEntryFromValueBytes(record.value)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		// Skip Delete entries, they are not returned by APIs.
 		if entry.GetValueType() == proto.DataDelete {
 			continue
 		}
 		entry.SetKey(entryKey.entryKey)
-		entries = append(entries, entry)
+		if err := fn(entry); err != nil {
+			return err
+		}
 	}
-	return entries, nil
+	return nil
+}
+
+// entriesStateSize returns the total size of stored keys and values of the account's data entries.
+// Deleted entries are not counted.
+func (s *accountsDataStorage) entriesStateSize(addr proto.Address) (int64, error) {
+	addrNum, err := s.addrToNum(addr)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return 0, nil // no data was saved for the address
+		}
+		return 0, err
+	}
+	key := accountsDataStorKey{addrNum: addrNum}
+	iter, err := s.hs.newTopEntryIteratorByPrefix(key.accountPrefix())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			zap.S().Fatalf("Iterator error: %v", err)
+		}
+	}()
+
+	var size int64
+	for iter.Next() {
+		recordBytes := keyvalue.SafeValue(iter)
+		var record dataEntryRecord
+		if err := record.unmarshalBinary(recordBytes); err != nil {
+			return 0, err
+		}
+		if len(record.value) > 0 && proto.DataValueType(record.value[0]) == proto.DataDelete {
+			continue
+		}
+		size += int64(len(keyvalue.SafeKey(iter)) + len(recordBytes))
+	}
+	return size, nil
 }
 
 func (s *accountsDataStorage) newestEntryExists(addr proto.Address) (bool, error) {
@@ -347,6 +410,45 @@ func (s *accountsDataStorage) retrieveEntry(addr proto.Address, key string) (pro
 	return entry, nil
 }
 
+// retrieveEntryAtHeight returns the data entry of the address as it was at the given height.
+// The keyvalue.ErrNotFound is returned if the entry didn't exist at that height, was removed or the address is unknown.
+func (s *accountsDataStorage) retrieveEntryAtHeight(addr proto.Address, key string, height proto.Height) (proto.DataEntry, error) {
+	if err := s.hs.checkHeightRetained(height); err != nil {
+		return nil, err
+	}
+	addrNum, err := s.addrToNum(addr)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) { // address never had data entries
+			return nil, keyvalue.ErrNotFound
+		}
+		return nil, err
+	}
+	storKey := accountsDataStorKey{addrNum, key}
+	recordBytes, err := s.hs.entryDataAtHeight(storKey.bytes(), height)
+	if err != nil {
+		if errors.Is(err, errEmptyHist) {
+			return nil, keyvalue.ErrNotFound
+		}
+		return nil, err
+	}
+	if recordBytes == nil { // entry was set after the given height
+		return nil, keyvalue.ErrNotFound
+	}
+	var record dataEntryRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, err
+	}
+	entry, err := proto.NewDataEntryFromValueBytes(record.value)
+	if err != nil {
+		return nil, err
+	}
+	if entry.GetValueType() == proto.DataDelete {
+		return nil, keyvalue.ErrNotFound
+	}
+	entry.SetKey(key)
+	return entry, nil
+}
+
 func (s *accountsDataStorage) retrieveNewestIntegerEntry(addr proto.Address, key string) (*proto.IntegerDataEntry, error) {
 	id := entryId{addr.ID(), key}
 	if entry, ok := s.uncertainEntries[id]; ok {
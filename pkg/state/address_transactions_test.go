@@ -2,11 +2,13 @@ package state
 
 import (
 	"encoding/json"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 )
@@ -93,6 +95,77 @@ func testIterImpl(t *testing.T, params StateParams) {
 	assert.Equal(t, 2, i)
 	iter.Release()
 	require.NoError(t, iter.Error())
+
+	txs, err := st.AddressTransactions(addr, 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, validTxs, txs)
+	txs, err = st.AddressTransactions(addr, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, validTxs[:1], txs)
+	unknown := crypto.MustDigestFromBase58("AdquzVHoRYT9XzXUMNAnJqbqaUmU8kZmGjN7sDDZ9KyE")
+	_, err = st.AddressTransactions(addr, 10, &unknown)
+	assert.True(t, IsNotFound(err))
+	_, err = st.AddressTransactions(addr, 0, nil)
+	assert.True(t, IsInvalidInput(err))
+}
+
+type sliceTransactionIterator struct {
+	txs []proto.Transaction
+	i   int
+}
+
+func (it *sliceTransactionIterator) Transaction() (proto.Transaction, proto.TransactionStatus, error) {
+	return it.txs[it.i-1], proto.TransactionSucceeded, nil
+}
+
+func (it *sliceTransactionIterator) Next() bool {
+	if it.i >= len(it.txs) {
+		return false
+	}
+	it.i++
+	return true
+}
+
+func (it *sliceTransactionIterator) Release() {}
+
+func (it *sliceTransactionIterator) Error() error {
+	return nil
+}
+
+func TestTransactionsPage(t *testing.T) {
+	txs := make([]proto.Transaction, 5)
+	ids := make([]crypto.Digest, len(txs))
+	for i := range txs {
+		tx := proto.NewUnsignedTransferWithProofs(2, testGlobal.senderInfo.pk, proto.NewOptionalAssetWaves(),
+			proto.NewOptionalAssetWaves(), defaultTimestamp+uint64(i), FeeUnit, FeeUnit,
+			proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), nil)
+		require.NoError(t, tx.Sign(proto.TestNetScheme, testGlobal.senderInfo.sk))
+		txs[i] = tx
+		ids[i] = *tx.ID
+	}
+	unknown := crypto.MustDigestFromBase58("AdquzVHoRYT9XzXUMNAnJqbqaUmU8kZmGjN7sDDZ9KyE")
+	for i, test := range []struct {
+		limit    int
+		after    *crypto.Digest
+		expected []proto.Transaction
+	}{
+		{10, nil, txs},
+		{2, nil, txs[:2]},
+		{2, &ids[0], txs[1:3]},
+		{10, &ids[2], txs[3:]},
+		{10, &ids[4], []proto.Transaction{}},
+		{10, &unknown, nil},
+	} {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			page, err := transactionsPage(&sliceTransactionIterator{txs: txs}, proto.TestNetScheme, test.limit, test.after)
+			if test.expected == nil {
+				assert.True(t, IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, page)
+		})
+	}
 }
 
 func TestTransactionsByAddrIterator(t *testing.T) {
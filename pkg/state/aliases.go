@@ -2,6 +2,7 @@ package state
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io"
 	"math"
 
@@ -145,7 +146,7 @@ type aliases struct {
 	dbBatch keyvalue.Batch
 	hs      *historyStorage
 
-	disabled map[string]bool
+	disabled map[string]uint32 // stolen aliases disabled in the current batch by the block number of disabling
 
 	scheme          proto.Scheme
 	calculateHashes bool
@@ -157,7 +158,7 @@ func newAliases(hs *historyStorage, scheme proto.Scheme, calcHashes bool) *alias
 		db:              hs.db,
 		dbBatch:         hs.dbBatch,
 		hs:              hs,
-		disabled:        make(map[string]bool),
+		disabled:        make(map[string]uint32),
 		scheme:          scheme,
 		calculateHashes: calcHashes,
 		hasher:          newStateHasher(),
@@ -237,6 +238,27 @@ func (a *aliases) isDisabled(aliasStr string) (bool, error) {
 	return a.db.Has(key.bytes())
 }
 
+// isDisabledAtHeight checks that the alias was already disabled at the given height.
+// Aliases disabled before the block number of disabling was stored are considered disabled at any height.
+func (a *aliases) isDisabledAtHeight(aliasStr string, height proto.Height) (bool, error) {
+	key := disabledAliasKey{alias: aliasStr}
+	blockNumBytes, err := a.db.Get(key.bytes())
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if len(blockNumBytes) != 4 {
+		return true, nil
+	}
+	blockNum, err := a.hs.stateDB.blockNumByHeight(height)
+	if err != nil {
+		return false, err
+	}
+	return blockNum >= binary.LittleEndian.Uint32(blockNumBytes), nil
+}
+
 func (a *aliases) newestAddrByAlias(aliasStr string) (proto.WavesAddress, error) {
 	disabled, err := a.newestIsDisabled(aliasStr)
 	if err != nil {
@@ -293,8 +315,41 @@ func (a *aliases) addrByAlias(aliasStr string) (proto.WavesAddress, error) {
 	return record.info.addressID.ToWavesAddress(a.scheme)
 }
 
+// addrByAliasAt returns the address which the alias pointed to at the given height.
+// The keyvalue.ErrNotFound is returned if the alias wasn't registered or was disabled at that height.
+// The errHeightNotRetained is returned if the height is below the rollback window.
+func (a *aliases) addrByAliasAt(aliasStr string, height proto.Height) (proto.WavesAddress, error) {
+	key := aliasKey{alias: aliasStr}
+	recordBytes, err := a.hs.retainedEntryDataAtHeight(key.bytes(), height)
+	if err != nil {
+		if errors.Is(err, errEmptyHist) || errors.Is(err, keyvalue.ErrNotFound) {
+			return proto.WavesAddress{}, keyvalue.ErrNotFound
+		}
+		return proto.WavesAddress{}, err
+	}
+	if recordBytes == nil { // alias was registered after the given height
+		return proto.WavesAddress{}, keyvalue.ErrNotFound
+	}
+	disabled, err := a.isDisabledAtHeight(aliasStr, height)
+	if err != nil {
+		return proto.WavesAddress{}, err
+	}
+	if disabled {
+		return proto.WavesAddress{}, errAliasDisabled
+	}
+	var record aliasRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return proto.WavesAddress{}, errors.Wrap(err, "failed to unmarshal record")
+	}
+	return record.info.addressID.ToWavesAddress(a.scheme)
+}
+
 func (a *aliases) disableStolenAliases(blockID proto.BlockID) error {
 	// TODO: this action can not be rolled back now, do we need it?
+	blockNum, err := a.hs.stateDB.newestBlockIdToNum(blockID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get number of the block disabling aliases")
+	}
 	iter, err := a.hs.newNewestTopEntryIterator(alias)
 	if err != nil {
 		return err
@@ -320,7 +375,7 @@ func (a *aliases) disableStolenAliases(blockID proto.BlockID) error {
 			continue
 		}
 		zap.S().Debugf("Forbidding stolen alias %s", key.alias)
-		a.disabled[key.alias] = true
+		a.disabled[key.alias] = blockNum
 		if err := a.removeAliasByAddressID(record.info.addressID, key.alias, blockID); err != nil {
 			return errors.Wrap(err, "failed to disable aliases")
 		}
@@ -366,14 +421,16 @@ func (a *aliases) prepareHashes() error {
 }
 
 func (a *aliases) flush() {
-	for alias := range a.disabled {
+	for alias, blockNum := range a.disabled {
 		disabledKey := disabledAliasKey{alias}
-		a.dbBatch.Put(disabledKey.bytes(), void)
+		blockNumBytes := make([]byte, 4)
+		binary.LittleEndian.PutUint32(blockNumBytes, blockNum)
+		a.dbBatch.Put(disabledKey.bytes(), blockNumBytes)
 	}
 }
 
 func (a *aliases) reset() {
-	a.disabled = make(map[string]bool)
+	a.disabled = make(map[string]uint32)
 	a.hasher.reset()
 }
 
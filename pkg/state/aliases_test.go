@@ -1,10 +1,13 @@
 package state
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 )
 
@@ -26,6 +29,56 @@ func TestCreateAlias(t *testing.T) {
 	assert.Equal(t, aliasAddr, addr)
 }
 
+func TestAddrByAliasAt(t *testing.T) {
+	to := createStorageObjects(t, true)
+
+	aliasStr := "alias"
+	to.addBlock(t, blockID0)
+	to.addBlock(t, blockID1)
+	to.flush(t)
+	aliasAddr, err := proto.NewAddressFromString(addr0)
+	require.NoError(t, err, "NewAddressFromString() failed")
+	err = to.entities.aliases.createAlias(aliasStr, aliasAddr, blockID1)
+	require.NoError(t, err, "createAlias() failed")
+	to.flush(t)
+
+	addr, err := to.entities.aliases.addrByAliasAt(aliasStr, 2)
+	require.NoError(t, err, "addrByAliasAt() failed")
+	assert.Equal(t, aliasAddr, addr)
+	_, err = to.entities.aliases.addrByAliasAt(aliasStr, 1)
+	assert.ErrorIs(t, err, keyvalue.ErrNotFound)
+	_, err = to.entities.aliases.addrByAliasAt("unknown", 2)
+	assert.ErrorIs(t, err, keyvalue.ErrNotFound)
+
+	// The alias is stolen at the height 3 and disabled at the height 4.
+	stealAddr, err := proto.NewAddressFromString(addr1)
+	require.NoError(t, err, "NewAddressFromString() failed")
+	to.addBlock(t, blockID2)
+	err = to.entities.aliases.createAlias(aliasStr, stealAddr, blockID2)
+	require.NoError(t, err, "createAlias() failed")
+	to.flush(t)
+	to.addBlock(t, blockID3)
+	err = to.entities.aliases.disableStolenAliases(blockID3)
+	require.NoError(t, err, "disableStolenAliases() failed")
+	to.flush(t)
+
+	addr, err = to.entities.aliases.addrByAliasAt(aliasStr, 2)
+	require.NoError(t, err, "addrByAliasAt() failed")
+	assert.Equal(t, aliasAddr, addr)
+	addr, err = to.entities.aliases.addrByAliasAt(aliasStr, 3)
+	require.NoError(t, err, "addrByAliasAt() failed")
+	assert.Equal(t, stealAddr, addr)
+	_, err = to.entities.aliases.addrByAliasAt(aliasStr, 4)
+	assert.ErrorIs(t, err, errAliasDisabled)
+
+	// Heights below the rollback window are not retained.
+	minHeight := make([]byte, 8)
+	binary.LittleEndian.PutUint64(minHeight, 3)
+	require.NoError(t, to.stateDB.db.Put(rollbackMinHeightKeyBytes, minHeight))
+	_, err = to.entities.aliases.addrByAliasAt(aliasStr, 2)
+	assert.ErrorIs(t, err, errHeightNotRetained)
+}
+
 func TestDisableStolenAliases(t *testing.T) {
 	to := createStorageObjects(t, true)
 
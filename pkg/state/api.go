@@ -1,6 +1,9 @@
 package state
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"math/big"
 	"runtime"
 
@@ -39,6 +42,9 @@ type StateInfo interface {
 	// Header getters.
 	Header(blockID proto.BlockID) (*proto.BlockHeader, error)
 	HeaderByHeight(height proto.Height) (*proto.BlockHeader, error)
+	// BlockHeaderWithTxCount returns the header of the block at the given height and the number of the block's
+	// transactions. Only the stored header is read, transactions of the block are not loaded.
+	BlockHeaderWithTxCount(height proto.Height) (*proto.BlockHeader, int, error)
 	// Height returns current blockchain height.
 	Height() (proto.Height, error)
 	// Height <---> blockID converters.
@@ -48,11 +54,18 @@ type StateInfo interface {
 	// FullWavesBalance returns complete Waves balance record.
 	FullWavesBalance(account proto.Recipient) (*proto.FullWavesBalance, error)
 	GeneratingBalance(account proto.Recipient, height proto.Height) (uint64, error)
+	// GeneratingBalances returns generating balances of the given addresses at the given height.
+	// It's intended for debugging of the generator selection.
+	GeneratingBalances(height proto.Height, addrs []proto.WavesAddress) (map[proto.WavesAddress]uint64, error)
 	// AssetBalance retrieves balance of account in specific currency, asset is asset's ID.
 	AssetBalance(account proto.Recipient, assetID proto.AssetID) (uint64, error)
 	// WavesAddressesNumber returns total number of Waves addresses in state.
 	// It is extremely slow, so it is recommended to only use for testing purposes.
 	WavesAddressesNumber() (uint64, error)
+	// ExportBalancesCSV writes non-zero balances of all addresses for the asset (or Waves if assetID is nil)
+	// at the given height to w as CSV rows "address,balance". The height must be within the rollback window.
+	// The state lock is not held while writing, an error is returned if the block is rolled back meanwhile.
+	ExportBalancesCSV(w io.Writer, assetID *proto.AssetID, height proto.Height) error
 
 	// Get cumulative blocks score at given height.
 	ScoreAtHeight(height proto.Height) (*big.Int, error)
@@ -72,15 +85,26 @@ type StateInfo interface {
 	IsApprovedAtHeight(featureID int16, height proto.Height) (bool, error)
 	ApprovalHeight(featureID int16) (proto.Height, error)
 	AllFeatures() ([]int16, error)
+	// PendingFeatures returns the statuses of features voted for in the voting period of the given height,
+	// approved features waiting for activation and features activated in the period.
+	PendingFeatures(height proto.Height) ([]FeatureVoteStatus, error)
 	EstimatorVersion() (int, error)
 	IsActiveLightNodeNewBlocksFields(blockHeight proto.Height) (bool, error)
 
 	// Aliases.
 	AddrByAlias(alias proto.Alias) (proto.WavesAddress, error)
+	// AddressByAliasAt resolves the alias to the address it pointed to at the given height.
+	AddressByAliasAt(alias proto.Alias, height proto.Height) (proto.WavesAddress, error)
 	AliasesByAddr(addr proto.WavesAddress) ([]string, error)
 
 	// Accounts data storage.
 	RetrieveEntries(account proto.Recipient) ([]proto.DataEntry, error)
+	// AllDataEntries returns all current data entries of the address, empty list is returned for the address
+	// without data. Use IterateDataEntries for accounts with large data sets.
+	AllDataEntries(addr proto.WavesAddress) ([]proto.DataEntry, error)
+	// IterateDataEntries calls fn for every current data entry of the address without loading all entries
+	// at once. Iteration stops on the first error returned by fn. The fn must not call the state.
+	IterateDataEntries(addr proto.WavesAddress, fn func(entry proto.DataEntry) error) error
 	RetrieveEntry(account proto.Recipient, key string) (proto.DataEntry, error)
 	RetrieveIntegerEntry(account proto.Recipient, key string) (*proto.IntegerDataEntry, error)
 	RetrieveBooleanEntry(account proto.Recipient, key string) (*proto.BooleanDataEntry, error)
@@ -95,23 +119,52 @@ type StateInfo interface {
 	// given address.
 	// Iterator will move in range from most recent to oldest transactions.
 	NewAddrTransactionsIterator(addr proto.Address) (TransactionIterator, error)
+	// AddressTransactions returns up to limit transactions that affected the given address starting from the
+	// most recent one. If after is not nil, only transactions older than the transaction with this ID are returned.
+	AddressTransactions(addr proto.WavesAddress, limit int, after *crypto.Digest) ([]proto.Transaction, error)
 
 	// Asset fee sponsorship.
 	AssetIsSponsored(assetID proto.AssetID) (bool, error)
 	IsAssetExist(assetID proto.AssetID) (bool, error)
 	AssetInfo(assetID proto.AssetID) (*proto.AssetInfo, error)
 	FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error)
+	// AssetSupply returns the total supply of the asset, which accounts all issues, reissues and burns, and its
+	// circulating supply, which is the total supply minus the amount held by the asset's issuer.
+	AssetSupply(assetID proto.AssetID) (total uint64, circulating uint64, err error)
 	EnrichedFullAssetInfo(assetID proto.AssetID) (*proto.EnrichedFullAssetInfo, error)
 	NFTList(account proto.Recipient, limit uint64, afterAssetID *proto.AssetID) ([]*proto.FullAssetInfo, error)
+	// AssetsIssuedBy returns IDs of all assets issued by the address in the order of issue,
+	// regardless of the current balances of the assets. The index of issued assets is built during
+	// block application, so states of versions prior to 27 have to be reimported.
+	AssetsIssuedBy(addr proto.WavesAddress) ([]proto.AssetID, error)
+	// AccountStateSize returns the total number of bytes the account occupies in the stored state:
+	// data entries, script and balances. Deleted data entries and zero asset balances are not counted.
+	AccountStateSize(addr proto.WavesAddress) (int64, error)
 	// Script information.
 	ScriptBasicInfoByAccount(account proto.Recipient) (*proto.ScriptBasicInfo, error)
 	ScriptInfoByAccount(account proto.Recipient) (*proto.ScriptInfo, error)
 	ScriptInfoByAsset(assetID proto.AssetID) (*proto.ScriptInfo, error)
 	NewestScriptByAccount(account proto.Recipient) (*ast.Tree, error)
 	NewestScriptBytesByAccount(account proto.Recipient) (proto.Script, error)
+	// NewestAccountIsDApp reports whether the account has a dApp script with callable functions.
+	// False is returned for accounts without script or with a script having only a verifier. The script tree
+	// is taken from the scripts cache and is decoded only for dApps.
+	NewestAccountIsDApp(addr proto.WavesAddress) (bool, error)
+	// IterateStoredScripts calls fn for every stored account and asset script, account scripts go first, removed
+	// scripts are skipped. Scripts are read one by one from the DB iterator and the context is checked before each
+	// of them. Only the data committed to DB is read, so the state lock is not held while fn is called.
+	// The script bytes are valid only during the call of fn. Iteration stops on the first error returned by fn.
+	// See FindScripts for the search of scripts.
+	IterateStoredScripts(ctx context.Context, fn func(sc StoredScript) error) error
+	// EthereumCall evaluates the callable of the dApp given the Ethereum call data without a transaction, like
+	// eth_call does. The changes made by the callable are discarded, the returned value is encoded into Ethereum ABI.
+	EthereumCall(from, to proto.EthereumAddress, data []byte) ([]byte, error)
 
 	// Leases.
 	IsActiveLeasing(leaseID crypto.Digest) (bool, error)
+	// LeaseTotals returns the sums of amounts leased out by the address and leased to it by the leases
+	// active at the given height.
+	LeaseTotals(addr proto.WavesAddress, height proto.Height) (out uint64, in uint64, err error)
 
 	// Invoke results.
 	InvokeResultByID(invokeID crypto.Digest) (*proto.ScriptResult, error)
@@ -133,6 +186,9 @@ type StateInfo interface {
 	HitSourceAtHeight(height proto.Height) ([]byte, error)
 	// BlockVRF calculates VRF value for the block at given height.
 	BlockVRF(blockHeader *proto.BlockHeader, blockHeight proto.Height) ([]byte, error)
+	// BlockGenerationSignature computes the generation signature and VRF value of the block with given ID.
+	// VRF is nil for blocks of versions before the protobuf one.
+	BlockGenerationSignature(blockID proto.BlockID) ([]byte, []byte, error)
 
 	// ShouldPersistAddressTransactions checks the size of temporary transaction storage file and returns true if we
 	// should move transactions into the main storage.
@@ -143,6 +199,8 @@ type StateInfo interface {
 	// It takes into account the reward multiplier introduced with the feature #23 "BoostBlockReward".
 	RewardAtHeight(height proto.Height) (uint64, error)
 
+	// RewardVotes returns the numbers of votes for increase and decrease of the block reward collected
+	// in the voting period up to the given height. Zero votes are returned outside the voting period.
 	RewardVotes(height proto.Height) (proto.RewardVotes, error)
 
 	// TotalWavesAmount returns total amount of Waves in the system at the given height.
@@ -154,6 +212,82 @@ type StateInfo interface {
 
 	// SnapshotsAtHeight returns block snapshots at the given height.
 	SnapshotsAtHeight(height proto.Height) (proto.BlockSnapshot, error)
+	// BlockSnapshot returns snapshots of all transactions of the block with the given ID.
+	// Not found error is returned for unknown blocks and blocks that have no stored snapshots.
+	BlockSnapshot(blockID proto.BlockID) (*proto.BlockSnapshot, error)
+	// BlockDataChanges returns the data entries changed by the transactions of the block grouped by address.
+	// Entries are listed in the order of application, an entry changed several times is listed once with
+	// its final value. Removed entries are reported as proto.DeleteDataEntry.
+	BlockDataChanges(blockID proto.BlockID) (map[proto.WavesAddress][]proto.DataEntry, error)
+	// BlockBalanceChanges returns the balances of the given asset (or Waves if assetID is nil) before and after
+	// the block for every address whose balance was changed by the transactions of the block.
+	// The block must follow the first height of the rollback window, the history of older balances is not retained.
+	BlockBalanceChanges(blockID proto.BlockID, assetID *proto.AssetID) (map[proto.WavesAddress][2]uint64, error)
+	// StateDelta returns the blocks and snapshots applied after the height 'from' up to the height 'to'.
+	StateDelta(from, to proto.Height) ([]BlockSnapshots, error)
+	// ExportStateSnapshot writes the committed state at the current height for ImportStateSnapshot.
+	ExportStateSnapshot(w io.Writer, height proto.Height) error
+	// StateAt returns the read-only view of the state pinned to the given height.
+	// Only the heights of the rollback window are accepted, older history is not retained.
+	StateAt(height proto.Height) (ReadOnlyState, error)
+	// WarmCaches loads scripts used by transactions of the recent blocks into the scripts cache and reads
+	// the sponsorships of their fee assets. It's intended to be called once after the start, loading is limited
+	// by the number of blocks and scripts.
+	WarmCaches(ctx context.Context) error
+}
+
+// FeatureStatus is the status of the feature in the blockchain.
+type FeatureStatus byte
+
+const (
+	FeatureVoting FeatureStatus = iota
+	FeatureApproved
+	FeatureActivated
+)
+
+func (s FeatureStatus) String() string {
+	switch s {
+	case FeatureVoting:
+		return "voting"
+	case FeatureApproved:
+		return "approved"
+	case FeatureActivated:
+		return "activated"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(s))
+	}
+}
+
+// FeatureVoteStatus describes the voting for the feature at some height. Votes is the number of blocks supporting
+// the feature from the beginning of the voting period, the feature is approved at the end of the period if Votes
+// reaches Threshold. BlocksRemaining is the number of blocks left till the end of the voting period.
+type FeatureVoteStatus struct {
+	FeatureID       int16
+	Votes           uint64
+	Threshold       uint64
+	Status          FeatureStatus
+	BlocksRemaining uint64
+}
+
+// ScriptsReestimation is the result of the re-estimation of a batch of stored scripts. Next is the key of the script
+// to start the next batch from, it's nil if there are no more scripts. Failures of individual scripts of the batch
+// are collected in Failures, such scripts are counted as processed.
+type ScriptsReestimation struct {
+	Next      []byte
+	Processed int
+	Total     int
+	Failures  []error
+}
+
+// ReadOnlyState is a view of the state pinned to some height. All reads through the view reflect the state
+// at that height, so the view could be used to serve consistent historical queries.
+type ReadOnlyState interface {
+	// Height returns the height the view is pinned to.
+	Height() proto.Height
+	WavesBalance(account proto.Recipient) (uint64, error)
+	AssetBalance(account proto.Recipient, assetID proto.AssetID) (uint64, error)
+	RetrieveEntry(account proto.Recipient, key string) (proto.DataEntry, error)
+	ScriptBytesByAccount(account proto.Recipient) (proto.Script, error)
 }
 
 // StateModifier contains all the methods needed to modify node's state.
@@ -170,6 +304,9 @@ type StateModifier interface {
 	// AddDeserializedBlocks marshals blocks to binary and calls AddBlocks.
 	AddDeserializedBlocks(blocks []*proto.Block) (*proto.Block, error)
 	AddDeserializedBlocksWithSnapshots(blocks []*proto.Block, snapshots []*proto.BlockSnapshot) (*proto.Block, error)
+	// ApplyStateDelta applies the delta produced by StateDelta and verifies the state hashes before and after it.
+	// The state is rolled back to its height before the call if the delta fails to apply or verify.
+	ApplyStateDelta(delta []BlockSnapshots) error
 	// Rollback functionality.
 	RollbackToHeight(height proto.Height) error
 	RollbackTo(removalEdge proto.BlockID) error
@@ -192,6 +329,28 @@ type StateModifier interface {
 
 	// Func internally calls ResetValidationList.
 	TxValidation(func(validation TxValidation) error) error
+	// SelectNonConflicting dry-runs the transactions in the given order against the committed state and returns
+	// the transactions which can be applied together and the rejected ones. State is not changed.
+	// The currentTimestamp is the timestamp of the block being assembled, it's used to validate the transactions.
+	SelectNonConflicting(
+		txs []proto.Transaction, currentTimestamp uint64,
+	) (accepted, rejected []proto.Transaction, err error)
+
+	// SetFeatureActivationHeight sets the feature activated at the given height bypassing the voting.
+	// Only for tests, fails unless StateParams.UnsafeAllowFeatureActivationOverride is set.
+	SetFeatureActivationHeight(featureID int16, height proto.Height) error
+	// ReestimateScripts estimates at most limit stored account and asset scripts, which keys are not less than from,
+	// with the given estimator version. The new complexities are added at the top block through the usual batch,
+	// history of complexities is not changed, so the rollback of the top block reverts the re-estimation.
+	// Scripts already estimated with the version are skipped. The batch is saved at once, also on cancellation.
+	ReestimateScripts(
+		ctx context.Context, estimatorVersion int, from []byte, limit int,
+	) (ScriptsReestimation, error)
+	// ReestimateAllScripts re-estimates all stored scripts batch by batch with ReestimateScripts. Progress is
+	// reported after every batch. Scripts already estimated with the version are skipped, so the interrupted
+	// re-estimation could be resumed by calling the function again. Failures of individual scripts don't stop
+	// the re-estimation, they are returned joined together at the end, as well as the context error on cancellation.
+	ReestimateAllScripts(ctx context.Context, estimatorVersion int, progress func(done, total int)) error
 
 	// Way to call multiple operations under same lock.
 	Map(func(state NonThreadSafeState) error) error
@@ -258,6 +417,7 @@ func DefaultStorageParams() StorageParams {
 		CompactionTableSize:    DefaultCompactionTableSize,
 		CompactionTotalSize:    DefaultCompactionTotalSize,
 		OpenFilesCacheCapacity: DefaultOpenFilesCacheCapacity,
+		OpenRetryTimeout:       DefaultDBOpenRetryTimeout,
 	}
 	return StorageParams{
 		OffsetLen:       DefaultOffsetLen,
@@ -277,6 +437,9 @@ func DefaultTestingStorageParams() StorageParams {
 type ValidationParams struct {
 	VerificationGoroutinesNum int
 	Time                      types.Time
+	// MaxConcurrentEvaluations limits the number of scripts evaluated concurrently during transactions validation
+	// and read-only calls, evaluations beyond the limit wait for a free slot. Zero means no limit.
+	MaxConcurrentEvaluations int
 }
 
 type StateParams struct {
@@ -288,6 +451,19 @@ type StateParams struct {
 	ProvideExtendedApi bool
 	// BuildStateHashes enables building and storing state hashes by height.
 	BuildStateHashes bool
+	// SponsorshipPolicy overrides conversion of sponsored fees to Waves, DefaultSponsorshipPolicy is used if nil.
+	// It's intended only for experimental networks, changing it for existing networks breaks consensus.
+	SponsorshipPolicy SponsorshipPolicy
+	// CheckSnapshotBalances enables rejection of balance snapshots that produce negative balances. Spendable Waves
+	// balances are checked on the state after all snapshots of the transaction are applied. It's a safety net
+	// additional to the transaction validation, NegativeBalanceError is returned.
+	CheckSnapshotBalances bool
+	// TxMetrics receives execution metrics of every transaction applied in blocks.
+	// Metrics are not measured if it's nil.
+	TxMetrics TxMetricsCollector
+	// UnsafeAllowFeatureActivationOverride enables setting of features activation heights bypassing the voting.
+	// It's intended only for tests, never enable it for a real node.
+	UnsafeAllowFeatureActivationOverride bool
 }
 
 func DefaultStateParams() StateParams {
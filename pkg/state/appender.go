@@ -54,6 +54,9 @@ type txAppender struct {
 	// buildApiData flag indicates that additional data for API is built when
 	// appending transactions.
 	buildApiData bool
+
+	// txMetrics receives execution metrics of transactions applied in blocks, metrics are not measured if it's nil.
+	txMetrics TxMetricsCollector
 }
 
 func newTxAppender(
@@ -64,12 +67,14 @@ func newTxAppender(
 	stateDB *stateDB,
 	atx *addressTransactions,
 	snapshotApplier *blockSnapshotsApplier,
+	maxConcurrentEvaluations int,
+	txMetrics TxMetricsCollector,
 ) (*txAppender, error) {
 	buildAPIData, err := stateDB.stateStoresApiData()
 	if err != nil {
 		return nil, err
 	}
-	sc, err := newScriptCaller(state, stor, settings)
+	sc, err := newScriptCaller(state, stor, settings, maxConcurrentEvaluations)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +117,7 @@ func newTxAppender(
 		diffApplier:       diffApplier,
 		buildApiData:      buildAPIData,
 		ethTxKindResolver: ethKindResolver,
+		txMetrics:         txMetrics,
 	}, nil
 }
 
@@ -552,6 +558,7 @@ func (a *txAppender) appendTx(tx proto.Transaction, params *appendTxParams) (txS
 		a.stor.dropUncertain()
 	}()
 
+	meter := newTxMeter(a.txMetrics != nil && !params.validatingUtx)
 	blockID := params.checkerInfo.blockID
 	// Check that Protobuf transactions are accepted.
 	if err := a.checkProtobufVersion(tx, params.blockV5Activated); err != nil {
@@ -580,6 +587,7 @@ func (a *txAppender) appendTx(tx proto.Transaction, params *appendTxParams) (txS
 	if err = a.verifyWavesTxSigAndData(tx, params, accountHasVerifierScript); err != nil {
 		return txSnapshot{}, errs.Extend(err, "tx signature or data verification failed")
 	}
+	meter.lap(&meter.metrics.Validation)
 
 	// Check tx against state, check tx scripts, calculate balance changes.
 	applicationRes, invocationResult, needToValidateBalanceDiff, err :=
@@ -593,6 +601,7 @@ func (a *txAppender) appendTx(tx proto.Transaction, params *appendTxParams) (txS
 			return txSnapshot{}, errs.Extend(err, "validate transaction diff")
 		}
 	}
+	meter.lap(&meter.metrics.ScriptExecution)
 	// Check complexity limits and scripts runs limits.
 	if err := a.checkScriptsLimits(a.totalScriptsRuns+applicationRes.totalScriptsRuns, blockID); err != nil {
 		return txSnapshot{}, errs.Extend(errors.Errorf("%s: %v", blockID.String(), err), "check scripts limits")
@@ -609,12 +618,14 @@ func (a *txAppender) appendTx(tx proto.Transaction, params *appendTxParams) (txS
 		zap.S().Errorf("failed to commit transaction (id %s) after successful validation; this should NEVER happen", base58.Encode(txID))
 		return txSnapshot{}, err
 	}
+	meter.lap(&meter.metrics.SnapshotApplication)
 	// Store additional data for API: transaction by address.
 	if !params.validatingUtx && a.buildApiData {
 		if err = a.saveTransactionIdByAddresses(applicationRes.changes.addresses(), txID, blockID); err != nil {
 			return txSnapshot{}, errs.Extend(err, "save transaction id by addresses")
 		}
 	}
+	meter.report(a.txMetrics, blockID, tx, txID)
 	return snapshot, nil
 }
 
@@ -644,10 +655,13 @@ func (a *txAppender) applySnapshotInLightNode(
 			)
 		}
 		stateHash = txSh
+		meter := newTxMeter(a.txMetrics != nil)
 		regSnapshots := txSnapshot{regular: txs}
 		if err := regSnapshots.Apply(a.txHandler.sa, tx, false); err != nil {
 			return crypto.Digest{}, errors.Wrap(err, "failed to apply tx snapshot")
 		}
+		meter.lap(&meter.metrics.SnapshotApplication)
+		meter.report(a.txMetrics, params.block.BlockID(), tx, txID)
 		if fErr := a.blockDiffer.countMinerFee(tx); fErr != nil {
 			return crypto.Digest{}, errors.Wrapf(fErr, "failed to count miner fee for tx %d", i+1)
 		}
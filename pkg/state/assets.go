@@ -133,17 +133,19 @@ type assets struct {
 	db      keyvalue.KeyValue
 	dbBatch keyvalue.Batch
 	hs      *historyStorage
+	scheme  proto.Scheme
 
 	freshConstInfo map[proto.AssetID]assetConstInfo
 
 	uncertainAssetInfo map[proto.AssetID]wrappedUncertainInfo
 }
 
-func newAssets(db keyvalue.KeyValue, dbBatch keyvalue.Batch, hs *historyStorage) *assets {
+func newAssets(db keyvalue.KeyValue, dbBatch keyvalue.Batch, hs *historyStorage, scheme proto.Scheme) *assets {
 	return &assets{
 		db:                 db,
 		dbBatch:            dbBatch,
 		hs:                 hs,
+		scheme:             scheme,
 		freshConstInfo:     make(map[proto.AssetID]assetConstInfo),
 		uncertainAssetInfo: make(map[proto.AssetID]wrappedUncertainInfo),
 	}
@@ -172,7 +174,51 @@ func (a *assets) storeAssetInfo(assetID proto.AssetID, asset *assetInfo, blockID
 }
 
 func (a *assets) issueAsset(assetID proto.AssetID, asset *assetInfo, blockID proto.BlockID) error {
-	return a.storeAssetInfo(assetID, asset, blockID)
+	if err := a.storeAssetInfo(assetID, asset, blockID); err != nil {
+		return err
+	}
+	return a.addIssuerAsset(assetID, asset, blockID)
+}
+
+// addIssuerAsset adds the asset to the index of assets issued by the issuer.
+func (a *assets) addIssuerAsset(assetID proto.AssetID, asset *assetInfo, blockID proto.BlockID) error {
+	addr, err := proto.NewAddressFromPublicKey(a.scheme, asset.Issuer)
+	if err != nil {
+		return errors.Wrap(err, "failed to create issuer address")
+	}
+	key := issuedAssetKey{
+		issuer:        addr.ID(),
+		issueHeight:   asset.IssueHeight,
+		issueSequence: asset.IssueSequenceInBlock,
+		asset:         assetID,
+	}
+	if err := a.hs.addNewEntry(issuerAssets, key.bytes(), []byte{1}, blockID); err != nil {
+		return errors.Wrapf(err, "failed to add issued asset %q for addr %q", assetID.String(), addr.String())
+	}
+	return nil
+}
+
+// assetsIssuedBy returns IDs of assets issued by the address in the order of issue.
+func (a *assets) assetsIssuedBy(addr proto.WavesAddress) (_ []proto.AssetID, err error) {
+	key := issuedAssetKey{issuer: addr.ID()}
+	iter, err := a.hs.newTopEntryIteratorByPrefix(key.issuerPrefix())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to iterate assets issued by addr %q", addr.String())
+	}
+	defer func() {
+		iter.Release()
+		if iErr := iter.Error(); iErr != nil && err == nil {
+			err = errors.Wrapf(iErr, "failed to iterate assets issued by addr %q", addr.String())
+		}
+	}()
+	var ids []proto.AssetID
+	for iter.Next() {
+		if uErr := key.unmarshal(keyvalue.SafeKey(iter)); uErr != nil {
+			return nil, errors.Wrap(uErr, "failed to unmarshal issued asset key")
+		}
+		ids = append(ids, key.asset)
+	}
+	return ids, nil
 }
 
 type wrappedUncertainInfo struct {
@@ -389,6 +435,12 @@ func (a *assets) assetInfo(assetID proto.AssetID) (*assetInfo, error) {
 func (a *assets) commitUncertain(blockID proto.BlockID) error {
 	for assetID, info := range a.uncertainAssetInfo {
 		infoCpy := info // prevent implicit memory aliasing in for loop
+		if infoCpy.wasJustIssued {
+			if err := a.issueAsset(assetID, &infoCpy.assetInfo, blockID); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := a.storeAssetInfo(assetID, &infoCpy.assetInfo, blockID); err != nil {
 			return err
 		}
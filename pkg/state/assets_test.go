@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
@@ -18,7 +19,7 @@ type assetsTestObjects struct {
 
 func createAssets(t *testing.T) *assetsTestObjects {
 	stor := createStorageObjects(t, true)
-	assets := newAssets(stor.db, stor.dbBatch, stor.hs)
+	assets := newAssets(stor.db, stor.dbBatch, stor.hs, proto.TestNetScheme)
 	return &assetsTestObjects{stor, assets}
 }
 
@@ -228,3 +229,47 @@ func TestAssetConstInfoRoundTrip(t *testing.T) {
 
 	assert.Equal(t, expectedInfo, info)
 }
+
+func TestAssetsIssuedBy(t *testing.T) {
+	stor := createStorageObjects(t, true)
+	issuer := testGlobal.issuerInfo.addr
+	recipient := testGlobal.recipientInfo.addr
+	asset0 := proto.AssetIDFromDigest(testGlobal.asset0.assetID)
+	asset1 := proto.AssetIDFromDigest(testGlobal.asset1.assetID)
+
+	ids, err := stor.entities.assets.assetsIssuedBy(issuer)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	// Assets are ordered by issue height and sequence in block rather than by ID.
+	info0 := defaultAssetInfo(proto.DigestTail(testGlobal.asset0.assetID), true)
+	info0.IssueHeight, info0.IssueSequenceInBlock = 1, 1
+	stor.createAssetUsingInfo(t, testGlobal.asset0.assetID, info0)
+	info1 := defaultAssetInfo(proto.DigestTail(testGlobal.asset1.assetID), true)
+	info1.IssueHeight, info1.IssueSequenceInBlock = 2, 0
+	stor.addBlock(t, blockID1)
+	err = stor.entities.assets.issueAsset(asset1, info1, blockID1)
+	require.NoError(t, err)
+	// Reissue, sponsorship and transfer of the whole balance to other address don't affect the list.
+	err = stor.entities.assets.reissueAsset(asset0, &assetReissueChange{false, 1}, blockID1)
+	require.NoError(t, err)
+	err = stor.entities.sponsoredAssets.sponsorAsset(testGlobal.asset1.assetID, 10, blockID1)
+	require.NoError(t, err)
+	err = stor.entities.balances.setAssetBalance(issuer.ID(), asset0, 0, blockID1)
+	require.NoError(t, err)
+	err = stor.entities.balances.setAssetBalance(recipient.ID(), asset0, 1000, blockID1)
+	require.NoError(t, err)
+	stor.flush(t)
+
+	ids, err = stor.entities.assets.assetsIssuedBy(issuer)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.AssetID{asset0, asset1}, ids)
+	ids, err = stor.entities.assets.assetsIssuedBy(recipient)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	stor.rollbackBlock(t, blockID1)
+	ids, err = stor.entities.assets.assetsIssuedBy(issuer)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.AssetID{asset0}, ids)
+}
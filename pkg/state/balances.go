@@ -555,6 +555,47 @@ func collectNFTs(
 	return res, nil
 }
 
+// balancesStateSize returns the total size of stored keys and records of Waves and non-zero asset balances
+// of the address.
+func (s *balances) balancesStateSize(addr proto.AddressID) (int64, error) {
+	var size int64
+	wavesKey := wavesBalanceKey{address: addr}
+	wavesKeyBytes := wavesKey.bytes()
+	recordBytes, err := s.hs.topEntryData(wavesKeyBytes)
+	switch {
+	case isNotFoundInHistoryOrDBErr(err):
+		// unknown address, no Waves balance stored
+	case err != nil:
+		return 0, err
+	default:
+		size += int64(len(wavesKeyBytes) + len(recordBytes))
+	}
+
+	key := assetBalanceKey{address: addr}
+	iter, err := s.hs.newTopEntryIteratorByPrefix(key.addressPrefix())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			zap.S().Fatalf("Iterator error: %v", err)
+		}
+	}()
+	var r assetBalanceRecord
+	for iter.Next() {
+		assetRecordBytes := keyvalue.SafeValue(iter)
+		if err := r.unmarshalBinary(assetRecordBytes); err != nil {
+			return 0, err
+		}
+		if r.balance == 0 {
+			continue
+		}
+		size += int64(len(keyvalue.SafeKey(iter)) + len(assetRecordBytes))
+	}
+	return size, nil
+}
+
 func (s *balances) wavesAddressesNumber() (uint64, error) {
 	iter, err := s.hs.newTopEntryIterator(wavesBalance)
 	if err != nil {
@@ -581,6 +622,113 @@ func (s *balances) wavesAddressesNumber() (uint64, error) {
 	return addressesNumber, nil
 }
 
+// balancesAtHeight calls fn for every address with non-zero balance of the asset (or Waves if assetID is nil)
+// at the given height. Balances are streamed from the storage, so memory consumption doesn't depend on the
+// number of addresses. Only the records committed to DB are read, so it's safe to call it concurrently with
+// the block application.
+func (s *balances) balancesAtHeight(
+	assetID *proto.AssetID, height proto.Height, fn func(addr proto.WavesAddress, balance uint64) error,
+) error {
+	blockNum, err := s.hs.stateDB.blockNumByHeight(height)
+	if err != nil {
+		return err
+	}
+	if assetID != nil {
+		return s.assetBalancesAtBlockNum(*assetID, blockNum, fn)
+	}
+	return s.wavesBalancesAtBlockNum(blockNum, fn)
+}
+
+func (s *balances) wavesBalancesAtBlockNum(
+	blockNum uint32, fn func(addr proto.WavesAddress, balance uint64) error,
+) (err error) {
+	iter, err := s.db.NewKeyIterator([]byte{wavesBalanceKeyPrefix})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		iter.Release()
+		if iErr := iter.Error(); iErr != nil && err == nil {
+			err = iErr
+		}
+	}()
+	for iter.Next() {
+		var k wavesBalanceKey
+		if uErr := k.unmarshal(iter.Key()); uErr != nil {
+			return uErr
+		}
+		recordBytes, rErr := s.hs.recordDataAtBlockNum(iter.Value(), blockNum)
+		if rErr != nil {
+			return rErr
+		}
+		if len(recordBytes) == 0 {
+			continue // no balance at the height yet
+		}
+		var r wavesBalanceRecord
+		if uErr := r.unmarshalBinary(recordBytes); uErr != nil {
+			return uErr
+		}
+		if cErr := s.callWithBalance(k.address, r.balance, fn); cErr != nil {
+			return cErr
+		}
+	}
+	return nil
+}
+
+// assetBalancesAtBlockNum walks all asset balance keys, the balances of other assets are skipped by the key
+// without decoding the history.
+func (s *balances) assetBalancesAtBlockNum(
+	assetID proto.AssetID, blockNum uint32, fn func(addr proto.WavesAddress, balance uint64) error,
+) (err error) {
+	iter, err := s.db.NewKeyIterator([]byte{assetBalanceKeyPrefix})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		iter.Release()
+		if iErr := iter.Error(); iErr != nil && err == nil {
+			err = iErr
+		}
+	}()
+	for iter.Next() {
+		var k assetBalanceKey
+		if uErr := k.unmarshal(iter.Key()); uErr != nil {
+			return uErr
+		}
+		if k.asset != assetID {
+			continue
+		}
+		recordBytes, rErr := s.hs.recordDataAtBlockNum(iter.Value(), blockNum)
+		if rErr != nil {
+			return rErr
+		}
+		if len(recordBytes) == 0 {
+			continue // no balance at the height yet
+		}
+		var r assetBalanceRecord
+		if uErr := r.unmarshalBinary(recordBytes); uErr != nil {
+			return uErr
+		}
+		if cErr := s.callWithBalance(k.address, r.balance, fn); cErr != nil {
+			return cErr
+		}
+	}
+	return nil
+}
+
+func (s *balances) callWithBalance(
+	addrID proto.AddressID, balance uint64, fn func(addr proto.WavesAddress, balance uint64) error,
+) error {
+	if balance == 0 {
+		return nil
+	}
+	addr, err := addrID.ToWavesAddress(s.sets.AddressSchemeCharacter)
+	if err != nil {
+		return err
+	}
+	return fn(addr, balance)
+}
+
 func minEffectiveBalanceInRangeCommon(records [][]byte) (uint64, error) {
 	minBalance := uint64(math.MaxUint64)
 	for _, recordBytes := range records {
@@ -798,6 +946,19 @@ func (s *balances) assetBalance(addr proto.AddressID, assetID proto.AssetID) (ui
 	return s.assetBalanceFromRecordBytes(recordBytes)
 }
 
+// assetBalanceAtHeight returns the asset balance of the address at the given height.
+func (s *balances) assetBalanceAtHeight(addr proto.AddressID, assetID proto.AssetID, height proto.Height) (uint64, error) {
+	key := assetBalanceKey{address: addr, asset: assetID}
+	recordBytes, err := s.hs.retainedEntryDataAtHeight(key.bytes(), height)
+	if isNotFoundInHistoryOrDBErr(err) || (err == nil && recordBytes == nil) {
+		// Unknown address or no balance at the height yet, zero is returned.
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return s.assetBalanceFromRecordBytes(recordBytes)
+}
+
 func (s *balances) newestAssetBalance(addr proto.AddressID, asset proto.AssetID) (uint64, error) {
 	key := assetBalanceKey{address: addr, asset: asset}
 	recordBytes, err := s.hs.newestTopEntryData(key.bytes())
@@ -861,6 +1022,23 @@ func (s *balances) wavesBalance(addr proto.AddressID) (balanceProfile, error) {
 	return r.balanceProfile, nil
 }
 
+// wavesBalanceAtHeight returns the regular waves balance of the address at the given height.
+func (s *balances) wavesBalanceAtHeight(addr proto.AddressID, height proto.Height) (uint64, error) {
+	key := wavesBalanceKey{address: addr}
+	recordBytes, err := s.hs.retainedEntryDataAtHeight(key.bytes(), height)
+	if isNotFoundInHistoryOrDBErr(err) || (err == nil && recordBytes == nil) {
+		// Unknown address or no balance at the height yet, zero is returned.
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var record wavesBalanceRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return 0, errors.Wrapf(err, "failed to unmarshal data to %T", record)
+	}
+	return record.balance, nil
+}
+
 func (s *balances) calculateStateHashesAssetBalance(addr proto.AddressID, assetID proto.AssetID,
 	balance uint64, blockID proto.BlockID, keyStr string) error {
 	info, err := s.assets.newestConstInfo(assetID)
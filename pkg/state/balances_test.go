@@ -490,3 +490,63 @@ func TestNftList(t *testing.T) {
 	assert.Equal(t, []crypto.Digest(nil), nfts)
 
 }
+
+func TestBalancesAtHeight(t *testing.T) {
+	to := createBalances(t)
+
+	addrs := make([]proto.WavesAddress, 3)
+	for i, s := range []string{addr0, addr1, addr2} {
+		a, err := proto.NewAddressFromString(s)
+		require.NoError(t, err)
+		addrs[i] = a
+	}
+	assetA := proto.AssetIDFromDigest(genAsset(1))
+	assetB := proto.AssetIDFromDigest(genAsset(2))
+	addTailInfoToAssetsState(to.stor.entities.assets, genAsset(1))
+	addTailInfoToAssetsState(to.stor.entities.assets, genAsset(2))
+	setWaves := func(addr proto.WavesAddress, balance uint64, blockID proto.BlockID) {
+		v := wavesValue{profile: balanceProfile{balance: balance}, balanceChange: true}
+		require.NoError(t, to.balances.setWavesBalance(addr.ID(), v, blockID))
+	}
+	setAsset := func(addr proto.WavesAddress, asset proto.AssetID, balance uint64, blockID proto.BlockID) {
+		require.NoError(t, to.balances.setAssetBalance(addr.ID(), asset, balance, blockID))
+	}
+
+	to.stor.addBlock(t, blockID0)
+	setWaves(addrs[0], 100, blockID0)
+	setWaves(addrs[1], 200, blockID0)
+	setAsset(addrs[0], assetA, 5, blockID0)
+	to.stor.flush(t)
+
+	to.stor.addBlock(t, blockID1)
+	setWaves(addrs[1], 0, blockID1)
+	setWaves(addrs[2], 300, blockID1)
+	setAsset(addrs[1], assetA, 7, blockID1)
+	setAsset(addrs[2], assetB, 9, blockID1)
+	to.stor.flush(t)
+
+	collect := func(asset *proto.AssetID, height proto.Height) map[proto.WavesAddress]uint64 {
+		res := make(map[proto.WavesAddress]uint64)
+		err := to.balances.balancesAtHeight(asset, height, func(addr proto.WavesAddress, balance uint64) error {
+			res[addr] = balance
+			return nil
+		})
+		require.NoError(t, err)
+		return res
+	}
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[0]: 100, addrs[1]: 200}, collect(nil, 1))
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[0]: 100, addrs[2]: 300}, collect(nil, 2))
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[0]: 5}, collect(&assetA, 1))
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[0]: 5, addrs[1]: 7}, collect(&assetA, 2))
+	assert.Empty(t, collect(&assetB, 1))
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[2]: 9}, collect(&assetB, 2))
+
+	to.stor.addBlock(t, blockID2)
+	setAsset(addrs[0], assetA, 0, blockID2)
+	setAsset(addrs[1], assetA, 8, blockID2)
+	to.stor.flush(t)
+
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[1]: 8}, collect(&assetA, 3))
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[0]: 5, addrs[1]: 7}, collect(&assetA, 2))
+	assert.Equal(t, map[proto.WavesAddress]uint64{addrs[2]: 9}, collect(&assetB, 3))
+}
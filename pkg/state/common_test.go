@@ -362,7 +362,7 @@ type testStorageObjectsOptions struct {
 	CalculateHashes bool
 }
 
-func createStorageObjectsWithOptions(t *testing.T, options testStorageObjectsOptions) *testStorageObjects {
+func createStorageObjectsWithOptions(t testing.TB, options testStorageObjectsOptions) *testStorageObjects {
 	if options.Settings == nil {
 		options.Settings = settings.MustMainNetSettings()
 	}
@@ -1,5 +1,7 @@
 package state
 
+import "time"
+
 const (
 	// Default values.
 	// Cache parameters.
@@ -16,6 +18,8 @@ const (
 	DefaultWriteBuffer         = 32 * 1024 * 1024
 	DefaultCompactionTableSize = 8 * 1024 * 1024
 	DefaultCompactionTotalSize = 10 * 1024 * 1024
+	// DefaultDBOpenRetryTimeout is the time during which opening of locked DB is retried.
+	DefaultDBOpenRetryTimeout = 5 * time.Second
 
 	// Block storage parameters.
 	// DefaultOffsetLen is the amount of bytes needed to store offset of transactions in blockchain file.
@@ -25,7 +29,7 @@ const (
 
 	// StateVersion is current version of state internal storage formats.
 	// It increases when backward compatibility with previous storage version is lost.
-	StateVersion = 26
+	StateVersion = 27
 
 	// Memory limit for address transactions. flush() is called when this
 	// limit is exceeded.
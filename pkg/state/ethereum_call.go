@@ -0,0 +1,132 @@
+package state
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/proto/ethabi"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+// maxEthereumCallComplexity limits the complexity of read-only calls. The calls are served by API for free,
+// so the limit is the complexity of a single callable of RIDE V5 instead of the whole chain of invocations.
+const maxEthereumCallComplexity = MaxCallableScriptComplexityV5
+
+// EthereumCall evaluates the callable function of the dApp on the address 'to' against the newest state without
+// a transaction. The call data is decoded using the ABI built from the dApp meta, the changes made by the callable
+// are discarded and the value returned by the callable is encoded into Ethereum ABI.
+func (s *stateManager) EthereumCall(from, to proto.EthereumAddress, data []byte) ([]byte, error) {
+	scheme := s.settings.AddressSchemeCharacter
+	caller, err := from.ToWavesAddress(scheme)
+	if err != nil {
+		return nil, wrapErr(InvalidInputError, err)
+	}
+	dApp, err := to.ToWavesAddress(scheme)
+	if err != nil {
+		return nil, wrapErr(InvalidInputError, err)
+	}
+	tree, err := s.stor.scriptsStorage.newestScriptByAddr(dApp)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil, wrapErr(NotFoundError, errors.Errorf("account '%s' has no script", dApp.String()))
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	if !tree.IsDApp() {
+		return nil, wrapErr(NotFoundError, errors.Errorf("account '%s' is not a dApp", dApp.String()))
+	}
+	mm, err := ethabi.NewMethodsMapFromRideDAppMeta(tree.Meta)
+	if err != nil {
+		return nil, wrapErr(Other, err)
+	}
+	decodedData, err := mm.ParseCallDataRide(data, true)
+	if err != nil {
+		return nil, wrapErr(InvalidInputError, errors.Wrap(err, "failed to parse call data"))
+	}
+	if len(decodedData.Payments) > 0 {
+		return nil, wrapErr(InvalidInputError, errors.New("payments are not allowed in read-only call"))
+	}
+	arguments, err := proto.ConvertDecodedEthereumArgumentsToProtoArguments(decodedData.Inputs)
+	if err != nil {
+		return nil, wrapErr(InvalidInputError, err)
+	}
+	env, err := s.newEthereumCallEnvironment(caller, dApp, tree)
+	if err != nil {
+		return nil, wrapErr(Other, err)
+	}
+	// Read-only calls are served concurrently, so they share the limit of concurrent evaluations with validation.
+	r, err := s.appender.sc.callFunction(env, tree, proto.NewFunctionCall(decodedData.Name, arguments))
+	if err != nil {
+		return nil, wrapErr(Other, errors.Wrapf(err, "failed to call function '%s'", decodedData.Name))
+	}
+	res, err := ride.EncodeResultToEthereumABI(r)
+	if err != nil {
+		return nil, wrapErr(Other, err)
+	}
+	return res, nil
+}
+
+func (s *stateManager) newEthereumCallEnvironment(
+	caller, dApp proto.WavesAddress, tree *ast.Tree,
+) (*ride.EvaluationEnvironment, error) {
+	features := []settings.Feature{
+		settings.BlockV5, settings.RideV5, settings.RideV6,
+		settings.ConsensusImprovements, settings.BlockRewardDistribution, settings.LightNode,
+	}
+	activated := make(map[settings.Feature]bool, len(features))
+	for _, f := range features {
+		ok, err := s.stor.features.newestIsActivated(int16(f))
+		if err != nil {
+			return nil, err
+		}
+		activated[f] = ok
+	}
+	env, err := ride.NewEnvironment(
+		s.settings.AddressSchemeCharacter,
+		s,
+		s.settings.InternalInvokePaymentsValidationAfterHeight,
+		s.settings.PaymentsFixAfterHeight,
+		activated[settings.BlockV5],
+		activated[settings.RideV6],
+		activated[settings.ConsensusImprovements],
+		activated[settings.BlockRewardDistribution],
+		activated[settings.LightNode],
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create RIDE environment")
+	}
+	height, err := s.NewestHeight()
+	if err != nil {
+		return nil, err
+	}
+	blockInfo, err := s.NewestBlockInfoByHeight(height)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last block info")
+	}
+	env.SetThisFromAddress(dApp)
+	env.ChooseSizeCheck(tree.LibVersion)
+	if err := env.SetLastBlockFromBlockInfo(blockInfo); err != nil {
+		return nil, errors.Wrap(err, "failed to create RIDE environment")
+	}
+	env.SetTimestamp(blockInfo.Timestamp)
+	env.ChooseTakeString(activated[settings.RideV5])
+	env.ChooseMaxDataEntriesSize(activated[settings.RideV5])
+	limit, err := ride.MaxChainInvokeComplexityByVersion(tree.LibVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set limit for call")
+	}
+	env.SetLimit(min(limit, maxEthereumCallComplexity))
+	env.SetEthereumCall(tree.LibVersion, caller)
+	// The wrapped state accumulates the changes made by the callable, so they never reach the storage.
+	if tree.LibVersion >= ast.LibV5 {
+		const checkSenderBalance = false // no payments are attached
+		env, err = ride.NewEnvironmentWithWrappedState(env, s, nil, caller, true, tree.LibVersion, checkSenderBalance)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create RIDE environment with wrapped state")
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,97 @@
+package state
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/proto/ethabi"
+	"github.com/wavesplatform/gowaves/pkg/ride"
+	ridec "github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func TestEthereumCall(t *testing.T) {
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), settings.MustMainNetSettings())
+	script, errs := ridec.Compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func double(n: Int) = ([IntegerEntry("n", n)], n * 2)
+
+@Callable(i)
+func heavy() = {
+  func f(acc: ByteVector, x: Int) = keccak256(acc)
+  let r = FOLD<60>([`+strings.TrimSuffix(strings.Repeat("0,", 60), ",")+`], base58'', f)
+  ([], r.size())
+}`, false, true)
+	require.Empty(t, errs)
+	tree, err := serialization.Parse(script)
+	require.NoError(t, err)
+	sig, err := ethabi.NewSignatureFromRideFunctionMeta(tree.Meta.Functions[0], true)
+	require.NoError(t, err)
+
+	dApp := proto.EthereumAddress{1, 2, 3}
+	dAppAddr, err := dApp.ToWavesAddress(manager.settings.AddressSchemeCharacter)
+	require.NoError(t, err)
+	err = manager.stor.scriptsStorage.setAccountScript(dAppAddr, script, testGlobal.recipientInfo.pk,
+		manager.TopBlock().BlockID())
+	require.NoError(t, err)
+
+	selector := sig.Selector()
+	callData := bytes.Join([][]byte{
+		selector[:],
+		ethabi.Int(21).EncodeToABI(),
+		ethabi.Int(2 * 32).EncodeToABI(), // offset of the payments array
+		ethabi.Int(0).EncodeToABI(),      // empty payments array
+	}, nil)
+	res, err := manager.EthereumCall(proto.EthereumAddress{}, dApp, callData)
+	require.NoError(t, err)
+	assert.Equal(t, ethabi.Int(42).EncodeToABI(), res)
+	// The data entry written by the callable is discarded.
+	_, err = manager.RetrieveNewestEntry(proto.NewRecipientFromAddress(dAppAddr), "n")
+	assert.True(t, IsNotFound(err))
+
+	// The call waits for a free slot if the limit of concurrent evaluations is reached.
+	manager.appender.sc.evaluations = ride.NewEvaluationSemaphore(1)
+	manager.appender.sc.evaluations.Acquire()
+	done := make(chan error, 1)
+	go func() {
+		_, cErr := manager.EthereumCall(proto.EthereumAddress{}, dApp, callData)
+		done <- cErr
+	}()
+	select {
+	case <-done:
+		require.Fail(t, "call is evaluated beyond the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+	manager.appender.sc.evaluations.Release()
+	require.NoError(t, <-done)
+
+	// Unknown selector.
+	_, err = manager.EthereumCall(proto.EthereumAddress{}, dApp, []byte{0xde, 0xad, 0xbe, 0xef})
+	assert.True(t, IsInvalidInput(err))
+	// Account without script.
+	_, err = manager.EthereumCall(proto.EthereumAddress{}, proto.EthereumAddress{4, 5, 6}, callData)
+	assert.True(t, IsNotFound(err))
+
+	// The complexity of 60 keccak256 calls exceeds the limit of read-only call, but not the limit of invocation.
+	sig, err = ethabi.NewSignatureFromRideFunctionMeta(tree.Meta.Functions[1], true)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(sig.String(), "heavy("))
+	selector = sig.Selector()
+	callData = bytes.Join([][]byte{
+		selector[:],
+		ethabi.Int(32).EncodeToABI(), // offset of the payments array
+		ethabi.Int(0).EncodeToABI(),  // empty payments array
+	}, nil)
+	_, err = manager.EthereumCall(proto.EthereumAddress{}, dApp, callData)
+	assert.ErrorContains(t, err, "complexity")
+}
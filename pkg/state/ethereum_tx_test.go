@@ -53,7 +53,7 @@ func defaultTxAppender(t *testing.T, storage scriptStorageState, state types.Enr
 	stor := createStorageObjectsWithOptions(t, testStorageObjectsOptions{
 		Settings: sett,
 	})
-	newAssets := newAssets(stor.db, stor.dbBatch, stor.hs)
+	newAssets := newAssets(stor.db, stor.dbBatch, stor.hs, scheme)
 	if assetsUncertain == nil {
 		assetsUncertain = make(map[proto.AssetID]wrappedUncertainInfo)
 	}
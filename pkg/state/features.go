@@ -84,6 +84,9 @@ type features struct {
 	settings            *settings.BlockchainSettings
 	definedFeaturesInfo map[settings.Feature]settings.FeatureInfo
 	activationCache     map[settings.Feature]featureActivationState
+	// activationOverrides are the activation heights of features set bypassing the voting.
+	// Overrides take precedence over stored activations, they are kept in memory and intended only for tests.
+	activationOverrides map[settings.Feature]uint64
 	mu                  sync.Mutex
 }
 
@@ -96,9 +99,35 @@ func newFeatures(rw *blockReadWriter, db keyvalue.IterableKeyVal, hs *historySto
 		settings:            stg,
 		definedFeaturesInfo: definedFeaturesInfo,
 		activationCache:     make(map[settings.Feature]featureActivationState),
+		activationOverrides: make(map[settings.Feature]uint64),
 	}
 }
 
+// overrideActivationHeight sets the feature activated at the given height regardless of the voting.
+func (f *features) overrideActivationHeight(featureID int16, height uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.activationOverrides[settings.Feature(featureID)] = height
+	f.activationCache = make(map[settings.Feature]featureActivationState)
+}
+
+// overriddenActivation returns the activation state of the feature at the given height if the activation height of
+// the feature is overridden. The mutex must be held by the caller.
+func (f *features) overriddenActivation(featureID int16, height uint64) (featureActivationState, bool) {
+	activationHeight, ok := f.activationOverrides[settings.Feature(featureID)]
+	if !ok {
+		return featureActivationState{}, false
+	}
+	return featureActivationState{activated: height >= activationHeight, height: activationHeight}, true
+}
+
+func (f *features) overriddenActivationHeight(featureID int16) (uint64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	height, ok := f.activationOverrides[settings.Feature(featureID)]
+	return height, ok
+}
+
 // addVote adds vote for feature by its featureID at given blockID.
 func (f *features) addVote(featureID int16, blockID proto.BlockID) error {
 	key := votesFeaturesKey{featureID: featureID}
@@ -224,6 +253,9 @@ func (f *features) newestIsActivatedForNBlocks(featureID int16, n int) (bool, er
 func (f *features) newestIsActivated(featureID int16) (bool, error) {
 	defer f.mu.Unlock()
 	f.mu.Lock()
+	if as, ok := f.overriddenActivation(featureID, f.rw.addingBlockHeight()); ok {
+		return as.activated, nil
+	}
 	if as, ok := f.activationCache[settings.Feature(featureID)]; ok {
 		return as.activated, nil
 	}
@@ -242,6 +274,9 @@ func (f *features) newestIsActivated(featureID int16) (bool, error) {
 func (f *features) isActivated(featureID int16) (bool, error) {
 	defer f.mu.Unlock()
 	f.mu.Lock()
+	if as, ok := f.overriddenActivation(featureID, f.rw.recentHeight()); ok {
+		return as.activated, nil
+	}
 	if as, ok := f.activationCache[settings.Feature(featureID)]; ok {
 		return as.activated, nil
 	}
@@ -258,6 +293,9 @@ func (f *features) isActivated(featureID int16) (bool, error) {
 }
 
 func (f *features) newestIsActivatedAtHeight(featureID int16, height uint64) bool {
+	if activationHeight, ok := f.overriddenActivationHeight(featureID); ok {
+		return height >= activationHeight
+	}
 	activationHeight, err := f.newestActivationHeight(featureID)
 	if err == nil {
 		return height >= activationHeight
@@ -270,6 +308,9 @@ func (f *features) newestIsActivatedAtHeight(featureID int16, height uint64) boo
 }
 
 func (f *features) isActivatedAtHeight(featureID int16, height uint64) bool {
+	if activationHeight, ok := f.overriddenActivationHeight(featureID); ok {
+		return height >= activationHeight
+	}
 	activationHeight, err := f.activationHeight(featureID)
 	if err == nil {
 		return height >= activationHeight
@@ -318,6 +359,12 @@ func (f *features) activatedFeaturesRecord(featureID int16) (*activatedFeaturesR
 func (f *features) newestActivationHeight(featureID int16) (uint64, error) {
 	defer f.mu.Unlock()
 	f.mu.Lock()
+	if as, ok := f.overriddenActivation(featureID, f.rw.addingBlockHeight()); ok {
+		if as.activated {
+			return as.height, nil
+		}
+		return 0, keyvalue.ErrNotFound
+	}
 	if as, ok := f.activationCache[settings.Feature(featureID)]; ok {
 		if as.activated {
 			return as.height, nil
@@ -335,6 +382,12 @@ func (f *features) newestActivationHeight(featureID int16) (uint64, error) {
 func (f *features) activationHeight(featureID int16) (uint64, error) {
 	defer f.mu.Unlock()
 	f.mu.Lock()
+	if as, ok := f.overriddenActivation(featureID, f.rw.recentHeight()); ok {
+		if as.activated {
+			return as.height, nil
+		}
+		return 0, keyvalue.ErrNotFound
+	}
 	if as, ok := f.activationCache[settings.Feature(featureID)]; ok {
 		if as.activated {
 			return as.height, nil
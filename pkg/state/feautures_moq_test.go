@@ -78,6 +78,9 @@ var _ featuresState = &mockFeaturesState{}
 //			newestIsApprovedFunc: func(featureID int16) (bool, error) {
 //				panic("mock out the newestIsApproved method")
 //			},
+//			overrideActivationHeightFunc: func(featureID int16, height uint64) {
+//				panic("mock out the overrideActivationHeight method")
+//			},
 //			resetVotesFunc: func(blockID proto.BlockID) error {
 //				panic("mock out the resetVotes method")
 //			},
@@ -148,6 +151,9 @@ type mockFeaturesState struct {
 	// newestIsApprovedFunc mocks the newestIsApproved method.
 	newestIsApprovedFunc func(featureID int16) (bool, error)
 
+	// overrideActivationHeightFunc mocks the overrideActivationHeight method.
+	overrideActivationHeightFunc func(featureID int16, height uint64)
+
 	// resetVotesFunc mocks the resetVotes method.
 	resetVotesFunc func(blockID proto.BlockID) error
 
@@ -271,6 +277,13 @@ type mockFeaturesState struct {
 			// FeatureID is the featureID argument value.
 			FeatureID int16
 		}
+		// overrideActivationHeight holds details about calls to the overrideActivationHeight method.
+		overrideActivationHeight []struct {
+			// FeatureID is the featureID argument value.
+			FeatureID int16
+			// Height is the height argument value.
+			Height uint64
+		}
 		// resetVotes holds details about calls to the resetVotes method.
 		resetVotes []struct {
 			// BlockID is the blockID argument value.
@@ -297,6 +310,7 @@ type mockFeaturesState struct {
 	locknewestIsActivatedAtHeight   sync.RWMutex
 	locknewestIsActivatedForNBlocks sync.RWMutex
 	locknewestIsApproved            sync.RWMutex
+	lockoverrideActivationHeight    sync.RWMutex
 	lockresetVotes                  sync.RWMutex
 }
 
@@ -974,6 +988,42 @@ func (mock *mockFeaturesState) newestIsApprovedCalls() []struct {
 	return calls
 }
 
+// overrideActivationHeight calls overrideActivationHeightFunc.
+func (mock *mockFeaturesState) overrideActivationHeight(featureID int16, height uint64) {
+	if mock.overrideActivationHeightFunc == nil {
+		panic("mockFeaturesState.overrideActivationHeightFunc: method is nil but featuresState.overrideActivationHeight was just called")
+	}
+	callInfo := struct {
+		FeatureID int16
+		Height    uint64
+	}{
+		FeatureID: featureID,
+		Height:    height,
+	}
+	mock.lockoverrideActivationHeight.Lock()
+	mock.calls.overrideActivationHeight = append(mock.calls.overrideActivationHeight, callInfo)
+	mock.lockoverrideActivationHeight.Unlock()
+	mock.overrideActivationHeightFunc(featureID, height)
+}
+
+// overrideActivationHeightCalls gets all the calls that were made to overrideActivationHeight.
+// Check the length with:
+//
+//	len(mockedfeaturesState.overrideActivationHeightCalls())
+func (mock *mockFeaturesState) overrideActivationHeightCalls() []struct {
+	FeatureID int16
+	Height    uint64
+} {
+	var calls []struct {
+		FeatureID int16
+		Height    uint64
+	}
+	mock.lockoverrideActivationHeight.RLock()
+	calls = mock.calls.overrideActivationHeight
+	mock.lockoverrideActivationHeight.RUnlock()
+	return calls
+}
+
 // resetVotes calls resetVotesFunc.
 func (mock *mockFeaturesState) resetVotes(blockID proto.BlockID) error {
 	if mock.resetVotesFunc == nil {
@@ -196,9 +196,9 @@ func minFeeInUnits(params *feeValidationParams, tx proto.Transaction) (uint64, e
 		}
 		switch kind := ethTx.TxKind.(type) {
 		case *proto.EthereumTransferWavesTxKind, *proto.EthereumTransferAssetsErc20TxKind:
-			fee = feeConstants[proto.TransferTransaction]
+			fee = minEthereumFeeInUnits(false)
 		case *proto.EthereumInvokeScriptTxKind:
-			fee = feeConstants[proto.InvokeScriptTransaction]
+			fee = minEthereumFeeInUnits(true)
 		default:
 			return 0, errors.Errorf("unknown ethereum tx kind (%T)", kind)
 		}
@@ -209,6 +209,21 @@ func minFeeInUnits(params *feeValidationParams, tx proto.Transaction) (uint64, e
 	return fee, nil
 }
 
+// minEthereumFeeInUnits returns minimal fee in units of Ethereum transfer or dApp invocation.
+func minEthereumFeeInUnits(isInvoke bool) uint64 {
+	if isInvoke {
+		return feeConstants[proto.InvokeScriptTransaction]
+	}
+	return feeConstants[proto.TransferTransaction]
+}
+
+// MinEthereumGas returns the minimal gas limit of Ethereum transaction for transfer or dApp invocation.
+// Gas limit of Ethereum transaction is its fee in wavelets, so the value is the minimal Waves fee for the
+// operation. Extra fees for smart accounts and smart assets are not included.
+func MinEthereumGas(isInvoke bool) uint64 {
+	return minEthereumFeeInUnits(isInvoke) * FeeUnit
+}
+
 type txCosts struct {
 	smartAssets      uint64
 	smartAssetsFee   uint64
@@ -9,6 +9,7 @@ import (
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/proto/ethabi"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 )
 
@@ -341,3 +342,24 @@ func TestSetScriptTransactionDynamicFee(t *testing.T) {
 	err = checkMinFeeWaves(tx, params)
 	assert.NoError(t, err, "checkMinFeeWaves() failed with valid SetScriptTx fee")
 }
+
+func TestMinEthereumGas(t *testing.T) {
+	assert.Equal(t, uint64(FeeUnit), MinEthereumGas(false))
+	assert.Equal(t, uint64(5*FeeUnit), MinEthereumGas(true))
+
+	params := &feeValidationParams{settings: settings.MustMainNetSettings()}
+	for _, test := range []struct {
+		kind     proto.EthereumTransactionKind
+		isInvoke bool
+	}{
+		{proto.NewEthereumTransferWavesTxKind(), false},
+		{proto.NewEthereumTransferAssetsErc20TxKind(ethabi.DecodedCallData{}, proto.NewOptionalAssetWaves(),
+			ethabi.ERC20TransferArguments{}), false},
+		{proto.NewEthereumInvokeScriptTxKind(ethabi.DecodedCallData{}), true},
+	} {
+		tx := proto.NewEthereumTransaction(&proto.EthereumLegacyTx{}, test.kind, nil, nil, 0)
+		units, err := minFeeInUnits(params, &tx)
+		require.NoError(t, err)
+		assert.Equal(t, units*FeeUnit, MinEthereumGas(test.isInvoke), test.kind.String())
+	}
+}
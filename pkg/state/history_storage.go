@@ -13,6 +13,10 @@ import (
 
 var errEmptyHist = errors.New("empty history for this record")
 
+// errHeightNotRetained is returned for the reads at heights below the rollback window,
+// the history of records is cut there and can't be restored.
+var errHeightNotRetained = errors.New("history at the height is not retained")
+
 type blockchainEntity byte
 
 const (
@@ -45,6 +49,7 @@ const (
 	snapshots
 	patches
 	challengedAddress
+	issuerAssets
 )
 
 type blockchainEntityProperties struct {
@@ -212,6 +217,11 @@ var properties = map[blockchainEntity]blockchainEntityProperties{
 		needToCut:    true,
 		fixedSize:    false,
 	},
+	issuerAssets: {
+		needToFilter: true,
+		needToCut:    true,
+		fixedSize:    false,
+	},
 }
 
 type historyEntry struct {
@@ -727,6 +737,49 @@ func (hs *historyStorage) entryDataAtHeight(key []byte, height uint64) ([]byte,
 	return hs.entryDataWithHeightFilter(key, height, cmp)
 }
 
+// recordDataAtBlockNum() decodes the history record read directly from DB and returns the data of its last
+// entry added not later than the block number. Nil is returned if there is no such entry.
+// Unlike entryDataAtHeight() it doesn't touch DB to get the record, so it can be used with values of iterators.
+func (hs *historyStorage) recordDataAtBlockNum(historyBytes []byte, limitBlockNum uint32) ([]byte, error) {
+	history, err := newHistoryRecordFromBytes(historyBytes)
+	if err != nil {
+		return nil, errs.Extend(err, "newHistoryRecordFromBytes")
+	}
+	if _, err := hs.fmt.normalize(history, hs.amend); err != nil {
+		return nil, err
+	}
+	var res historyEntry
+	for _, entry := range history.entries {
+		if entry.blockNum > limitBlockNum {
+			break
+		}
+		res = entry
+	}
+	return res.data, nil
+}
+
+// checkHeightRetained() returns errHeightNotRetained if the height is below the rollback window.
+func (hs *historyStorage) checkHeightRetained(height uint64) error {
+	minHeight, err := hs.stateDB.getRollbackMinHeight()
+	if err != nil {
+		return err
+	}
+	if height < minHeight {
+		return errors.Wrapf(errHeightNotRetained, "height %d is below the rollback window starting at %d",
+			height, minHeight)
+	}
+	return nil
+}
+
+// retainedEntryDataAtHeight() is entryDataAtHeight() that fails with errHeightNotRetained
+// if the height is below the rollback window.
+func (hs *historyStorage) retainedEntryDataAtHeight(key []byte, height uint64) ([]byte, error) {
+	if err := hs.checkHeightRetained(height); err != nil {
+		return nil, err
+	}
+	return hs.entryDataAtHeight(key, height)
+}
+
 // blockRangeEntries() returns list of entries corresponding to given block interval.
 // IMPORTANTLY, it does not simply return list of entries with block nums between startBlockNum and endBlockNum,
 // instead this function returns values which are relevant for this block range.
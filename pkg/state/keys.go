@@ -30,6 +30,7 @@ const (
 	snapshotKeySize          = 1 + 8
 	rewardVotesKeySize       = 1 + 8
 	challengedAddressKeySize = 1 + proto.AddressIDSize
+	issuedAssetKeySize       = 1 + proto.AddressIDSize + 8 + 4 + proto.AssetIDSize
 )
 
 // Primary prefixes for storage keys
@@ -135,6 +136,8 @@ const (
 	patchKeyPrefix
 
 	challengedAddressKeyPrefix
+	// Issuer address, issue height, issue sequence in block and asset ID --> presence flag.
+	issuerAssetsKeyPrefix
 )
 
 var (
@@ -200,6 +203,8 @@ func prefixByEntity(entity blockchainEntity) ([]byte, error) {
 		return []byte{patchKeyPrefix}, nil
 	case challengedAddress:
 		return []byte{challengedAddressKeyPrefix}, nil
+	case issuerAssets:
+		return []byte{issuerAssetsKeyPrefix}, nil
 	default:
 		return nil, errors.New("bad entity type")
 	}
@@ -589,6 +594,17 @@ func (k *accountScriptKey) bytes() []byte {
 	return buf
 }
 
+func (k *accountScriptKey) unmarshal(data []byte) error {
+	if len(data) != 1+proto.AddressIDSize {
+		return errInvalidDataSize
+	}
+	if data[0] != accountScriptKeyPrefix {
+		return errInvalidPrefix
+	}
+	copy(k.addr[:], data[1:])
+	return nil
+}
+
 type assetScriptKey struct {
 	assetID proto.AssetID
 }
@@ -602,6 +618,17 @@ func (k *assetScriptKey) bytes() []byte {
 	return buf
 }
 
+func (k *assetScriptKey) unmarshal(data []byte) error {
+	if len(data) != 1+proto.AssetIDSize {
+		return errInvalidDataSize
+	}
+	if data[0] != assetScriptKeyPrefix {
+		return errInvalidPrefix
+	}
+	copy(k.assetID[:], data[1:])
+	return nil
+}
+
 type scriptBasicInfoKey struct {
 	scriptKey scriptKey
 }
@@ -761,3 +788,43 @@ func (k *challengedAddressKey) bytes() []byte {
 	copy(buf[1:], k.address[:])
 	return buf
 }
+
+type issuedAssetKey struct {
+	issuer        proto.AddressID
+	issueHeight   proto.Height
+	issueSequence uint32
+	asset         proto.AssetID
+}
+
+func (k *issuedAssetKey) issuerPrefix() []byte {
+	buf := make([]byte, 1+proto.AddressIDSize)
+	buf[0] = issuerAssetsKeyPrefix
+	copy(buf[1:], k.issuer[:])
+	return buf
+}
+
+func (k *issuedAssetKey) bytes() []byte {
+	buf := make([]byte, issuedAssetKeySize)
+	buf[0] = issuerAssetsKeyPrefix
+	copy(buf[1:], k.issuer[:])
+	binary.BigEndian.PutUint64(buf[1+proto.AddressIDSize:], k.issueHeight)
+	binary.BigEndian.PutUint32(buf[1+proto.AddressIDSize+8:], k.issueSequence)
+	copy(buf[1+proto.AddressIDSize+8+4:], k.asset[:])
+	return buf
+}
+
+func (k *issuedAssetKey) unmarshal(data []byte) error {
+	if len(data) != issuedAssetKeySize {
+		return errInvalidDataSize
+	}
+	if data[0] != issuerAssetsKeyPrefix {
+		return errInvalidPrefix
+	}
+	data = data[1:]
+	copy(k.issuer[:], data[:proto.AddressIDSize])
+	data = data[proto.AddressIDSize:]
+	k.issueHeight = binary.BigEndian.Uint64(data[:8])
+	k.issueSequence = binary.BigEndian.Uint32(data[8:12])
+	copy(k.asset[:], data[12:])
+	return nil
+}
@@ -218,6 +218,53 @@ func (l *leases) validLeaseIns() (map[proto.WavesAddress]int64, error) {
 	return leaseIns, nil
 }
 
+// isActiveAtHeight reports whether the lease affects the effective balances at the given height.
+// Lease is active starting from the height of its origin and until the height of its cancellation.
+func (l *leasing) isActiveAtHeight(height proto.Height) bool {
+	if l.OriginHeight > height {
+		return false
+	}
+	return l.isActive() || l.CancelHeight > height
+}
+
+// leaseTotalsAtHeight returns the sums of leases from and to the address which are active at the given height.
+func (l *leases) leaseTotalsAtHeight(
+	scheme proto.Scheme, addr proto.WavesAddress, height proto.Height,
+) (uint64, uint64, error) {
+	leaseIter, err := l.hs.newTopEntryIterator(lease)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to create key iterator to sum leases")
+	}
+	defer func() {
+		leaseIter.Release()
+		if err := leaseIter.Error(); err != nil {
+			zap.S().Fatalf("Iterator error: %v", err)
+		}
+	}()
+
+	var out, in uint64
+	for leaseIter.Next() {
+		record := new(leasing)
+		if err := record.unmarshalBinary(keyvalue.SafeValue(leaseIter)); err != nil {
+			return 0, 0, errors.Wrap(err, "failed to unmarshal lease")
+		}
+		if !record.isActiveAtHeight(height) {
+			continue
+		}
+		if record.RecipientAddr == addr {
+			in += record.Amount
+		}
+		sender, err := proto.NewAddressFromPublicKey(scheme, record.SenderPK)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to build address from PK %q", record.SenderPK)
+		}
+		if sender == addr {
+			out += record.Amount
+		}
+	}
+	return out, in, nil
+}
+
 // Leasing info from DB or local storage.
 func (l *leases) newestLeasingInfo(id crypto.Digest) (*leasing, error) {
 	if leasing, ok := l.uncertainLeases[id]; ok {
@@ -181,3 +181,63 @@ func TestCancelLeasing(t *testing.T) {
 	assert.NoError(t, err, "failed to get leasing info")
 	assert.Equal(t, resLeasing, r, "invalid leasing record after cancellation")
 }
+
+func TestLeaseTotalsAtHeight(t *testing.T) {
+	to := createLeases(t)
+	to.stor.addBlock(t, blockID0)
+
+	scheme := to.stor.settings.AddressSchemeCharacter
+	senderPK := crypto.MustPublicKeyFromBase58("81w5qdM6iZL7xTh5QZrZdX2Y3Z5G8KugRT8F189fpxFD")
+	otherPK := crypto.MustPublicKeyFromBase58("2cZf5zywhy3JtzhvALBUXM4JTgvWV8DqRbwVGQWV3KLk")
+	addr, err := proto.NewAddressFromPublicKey(scheme, senderPK)
+	assert.NoError(t, err)
+	recipient := createLease(t, senderPK, crypto.Digest{}).RecipientAddr
+
+	leasings := []struct {
+		senderPK     crypto.PublicKey
+		recipient    proto.WavesAddress
+		amount       uint64
+		originHeight uint64
+		cancelHeight uint64
+	}{
+		{senderPK, recipient, 10, 1, 0},
+		{senderPK, recipient, 20, 1, 3},
+		{otherPK, addr, 30, 2, 0},
+		{otherPK, addr, 40, 1, 2},
+		{senderPK, recipient, 50, 5, 0},
+	}
+	for i, l := range leasings {
+		leaseID, err := crypto.NewDigestFromBytes(bytes.Repeat([]byte{byte(i + 1)}, crypto.DigestSize))
+		assert.NoError(t, err, "failed to create digest from bytes")
+		r := createLease(t, l.senderPK, leaseID)
+		r.RecipientAddr = l.recipient
+		r.Amount = l.amount
+		r.OriginHeight = l.originHeight
+		if l.cancelHeight != 0 {
+			r.Status = LeaseCancelled
+			r.CancelHeight = l.cancelHeight
+		}
+		err = to.leases.addLeasing(leaseID, r, blockID0)
+		assert.NoError(t, err, "failed to add leasing")
+	}
+	to.stor.flush(t)
+
+	for _, test := range []struct {
+		addr   proto.WavesAddress
+		height proto.Height
+		out    uint64
+		in     uint64
+	}{
+		{addr, 1, 30, 40},
+		{addr, 2, 30, 30},
+		{addr, 3, 10, 30},
+		{addr, 5, 60, 30},
+		{recipient, 1, 0, 30},
+		{recipient, 5, 0, 60},
+	} {
+		out, in, err := to.leases.leaseTotalsAtHeight(scheme, test.addr, test.height)
+		assert.NoError(t, err)
+		assert.Equal(t, test.out, out, "leased out by %s at height %d", test.addr, test.height)
+		assert.Equal(t, test.in, in, "leased to %s at height %d", test.addr, test.height)
+	}
+}
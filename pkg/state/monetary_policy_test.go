@@ -163,6 +163,9 @@ func TestFinishRewardVoting(t *testing.T) {
 		assert.Equal(t, step.increase, votes.increase, "increase: "+msg)
 		assert.Equal(t, step.decrease, votes.decrease, "decrease: "+msg)
 		storage.flush(t)
+		committedVotes, err := mo.votes(h, blockRewardActivationHeight, step.isCappedRewardsActivated)
+		require.NoError(t, err, msg)
+		assert.Equal(t, votes, committedVotes, "committed votes: "+msg)
 		reward, err := mo.reward()
 		require.NoError(t, err, msg)
 		assert.Equal(t, step.reward, reward, fmt.Sprintf("unexpected reward %d: %s", reward, msg))
@@ -18,6 +18,8 @@ type scriptCaller struct {
 
 	stor     *blockchainEntitiesStorage
 	settings *settings.BlockchainSettings
+	// evaluations limits the number of concurrently evaluated scripts, nil means no limit.
+	evaluations *ride.EvaluationSemaphore
 
 	totalComplexity    uint64
 	recentTxComplexity uint64
@@ -27,14 +29,32 @@ func newScriptCaller(
 	state types.EnrichedSmartState,
 	stor *blockchainEntitiesStorage,
 	settings *settings.BlockchainSettings,
+	maxConcurrentEvaluations int,
 ) (*scriptCaller, error) {
 	return &scriptCaller{
-		state:    state,
-		stor:     stor,
-		settings: settings,
+		state:       state,
+		stor:        stor,
+		settings:    settings,
+		evaluations: ride.NewEvaluationSemaphore(maxConcurrentEvaluations),
 	}, nil
 }
 
+// callVerifier evaluates the verifier waiting for a free evaluation slot if the limit of concurrent
+// evaluations is reached.
+func (a *scriptCaller) callVerifier(env *ride.EvaluationEnvironment, tree *ast.Tree) (ride.Result, error) {
+	a.evaluations.Acquire()
+	defer a.evaluations.Release()
+	return ride.CallVerifier(env, tree)
+}
+
+// callFunction calls the callable function waiting for a free evaluation slot if the limit of concurrent
+// evaluations is reached.
+func (a *scriptCaller) callFunction(env *ride.EvaluationEnvironment, tree *ast.Tree, fc proto.FunctionCall) (ride.Result, error) {
+	a.evaluations.Acquire()
+	defer a.evaluations.Release()
+	return ride.CallFunction(env, tree, fc)
+}
+
 // callAccountScriptWithOrder calls account script. This method must not be called for proto.EthereumAddress.
 func (a *scriptCaller) callAccountScriptWithOrder(order proto.Order, lastBlockInfo *proto.BlockInfo, info *fallibleValidationParams) error {
 	senderAddr, err := order.GetSender(a.settings.AddressSchemeCharacter)
@@ -78,7 +98,7 @@ func (a *scriptCaller) callAccountScriptWithOrder(order proto.Order, lastBlockIn
 	if err = env.SetTransactionFromOrder(order, tree.LibVersion); err != nil {
 		return errors.Wrap(err, "failed to convert order")
 	}
-	r, err := ride.CallVerifier(env, tree)
+	r, err := a.callVerifier(env, tree)
 	if err != nil {
 		return errors.Errorf("account script on order '%s' thrown error with message: %s", base58.Encode(id), err.Error())
 	}
@@ -142,7 +162,7 @@ func (a *scriptCaller) callAccountScriptWithTx(tx proto.Transaction, params *app
 	if err := env.SetTransaction(tx); err != nil {
 		return errors.Wrapf(err, "failed to call account script on transaction '%s'", base58.Encode(id))
 	}
-	r, err := ride.CallVerifier(env, tree)
+	r, err := a.callVerifier(env, tree)
 	if err != nil {
 		return errors.Errorf("account script on transaction '%s' failed with error: %v", base58.Encode(id), err.Error())
 	}
@@ -195,7 +215,7 @@ func (a *scriptCaller) callAssetScriptCommon(env *ride.EvaluationEnvironment, se
 	if err := env.SetLastBlockFromBlockInfo(params.blockInfo); err != nil {
 		return nil, err
 	}
-	r, err := ride.CallVerifier(env, tree)
+	r, err := a.callVerifier(env, tree)
 	if err != nil {
 		return nil, errs.NewTransactionNotAllowedByScript(err.Error(), assetID.Bytes())
 	}
@@ -370,7 +390,7 @@ func (a *scriptCaller) invokeFunctionByInvokeWithProofsTx(
 
 	functionCall := tx.FunctionCall
 
-	r, err := ride.CallFunction(env, tree, functionCall)
+	r, err := a.callFunction(env, tree, functionCall)
 	if err != nil {
 		complexity := ride.EvaluationErrorSpentComplexity(err)
 		appendErr := a.appendFunctionComplexity(complexity, scriptAddress, scriptEstimationUpdate, functionCall, info)
@@ -427,7 +447,7 @@ func (a *scriptCaller) invokeFunctionByEthereumTx(
 	}
 	functionCall := proto.NewFunctionCall(decodedData.Name, arguments)
 
-	r, err := ride.CallFunction(env, tree, functionCall)
+	r, err := a.callFunction(env, tree, functionCall)
 	if err != nil {
 		complexity := ride.EvaluationErrorSpentComplexity(err)
 		appendErr := a.appendFunctionComplexity(complexity, scriptAddress, scriptEstimationUpdate, functionCall, info)
@@ -465,7 +485,7 @@ func (a *scriptCaller) invokeFunctionByInvokeExpressionWithProofsTx(
 		}
 	}
 
-	r, err := ride.CallVerifier(env, tree)
+	r, err := a.callVerifier(env, tree)
 	if err != nil {
 		complexity := ride.EvaluationErrorSpentComplexity(err)
 		appendErr := a.appendFunctionComplexity(complexity, scriptAddress, scriptEstimationUpdate, functionCall, info)
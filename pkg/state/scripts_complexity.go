@@ -96,6 +96,30 @@ func (sc *scriptsComplexity) scriptComplexityByAddress(addr proto.Address) (*rid
 	return &r.Estimation, nil
 }
 
+// scriptEstimatorVersionByAddr returns the version of estimator which was used to estimate the complexity of
+// the account's script. If filter is true only the records of already stored blocks are considered, otherwise
+// the newest record is used.
+func (sc *scriptsComplexity) scriptEstimatorVersionByAddr(addr proto.WavesAddress, filter bool) (int, error) {
+	key := accountScriptComplexityKey{addr.ID()}
+	var (
+		recordBytes []byte
+		err         error
+	)
+	if filter {
+		recordBytes, err = sc.hs.topEntryData(key.bytes())
+	} else {
+		recordBytes, err = sc.hs.newestTopEntryData(key.bytes())
+	}
+	if err != nil {
+		return 0, err
+	}
+	r := new(estimationRecord)
+	if err = r.unmarshalBinary(recordBytes); err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal account script complexities record")
+	}
+	return int(r.EstimatorVersion), nil
+}
+
 func (sc *scriptsComplexity) saveComplexitiesForAddr(
 	addr proto.Address,
 	se scriptEstimation,
@@ -201,3 +225,59 @@ func (sc *scriptsComplexity) saveComplexitiesForAsset(
 	}
 	return nil
 }
+
+// complexityKeyByScriptKey returns the entity and the key of the complexity record of the script.
+func complexityKeyByScriptKey(key scriptKey) (blockchainEntity, []byte, error) {
+	switch k := key.(type) {
+	case *accountScriptKey:
+		ck := accountScriptComplexityKey{addressID: k.addr}
+		return accountScriptComplexity, ck.bytes(), nil
+	case *assetScriptKey:
+		ck := assetScriptComplexityKey{asset: k.assetID}
+		return assetScriptComplexity, ck.bytes(), nil
+	default:
+		return 0, nil, errors.Errorf("unexpected script key type %T", key)
+	}
+}
+
+// estimatorVersionByScriptKey returns the version of estimator used to estimate the stored script.
+func (sc *scriptsComplexity) estimatorVersionByScriptKey(key scriptKey) (int, error) {
+	_, complexityKey, err := complexityKeyByScriptKey(key)
+	if err != nil {
+		return 0, err
+	}
+	recordBytes, err := sc.hs.newestTopEntryData(complexityKey)
+	if err != nil {
+		return 0, err
+	}
+	r := new(estimationRecord)
+	if err = r.unmarshalBinary(recordBytes); err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal script complexities record")
+	}
+	return int(r.EstimatorVersion), nil
+}
+
+// reestimate estimates the script with the given estimator version and adds the new complexities record of it.
+// The record is added at the given block like any other change, so it's reverted by the rollback of the block.
+func (sc *scriptsComplexity) reestimate(
+	key scriptKey, script proto.Script, estimatorVersion int, blockID proto.BlockID,
+) error {
+	entity, complexityKey, err := complexityKeyByScriptKey(key)
+	if err != nil {
+		return err
+	}
+	tree, err := scriptBytesToTree(script)
+	if err != nil {
+		return err
+	}
+	estimation, err := ride.EstimateTree(tree, estimatorVersion)
+	if err != nil {
+		return err
+	}
+	r := estimationRecord{EstimatorVersion: uint8(estimatorVersion), Estimation: estimation}
+	recordBytes, err := r.marshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal script complexities record")
+	}
+	return sc.hs.addNewEntry(entity, complexityKey, recordBytes, blockID)
+}
@@ -135,3 +135,47 @@ func TestSaveComplexityForAsset(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, est.estimation, *res1)
 }
+
+func TestScriptEstimatorVersionByAddr(t *testing.T) {
+	to := createScriptsComplexityStorageObjects(t)
+
+	to.stor.addBlock(t, blockID0)
+	addr := testGlobal.senderInfo.addr
+	se := scriptEstimation{
+		currentEstimatorVersion: 3,
+		estimation:              ride.TreeEstimation{Estimation: 100, Verifier: 100},
+	}
+	err := to.scriptsComplexity.saveComplexitiesForAddr(addr, se, blockID0)
+	require.NoError(t, err)
+	v, err := to.scriptsComplexity.scriptEstimatorVersionByAddr(addr, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+	// The record is not stored yet.
+	_, err = to.scriptsComplexity.scriptEstimatorVersionByAddr(addr, true)
+	assert.Error(t, err)
+
+	to.stor.flush(t)
+
+	for _, filter := range []bool{false, true} {
+		v, err = to.scriptsComplexity.scriptEstimatorVersionByAddr(addr, filter)
+		require.NoError(t, err)
+		assert.Equal(t, 3, v)
+	}
+
+	to.stor.addBlock(t, blockID1)
+	se.currentEstimatorVersion = maxEstimatorVersion
+	err = to.scriptsComplexity.saveComplexitiesForAddr(addr, se, blockID1)
+	require.NoError(t, err)
+	v, err = to.scriptsComplexity.scriptEstimatorVersionByAddr(addr, false)
+	require.NoError(t, err)
+	assert.Equal(t, maxEstimatorVersion, v)
+	v, err = to.scriptsComplexity.scriptEstimatorVersionByAddr(addr, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	to.stor.flush(t)
+
+	v, err = to.scriptsComplexity.scriptEstimatorVersionByAddr(addr, true)
+	require.NoError(t, err)
+	assert.Equal(t, maxEstimatorVersion, v)
+}
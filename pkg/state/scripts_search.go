@@ -0,0 +1,93 @@
+package state
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/ast"
+)
+
+// StoredScript is the script stored in the state, see StateInfo.IterateStoredScripts. Either Address or AssetID
+// is set depending on whether it's the script of an account or an asset.
+type StoredScript struct {
+	Address *proto.WavesAddress
+	AssetID *proto.AssetID
+	Script  proto.Script
+}
+
+// FindScripts returns the addresses of accounts with scripts satisfying the predicate, asset scripts are not checked.
+// It's a package function rather than a method of StateInfo, because the scripts are streamed with
+// StateInfo.IterateStoredScripts and the state is not locked while the scripts are decoded and checked.
+// The call is expensive and could be cancelled with the context. Scripts that can't be parsed are skipped.
+func FindScripts(ctx context.Context, s StateInfo, predicate func(tree *ast.Tree) bool) ([]proto.WavesAddress, error) {
+	var addrs []proto.WavesAddress
+	err := searchScripts(ctx, s, predicate, func(sc StoredScript) {
+		if sc.Address != nil {
+			addrs = append(addrs, *sc.Address)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// searchScripts calls found for every stored script satisfying the predicate.
+// Scripts that can't be parsed are logged and skipped, so a single broken script doesn't abort the search.
+func searchScripts(
+	ctx context.Context, s StateInfo, predicate func(tree *ast.Tree) bool, found func(sc StoredScript),
+) error {
+	return s.IterateStoredScripts(ctx, func(sc StoredScript) error {
+		tree, err := scriptBytesToTree(sc.Script)
+		if err != nil {
+			zap.S().Warnf("Skipping stored script of %s: %v", storedScriptOwner(sc), err)
+			return nil
+		}
+		if predicate(tree) {
+			found(sc)
+		}
+		return nil
+	})
+}
+
+func storedScriptOwner(sc StoredScript) string {
+	switch {
+	case sc.Address != nil:
+		return "account " + sc.Address.String()
+	case sc.AssetID != nil:
+		return "asset " + sc.AssetID.String()
+	default:
+		return "unknown owner"
+	}
+}
+
+// ScriptsUsingBuiltin returns the addresses of accounts and the IDs of assets with scripts calling the built-in
+// function, see ast.UsedBuiltins for the naming of functions. Names of value extracting functions, like
+// "@extrNative(1050)", are normalized to the names of wrapped functions. Scripts that can't be parsed are skipped.
+func ScriptsUsingBuiltin(
+	ctx context.Context, s StateInfo, name string,
+) ([]proto.WavesAddress, []proto.AssetID, error) {
+	name = ast.NormalizeBuiltinName(name)
+	var (
+		addrs  []proto.WavesAddress
+		assets []proto.AssetID
+	)
+	usesBuiltin := func(tree *ast.Tree) bool {
+		return slices.Contains(ast.UsedBuiltins(tree), name)
+	}
+	err := searchScripts(ctx, s, usesBuiltin, func(sc StoredScript) {
+		switch {
+		case sc.Address != nil:
+			addrs = append(addrs, *sc.Address)
+		case sc.AssetID != nil:
+			assets = append(assets, *sc.AssetID)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return addrs, assets, nil
+}
@@ -2,12 +2,16 @@ package state
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"slices"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
@@ -286,6 +290,24 @@ func (ss *scriptsStorage) dropUncertain() {
 	ss.uncertainAssetScripts = make(map[proto.AssetID]assetScriptRecordWithAssetIDTail)
 }
 
+// hasUncertain reports whether the uncertain buffer contains any scripts. Note that commitUncertain doesn't clear
+// the buffer, it must be cleared with dropUncertain after committing.
+func (ss *scriptsStorage) hasUncertain() bool {
+	return len(ss.uncertainAssetScripts) != 0
+}
+
+// uncertainKeys returns the keys of scripts in the uncertain buffer sorted by their binary representation.
+func (ss *scriptsStorage) uncertainKeys() []scriptKey {
+	keys := make([]scriptKey, 0, len(ss.uncertainAssetScripts))
+	for assetID := range ss.uncertainAssetScripts {
+		keys = append(keys, &assetScriptKey{assetID: assetID})
+	}
+	slices.SortFunc(keys, func(a, b scriptKey) int {
+		return bytes.Compare(a.bytes(), b.bytes())
+	})
+	return keys
+}
+
 func (ss *scriptsStorage) setAssetScriptUncertain(fullAssetID crypto.Digest, script proto.Script, pk crypto.PublicKey) error {
 	// NOTE: we use fullAssetID (crypto.Digest) only for state hashes compatibility
 	var (
@@ -513,6 +535,20 @@ func (ss *scriptsStorage) newestAccountHasScript(addr proto.WavesAddress) (bool,
 	return info.scriptExists(), nil
 }
 
+// newestAccountHasCallables reports whether the account has a dApp script with at least one callable function.
+// The basic info is checked first, so the script tree is taken from the cache or decoded only for dApps.
+func (ss *scriptsStorage) newestAccountHasCallables(addr proto.WavesAddress) (bool, error) {
+	isDApp, err := ss.newestAccountIsDApp(addr)
+	if err != nil || !isDApp {
+		return false, err
+	}
+	tree, err := ss.newestScriptByAddr(addr)
+	if err != nil {
+		return false, err
+	}
+	return len(tree.Functions) > 0, nil
+}
+
 func (ss *scriptsStorage) accountHasScript(addr proto.WavesAddress) (bool, error) {
 	key := scriptBasicInfoKey{scriptKey: &accountScriptKey{addr.ID()}}
 	recordBytes, err := ss.hs.topEntryData(key.bytes())
@@ -586,6 +622,111 @@ func (ss *scriptsStorage) scriptBytesByAddr(addr proto.WavesAddress) (proto.Scri
 	return ss.scriptBytesByKey(key.bytes())
 }
 
+// scriptBytesByAddrAtHeight returns the script of the account as it was at the given height.
+// The keyvalue.ErrNotFound is returned if the account had no script set at that height.
+func (ss *scriptsStorage) scriptBytesByAddrAtHeight(addr proto.WavesAddress, height proto.Height) (proto.Script, error) {
+	key := accountScriptKey{addr: addr.ID()}
+	script, err := ss.hs.retainedEntryDataAtHeight(key.bytes(), height)
+	if err != nil {
+		if errors.Is(err, errEmptyHist) {
+			return proto.Script{}, keyvalue.ErrNotFound
+		}
+		return proto.Script{}, err
+	}
+	if script == nil { // script was set after the given height
+		return proto.Script{}, keyvalue.ErrNotFound
+	}
+	return script, nil
+}
+
+// accountScriptStateSize returns the size of the stored key and script of the account, zero if there is no script.
+func (ss *scriptsStorage) accountScriptStateSize(addr proto.WavesAddress) (int64, error) {
+	key := accountScriptKey{addr: addr.ID()}
+	keyBytes := key.bytes()
+	script, err := ss.scriptBytesByKey(keyBytes)
+	if err != nil {
+		if isNotFoundInHistoryOrDBErr(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if script.IsEmpty() {
+		return 0, nil // script was removed
+	}
+	return int64(len(keyBytes) + len(script)), nil
+}
+
+// scriptsKeys returns the keys of all stored account and asset scripts, accounts scripts go first.
+// Scripts are not decoded, removed scripts are skipped.
+func (ss *scriptsStorage) scriptsKeys(ctx context.Context) ([]scriptKey, error) {
+	var keys []scriptKey
+	err := ss.iterateScriptsBytes(ctx, func(key scriptKey, _ proto.Script) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// iterateScriptsBytes calls fn for every stored account and asset script, accounts scripts go first. The key passed
+// to fn is either *accountScriptKey or *assetScriptKey. Scripts are not decoded, removed scripts are skipped.
+// The script bytes are valid only during the call of fn. Iteration stops on the first error returned by fn or
+// when the context is done.
+func (ss *scriptsStorage) iterateScriptsBytes(
+	ctx context.Context, fn func(key scriptKey, script proto.Script) error,
+) error {
+	for _, entity := range []blockchainEntity{accountScript, assetScript} {
+		if err := ss.iterateScriptsBytesOfEntity(ctx, entity, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *scriptsStorage) iterateScriptsBytesOfEntity(
+	ctx context.Context, entity blockchainEntity, fn func(key scriptKey, script proto.Script) error,
+) error {
+	iter, err := ss.hs.newTopEntryIterator(entity)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			zap.S().Fatalf("Iterator error: %v", err)
+		}
+	}()
+	for iter.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		script := proto.Script(iter.Value())
+		if script.IsEmpty() {
+			continue // script was removed
+		}
+		var key scriptKey
+		if entity == accountScript {
+			k := new(accountScriptKey)
+			if err := k.unmarshal(iter.Key()); err != nil {
+				return err
+			}
+			key = k
+		} else {
+			k := new(assetScriptKey)
+			if err := k.unmarshal(iter.Key()); err != nil {
+				return err
+			}
+			key = k
+		}
+		if err := fn(key, script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (ss *scriptsStorage) clearCache() error {
 	var err error
 	ss.cache, err = newLru(maxCacheSize, maxCacheBytes)
@@ -1,6 +1,8 @@
 package state
 
 import (
+	"context"
+
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
@@ -10,6 +12,8 @@ import (
 type scriptStorageState interface {
 	commitUncertain(blockID proto.BlockID) error
 	dropUncertain()
+	hasUncertain() bool
+	uncertainKeys() []scriptKey
 	uncertainAssetScriptsCopy() map[proto.AssetID]assetScriptRecordWithAssetIDTail
 	setAssetScriptUncertain(fullAssetID crypto.Digest, script proto.Script, pk crypto.PublicKey) error
 	setAssetScript(assetID crypto.Digest, script proto.Script, blockID proto.BlockID) error
@@ -26,12 +30,17 @@ type scriptStorageState interface {
 	newestAccountHasVerifier(addr proto.WavesAddress) (bool, error)
 	accountHasVerifier(addr proto.WavesAddress) (bool, error)
 	newestAccountHasScript(addr proto.WavesAddress) (bool, error)
+	newestAccountHasCallables(addr proto.WavesAddress) (bool, error)
 	accountHasScript(addr proto.WavesAddress) (bool, error)
 	newestScriptByAddr(addr proto.WavesAddress) (*ast.Tree, error)
 	newestScriptBasicInfoByAddressID(addressID proto.AddressID) (scriptBasicInfoRecord, error)
 	scriptBasicInfoByAddressID(addressID proto.AddressID) (scriptBasicInfoRecord, error)
 	scriptByAddr(addr proto.WavesAddress) (*ast.Tree, error)
 	scriptBytesByAddr(addr proto.WavesAddress) (proto.Script, error)
+	scriptBytesByAddrAtHeight(addr proto.WavesAddress, height proto.Height) (proto.Script, error)
+	accountScriptStateSize(addr proto.WavesAddress) (int64, error)
+	iterateScriptsBytes(ctx context.Context, fn func(key scriptKey, script proto.Script) error) error
+	scriptsKeys(ctx context.Context) ([]scriptKey, error)
 	clearCache() error
 	prepareHashes() error
 	reset()
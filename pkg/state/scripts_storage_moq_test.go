@@ -4,6 +4,7 @@
 package state
 
 import (
+	"context"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
@@ -29,6 +30,9 @@ var _ scriptStorageState = &mockScriptStorageState{}
 //			accountIsDAppFunc: func(addr proto.WavesAddress) (bool, error) {
 //				panic("mock out the accountIsDApp method")
 //			},
+//			accountScriptStateSizeFunc: func(addr proto.WavesAddress) (int64, error) {
+//				panic("mock out the accountScriptStateSize method")
+//			},
 //			clearCacheFunc: func() error {
 //				panic("mock out the clearCache method")
 //			},
@@ -44,9 +48,18 @@ var _ scriptStorageState = &mockScriptStorageState{}
 //			getAssetScriptsHasherFunc: func() *stateHasher {
 //				panic("mock out the getAssetScriptsHasher method")
 //			},
+//			hasUncertainFunc: func() bool {
+//				panic("mock out the hasUncertain method")
+//			},
 //			isSmartAssetFunc: func(assetID proto.AssetID) (bool, error) {
 //				panic("mock out the isSmartAsset method")
 //			},
+//			iterateScriptsBytesFunc: func(ctx context.Context, fn func(key scriptKey, script proto.Script) error) error {
+//				panic("mock out the iterateScriptsBytes method")
+//			},
+//			newestAccountHasCallablesFunc: func(addr proto.WavesAddress) (bool, error) {
+//				panic("mock out the newestAccountHasCallables method")
+//			},
 //			newestAccountHasScriptFunc: func(addr proto.WavesAddress) (bool, error) {
 //				panic("mock out the newestAccountHasScript method")
 //			},
@@ -92,9 +105,15 @@ var _ scriptStorageState = &mockScriptStorageState{}
 //			scriptBytesByAddrFunc: func(addr proto.WavesAddress) (proto.Script, error) {
 //				panic("mock out the scriptBytesByAddr method")
 //			},
+//			scriptBytesByAddrAtHeightFunc: func(addr proto.WavesAddress, height proto.Height) (proto.Script, error) {
+//				panic("mock out the scriptBytesByAddrAtHeight method")
+//			},
 //			scriptBytesByAssetFunc: func(assetID proto.AssetID) (proto.Script, error) {
 //				panic("mock out the scriptBytesByAsset method")
 //			},
+//			scriptsKeysFunc: func(ctx context.Context) ([]scriptKey, error) {
+//				panic("mock out the scriptsKeys method")
+//			},
 //			setAccountScriptFunc: func(addr proto.WavesAddress, script proto.Script, pk crypto.PublicKey, blockID proto.BlockID) error {
 //				panic("mock out the setAccountScript method")
 //			},
@@ -107,6 +126,9 @@ var _ scriptStorageState = &mockScriptStorageState{}
 //			uncertainAssetScriptsCopyFunc: func() map[proto.AssetID]assetScriptRecordWithAssetIDTail {
 //				panic("mock out the uncertainAssetScriptsCopy method")
 //			},
+//			uncertainKeysFunc: func() []scriptKey {
+//				panic("mock out the uncertainKeys method")
+//			},
 //		}
 //
 //		// use mockedscriptStorageState in code that requires scriptStorageState
@@ -123,6 +145,9 @@ type mockScriptStorageState struct {
 	// accountIsDAppFunc mocks the accountIsDApp method.
 	accountIsDAppFunc func(addr proto.WavesAddress) (bool, error)
 
+	// accountScriptStateSizeFunc mocks the accountScriptStateSize method.
+	accountScriptStateSizeFunc func(addr proto.WavesAddress) (int64, error)
+
 	// clearCacheFunc mocks the clearCache method.
 	clearCacheFunc func() error
 
@@ -138,9 +163,18 @@ type mockScriptStorageState struct {
 	// getAssetScriptsHasherFunc mocks the getAssetScriptsHasher method.
 	getAssetScriptsHasherFunc func() *stateHasher
 
+	// hasUncertainFunc mocks the hasUncertain method.
+	hasUncertainFunc func() bool
+
 	// isSmartAssetFunc mocks the isSmartAsset method.
 	isSmartAssetFunc func(assetID proto.AssetID) (bool, error)
 
+	// iterateScriptsBytesFunc mocks the iterateScriptsBytes method.
+	iterateScriptsBytesFunc func(ctx context.Context, fn func(key scriptKey, script proto.Script) error) error
+
+	// newestAccountHasCallablesFunc mocks the newestAccountHasCallables method.
+	newestAccountHasCallablesFunc func(addr proto.WavesAddress) (bool, error)
+
 	// newestAccountHasScriptFunc mocks the newestAccountHasScript method.
 	newestAccountHasScriptFunc func(addr proto.WavesAddress) (bool, error)
 
@@ -186,9 +220,15 @@ type mockScriptStorageState struct {
 	// scriptBytesByAddrFunc mocks the scriptBytesByAddr method.
 	scriptBytesByAddrFunc func(addr proto.WavesAddress) (proto.Script, error)
 
+	// scriptBytesByAddrAtHeightFunc mocks the scriptBytesByAddrAtHeight method.
+	scriptBytesByAddrAtHeightFunc func(addr proto.WavesAddress, height proto.Height) (proto.Script, error)
+
 	// scriptBytesByAssetFunc mocks the scriptBytesByAsset method.
 	scriptBytesByAssetFunc func(assetID proto.AssetID) (proto.Script, error)
 
+	// scriptsKeysFunc mocks the scriptsKeys method.
+	scriptsKeysFunc func(ctx context.Context) ([]scriptKey, error)
+
 	// setAccountScriptFunc mocks the setAccountScript method.
 	setAccountScriptFunc func(addr proto.WavesAddress, script proto.Script, pk crypto.PublicKey, blockID proto.BlockID) error
 
@@ -201,6 +241,9 @@ type mockScriptStorageState struct {
 	// uncertainAssetScriptsCopyFunc mocks the uncertainAssetScriptsCopy method.
 	uncertainAssetScriptsCopyFunc func() map[proto.AssetID]assetScriptRecordWithAssetIDTail
 
+	// uncertainKeysFunc mocks the uncertainKeys method.
+	uncertainKeysFunc func() []scriptKey
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// accountHasScript holds details about calls to the accountHasScript method.
@@ -218,6 +261,11 @@ type mockScriptStorageState struct {
 			// Addr is the addr argument value.
 			Addr proto.WavesAddress
 		}
+		// accountScriptStateSize holds details about calls to the accountScriptStateSize method.
+		accountScriptStateSize []struct {
+			// Addr is the addr argument value.
+			Addr proto.WavesAddress
+		}
 		// clearCache holds details about calls to the clearCache method.
 		clearCache []struct {
 		}
@@ -235,11 +283,26 @@ type mockScriptStorageState struct {
 		// getAssetScriptsHasher holds details about calls to the getAssetScriptsHasher method.
 		getAssetScriptsHasher []struct {
 		}
+		// hasUncertain holds details about calls to the hasUncertain method.
+		hasUncertain []struct {
+		}
 		// isSmartAsset holds details about calls to the isSmartAsset method.
 		isSmartAsset []struct {
 			// AssetID is the assetID argument value.
 			AssetID proto.AssetID
 		}
+		// iterateScriptsBytes holds details about calls to the iterateScriptsBytes method.
+		iterateScriptsBytes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn func(key scriptKey, script proto.Script) error
+		}
+		// newestAccountHasCallables holds details about calls to the newestAccountHasCallables method.
+		newestAccountHasCallables []struct {
+			// Addr is the addr argument value.
+			Addr proto.WavesAddress
+		}
 		// newestAccountHasScript holds details about calls to the newestAccountHasScript method.
 		newestAccountHasScript []struct {
 			// Addr is the addr argument value.
@@ -311,11 +374,23 @@ type mockScriptStorageState struct {
 			// Addr is the addr argument value.
 			Addr proto.WavesAddress
 		}
+		// scriptBytesByAddrAtHeight holds details about calls to the scriptBytesByAddrAtHeight method.
+		scriptBytesByAddrAtHeight []struct {
+			// Addr is the addr argument value.
+			Addr proto.WavesAddress
+			// Height is the height argument value.
+			Height proto.Height
+		}
 		// scriptBytesByAsset holds details about calls to the scriptBytesByAsset method.
 		scriptBytesByAsset []struct {
 			// AssetID is the assetID argument value.
 			AssetID proto.AssetID
 		}
+		// scriptsKeys holds details about calls to the scriptsKeys method.
+		scriptsKeys []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// setAccountScript holds details about calls to the setAccountScript method.
 		setAccountScript []struct {
 			// Addr is the addr argument value.
@@ -348,16 +423,23 @@ type mockScriptStorageState struct {
 		// uncertainAssetScriptsCopy holds details about calls to the uncertainAssetScriptsCopy method.
 		uncertainAssetScriptsCopy []struct {
 		}
+		// uncertainKeys holds details about calls to the uncertainKeys method.
+		uncertainKeys []struct {
+		}
 	}
 	lockaccountHasScript                 sync.RWMutex
 	lockaccountHasVerifier               sync.RWMutex
 	lockaccountIsDApp                    sync.RWMutex
+	lockaccountScriptStateSize           sync.RWMutex
 	lockclearCache                       sync.RWMutex
 	lockcommitUncertain                  sync.RWMutex
 	lockdropUncertain                    sync.RWMutex
 	lockgetAccountScriptsHasher          sync.RWMutex
 	lockgetAssetScriptsHasher            sync.RWMutex
+	lockhasUncertain                     sync.RWMutex
 	lockisSmartAsset                     sync.RWMutex
+	lockiterateScriptsBytes              sync.RWMutex
+	locknewestAccountHasCallables        sync.RWMutex
 	locknewestAccountHasScript           sync.RWMutex
 	locknewestAccountHasVerifier         sync.RWMutex
 	locknewestAccountIsDApp              sync.RWMutex
@@ -373,11 +455,14 @@ type mockScriptStorageState struct {
 	lockscriptByAddr                     sync.RWMutex
 	lockscriptByAsset                    sync.RWMutex
 	lockscriptBytesByAddr                sync.RWMutex
+	lockscriptBytesByAddrAtHeight        sync.RWMutex
 	lockscriptBytesByAsset               sync.RWMutex
+	lockscriptsKeys                      sync.RWMutex
 	locksetAccountScript                 sync.RWMutex
 	locksetAssetScript                   sync.RWMutex
 	locksetAssetScriptUncertain          sync.RWMutex
 	lockuncertainAssetScriptsCopy        sync.RWMutex
+	lockuncertainKeys                    sync.RWMutex
 }
 
 // accountHasScript calls accountHasScriptFunc.
@@ -476,6 +561,38 @@ func (mock *mockScriptStorageState) accountIsDAppCalls() []struct {
 	return calls
 }
 
+// accountScriptStateSize calls accountScriptStateSizeFunc.
+func (mock *mockScriptStorageState) accountScriptStateSize(addr proto.WavesAddress) (int64, error) {
+	if mock.accountScriptStateSizeFunc == nil {
+		panic("mockScriptStorageState.accountScriptStateSizeFunc: method is nil but scriptStorageState.accountScriptStateSize was just called")
+	}
+	callInfo := struct {
+		Addr proto.WavesAddress
+	}{
+		Addr: addr,
+	}
+	mock.lockaccountScriptStateSize.Lock()
+	mock.calls.accountScriptStateSize = append(mock.calls.accountScriptStateSize, callInfo)
+	mock.lockaccountScriptStateSize.Unlock()
+	return mock.accountScriptStateSizeFunc(addr)
+}
+
+// accountScriptStateSizeCalls gets all the calls that were made to accountScriptStateSize.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.accountScriptStateSizeCalls())
+func (mock *mockScriptStorageState) accountScriptStateSizeCalls() []struct {
+	Addr proto.WavesAddress
+} {
+	var calls []struct {
+		Addr proto.WavesAddress
+	}
+	mock.lockaccountScriptStateSize.RLock()
+	calls = mock.calls.accountScriptStateSize
+	mock.lockaccountScriptStateSize.RUnlock()
+	return calls
+}
+
 // clearCache calls clearCacheFunc.
 func (mock *mockScriptStorageState) clearCache() error {
 	if mock.clearCacheFunc == nil {
@@ -616,6 +733,33 @@ func (mock *mockScriptStorageState) getAssetScriptsHasherCalls() []struct {
 	return calls
 }
 
+// hasUncertain calls hasUncertainFunc.
+func (mock *mockScriptStorageState) hasUncertain() bool {
+	if mock.hasUncertainFunc == nil {
+		panic("mockScriptStorageState.hasUncertainFunc: method is nil but scriptStorageState.hasUncertain was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockhasUncertain.Lock()
+	mock.calls.hasUncertain = append(mock.calls.hasUncertain, callInfo)
+	mock.lockhasUncertain.Unlock()
+	return mock.hasUncertainFunc()
+}
+
+// hasUncertainCalls gets all the calls that were made to hasUncertain.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.hasUncertainCalls())
+func (mock *mockScriptStorageState) hasUncertainCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockhasUncertain.RLock()
+	calls = mock.calls.hasUncertain
+	mock.lockhasUncertain.RUnlock()
+	return calls
+}
+
 // isSmartAsset calls isSmartAssetFunc.
 func (mock *mockScriptStorageState) isSmartAsset(assetID proto.AssetID) (bool, error) {
 	if mock.isSmartAssetFunc == nil {
@@ -648,6 +792,74 @@ func (mock *mockScriptStorageState) isSmartAssetCalls() []struct {
 	return calls
 }
 
+// iterateScriptsBytes calls iterateScriptsBytesFunc.
+func (mock *mockScriptStorageState) iterateScriptsBytes(ctx context.Context, fn func(key scriptKey, script proto.Script) error) error {
+	if mock.iterateScriptsBytesFunc == nil {
+		panic("mockScriptStorageState.iterateScriptsBytesFunc: method is nil but scriptStorageState.iterateScriptsBytes was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Fn  func(key scriptKey, script proto.Script) error
+	}{
+		Ctx: ctx,
+		Fn:  fn,
+	}
+	mock.lockiterateScriptsBytes.Lock()
+	mock.calls.iterateScriptsBytes = append(mock.calls.iterateScriptsBytes, callInfo)
+	mock.lockiterateScriptsBytes.Unlock()
+	return mock.iterateScriptsBytesFunc(ctx, fn)
+}
+
+// iterateScriptsBytesCalls gets all the calls that were made to iterateScriptsBytes.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.iterateScriptsBytesCalls())
+func (mock *mockScriptStorageState) iterateScriptsBytesCalls() []struct {
+	Ctx context.Context
+	Fn  func(key scriptKey, script proto.Script) error
+} {
+	var calls []struct {
+		Ctx context.Context
+		Fn  func(key scriptKey, script proto.Script) error
+	}
+	mock.lockiterateScriptsBytes.RLock()
+	calls = mock.calls.iterateScriptsBytes
+	mock.lockiterateScriptsBytes.RUnlock()
+	return calls
+}
+
+// newestAccountHasCallables calls newestAccountHasCallablesFunc.
+func (mock *mockScriptStorageState) newestAccountHasCallables(addr proto.WavesAddress) (bool, error) {
+	if mock.newestAccountHasCallablesFunc == nil {
+		panic("mockScriptStorageState.newestAccountHasCallablesFunc: method is nil but scriptStorageState.newestAccountHasCallables was just called")
+	}
+	callInfo := struct {
+		Addr proto.WavesAddress
+	}{
+		Addr: addr,
+	}
+	mock.locknewestAccountHasCallables.Lock()
+	mock.calls.newestAccountHasCallables = append(mock.calls.newestAccountHasCallables, callInfo)
+	mock.locknewestAccountHasCallables.Unlock()
+	return mock.newestAccountHasCallablesFunc(addr)
+}
+
+// newestAccountHasCallablesCalls gets all the calls that were made to newestAccountHasCallables.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.newestAccountHasCallablesCalls())
+func (mock *mockScriptStorageState) newestAccountHasCallablesCalls() []struct {
+	Addr proto.WavesAddress
+} {
+	var calls []struct {
+		Addr proto.WavesAddress
+	}
+	mock.locknewestAccountHasCallables.RLock()
+	calls = mock.calls.newestAccountHasCallables
+	mock.locknewestAccountHasCallables.RUnlock()
+	return calls
+}
+
 // newestAccountHasScript calls newestAccountHasScriptFunc.
 func (mock *mockScriptStorageState) newestAccountHasScript(addr proto.WavesAddress) (bool, error) {
 	if mock.newestAccountHasScriptFunc == nil {
@@ -1118,6 +1330,42 @@ func (mock *mockScriptStorageState) scriptBytesByAddrCalls() []struct {
 	return calls
 }
 
+// scriptBytesByAddrAtHeight calls scriptBytesByAddrAtHeightFunc.
+func (mock *mockScriptStorageState) scriptBytesByAddrAtHeight(addr proto.WavesAddress, height proto.Height) (proto.Script, error) {
+	if mock.scriptBytesByAddrAtHeightFunc == nil {
+		panic("mockScriptStorageState.scriptBytesByAddrAtHeightFunc: method is nil but scriptStorageState.scriptBytesByAddrAtHeight was just called")
+	}
+	callInfo := struct {
+		Addr   proto.WavesAddress
+		Height proto.Height
+	}{
+		Addr:   addr,
+		Height: height,
+	}
+	mock.lockscriptBytesByAddrAtHeight.Lock()
+	mock.calls.scriptBytesByAddrAtHeight = append(mock.calls.scriptBytesByAddrAtHeight, callInfo)
+	mock.lockscriptBytesByAddrAtHeight.Unlock()
+	return mock.scriptBytesByAddrAtHeightFunc(addr, height)
+}
+
+// scriptBytesByAddrAtHeightCalls gets all the calls that were made to scriptBytesByAddrAtHeight.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.scriptBytesByAddrAtHeightCalls())
+func (mock *mockScriptStorageState) scriptBytesByAddrAtHeightCalls() []struct {
+	Addr   proto.WavesAddress
+	Height proto.Height
+} {
+	var calls []struct {
+		Addr   proto.WavesAddress
+		Height proto.Height
+	}
+	mock.lockscriptBytesByAddrAtHeight.RLock()
+	calls = mock.calls.scriptBytesByAddrAtHeight
+	mock.lockscriptBytesByAddrAtHeight.RUnlock()
+	return calls
+}
+
 // scriptBytesByAsset calls scriptBytesByAssetFunc.
 func (mock *mockScriptStorageState) scriptBytesByAsset(assetID proto.AssetID) (proto.Script, error) {
 	if mock.scriptBytesByAssetFunc == nil {
@@ -1150,6 +1398,38 @@ func (mock *mockScriptStorageState) scriptBytesByAssetCalls() []struct {
 	return calls
 }
 
+// scriptsKeys calls scriptsKeysFunc.
+func (mock *mockScriptStorageState) scriptsKeys(ctx context.Context) ([]scriptKey, error) {
+	if mock.scriptsKeysFunc == nil {
+		panic("mockScriptStorageState.scriptsKeysFunc: method is nil but scriptStorageState.scriptsKeys was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockscriptsKeys.Lock()
+	mock.calls.scriptsKeys = append(mock.calls.scriptsKeys, callInfo)
+	mock.lockscriptsKeys.Unlock()
+	return mock.scriptsKeysFunc(ctx)
+}
+
+// scriptsKeysCalls gets all the calls that were made to scriptsKeys.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.scriptsKeysCalls())
+func (mock *mockScriptStorageState) scriptsKeysCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockscriptsKeys.RLock()
+	calls = mock.calls.scriptsKeys
+	mock.lockscriptsKeys.RUnlock()
+	return calls
+}
+
 // setAccountScript calls setAccountScriptFunc.
 func (mock *mockScriptStorageState) setAccountScript(addr proto.WavesAddress, script proto.Script, pk crypto.PublicKey, blockID proto.BlockID) error {
 	if mock.setAccountScriptFunc == nil {
@@ -1300,3 +1580,30 @@ func (mock *mockScriptStorageState) uncertainAssetScriptsCopyCalls() []struct {
 	mock.lockuncertainAssetScriptsCopy.RUnlock()
 	return calls
 }
+
+// uncertainKeys calls uncertainKeysFunc.
+func (mock *mockScriptStorageState) uncertainKeys() []scriptKey {
+	if mock.uncertainKeysFunc == nil {
+		panic("mockScriptStorageState.uncertainKeysFunc: method is nil but scriptStorageState.uncertainKeys was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockuncertainKeys.Lock()
+	mock.calls.uncertainKeys = append(mock.calls.uncertainKeys, callInfo)
+	mock.lockuncertainKeys.Unlock()
+	return mock.uncertainKeysFunc()
+}
+
+// uncertainKeysCalls gets all the calls that were made to uncertainKeys.
+// Check the length with:
+//
+//	len(mockedscriptStorageState.uncertainKeysCalls())
+func (mock *mockScriptStorageState) uncertainKeysCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockuncertainKeys.RLock()
+	calls = mock.calls.uncertainKeys
+	mock.lockuncertainKeys.RUnlock()
+	return calls
+}
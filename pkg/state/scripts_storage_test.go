@@ -1,6 +1,8 @@
 package state
 
 import (
+	"bytes"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -251,3 +253,41 @@ func TestSetAssetScript(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, testGlobal.scriptAst, scriptAst)
 }
+
+func TestUncertainDiagnostics(t *testing.T) {
+	to := createScriptsStorageTestObjects(t)
+
+	to.stor.addBlock(t, blockID0)
+	asset0 := testGlobal.asset0.asset.ID
+	asset1 := testGlobal.asset1.asset.ID
+	assert.False(t, to.scriptsStorage.hasUncertain())
+	assert.Empty(t, to.scriptsStorage.uncertainKeys())
+
+	err := to.scriptsStorage.setAssetScriptUncertain(asset0, testGlobal.scriptBytes, testGlobal.senderInfo.pk)
+	require.NoError(t, err)
+	err = to.scriptsStorage.setAssetScriptUncertain(asset1, testGlobal.scriptBytes, testGlobal.senderInfo.pk)
+	require.NoError(t, err)
+	assert.True(t, to.scriptsStorage.hasUncertain())
+	expected := []scriptKey{
+		&assetScriptKey{assetID: proto.AssetIDFromDigest(asset0)},
+		&assetScriptKey{assetID: proto.AssetIDFromDigest(asset1)},
+	}
+	slices.SortFunc(expected, func(a, b scriptKey) int { return bytes.Compare(a.bytes(), b.bytes()) })
+	assert.Equal(t, expected, to.scriptsStorage.uncertainKeys())
+
+	to.scriptsStorage.dropUncertain()
+	assert.False(t, to.scriptsStorage.hasUncertain())
+	assert.Empty(t, to.scriptsStorage.uncertainKeys())
+
+	// Commit keeps the buffer until it's dropped.
+	err = to.scriptsStorage.setAssetScriptUncertain(asset0, testGlobal.scriptBytes, testGlobal.senderInfo.pk)
+	require.NoError(t, err)
+	err = to.scriptsStorage.commitUncertain(blockID0)
+	require.NoError(t, err)
+	assert.True(t, to.scriptsStorage.hasUncertain())
+	assert.Equal(t, []scriptKey{&assetScriptKey{assetID: proto.AssetIDFromDigest(asset0)}},
+		to.scriptsStorage.uncertainKeys())
+	to.scriptsStorage.dropUncertain()
+	assert.False(t, to.scriptsStorage.hasUncertain())
+	assert.Empty(t, to.scriptsStorage.uncertainKeys())
+}
@@ -2,6 +2,10 @@ package state
 
 import (
 	stderrs "errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
 
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
@@ -33,6 +37,76 @@ type blockSnapshotsApplier struct {
 
 	// used for legacy SH
 	balanceRecordsContext balanceRecordsContext
+
+	// checkNegativeBalances enables rejection of balance snapshots that produce negative balances.
+	checkNegativeBalances bool
+	// wavesBalanceChanges are the addresses whose Waves profiles were changed by the transaction's snapshots,
+	// their spendable balances are checked once all snapshots of the transaction are applied.
+	wavesBalanceChanges []proto.WavesAddress
+}
+
+// NegativeBalanceError is returned by the snapshot applier if the balance snapshot produces a negative balance.
+type NegativeBalanceError struct {
+	Address proto.WavesAddress
+	Asset   proto.OptionalAsset
+	Balance *big.Int
+}
+
+func (e *NegativeBalanceError) Error() string {
+	return fmt.Sprintf("snapshot produces negative balance %s of asset %q for address %q",
+		e.Balance.String(), e.Asset.String(), e.Address.String())
+}
+
+// checkWavesProfile checks that the balance and the lease out amount of the snapshot are not negative.
+// The balance values of snapshots are unsigned, so values greater than math.MaxInt64 are negative ones overflowed.
+// The spendable balance isn't checked here, because the transaction's Waves balance snapshots are applied before
+// its lease balance snapshots, see checkSpendableBalances.
+func (a *blockSnapshotsApplier) checkWavesProfile(addr proto.WavesAddress, balance uint64, leaseOut int64) error {
+	if !a.checkNegativeBalances {
+		return nil
+	}
+	waves := proto.NewOptionalAssetWaves()
+	if balance > math.MaxInt64 {
+		return &NegativeBalanceError{Address: addr, Asset: waves, Balance: big.NewInt(int64(balance))}
+	}
+	if leaseOut < 0 {
+		return &NegativeBalanceError{Address: addr, Asset: waves, Balance: big.NewInt(leaseOut)}
+	}
+	if !slices.Contains(a.wavesBalanceChanges, addr) {
+		a.wavesBalanceChanges = append(a.wavesBalanceChanges, addr)
+	}
+	return nil
+}
+
+// checkSpendableBalances checks that the spendable balances of the addresses changed by the transaction
+// are not negative. The check is done on the final state of the transaction, because the intermediate state
+// between its Waves and lease balance snapshots could be negative, like for the lease cancel of the fully leased
+// account which fee is debited before the lease out amount is decreased.
+func (a *blockSnapshotsApplier) checkSpendableBalances() error {
+	defer func() { a.wavesBalanceChanges = a.wavesBalanceChanges[:0] }()
+	for _, addr := range a.wavesBalanceChanges {
+		profile, err := a.stor.balances.newestWavesBalance(addr.ID())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get newest waves balance profile for address %q", addr.String())
+		}
+		spendable := new(big.Int).Sub(new(big.Int).SetUint64(profile.balance), big.NewInt(profile.leaseOut))
+		if spendable.Sign() < 0 {
+			return &NegativeBalanceError{Address: addr, Asset: proto.NewOptionalAssetWaves(), Balance: spendable}
+		}
+	}
+	return nil
+}
+
+// checkAssetBalance checks that the asset balance is not negative.
+func (a *blockSnapshotsApplier) checkAssetBalance(addr proto.WavesAddress, asset crypto.Digest, balance uint64) error {
+	if !a.checkNegativeBalances || balance <= math.MaxInt64 {
+		return nil
+	}
+	return &NegativeBalanceError{
+		Address: addr,
+		Asset:   *proto.NewOptionalAssetFromDigest(asset),
+		Balance: big.NewInt(int64(balance)),
+	}
 }
 
 func (a *blockSnapshotsApplier) BeforeTxSnapshotApply(tx proto.Transaction, validatingUTX bool) error {
@@ -49,10 +123,14 @@ func (a *blockSnapshotsApplier) BeforeTxSnapshotApply(tx proto.Transaction, vali
 	if len(a.cancelledLeases) != 0 {
 		a.cancelledLeases = make(map[crypto.Digest]struct{})
 	}
+	a.wavesBalanceChanges = a.wavesBalanceChanges[:0]
 	return nil
 }
 
 func (a *blockSnapshotsApplier) AfterTxSnapshotApply() error {
+	if err := a.checkSpendableBalances(); err != nil {
+		return err
+	}
 	for _, assetID := range a.issuedAssets {
 		if _, ok := a.scriptedAssets[assetID]; ok { // don't set an empty script for scripted assets or script updates
 			continue
@@ -375,11 +453,6 @@ func (a *blockSnapshotsApplier) SetApplierInfo(info extendedSnapshotApplierInfo)
 }
 
 func (a *blockSnapshotsApplier) ApplyWavesBalance(snapshot proto.WavesBalanceSnapshot) error {
-	// for compatibility with the legacy state hashes
-	err := a.addWavesBalanceRecordLegacySH(snapshot.Address, int64(snapshot.Balance))
-	if err != nil {
-		return err
-	}
 	addrID := snapshot.Address.ID()
 	profile, err := a.stor.balances.newestWavesBalance(addrID)
 	if err != nil {
@@ -387,6 +460,13 @@ func (a *blockSnapshotsApplier) ApplyWavesBalance(snapshot proto.WavesBalanceSna
 	}
 	newProfile := profile
 	newProfile.balance = snapshot.Balance
+	if err = a.checkWavesProfile(snapshot.Address, newProfile.balance, newProfile.leaseOut); err != nil {
+		return err
+	}
+	// for compatibility with the legacy state hashes
+	if err = a.addWavesBalanceRecordLegacySH(snapshot.Address, int64(snapshot.Balance)); err != nil {
+		return err
+	}
 	value := newWavesValue(profile, newProfile)
 	if err = a.stor.balances.setWavesBalance(addrID, value, a.info.BlockID()); err != nil {
 		return errors.Wrapf(err, "failed to get set balance profile for address %q", snapshot.Address.String())
@@ -395,11 +475,6 @@ func (a *blockSnapshotsApplier) ApplyWavesBalance(snapshot proto.WavesBalanceSna
 }
 
 func (a *blockSnapshotsApplier) ApplyLeaseBalance(snapshot proto.LeaseBalanceSnapshot) error {
-	err := a.addLeasesBalanceRecordLegacySH(snapshot.Address, int64(snapshot.LeaseIn), int64(snapshot.LeaseOut))
-	if err != nil {
-		return err
-	}
-
 	addrID := snapshot.Address.ID()
 	profile, err := a.stor.balances.newestWavesBalance(addrID)
 	if err != nil {
@@ -408,6 +483,13 @@ func (a *blockSnapshotsApplier) ApplyLeaseBalance(snapshot proto.LeaseBalanceSna
 	newProfile := profile
 	newProfile.leaseIn = int64(snapshot.LeaseIn)
 	newProfile.leaseOut = int64(snapshot.LeaseOut)
+	if err = a.checkWavesProfile(snapshot.Address, newProfile.balance, newProfile.leaseOut); err != nil {
+		return err
+	}
+	err = a.addLeasesBalanceRecordLegacySH(snapshot.Address, int64(snapshot.LeaseIn), int64(snapshot.LeaseOut))
+	if err != nil {
+		return err
+	}
 	value := newWavesValue(profile, newProfile)
 	if err = a.stor.balances.setWavesBalance(addrID, value, a.info.BlockID()); err != nil {
 		return errors.Wrapf(err, "failed to get set balance profile for address %q", snapshot.Address.String())
@@ -416,6 +498,9 @@ func (a *blockSnapshotsApplier) ApplyLeaseBalance(snapshot proto.LeaseBalanceSna
 }
 
 func (a *blockSnapshotsApplier) ApplyAssetBalance(snapshot proto.AssetBalanceSnapshot) error {
+	if err := a.checkAssetBalance(snapshot.Address, snapshot.AssetID, snapshot.Balance); err != nil {
+		return err
+	}
 	assetID := proto.AssetIDFromDigest(snapshot.AssetID)
 	// for compatibility with the legacy state hashes
 	err := a.addAssetBalanceRecordLegacySH(snapshot.Address, assetID, int64(snapshot.Balance))
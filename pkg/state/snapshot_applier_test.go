@@ -0,0 +1,98 @@
+package state
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func newNegativeBalancesTestApplier(
+	t *testing.T, balance uint64, leaseOut int64,
+) (*blockSnapshotsApplier, *testStorageObjects, proto.WavesAddress) {
+	sets := settings.MustMainNetSettings()
+	to := createStorageObjects(t, true)
+	applier := newBlockSnapshotsApplier(
+		newBlockSnapshotsApplierInfo(defaultCheckerInfo(), sets.AddressSchemeCharacter),
+		newSnapshotApplierStorages(to.entities, to.rw),
+	)
+	applier.checkNegativeBalances = true
+	addr := testGlobal.senderInfo.addr
+	to.addBlockAndDo(t, blockID0, func(blockID proto.BlockID) {
+		err := to.entities.balances.setWavesBalance(addr.ID(), wavesValue{
+			profile:       balanceProfile{balance: balance, leaseOut: leaseOut},
+			balanceChange: true,
+			leaseChange:   true,
+		}, blockID)
+		require.NoError(t, err)
+	})
+	return &applier, to, addr
+}
+
+func TestSnapshotApplierNegativeBalances(t *testing.T) {
+	overspend := func(t *testing.T, err error, addr proto.WavesAddress, asset proto.OptionalAsset, balance int64) {
+		var nbErr *NegativeBalanceError
+		require.ErrorAs(t, err, &nbErr)
+		assert.Equal(t, addr, nbErr.Address)
+		assert.Equal(t, asset, nbErr.Asset)
+		assert.Equal(t, big.NewInt(balance), nbErr.Balance)
+	}
+	applyTx := func(applier *blockSnapshotsApplier, snapshots ...proto.AtomicSnapshot) error {
+		return txSnapshot{regular: snapshots}.Apply(applier, nil, false)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		applier, _, addr := newNegativeBalancesTestApplier(t, 1000, 400)
+		applier.checkNegativeBalances = false
+		err := applyTx(applier, &proto.LeaseBalanceSnapshot{Address: addr, LeaseOut: 1500})
+		assert.NoError(t, err)
+	})
+	t.Run("waves balance", func(t *testing.T) {
+		applier, _, addr := newNegativeBalancesTestApplier(t, 1000, 400)
+		err := applyTx(applier, &proto.WavesBalanceSnapshot{Address: addr, Balance: 400})
+		assert.NoError(t, err)
+		err = applyTx(applier, &proto.WavesBalanceSnapshot{Address: addr, Balance: 300})
+		overspend(t, err, addr, proto.NewOptionalAssetWaves(), -100)
+		err = applier.ApplyWavesBalance(proto.WavesBalanceSnapshot{Address: addr, Balance: math.MaxUint64})
+		overspend(t, err, addr, proto.NewOptionalAssetWaves(), -1)
+	})
+	t.Run("lease balance", func(t *testing.T) {
+		applier, _, addr := newNegativeBalancesTestApplier(t, 1000, 400)
+		err := applyTx(applier, &proto.LeaseBalanceSnapshot{Address: addr, LeaseOut: 1000})
+		assert.NoError(t, err)
+		err = applyTx(applier, &proto.LeaseBalanceSnapshot{Address: addr, LeaseOut: 1001})
+		overspend(t, err, addr, proto.NewOptionalAssetWaves(), -1)
+	})
+	t.Run("lease cancel with fee from fully leased account", func(t *testing.T) {
+		applier, to, addr := newNegativeBalancesTestApplier(t, 1000, 1000)
+		// fee is debited before the lease out amount is decreased, the intermediate spendable balance is -100
+		err := applyTx(applier,
+			&proto.WavesBalanceSnapshot{Address: addr, Balance: 900},
+			&proto.LeaseBalanceSnapshot{Address: addr, LeaseOut: 0},
+		)
+		require.NoError(t, err)
+		profile, err := to.entities.balances.newestWavesBalance(addr.ID())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(900), profile.balance)
+		assert.Equal(t, int64(0), profile.leaseOut)
+	})
+	t.Run("fee from fully leased account", func(t *testing.T) {
+		applier, _, addr := newNegativeBalancesTestApplier(t, 1000, 1000)
+		err := applyTx(applier, &proto.WavesBalanceSnapshot{Address: addr, Balance: 900})
+		overspend(t, err, addr, proto.NewOptionalAssetWaves(), -100)
+	})
+	t.Run("asset balance", func(t *testing.T) {
+		applier, _, addr := newNegativeBalancesTestApplier(t, 1000, 400)
+		asset := testGlobal.asset0.assetID
+		err := applier.ApplyAssetBalance(proto.AssetBalanceSnapshot{Address: addr, AssetID: asset, Balance: 10})
+		assert.NoError(t, err)
+		err = applier.ApplyAssetBalance(proto.AssetBalanceSnapshot{Address: addr, AssetID: asset,
+			Balance: math.MaxUint64})
+		overspend(t, err, addr, *proto.NewOptionalAssetFromDigest(asset), -1)
+	})
+}
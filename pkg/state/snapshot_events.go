@@ -0,0 +1,297 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// StateEventType is the stable name of the state event type.
+type StateEventType string
+
+const (
+	BalanceEventType           StateEventType = "balance"
+	LeaseBalanceEventType      StateEventType = "lease_balance"
+	DataEntriesEventType       StateEventType = "data_entries"
+	AliasEventType             StateEventType = "alias"
+	AssetIssuedEventType       StateEventType = "asset_issued"
+	AssetVolumeEventType       StateEventType = "asset_volume"
+	AssetInfoEventType         StateEventType = "asset_info"
+	AssetScriptEventType       StateEventType = "asset_script"
+	AccountScriptEventType     StateEventType = "account_script"
+	SponsorshipEventType       StateEventType = "sponsorship"
+	OrderFillEventType         StateEventType = "order_fill"
+	LeaseOpenedEventType       StateEventType = "lease_opened"
+	LeaseCancelledEventType    StateEventType = "lease_cancelled"
+	TransactionStatusEventType StateEventType = "transaction_status"
+)
+
+// StateEvent is a single change of the state made by a transaction.
+type StateEvent interface {
+	Type() StateEventType
+	TransactionID() crypto.Digest
+}
+
+// EventTx holds the ID of the transaction that produced the event.
+type EventTx struct {
+	TxID crypto.Digest `json:"txId"`
+}
+
+func (e EventTx) TransactionID() crypto.Digest { return e.TxID }
+
+// BalanceEvent reports the balance of Waves or an asset of the address after the transaction.
+// Like snapshots the event holds the post-balance, not the signed difference, the debited or credited amount
+// is the difference with the post-balance of the previous event or the balance before the transaction.
+type BalanceEvent struct {
+	EventTx
+	Address     proto.WavesAddress  `json:"address"`
+	Asset       proto.OptionalAsset `json:"asset"`
+	PostBalance uint64              `json:"postBalance"`
+}
+
+func (BalanceEvent) Type() StateEventType { return BalanceEventType }
+
+// LeaseBalanceEvent reports the leasing balances of the address after the transaction, see BalanceEvent.
+type LeaseBalanceEvent struct {
+	EventTx
+	Address      proto.WavesAddress `json:"address"`
+	PostLeaseIn  uint64             `json:"postIn"`
+	PostLeaseOut uint64             `json:"postOut"`
+}
+
+func (LeaseBalanceEvent) Type() StateEventType { return LeaseBalanceEventType }
+
+// DataEntriesEvent reports the updated or removed data entries of the address.
+type DataEntriesEvent struct {
+	EventTx
+	Address proto.WavesAddress `json:"address"`
+	Entries proto.DataEntries  `json:"entries"`
+}
+
+func (DataEntriesEvent) Type() StateEventType { return DataEntriesEventType }
+
+// AliasEvent reports the new alias of the address.
+type AliasEvent struct {
+	EventTx
+	Address proto.WavesAddress `json:"address"`
+	Alias   string             `json:"alias"`
+}
+
+func (AliasEvent) Type() StateEventType { return AliasEventType }
+
+// AssetIssuedEvent reports the issue of a new asset.
+type AssetIssuedEvent struct {
+	EventTx
+	AssetID  crypto.Digest    `json:"assetId"`
+	Issuer   crypto.PublicKey `json:"issuer"`
+	Decimals uint8            `json:"decimals"`
+	IsNFT    bool             `json:"nft"`
+}
+
+func (AssetIssuedEvent) Type() StateEventType { return AssetIssuedEventType }
+
+// AssetVolumeEvent reports the new total quantity and reissuability of the asset.
+type AssetVolumeEvent struct {
+	EventTx
+	AssetID    crypto.Digest `json:"assetId"`
+	Quantity   *big.Int      `json:"quantity"`
+	Reissuable bool          `json:"reissuable"`
+}
+
+func (AssetVolumeEvent) Type() StateEventType { return AssetVolumeEventType }
+
+// AssetInfoEvent reports the new name and description of the asset.
+type AssetInfoEvent struct {
+	EventTx
+	AssetID     crypto.Digest `json:"assetId"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+}
+
+func (AssetInfoEvent) Type() StateEventType { return AssetInfoEventType }
+
+// AssetScriptEvent reports the new script of the asset.
+type AssetScriptEvent struct {
+	EventTx
+	AssetID crypto.Digest `json:"assetId"`
+	Script  proto.Script  `json:"script"`
+}
+
+func (AssetScriptEvent) Type() StateEventType { return AssetScriptEventType }
+
+// AccountScriptEvent reports the new script of the account, empty script means the script removal.
+type AccountScriptEvent struct {
+	EventTx
+	PublicKey          crypto.PublicKey `json:"publicKey"`
+	Script             proto.Script     `json:"script"`
+	VerifierComplexity uint64           `json:"verifierComplexity"`
+}
+
+func (AccountScriptEvent) Type() StateEventType { return AccountScriptEventType }
+
+// SponsorshipEvent reports the new minimal sponsored fee of the asset, zero fee means the sponsorship cancel.
+type SponsorshipEvent struct {
+	EventTx
+	AssetID         crypto.Digest `json:"assetId"`
+	MinSponsoredFee uint64        `json:"minSponsoredFee"`
+}
+
+func (SponsorshipEvent) Type() StateEventType { return SponsorshipEventType }
+
+// OrderFillEvent reports the new filled volume and fee of the order.
+type OrderFillEvent struct {
+	EventTx
+	OrderID crypto.Digest `json:"orderId"`
+	Volume  uint64        `json:"volume"`
+	Fee     uint64        `json:"fee"`
+}
+
+func (OrderFillEvent) Type() StateEventType { return OrderFillEventType }
+
+// LeaseOpenedEvent reports the new lease.
+type LeaseOpenedEvent struct {
+	EventTx
+	LeaseID   crypto.Digest      `json:"leaseId"`
+	Amount    uint64             `json:"amount"`
+	Sender    crypto.PublicKey   `json:"sender"`
+	Recipient proto.WavesAddress `json:"recipient"`
+}
+
+func (LeaseOpenedEvent) Type() StateEventType { return LeaseOpenedEventType }
+
+// LeaseCancelledEvent reports the cancel of the lease.
+type LeaseCancelledEvent struct {
+	EventTx
+	LeaseID crypto.Digest `json:"leaseId"`
+}
+
+func (LeaseCancelledEvent) Type() StateEventType { return LeaseCancelledEventType }
+
+// TransactionStatusEvent reports the application status of the transaction.
+type TransactionStatusEvent struct {
+	EventTx
+	Status proto.TransactionStatus `json:"status"`
+}
+
+func (TransactionStatusEvent) Type() StateEventType { return TransactionStatusEventType }
+
+// SnapshotsToEvents translates atomic snapshots of the transaction into the list of state events
+// in the order of snapshots.
+func SnapshotsToEvents(snapshots []proto.AtomicSnapshot, txID crypto.Digest) []StateEvent {
+	c := &stateEventsCollector{tx: EventTx{TxID: txID}, events: make([]StateEvent, 0, len(snapshots))}
+	for _, s := range snapshots {
+		_ = s.Apply(c) // collector never fails
+	}
+	return c.events
+}
+
+// stateEventsCollector converts atomic snapshots to state events using the same interface as snapshot applier.
+type stateEventsCollector struct {
+	tx     EventTx
+	events []StateEvent
+}
+
+var _ = proto.SnapshotApplier((*stateEventsCollector)(nil))
+
+func (c *stateEventsCollector) add(e StateEvent) error {
+	c.events = append(c.events, e)
+	return nil
+}
+
+func (c *stateEventsCollector) ApplyWavesBalance(s proto.WavesBalanceSnapshot) error {
+	return c.add(BalanceEvent{
+		EventTx:     c.tx,
+		Address:     s.Address,
+		Asset:       proto.NewOptionalAssetWaves(),
+		PostBalance: s.Balance,
+	})
+}
+
+func (c *stateEventsCollector) ApplyAssetBalance(s proto.AssetBalanceSnapshot) error {
+	return c.add(BalanceEvent{
+		EventTx:     c.tx,
+		Address:     s.Address,
+		Asset:       *proto.NewOptionalAssetFromDigest(s.AssetID),
+		PostBalance: s.Balance,
+	})
+}
+
+func (c *stateEventsCollector) ApplyLeaseBalance(s proto.LeaseBalanceSnapshot) error {
+	return c.add(LeaseBalanceEvent{
+		EventTx:      c.tx,
+		Address:      s.Address,
+		PostLeaseIn:  s.LeaseIn,
+		PostLeaseOut: s.LeaseOut,
+	})
+}
+
+func (c *stateEventsCollector) ApplyAlias(s proto.AliasSnapshot) error {
+	return c.add(AliasEvent{EventTx: c.tx, Address: s.Address, Alias: s.Alias})
+}
+
+func (c *stateEventsCollector) ApplyNewAsset(s proto.NewAssetSnapshot) error {
+	return c.add(AssetIssuedEvent{
+		EventTx:  c.tx,
+		AssetID:  s.AssetID,
+		Issuer:   s.IssuerPublicKey,
+		Decimals: s.Decimals,
+		IsNFT:    s.IsNFT,
+	})
+}
+
+func (c *stateEventsCollector) ApplyAssetDescription(s proto.AssetDescriptionSnapshot) error {
+	return c.add(AssetInfoEvent{EventTx: c.tx, AssetID: s.AssetID, Name: s.AssetName, Description: s.AssetDescription})
+}
+
+func (c *stateEventsCollector) ApplyAssetVolume(s proto.AssetVolumeSnapshot) error {
+	return c.add(AssetVolumeEvent{
+		EventTx:    c.tx,
+		AssetID:    s.AssetID,
+		Quantity:   new(big.Int).Set(&s.TotalQuantity),
+		Reissuable: s.IsReissuable,
+	})
+}
+
+func (c *stateEventsCollector) ApplyAssetScript(s proto.AssetScriptSnapshot) error {
+	return c.add(AssetScriptEvent{EventTx: c.tx, AssetID: s.AssetID, Script: s.Script})
+}
+
+func (c *stateEventsCollector) ApplySponsorship(s proto.SponsorshipSnapshot) error {
+	return c.add(SponsorshipEvent{EventTx: c.tx, AssetID: s.AssetID, MinSponsoredFee: s.MinSponsoredFee})
+}
+
+func (c *stateEventsCollector) ApplyAccountScript(s proto.AccountScriptSnapshot) error {
+	return c.add(AccountScriptEvent{
+		EventTx:            c.tx,
+		PublicKey:          s.SenderPublicKey,
+		Script:             s.Script,
+		VerifierComplexity: s.VerifierComplexity,
+	})
+}
+
+func (c *stateEventsCollector) ApplyFilledVolumeAndFee(s proto.FilledVolumeFeeSnapshot) error {
+	return c.add(OrderFillEvent{EventTx: c.tx, OrderID: s.OrderID, Volume: s.FilledVolume, Fee: s.FilledFee})
+}
+
+func (c *stateEventsCollector) ApplyDataEntries(s proto.DataEntriesSnapshot) error {
+	return c.add(DataEntriesEvent{EventTx: c.tx, Address: s.Address, Entries: s.DataEntries})
+}
+
+func (c *stateEventsCollector) ApplyNewLease(s proto.NewLeaseSnapshot) error {
+	return c.add(LeaseOpenedEvent{
+		EventTx:   c.tx,
+		LeaseID:   s.LeaseID,
+		Amount:    s.Amount,
+		Sender:    s.SenderPK,
+		Recipient: s.RecipientAddr,
+	})
+}
+
+func (c *stateEventsCollector) ApplyCancelledLease(s proto.CancelledLeaseSnapshot) error {
+	return c.add(LeaseCancelledEvent{EventTx: c.tx, LeaseID: s.LeaseID})
+}
+
+func (c *stateEventsCollector) ApplyTransactionsStatus(s proto.TransactionStatusSnapshot) error {
+	return c.add(TransactionStatusEvent{EventTx: c.tx, Status: s.Status})
+}
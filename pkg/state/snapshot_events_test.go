@@ -0,0 +1,89 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func TestSnapshotsToEventsTransfer(t *testing.T) {
+	to := createSnapshotGeneratorTestObjects(t)
+
+	to.stor.addBlock(t, blockID0)
+	to.stor.activateFeature(t, int16(settings.NG))
+
+	err := to.stor.entities.balances.setWavesBalance(testGlobal.issuerInfo.addr.ID(),
+		wavesValue{profile: balanceProfile{balance: 1000 * FeeUnit * 3}}, blockID0)
+	require.NoError(t, err, "failed to set waves balance")
+
+	tx := proto.NewUnsignedTransferWithSig(testGlobal.issuerInfo.pk,
+		proto.NewOptionalAssetWaves(), proto.NewOptionalAssetWaves(), defaultTimestamp,
+		defaultAmount*1000*2, uint64(FeeUnit), testGlobal.recipientInfo.Recipient(), nil)
+	err = tx.Sign(proto.TestNetScheme, testGlobal.issuerInfo.sk)
+	require.NoError(t, err, "failed to sign transfer tx")
+	txID, err := tx.GetID(proto.TestNetScheme)
+	require.NoError(t, err)
+	id, err := crypto.NewDigestFromBytes(txID)
+	require.NoError(t, err)
+
+	ch, err := to.td.createDiffTransferWithSig(tx, defaultDifferInfo())
+	require.NoError(t, err, "createDiffTransferWithSig() failed")
+	snapshot, err := to.tp.performTransferWithSig(tx, defaultPerformerInfo(), ch.diff.balancesChanges())
+	require.NoError(t, err, "failed to perform transfer tx")
+
+	waves := proto.NewOptionalAssetWaves()
+	expected := []StateEvent{
+		// Miner's balance after the fee is credited.
+		BalanceEvent{EventTx: EventTx{TxID: id}, Address: testGlobal.minerInfo.addr, Asset: waves, PostBalance: 40000},
+		// Sender's balance after the amount and fee are debited from 300000000.
+		BalanceEvent{EventTx: EventTx{TxID: id}, Address: testGlobal.issuerInfo.addr, Asset: waves,
+			PostBalance: 299700000},
+		// Recipient's balance after the amount is credited.
+		BalanceEvent{EventTx: EventTx{TxID: id}, Address: testGlobal.recipientInfo.addr, Asset: waves,
+			PostBalance: 200000},
+	}
+	events := SnapshotsToEvents(snapshot.regular, id)
+	assert.ElementsMatch(t, expected, events)
+	for _, e := range events {
+		assert.Equal(t, BalanceEventType, e.Type())
+		assert.Equal(t, id, e.TransactionID())
+	}
+}
+
+func TestSnapshotsToEventsTypes(t *testing.T) {
+	id := crypto.MustFastHash([]byte("tx"))
+	assetID := crypto.MustFastHash([]byte("asset"))
+	leaseID := crypto.MustFastHash([]byte("lease"))
+	addr := testGlobal.senderInfo.addr
+	pk := testGlobal.senderInfo.pk
+	snapshot := []proto.AtomicSnapshot{
+		&proto.NewAssetSnapshot{AssetID: assetID, IssuerPublicKey: pk, Decimals: 2},
+		&proto.AssetVolumeSnapshot{AssetID: assetID, TotalQuantity: *big.NewInt(1000), IsReissuable: true},
+		&proto.AssetDescriptionSnapshot{AssetID: assetID, AssetName: "name", AssetDescription: "description"},
+		&proto.AssetBalanceSnapshot{Address: addr, AssetID: assetID, Balance: 1000},
+		&proto.AliasSnapshot{Address: addr, Alias: "alias"},
+		&proto.NewLeaseSnapshot{LeaseID: leaseID, Amount: 10, SenderPK: pk, RecipientAddr: addr},
+		&proto.LeaseBalanceSnapshot{Address: addr, LeaseIn: 10, LeaseOut: 0},
+		&proto.CancelledLeaseSnapshot{LeaseID: leaseID},
+		&proto.TransactionStatusSnapshot{Status: proto.TransactionSucceeded},
+	}
+	tx := EventTx{TxID: id}
+	expected := []StateEvent{
+		AssetIssuedEvent{EventTx: tx, AssetID: assetID, Issuer: pk, Decimals: 2},
+		AssetVolumeEvent{EventTx: tx, AssetID: assetID, Quantity: big.NewInt(1000), Reissuable: true},
+		AssetInfoEvent{EventTx: tx, AssetID: assetID, Name: "name", Description: "description"},
+		BalanceEvent{EventTx: tx, Address: addr, Asset: *proto.NewOptionalAssetFromDigest(assetID), PostBalance: 1000},
+		AliasEvent{EventTx: tx, Address: addr, Alias: "alias"},
+		LeaseOpenedEvent{EventTx: tx, LeaseID: leaseID, Amount: 10, Sender: pk, Recipient: addr},
+		LeaseBalanceEvent{EventTx: tx, Address: addr, PostLeaseIn: 10},
+		LeaseCancelledEvent{EventTx: tx, LeaseID: leaseID},
+		TransactionStatusEvent{EventTx: tx, Status: proto.TransactionSucceeded},
+	}
+	assert.Equal(t, expected, SnapshotsToEvents(snapshot, id))
+}
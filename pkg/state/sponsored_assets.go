@@ -58,6 +58,52 @@ func (s *sponsorshipRecord) unmarshalBinary(data []byte) error {
 	return nil
 }
 
+// SponsorshipPolicy defines conversion of fees between sponsored assets and Waves.
+// The assetCost argument is the amount of asset set by the sponsor as equal to FeeUnit Waves.
+type SponsorshipPolicy interface {
+	AssetToWaves(assetAmount, assetCost uint64) (uint64, error)
+	WavesToAsset(wavesAmount, assetCost uint64) (uint64, error)
+}
+
+// DefaultSponsorshipPolicy is the policy of Waves networks: fee amounts are converted proportionally to the asset cost.
+type DefaultSponsorshipPolicy struct{}
+
+func (DefaultSponsorshipPolicy) AssetToWaves(assetAmount, assetCost uint64) (uint64, error) {
+	if assetCost == 0 {
+		return 0, errors.New("0 asset cost")
+	}
+	var wavesAmount big.Int
+	wavesAmount.SetUint64(assetAmount)
+	var unit big.Int
+	unit.SetUint64(FeeUnit)
+	wavesAmount.Mul(&wavesAmount, &unit)
+	var costBig big.Int
+	costBig.SetUint64(assetCost)
+	wavesAmount.Quo(&wavesAmount, &costBig)
+	if !wavesAmount.IsInt64() {
+		return 0, errors.New("waves amount exceeds MaxInt64")
+	}
+	return wavesAmount.Uint64(), nil
+}
+
+func (DefaultSponsorshipPolicy) WavesToAsset(wavesAmount, assetCost uint64) (uint64, error) {
+	if assetCost == 0 || wavesAmount == 0 {
+		return 0, nil
+	}
+	var assetAmount big.Int
+	assetAmount.SetUint64(wavesAmount)
+	var costBig big.Int
+	costBig.SetUint64(assetCost)
+	assetAmount.Mul(&assetAmount, &costBig)
+	var unit big.Int
+	unit.SetUint64(FeeUnit)
+	assetAmount.Quo(&assetAmount, &unit)
+	if !assetAmount.IsInt64() {
+		return 0, errors.New("asset amount exceeds MaxInt64")
+	}
+	return assetAmount.Uint64(), nil
+}
+
 type uncertainSponsoredAsset struct {
 	assetID   crypto.Digest
 	assetCost uint64
@@ -70,6 +116,7 @@ type sponsoredAssets struct {
 	settings *settings.BlockchainSettings
 
 	uncertainSponsoredAssets map[proto.AssetID]uncertainSponsoredAsset
+	policy                   SponsorshipPolicy
 
 	calculateHashes bool
 	hasher          *stateHasher
@@ -88,6 +135,7 @@ func newSponsoredAssets(
 		hs:                       hs,
 		settings:                 settings,
 		uncertainSponsoredAssets: make(map[proto.AssetID]uncertainSponsoredAsset),
+		policy:                   DefaultSponsorshipPolicy{},
 		hasher:                   newStateHasher(),
 		calculateHashes:          calcHashes,
 	}
@@ -187,21 +235,7 @@ func (s *sponsoredAssets) sponsoredAssetToWaves(assetID proto.AssetID, assetAmou
 	if err != nil {
 		return 0, err
 	}
-	if cost == 0 {
-		return 0, errors.New("0 asset cost")
-	}
-	var wavesAmount big.Int
-	wavesAmount.SetUint64(assetAmount)
-	var unit big.Int
-	unit.SetUint64(FeeUnit)
-	wavesAmount.Mul(&wavesAmount, &unit)
-	var costBig big.Int
-	costBig.SetUint64(cost)
-	wavesAmount.Quo(&wavesAmount, &costBig)
-	if !wavesAmount.IsInt64() {
-		return 0, errors.New("waves amount exceeds MaxInt64")
-	}
-	return wavesAmount.Uint64(), nil
+	return s.policy.AssetToWaves(assetAmount, cost)
 }
 
 func (s *sponsoredAssets) wavesToSponsoredAsset(assetID proto.AssetID, wavesAmount uint64) (uint64, error) {
@@ -209,21 +243,7 @@ func (s *sponsoredAssets) wavesToSponsoredAsset(assetID proto.AssetID, wavesAmou
 	if err != nil {
 		return 0, err
 	}
-	if cost == 0 || wavesAmount == 0 {
-		return 0, nil
-	}
-	var assetAmount big.Int
-	assetAmount.SetUint64(wavesAmount)
-	var costBig big.Int
-	costBig.SetUint64(cost)
-	assetAmount.Mul(&assetAmount, &costBig)
-	var unit big.Int
-	unit.SetUint64(FeeUnit)
-	assetAmount.Quo(&assetAmount, &unit)
-	if !assetAmount.IsInt64() {
-		return 0, errors.New("asset amount exceeds MaxInt64")
-	}
-	return assetAmount.Uint64(), nil
+	return s.policy.WavesToAsset(wavesAmount, cost)
 }
 
 func (s *sponsoredAssets) isSponsorshipActivated() (bool, error) {
@@ -1,6 +1,8 @@
 package state
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -159,6 +161,80 @@ func TestWavesToSponsoredAsset(t *testing.T) {
 	assert.Equal(t, assetAmount, properAssetAmount)
 }
 
+type flatSponsorshipPolicy struct {
+	rate uint64
+}
+
+func (p flatSponsorshipPolicy) AssetToWaves(assetAmount, _ uint64) (uint64, error) {
+	return assetAmount * p.rate, nil
+}
+
+func (p flatSponsorshipPolicy) WavesToAsset(wavesAmount, _ uint64) (uint64, error) {
+	return wavesAmount / p.rate, nil
+}
+
+func TestSponsorshipPolicy(t *testing.T) {
+	to := createSponsoredAssets(t, true)
+
+	to.stor.addBlock(t, blockID0)
+	id := testGlobal.asset0.asset.ID
+	assetID := proto.AssetIDFromDigest(id)
+	err := to.sponsoredAssets.sponsorAsset(id, 3, blockID0)
+	require.NoError(t, err, "sponsorAsset() failed")
+
+	// Default policy must produce exactly the same results as the production formulas.
+	for _, tc := range []struct {
+		cost, assetAmount, wavesAmount uint64
+	}{
+		{1, 1, 1},
+		{3, 100500, 100500},
+		{7, 12345678, 987654321},
+		{100000, 1, 1},
+		{math.MaxInt64, math.MaxInt64, math.MaxInt64},
+	} {
+		expectedWaves := new(big.Int).Quo(
+			new(big.Int).Mul(new(big.Int).SetUint64(tc.assetAmount), big.NewInt(FeeUnit)),
+			new(big.Int).SetUint64(tc.cost),
+		)
+		wavesAmount, err := DefaultSponsorshipPolicy{}.AssetToWaves(tc.assetAmount, tc.cost)
+		if expectedWaves.IsInt64() {
+			require.NoError(t, err)
+			assert.Equal(t, expectedWaves.Uint64(), wavesAmount)
+		} else {
+			assert.Error(t, err)
+		}
+		expectedAsset := new(big.Int).Quo(
+			new(big.Int).Mul(new(big.Int).SetUint64(tc.wavesAmount), new(big.Int).SetUint64(tc.cost)),
+			big.NewInt(FeeUnit),
+		)
+		assetAmount, err := DefaultSponsorshipPolicy{}.WavesToAsset(tc.wavesAmount, tc.cost)
+		if expectedAsset.IsInt64() {
+			require.NoError(t, err)
+			assert.Equal(t, expectedAsset.Uint64(), assetAmount)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+	_, err = DefaultSponsorshipPolicy{}.AssetToWaves(100, 0)
+	assert.Error(t, err)
+	assetAmount, err := DefaultSponsorshipPolicy{}.WavesToAsset(100, 0)
+	require.NoError(t, err)
+	assert.Zero(t, assetAmount)
+
+	wavesAmount, err := to.sponsoredAssets.sponsoredAssetToWaves(assetID, 300)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300*FeeUnit/3), wavesAmount)
+
+	// Custom policy ignores the asset cost.
+	to.sponsoredAssets.policy = flatSponsorshipPolicy{rate: 10}
+	wavesAmount, err = to.sponsoredAssets.sponsoredAssetToWaves(assetID, 300)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3000), wavesAmount)
+	assetAmount, err = to.sponsoredAssets.wavesToSponsoredAsset(assetID, 3000)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300), assetAmount)
+}
+
 func TestIsSponsorshipActivated_Double(t *testing.T) {
 	to := createSponsoredAssets(t, true)
 
@@ -3,18 +3,23 @@ package state
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	stderrs "errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"math/big"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"sync"
 
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/wavesplatform/gowaves/pkg/consensus"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
@@ -69,7 +74,7 @@ type blockchainEntitiesStorage struct {
 }
 
 func newBlockchainEntitiesStorage(hs *historyStorage, sets *settings.BlockchainSettings, rw *blockReadWriter, calcHashes bool) (*blockchainEntitiesStorage, error) {
-	assets := newAssets(hs.db, hs.dbBatch, hs)
+	assets := newAssets(hs.db, hs.dbBatch, hs, sets.AddressSchemeCharacter)
 	balances, err := newBalances(hs.db, hs, assets, sets, calcHashes)
 	if err != nil {
 		return nil, err
@@ -127,26 +132,27 @@ func (s *blockchainEntitiesStorage) putStateHash(prevHash []byte, height uint64,
 	return sh, nil
 }
 
-func (s *blockchainEntitiesStorage) prepareHashes() error {
-	if err := s.accountsDataStor.prepareHashes(); err != nil {
-		return err
-	}
-	if err := s.balances.prepareHashes(); err != nil {
-		return err
-	}
-	if err := s.scriptsStorage.prepareHashes(); err != nil {
-		return err
-	}
-	if err := s.leases.prepareHashes(); err != nil {
-		return err
+// hashesPreparers returns the functions calculating the pending legacy state hashes of every category.
+// Each function touches only the data of its own storage, so they are safe to run concurrently.
+func (s *blockchainEntitiesStorage) hashesPreparers() []func() error {
+	return []func() error{
+		s.accountsDataStor.prepareHashes,
+		s.balances.prepareHashes,
+		s.scriptsStorage.prepareHashes,
+		s.leases.prepareHashes,
+		s.sponsoredAssets.prepareHashes,
+		s.aliases.prepareHashes,
 	}
-	if err := s.sponsoredAssets.prepareHashes(); err != nil {
-		return err
-	}
-	if err := s.aliases.prepareHashes(); err != nil {
-		return err
+}
+
+// prepareHashes calculates the pending hashes of all categories concurrently. The result doesn't depend on
+// the order of calculation, because the hashes are combined later in the fixed order in putStateHash.
+func (s *blockchainEntitiesStorage) prepareHashes() error {
+	var eg errgroup.Group
+	for _, prepare := range s.hashesPreparers() {
+		eg.Go(prepare)
 	}
-	return nil
+	return eg.Wait()
 }
 
 func (s *blockchainEntitiesStorage) handleLegacyStateHashes(blockchainHeight uint64, blockIds []proto.BlockID) error {
@@ -420,6 +426,8 @@ type stateManager struct {
 
 	stor *blockchainEntitiesStorage
 	rw   *blockReadWriter
+	// Directory of the blocks storage files, see ExportStateSnapshot.
+	blockStorageDir string
 
 	// BlockchainSettings: general info about the blockchain type, constants etc.
 	settings *settings.BlockchainSettings
@@ -431,6 +439,8 @@ type stateManager struct {
 
 	// Specifies how many goroutines will be run for verification of transactions and blocks signatures.
 	verificationGoroutinesNum int
+	// Allows overriding of features activation heights, see SetFeatureActivationHeight.
+	allowFeatureActivationOverride bool
 
 	newBlocks *newBlocks
 
@@ -570,6 +580,9 @@ func newStateManager(
 	if err != nil {
 		return nil, wrapErr(Other, errors.Errorf("failed to create blockchain entities storage: %v", err))
 	}
+	if params.SponsorshipPolicy != nil {
+		stor.sponsoredAssets.policy = params.SponsorshipPolicy
+	}
 	atxParams := &addressTransactionsParams{
 		dir:                 blockStorageDir,
 		batchedStorMemLimit: AddressTransactionsMemLimit,
@@ -593,16 +606,22 @@ func newStateManager(
 		stateDB:                   sdb,
 		stor:                      stor,
 		rw:                        rw,
+		blockStorageDir:           blockStorageDir,
 		settings:                  settings,
 		atx:                       atx,
 		verificationGoroutinesNum: params.VerificationGoroutinesNum,
 		newBlocks:                 newNewBlocks(rw, settings),
 		enableLightNode:           enableLightNode,
+
+		allowFeatureActivationOverride: params.UnsafeAllowFeatureActivationOverride,
 	}
 	// Set fields which depend on state.
 	// Consensus validator is needed to check block headers.
 	snapshotApplier := newBlockSnapshotsApplier(nil, newSnapshotApplierStorages(stor, rw))
-	appender, err := newTxAppender(state, rw, stor, settings, sdb, atx, &snapshotApplier)
+	snapshotApplier.checkNegativeBalances = params.CheckSnapshotBalances
+	appender, err := newTxAppender(state, rw, stor, settings, sdb, atx, &snapshotApplier,
+		params.MaxConcurrentEvaluations, params.TxMetrics,
+	)
 	if err != nil {
 		return nil, wrapErr(Other, err)
 	}
@@ -818,6 +837,35 @@ func (s *stateManager) BlockVRF(blockHeader *proto.BlockHeader, blockHeight prot
 	return blockVRFCommon(s.settings, blockHeader, blockHeight, s.HitSourceAtHeight)
 }
 
+// BlockGenerationSignature computes the generation signature and VRF value of the block with given ID from
+// the block's header and the hit source of the reference block, see consensus.ComputeGenerationSignature.
+// The reference block is the parent block for blocks of versions before the protobuf one,
+// otherwise it's the block used by Fair PoS for hit calculation. The genesis block has no reference block,
+// its generation signature is returned as is.
+func (s *stateManager) BlockGenerationSignature(blockID proto.BlockID) ([]byte, []byte, error) {
+	header, err := s.Header(blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+	height, err := s.BlockIDToHeight(blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if height == 1 {
+		return header.GenSignature.Bytes(), nil, nil
+	}
+	refHeight := height - 1
+	if header.Version >= proto.ProtobufBlockVersion {
+		pos := consensus.NewFairPosCalculator(s.settings.DelayDelta, s.settings.MinBlockTime)
+		refHeight = pos.HeightForHit(height - 1)
+	}
+	refHitSource, err := s.HitSourceAtHeight(refHeight)
+	if err != nil {
+		return nil, nil, err
+	}
+	return consensus.ComputeGenerationSignature(header, refHitSource)
+}
+
 func blockRewardsCommon(
 	generatorAddress proto.WavesAddress,
 	height proto.Height,
@@ -885,6 +933,15 @@ func (s *stateManager) HeaderByHeight(height uint64) (*proto.BlockHeader, error)
 	return s.Header(blockID)
 }
 
+func (s *stateManager) BlockHeaderWithTxCount(height proto.Height) (*proto.BlockHeader, int, error) {
+	header, err := s.HeaderByHeight(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	// The transactions count is stored along with the header, so there is no need to read the block's body.
+	return header, header.TransactionCount, nil
+}
+
 func (s *stateManager) Block(blockID proto.BlockID) (*proto.Block, error) {
 	block, err := s.rw.readBlock(blockID)
 	if err != nil {
@@ -931,6 +988,14 @@ func (s *stateManager) NewestAccountHasScript(addr proto.WavesAddress) (bool, er
 	return s.stor.scriptsStorage.newestAccountHasScript(addr)
 }
 
+func (s *stateManager) NewestAccountIsDApp(addr proto.WavesAddress) (bool, error) {
+	isDApp, err := s.stor.scriptsStorage.newestAccountHasCallables(addr)
+	if err != nil {
+		return false, wrapErr(RetrievalError, err)
+	}
+	return isDApp, nil
+}
+
 func (s *stateManager) AddingBlockHeight() (uint64, error) {
 	return s.rw.addingBlockHeight(), nil
 }
@@ -1024,6 +1089,21 @@ func (s *stateManager) GeneratingBalance(account proto.Recipient, height proto.H
 	return s.stor.balances.generatingBalance(addr.ID(), height)
 }
 
+func (s *stateManager) GeneratingBalances(
+	height proto.Height,
+	addrs []proto.WavesAddress,
+) (map[proto.WavesAddress]uint64, error) {
+	r := make(map[proto.WavesAddress]uint64, len(addrs))
+	for _, addr := range addrs {
+		gb, err := s.stor.balances.generatingBalance(addr.ID(), height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get generating balance of address %s", addr.String())
+		}
+		r[addr] = gb
+	}
+	return r, nil
+}
+
 // NewestMinerGeneratingBalance returns the generating balance of the miner at the given height.
 // This method includes the challenger bonus if the block has a challenged header.
 func (s *stateManager) NewestMinerGeneratingBalance(header *proto.BlockHeader, height proto.Height) (uint64, error) {
@@ -1212,6 +1292,43 @@ func (s *stateManager) AssetBalance(account proto.Recipient, assetID proto.Asset
 	return balance, nil
 }
 
+// ExportBalancesCSV writes the non-zero balances of the asset (or Waves if assetID is nil) at the given height
+// as CSV rows "address,balance" preceded by the header row. Rows are written while iterating the storage.
+// The height must be within the rollback window, because older balances history is not retained.
+// Only the data committed to DB is read, so the export doesn't require the state lock. If the block at the height
+// is rolled back during the export, the error is returned and the written rows must be discarded.
+func (s *stateManager) ExportBalancesCSV(w io.Writer, assetID *proto.AssetID, height proto.Height) error {
+	// History of balances is kept only for the heights available for rollback.
+	if err := s.checkRollbackHeight(height); err != nil {
+		return wrapErr(InvalidInputError, err)
+	}
+	blockID, err := s.rw.blockIDByHeight(height)
+	if err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	cw := csv.NewWriter(w)
+	if wErr := cw.Write([]string{"address", "balance"}); wErr != nil {
+		return wrapErr(Other, wErr)
+	}
+	err = s.stor.balances.balancesAtHeight(assetID, height, func(addr proto.WavesAddress, balance uint64) error {
+		return cw.Write([]string{addr.String(), strconv.FormatUint(balance, 10)})
+	})
+	if err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	cw.Flush()
+	if fErr := cw.Error(); fErr != nil {
+		return wrapErr(Other, fErr)
+	}
+	if cErr := s.checkRollbackHeight(height); cErr != nil {
+		return wrapErr(Other, errors.Wrap(cErr, "balances history changed during export"))
+	}
+	if id, idErr := s.rw.blockIDByHeight(height); idErr != nil || id != blockID {
+		return wrapErr(Other, errors.Errorf("block at height %d was rolled back during export", height))
+	}
+	return nil
+}
+
 func (s *stateManager) WavesAddressesNumber() (uint64, error) {
 	res, err := s.stor.balances.wavesAddressesNumber()
 	if err != nil {
@@ -2174,6 +2291,31 @@ func (s *stateManager) ValidateNextTx(
 	return s.appender.validateNextTx(tx, currentTimestamp, parentTimestamp, v, acceptFailed)
 }
 
+// SelectNonConflicting dry-runs the transactions in the given order on top of the committed state and
+// splits them into the transactions which can be applied together and the rejected ones.
+// Changes made by the dry-run are discarded. It uses the UTX validation list, so like ValidateNextTx it must be called
+// under the state lock, that is taken by ThreadSafeWriteWrapper and shared with the miner.
+func (s *stateManager) SelectNonConflicting(
+	txs []proto.Transaction, currentTimestamp uint64,
+) ([]proto.Transaction, []proto.Transaction, error) {
+	s.ResetValidationList() // start from the committed state
+	defer s.ResetValidationList()
+	lastBlock := s.TopBlock()
+	var accepted, rejected []proto.Transaction
+	for _, tx := range txs {
+		_, err := s.ValidateNextTx(tx, currentTimestamp, lastBlock.Timestamp, lastBlock.Version, false)
+		switch {
+		case err == nil:
+			accepted = append(accepted, tx)
+		case IsTxCommitmentError(err):
+			return nil, nil, errors.Wrap(err, "failed to select non-conflicting transactions")
+		default:
+			rejected = append(rejected, tx)
+		}
+	}
+	return accepted, rejected, nil
+}
+
 func (s *stateManager) CreateNextSnapshotHash(block *proto.Block) (crypto.Digest, error) {
 	blockchainHeight, err := s.Height()
 	if err != nil {
@@ -2222,6 +2364,21 @@ func (s *stateManager) AddrByAlias(alias proto.Alias) (proto.WavesAddress, error
 	return addr, nil
 }
 
+func (s *stateManager) AddressByAliasAt(alias proto.Alias, height proto.Height) (proto.WavesAddress, error) {
+	addr, err := s.stor.aliases.addrByAliasAt(alias.Alias, height)
+	if err != nil {
+		if errors.Is(err, errHeightNotRetained) {
+			return proto.WavesAddress{}, wrapErr(InvalidInputError, err)
+		}
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return proto.WavesAddress{}, wrapErr(NotFoundError,
+				errors.Wrapf(err, "alias '%s' is not registered at height %d", alias.Alias, height))
+		}
+		return proto.WavesAddress{}, wrapErr(RetrievalError, err)
+	}
+	return addr, nil
+}
+
 func (s *stateManager) AliasesByAddr(addr proto.WavesAddress) ([]string, error) {
 	aliases, err := s.stor.aliases.aliasesByAddr(addr)
 	if err != nil {
@@ -2230,6 +2387,30 @@ func (s *stateManager) AliasesByAddr(addr proto.WavesAddress) ([]string, error)
 	return aliases, nil
 }
 
+func (s *stateManager) AssetsIssuedBy(addr proto.WavesAddress) ([]proto.AssetID, error) {
+	ids, err := s.stor.assets.assetsIssuedBy(addr)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return ids, nil
+}
+
+func (s *stateManager) AccountStateSize(addr proto.WavesAddress) (int64, error) {
+	dataSize, err := s.stor.accountsDataStor.entriesStateSize(addr)
+	if err != nil {
+		return 0, wrapErr(RetrievalError, errors.Wrap(err, "failed to calculate size of data entries"))
+	}
+	scriptSize, err := s.stor.scriptsStorage.accountScriptStateSize(addr)
+	if err != nil {
+		return 0, wrapErr(RetrievalError, errors.Wrap(err, "failed to calculate size of account script"))
+	}
+	balancesSize, err := s.stor.balances.balancesStateSize(addr.ID())
+	if err != nil {
+		return 0, wrapErr(RetrievalError, errors.Wrap(err, "failed to calculate size of balances"))
+	}
+	return dataSize + scriptSize + balancesSize, nil
+}
+
 func (s *stateManager) VotesNumAtHeight(featureID int16, height proto.Height) (uint64, error) {
 	votesNum, err := s.stor.features.featureVotesAtHeight(featureID, height)
 	if err != nil {
@@ -2278,6 +2459,17 @@ func (s *stateManager) ActivationHeight(featureID int16) (uint64, error) {
 	return height, nil
 }
 
+// SetFeatureActivationHeight sets the feature activated at the given height bypassing the voting.
+// The override isn't persisted and affects only the current instance of the state. It's intended only for tests
+// and fails unless the state is opened with StateParams.UnsafeAllowFeatureActivationOverride.
+func (s *stateManager) SetFeatureActivationHeight(featureID int16, height proto.Height) error {
+	if !s.allowFeatureActivationOverride {
+		return errors.New("override of features activation is not allowed")
+	}
+	s.stor.features.overrideActivationHeight(featureID, height)
+	return nil
+}
+
 func (s *stateManager) IsApproved(featureID int16) (bool, error) {
 	approved, err := s.stor.features.isApproved(featureID)
 	if err != nil {
@@ -2306,6 +2498,53 @@ func (s *stateManager) AllFeatures() ([]int16, error) {
 	return features, nil
 }
 
+func (s *stateManager) PendingFeatures(height proto.Height) ([]FeatureVoteStatus, error) {
+	maxHeight, err := s.Height()
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	if height < 1 || height > maxHeight {
+		return nil, wrapErr(InvalidInputError,
+			errors.Errorf("PendingFeatures: height %d out of valid range [1, %d]", height, maxHeight))
+	}
+	features, err := s.stor.features.allFeatures()
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	slices.Sort(features)
+	// Voting period ends at the height before the multiple of window size, votes are reset at the next block.
+	window := s.settings.ActivationWindowSize(height)
+	periodStart := height - height%window
+	threshold := s.settings.VotesForFeatureElection(height)
+	var res []FeatureVoteStatus
+	for _, id := range features {
+		if periodStart > 0 && s.stor.features.isActivatedAtHeight(id, periodStart-1) {
+			continue // activated before the current voting period
+		}
+		votes, err := s.stor.features.featureVotesAtHeight(id, height)
+		if err != nil {
+			return nil, wrapErr(RetrievalError, err)
+		}
+		st := FeatureVoting
+		switch {
+		case s.stor.features.isActivatedAtHeight(id, height):
+			st = FeatureActivated
+		case s.stor.features.isApprovedAtHeight(id, height):
+			st = FeatureApproved
+		case votes == 0:
+			continue // not voted for in the current period
+		}
+		res = append(res, FeatureVoteStatus{
+			FeatureID:       id,
+			Votes:           votes,
+			Threshold:       threshold,
+			Status:          st,
+			BlocksRemaining: periodStart + window - 1 - height,
+		})
+	}
+	return res, nil
+}
+
 func (s *stateManager) EstimatorVersion() (int, error) {
 	rideV6, err := s.IsActivated(int16(settings.RideV6))
 	if err != nil {
@@ -2367,6 +2606,24 @@ func (s *stateManager) RetrieveEntries(account proto.Recipient) ([]proto.DataEnt
 	return entries, nil
 }
 
+func (s *stateManager) AllDataEntries(addr proto.WavesAddress) ([]proto.DataEntry, error) {
+	entries, err := s.RetrieveEntries(proto.NewRecipientFromAddress(addr))
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil, nil // no data was saved for the address
+		}
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *stateManager) IterateDataEntries(addr proto.WavesAddress, fn func(entry proto.DataEntry) error) error {
+	if err := s.stor.accountsDataStor.iterateEntries(addr, fn); err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	return nil
+}
+
 func (s *stateManager) IsStateUntouched(account proto.Recipient) (bool, error) {
 	addr, err := s.recipientToAddress(account)
 	if err != nil {
@@ -2552,6 +2809,51 @@ func (s *stateManager) NewAddrTransactionsIterator(addr proto.Address) (Transact
 	return iter, nil
 }
 
+func (s *stateManager) AddressTransactions(
+	addr proto.WavesAddress, limit int, after *crypto.Digest,
+) ([]proto.Transaction, error) {
+	if limit <= 0 {
+		return nil, wrapErr(InvalidInputError, errors.Errorf("invalid limit %d", limit))
+	}
+	iter, err := s.NewAddrTransactionsIterator(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+	return transactionsPage(iter, s.settings.AddressSchemeCharacter, limit, after)
+}
+
+// transactionsPage collects up to limit transactions from the iterator skipping all transactions up to and including
+// the transaction with ID after. Transactions with IDs that are not digests (like Payments) can't be used as cursor.
+func transactionsPage(
+	iter TransactionIterator, scheme proto.Scheme, limit int, after *crypto.Digest,
+) ([]proto.Transaction, error) {
+	found := after == nil
+	res := make([]proto.Transaction, 0)
+	for len(res) < limit && iter.Next() {
+		tx, _, txErr := iter.Transaction()
+		if txErr != nil {
+			return nil, wrapErr(RetrievalError, txErr)
+		}
+		if !found {
+			id, idErr := tx.GetID(scheme)
+			if idErr != nil {
+				return nil, wrapErr(Other, idErr)
+			}
+			found = bytes.Equal(id, after.Bytes())
+			continue
+		}
+		res = append(res, tx)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	if !found {
+		return nil, wrapErr(NotFoundError, errors.Errorf("transaction %s not found", after.String()))
+	}
+	return res, nil
+}
+
 func (s *stateManager) NewestAssetIsSponsored(asset crypto.Digest) (bool, error) {
 	assetID := proto.AssetIDFromDigest(asset)
 	sponsored, err := s.stor.sponsoredAssets.newestIsSponsored(assetID)
@@ -2725,6 +3027,52 @@ func (s *stateManager) AssetInfo(assetID proto.AssetID) (*proto.AssetInfo, error
 	}, nil
 }
 
+// AssetSupply returns the total supply of the asset, which is the issued quantity adjusted by all reissues and
+// burns, and the circulating supply, which is the total supply minus the balance of the asset's issuer.
+func (s *stateManager) AssetSupply(assetID proto.AssetID) (uint64, uint64, error) {
+	info, err := s.stor.assets.assetInfo(assetID)
+	if err != nil {
+		if errors.Is(err, errs.UnknownAsset{}) {
+			return 0, 0, wrapErr(NotFoundError, err)
+		}
+		return 0, 0, wrapErr(RetrievalError, err)
+	}
+	if !info.quantity.IsUint64() {
+		return 0, 0, wrapErr(Other, errors.New("asset quantity overflows uint64"))
+	}
+	total := info.quantity.Uint64()
+	issuer, err := proto.NewAddressFromPublicKey(s.settings.AddressSchemeCharacter, info.Issuer)
+	if err != nil {
+		return 0, 0, wrapErr(Other, err)
+	}
+	issuerBalance, err := s.stor.balances.assetBalance(issuer.ID(), assetID)
+	if err != nil {
+		return 0, 0, wrapErr(RetrievalError, err)
+	}
+	if issuerBalance > total {
+		return 0, 0, wrapErr(Other, errors.Errorf("issuer balance %d exceeds asset quantity %d", issuerBalance, total))
+	}
+	return total, total - issuerBalance, nil
+}
+
+// LeaseTotals returns the sums of amounts leased out by the address and leased to the address by the leases which
+// are active at the given height. The sums are the leasing components of the address effective balance.
+func (s *stateManager) LeaseTotals(addr proto.WavesAddress, height proto.Height) (uint64, uint64, error) {
+	maxHeight, err := s.Height()
+	if err != nil {
+		return 0, 0, wrapErr(RetrievalError, err)
+	}
+	if height < 1 || height > maxHeight {
+		return 0, 0, wrapErr(InvalidInputError,
+			errors.Errorf("LeaseTotals: height %d out of valid range [1, %d]", height, maxHeight))
+	}
+	out, in, err := s.stor.leases.leaseTotalsAtHeight(s.settings.AddressSchemeCharacter, addr, height)
+	if err != nil {
+		return 0, 0, wrapErr(RetrievalError, err)
+	}
+	return out, in, nil
+}
+
 func (s *stateManager) FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error) {
 	ai, err := s.AssetInfo(assetID)
 	if err != nil {
@@ -2885,6 +3233,143 @@ func (s *stateManager) ScriptInfoByAsset(assetID proto.AssetID) (*proto.ScriptIn
 	}, nil
 }
 
+func (s *stateManager) IterateStoredScripts(ctx context.Context, fn func(sc StoredScript) error) error {
+	err := s.stor.scriptsStorage.iterateScriptsBytes(ctx, func(key scriptKey, script proto.Script) error {
+		sc := StoredScript{Script: script}
+		switch k := key.(type) {
+		case *accountScriptKey:
+			addr, err := k.addr.ToWavesAddress(s.settings.AddressSchemeCharacter)
+			if err != nil {
+				return err
+			}
+			sc.Address = &addr
+		case *assetScriptKey:
+			assetID := k.assetID
+			sc.AssetID = &assetID
+		default:
+			return errors.Errorf("unexpected script key type %T", key)
+		}
+		return fn(sc)
+	})
+	if err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	return nil
+}
+
+// reestimationBatchSize is the number of scripts re-estimated and saved at once.
+const reestimationBatchSize = 100
+
+type scriptsReestimator interface {
+	ReestimateScripts(ctx context.Context, estimatorVersion int, from []byte, limit int) (ScriptsReestimation, error)
+}
+
+func reestimateAllScripts(
+	ctx context.Context, r scriptsReestimator, estimatorVersion int, progress func(done, total int),
+) error {
+	var (
+		errs []error
+		from []byte
+		done int
+	)
+	for {
+		res, err := r.ReestimateScripts(ctx, estimatorVersion, from, reestimationBatchSize)
+		errs = append(errs, res.Failures...)
+		done += res.Processed
+		if progress != nil && res.Processed > 0 {
+			progress(done, res.Total)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if res.Next == nil {
+			break
+		}
+		from = res.Next
+	}
+	if len(errs) > 0 {
+		return wrapErr(ModificationError, stderrs.Join(errs...))
+	}
+	return nil
+}
+
+func (s *stateManager) ReestimateAllScripts(
+	ctx context.Context, estimatorVersion int, progress func(done, total int),
+) error {
+	return reestimateAllScripts(ctx, s, estimatorVersion, progress)
+}
+
+func (s *stateManager) ReestimateScripts(
+	ctx context.Context, estimatorVersion int, from []byte, limit int,
+) (ScriptsReestimation, error) {
+	if estimatorVersion < 1 || estimatorVersion > maxEstimatorVersion {
+		return ScriptsReestimation{}, wrapErr(InvalidInputError,
+			errors.Errorf("invalid estimator version %d", estimatorVersion),
+		)
+	}
+	if limit < 1 {
+		return ScriptsReestimation{}, wrapErr(InvalidInputError, errors.Errorf("invalid limit %d", limit))
+	}
+	keys, err := s.stor.scriptsStorage.scriptsKeys(ctx)
+	if err != nil {
+		return ScriptsReestimation{}, wrapErr(RetrievalError, err)
+	}
+	res := ScriptsReestimation{Total: len(keys)}
+	blockID := s.TopBlock().BlockID()
+	var cancelErr error
+	for _, key := range keys {
+		if bytes.Compare(key.bytes(), from) < 0 {
+			continue // processed in the previous batches
+		}
+		if res.Processed == limit {
+			res.Next = key.bytes()
+			break
+		}
+		if cErr := ctx.Err(); cErr != nil {
+			cancelErr = cErr
+			break
+		}
+		if rErr := s.reestimateScript(key, estimatorVersion, blockID); rErr != nil {
+			res.Failures = append(res.Failures,
+				errors.Wrapf(rErr, "failed to re-estimate script by key %x", key.bytes()),
+			)
+		}
+		res.Processed++
+	}
+	// Re-estimated complexities of the batch are saved at once, they belong to the top block.
+	if fErr := s.flush(); fErr != nil {
+		s.reset()
+		return ScriptsReestimation{}, wrapErr(ModificationError, fErr)
+	}
+	s.reset()
+	if cancelErr != nil {
+		return res, cancelErr
+	}
+	return res, nil
+}
+
+// reestimateScript estimates the newest script with the given estimator version and adds the new complexities
+// at the given block. Scripts already estimated with the version are skipped, that makes the re-estimation
+// resumable after an interruption.
+func (s *stateManager) reestimateScript(key scriptKey, estimatorVersion int, blockID proto.BlockID) error {
+	version, err := s.stor.scriptsComplexity.estimatorVersionByScriptKey(key)
+	if err != nil {
+		if isNotFoundInHistoryOrDBErr(err) {
+			return nil // the script has no complexities stored
+		}
+		return err
+	}
+	if version == estimatorVersion {
+		return nil
+	}
+	script, err := s.stor.hs.newestTopEntryData(key.bytes())
+	if err != nil {
+		return err
+	}
+	return s.stor.scriptsComplexity.reestimate(key, script, estimatorVersion, blockID)
+}
+
 func (s *stateManager) NewestScriptInfoByAsset(assetID proto.AssetID) (*proto.ScriptInfo, error) {
 	scriptBytes, err := s.stor.scriptsStorage.newestScriptBytesByAsset(assetID)
 	if err != nil {
@@ -3097,6 +3582,105 @@ func (s *stateManager) SnapshotsAtHeight(height proto.Height) (proto.BlockSnapsh
 	return s.stor.snapshots.getSnapshots(height)
 }
 
+func (s *stateManager) BlockSnapshot(blockID proto.BlockID) (*proto.BlockSnapshot, error) {
+	height, err := s.BlockIDToHeight(blockID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.stor.snapshots.getSnapshots(height)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return &snapshot, nil
+}
+
+func (s *stateManager) BlockDataChanges(blockID proto.BlockID) (map[proto.WavesAddress][]proto.DataEntry, error) {
+	snapshot, err := s.BlockSnapshot(blockID)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[proto.WavesAddress][]proto.DataEntry)
+	positions := make(map[proto.WavesAddress]map[string]int) // positions of keys in the lists of changes
+	for _, ts := range snapshot.TxSnapshots {
+		for _, e := range SnapshotsToEvents(ts, crypto.Digest{}) {
+			de, ok := e.(DataEntriesEvent)
+			if !ok {
+				continue
+			}
+			keys, ok := positions[de.Address]
+			if !ok {
+				keys = make(map[string]int)
+				positions[de.Address] = keys
+			}
+			for _, entry := range de.Entries {
+				if i, ok := keys[entry.GetKey()]; ok {
+					changes[de.Address][i] = entry
+					continue
+				}
+				keys[entry.GetKey()] = len(changes[de.Address])
+				changes[de.Address] = append(changes[de.Address], entry)
+			}
+		}
+	}
+	return changes, nil
+}
+
+func (s *stateManager) BlockBalanceChanges(
+	blockID proto.BlockID, assetID *proto.AssetID,
+) (map[proto.WavesAddress][2]uint64, error) {
+	snapshot, err := s.BlockSnapshot(blockID)
+	if err != nil {
+		return nil, err
+	}
+	height, err := s.BlockIDToHeight(blockID)
+	if err != nil {
+		return nil, err
+	}
+	// Balances before the block are read from the history, which is kept only for the rollback window.
+	if height > 1 {
+		minHeight, mhErr := s.stateDB.getRollbackMinHeight()
+		if mhErr != nil {
+			return nil, wrapErr(RetrievalError, mhErr)
+		}
+		if height-1 < minHeight {
+			return nil, wrapErr(InvalidInputError, errors.Errorf(
+				"block at height %d is out of the rollback window starting at height %d", height, minHeight+1))
+		}
+	}
+	// Snapshots hold the resulting balances, so only the last balance of each address is kept.
+	after := make(map[proto.WavesAddress]uint64)
+	for _, ts := range snapshot.TxSnapshots {
+		for _, as := range ts {
+			switch sn := as.(type) {
+			case *proto.WavesBalanceSnapshot:
+				if assetID == nil {
+					after[sn.Address] = sn.Balance
+				}
+			case *proto.AssetBalanceSnapshot:
+				if assetID != nil && proto.AssetIDFromDigest(sn.AssetID) == *assetID {
+					after[sn.Address] = sn.Balance
+				}
+			}
+		}
+	}
+	changes := make(map[proto.WavesAddress][2]uint64, len(after))
+	for addr, balance := range after {
+		var before uint64
+		if height > 1 {
+			if assetID == nil {
+				before, err = s.stor.balances.wavesBalanceAtHeight(addr.ID(), height-1)
+			} else {
+				before, err = s.stor.balances.assetBalanceAtHeight(addr.ID(), *assetID, height-1)
+			}
+			if err != nil {
+				return nil, wrapErr(RetrievalError, err)
+			}
+		}
+		changes[addr] = [2]uint64{before, balance}
+	}
+	return changes, nil
+}
+
 func (s *stateManager) Close() error {
 	if err := s.atx.close(); err != nil {
 		return wrapErr(ClosureError, err)
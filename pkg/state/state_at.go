@@ -0,0 +1,98 @@
+package state
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// stateAtHeight implements ReadOnlyState by reading the versioned storages with the height fixed.
+type stateAtHeight struct {
+	s      *stateManager
+	height proto.Height
+}
+
+// StateAt returns a read-only view of the state at the given height. Only the heights of the rollback window
+// are accepted, the view fails with InvalidInputError if the window moves past its height later.
+func (s *stateManager) StateAt(height proto.Height) (ReadOnlyState, error) {
+	if err := s.checkRollbackHeight(height); err != nil {
+		return nil, wrapErr(InvalidInputError, errors.Wrap(err, "StateAt"))
+	}
+	return &stateAtHeight{s: s, height: height}, nil
+}
+
+// heightErr wraps the error of reading at the view height.
+func (v *stateAtHeight) heightErr(err error) error {
+	if errors.Is(err, errHeightNotRetained) {
+		return wrapErr(InvalidInputError, err)
+	}
+	return wrapErr(RetrievalError, err)
+}
+
+func (v *stateAtHeight) Height() proto.Height {
+	return v.height
+}
+
+func (v *stateAtHeight) recipientToAddress(recipient proto.Recipient) (proto.WavesAddress, error) {
+	if addr := recipient.Address(); addr != nil {
+		return *addr, nil
+	}
+	return v.s.stor.aliases.addrByAliasAt(recipient.Alias().Alias, v.height)
+}
+
+func (v *stateAtHeight) WavesBalance(account proto.Recipient) (uint64, error) {
+	addr, err := v.recipientToAddress(account)
+	if err != nil {
+		return 0, v.heightErr(err)
+	}
+	balance, err := v.s.stor.balances.wavesBalanceAtHeight(addr.ID(), v.height)
+	if err != nil {
+		return 0, v.heightErr(err)
+	}
+	return balance, nil
+}
+
+func (v *stateAtHeight) AssetBalance(account proto.Recipient, assetID proto.AssetID) (uint64, error) {
+	addr, err := v.recipientToAddress(account)
+	if err != nil {
+		return 0, v.heightErr(err)
+	}
+	balance, err := v.s.stor.balances.assetBalanceAtHeight(addr.ID(), assetID, v.height)
+	if err != nil {
+		return 0, v.heightErr(err)
+	}
+	return balance, nil
+}
+
+func (v *stateAtHeight) RetrieveEntry(account proto.Recipient, key string) (proto.DataEntry, error) {
+	addr, err := v.recipientToAddress(account)
+	if err != nil {
+		return nil, v.heightErr(err)
+	}
+	entry, err := v.s.stor.accountsDataStor.retrieveEntryAtHeight(addr, key, v.height)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil, wrapErr(NotFoundError,
+				errors.Wrapf(err, "entry '%s' doesn't exist at height %d", key, v.height))
+		}
+		return nil, v.heightErr(err)
+	}
+	return entry, nil
+}
+
+func (v *stateAtHeight) ScriptBytesByAccount(account proto.Recipient) (proto.Script, error) {
+	addr, err := v.recipientToAddress(account)
+	if err != nil {
+		return nil, v.heightErr(err)
+	}
+	script, err := v.s.stor.scriptsStorage.scriptBytesByAddrAtHeight(addr, v.height)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return nil, wrapErr(NotFoundError,
+				errors.Wrapf(err, "account '%s' has no script at height %d", account.String(), v.height))
+		}
+		return nil, v.heightErr(err)
+	}
+	return script, nil
+}
@@ -0,0 +1,135 @@
+package state
+
+import (
+	stderrs "errors"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// BlockSnapshots is a block along with the snapshot of its transactions and the snapshot state hashes
+// of the state before and after the application of the block.
+type BlockSnapshots struct {
+	Block           *proto.Block
+	Snapshot        *proto.BlockSnapshot
+	ParentStateHash crypto.Digest
+	StateHash       crypto.Digest
+}
+
+// StateDelta returns the blocks and snapshots applied to the state after the height 'from' up to
+// the height 'to' inclusively. The delta could be applied with ApplyStateDelta to a state at the height 'from'.
+func (s *stateManager) StateDelta(from, to proto.Height) ([]BlockSnapshots, error) {
+	topHeight, err := s.Height()
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	if from < 1 || from >= to || to > topHeight {
+		return nil, wrapErr(InvalidInputError,
+			errors.Errorf("invalid delta heights range [%d, %d], blockchain height is %d", from, to, topHeight),
+		)
+	}
+	parentHash, err := s.SnapshotStateHashAtHeight(from)
+	if err != nil {
+		return nil, err
+	}
+	delta := make([]BlockSnapshots, 0, to-from)
+	for h := from + 1; h <= to; h++ {
+		block, bErr := s.BlockByHeight(h)
+		if bErr != nil {
+			return nil, bErr
+		}
+		snapshot, sErr := s.SnapshotsAtHeight(h)
+		if sErr != nil {
+			return nil, wrapErr(RetrievalError, errors.Wrapf(sErr, "failed to get snapshot at height %d", h))
+		}
+		sh, hErr := s.SnapshotStateHashAtHeight(h)
+		if hErr != nil {
+			return nil, hErr
+		}
+		delta = append(delta, BlockSnapshots{
+			Block:           block,
+			Snapshot:        &snapshot,
+			ParentStateHash: parentHash,
+			StateHash:       sh,
+		})
+		parentHash = sh
+	}
+	return delta, nil
+}
+
+// ApplyStateDelta applies the delta produced by StateDelta. The first block of the delta must reference the top
+// block and the snapshot state hash of the current state must match the starting state hash of the delta.
+// After the application the resulting snapshot state hash is compared with the final state hash of the delta,
+// on mismatch or failed application the state is rolled back to the height it had before the call.
+//
+// Note that the state hashes are taken from the delta itself, so the checks detect a corrupted or inconsistent
+// delta, but not a forged one. The caller must compare the resulting state hash with the one obtained from
+// a trusted source, unless the blocks commit to their state hashes after the LightNode feature activation.
+func (s *stateManager) ApplyStateDelta(delta []BlockSnapshots) error {
+	if len(delta) == 0 {
+		return nil
+	}
+	height, err := s.Height()
+	if err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	top := s.TopBlock()
+	if parent := delta[0].Block.Parent; parent != top.BlockID() {
+		return wrapErr(InvalidInputError, errors.Errorf(
+			"delta parent block %s doesn't match top block %s at height %d",
+			parent.String(), top.BlockID().String(), height,
+		))
+	}
+	sh, err := s.SnapshotStateHashAtHeight(height)
+	if err != nil {
+		return err
+	}
+	if first := delta[0].ParentStateHash; sh != first {
+		return wrapErr(InvalidInputError, errors.Errorf(
+			"state hash mismatch at height %d: delta starts from %s, actual %s",
+			height, first.String(), sh.String(),
+		))
+	}
+	blocks := make([]*proto.Block, len(delta))
+	snapshots := make([]*proto.BlockSnapshot, len(delta))
+	for i := range delta {
+		if i > 0 && delta[i].ParentStateHash != delta[i-1].StateHash {
+			return wrapErr(InvalidInputError, errors.Errorf("delta is not continuous at block %d", i))
+		}
+		blocks[i] = delta[i].Block
+		snapshots[i] = delta[i].Snapshot
+	}
+	if _, aErr := s.AddDeserializedBlocksWithSnapshots(blocks, snapshots); aErr != nil {
+		return s.rollbackStateDelta(height, aErr)
+	}
+	finalHeight := height + uint64(len(delta))
+	actualHash, err := s.SnapshotStateHashAtHeight(finalHeight)
+	if err != nil {
+		return s.rollbackStateDelta(height, err)
+	}
+	if expected := delta[len(delta)-1].StateHash; actualHash != expected {
+		return s.rollbackStateDelta(height, wrapErr(Other, errors.Errorf(
+			"state hash mismatch after delta application at height %d: expected %s, actual %s",
+			finalHeight, expected.String(), actualHash.String(),
+		)))
+	}
+	return nil
+}
+
+// rollbackStateDelta rolls the state back to the height it had before the delta application and returns
+// the error of the application.
+func (s *stateManager) rollbackStateDelta(height proto.Height, applyErr error) error {
+	current, err := s.Height()
+	if err != nil {
+		return stderrs.Join(applyErr, wrapErr(RetrievalError, err))
+	}
+	if current == height { // nothing was applied
+		return applyErr
+	}
+	if rbErr := s.RollbackToHeight(height); rbErr != nil {
+		return stderrs.Join(applyErr, errors.Wrapf(rbErr, "failed to rollback to height %d", height))
+	}
+	return applyErr
+}
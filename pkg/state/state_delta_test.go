@@ -0,0 +1,89 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/importer"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func TestStateDelta(t *testing.T) {
+	const (
+		from = proto.Height(10)
+		to   = proto.Height(20)
+	)
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	newState := func(height proto.Height) *stateManager {
+		m := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+		err := importer.ApplyFromFile(
+			context.Background(),
+			importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+			m, height-1, 1,
+		)
+		require.NoError(t, err)
+		return m
+	}
+	src := newState(to)
+
+	_, err = src.StateDelta(from, to+1)
+	assert.True(t, IsInvalidInput(err))
+	_, err = src.StateDelta(from, from)
+	assert.True(t, IsInvalidInput(err))
+
+	delta, err := src.StateDelta(from, to)
+	require.NoError(t, err)
+	require.Len(t, delta, int(to-from))
+	for i, bs := range delta {
+		h := from + 1 + proto.Height(i)
+		block, bErr := src.BlockByHeight(h)
+		require.NoError(t, bErr)
+		assert.Equal(t, block.BlockID(), bs.Block.BlockID())
+		assert.Len(t, bs.Snapshot.TxSnapshots, len(block.Transactions))
+	}
+
+	dst := newState(from)
+	require.NoError(t, dst.ApplyStateDelta(delta))
+	dstHeight, err := dst.Height()
+	require.NoError(t, err)
+	assert.Equal(t, to, dstHeight)
+	srcHash, err := src.SnapshotStateHashAtHeight(to)
+	require.NoError(t, err)
+	dstHash, err := dst.SnapshotStateHashAtHeight(to)
+	require.NoError(t, err)
+	assert.Equal(t, srcHash, dstHash)
+
+	// The delta can't be applied to the state at the different height.
+	other := newState(from - 1)
+	err = other.ApplyStateDelta(delta)
+	assert.True(t, IsInvalidInput(err))
+	// The delta starting from the different state is rejected.
+	wrongStart := append([]BlockSnapshots(nil), delta...)
+	wrongStart[0].ParentStateHash = crypto.MustFastHash([]byte("wrong"))
+	err = newState(from).ApplyStateDelta(wrongStart)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "state hash mismatch")
+
+	// Corrupted final state hash is detected after the application.
+	corrupted := append([]BlockSnapshots(nil), delta...)
+	corrupted[len(corrupted)-1].StateHash = crypto.MustFastHash([]byte("corrupted"))
+	another := newState(from)
+	err = another.ApplyStateDelta(corrupted)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "state hash mismatch after delta application")
+	// The state is rolled back to the height before the application and the valid delta still applies.
+	anotherHeight, err := another.Height()
+	require.NoError(t, err)
+	assert.Equal(t, from, anotherHeight)
+	require.NoError(t, another.ApplyStateDelta(delta))
+	anotherHash, err := another.SnapshotStateHashAtHeight(to)
+	require.NoError(t, err)
+	assert.Equal(t, srcHash, anotherHash)
+}
@@ -0,0 +1,349 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	stderrs "errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+const (
+	stateSnapshotVersion = 1
+	// stateSnapshotBatchSize is the number of database records written at once on import.
+	stateSnapshotBatchSize = 10000
+)
+
+// Kinds of the state snapshot records.
+const (
+	stateSnapshotEnd byte = iota
+	stateSnapshotEntry
+	stateSnapshotFile
+)
+
+var stateSnapshotMagic = []byte("WSTS")
+
+// stateSnapshotFiles are the files of the blocks storage directory that are exported along with the database.
+// The bloom filter is not exported, it's rebuilt from the database on the first start.
+var stateSnapshotFiles = []string{"blockchain", "headers", "block_height_to_id", "address_transactions"}
+
+// ExportStateSnapshot writes the committed state at the given height to w. The snapshot includes all the records
+// of the state database, that is balances, data entries, scripts, leases, assets and their history within
+// the rollback window, and the blocks storage files, so the node started from the imported snapshot continues
+// from the same height. Only the current height is accepted, to export an older state roll it back first.
+//
+// The snapshot is a header with the scheme, the height and the snapshot state hash at the height, followed by
+// the records and the SHA-256 checksum of all the preceding bytes.
+func (s *stateManager) ExportStateSnapshot(w io.Writer, height proto.Height) error {
+	top, err := s.Height()
+	if err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	if height != top {
+		return wrapErr(InvalidInputError,
+			errors.Errorf("state snapshot can be exported only at the current height %d, requested %d", top, height),
+		)
+	}
+	sh, err := s.SnapshotStateHashAtHeight(height)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	h := sha256.New()
+	mw := io.MultiWriter(bw, h)
+	if err := writeStateSnapshotHeader(mw, s.settings.AddressSchemeCharacter, height, sh); err != nil {
+		return wrapErr(Other, err)
+	}
+	if err := s.exportStateSnapshotEntries(mw); err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	for _, name := range stateSnapshotFiles {
+		if err := exportStateSnapshotFile(mw, s.blockStorageDir, name); err != nil {
+			return wrapErr(RetrievalError, err)
+		}
+	}
+	if _, err := mw.Write([]byte{stateSnapshotEnd}); err != nil {
+		return wrapErr(Other, err)
+	}
+	if _, err := bw.Write(h.Sum(nil)); err != nil {
+		return wrapErr(Other, err)
+	}
+	if err := bw.Flush(); err != nil {
+		return wrapErr(Other, err)
+	}
+	return nil
+}
+
+func (s *stateManager) exportStateSnapshotEntries(w io.Writer) (err error) {
+	iter, err := s.stor.hs.db.NewKeyIterator(nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create iterator")
+	}
+	defer func() {
+		iter.Release()
+		if itErr := iter.Error(); itErr != nil {
+			err = stderrs.Join(err, errors.Wrap(itErr, "iterator error"))
+		}
+	}()
+	for iter.Next() {
+		if wErr := writeStateSnapshotEntry(w, iter.Key(), iter.Value()); wErr != nil {
+			return wErr
+		}
+	}
+	return nil
+}
+
+func exportStateSnapshotFile(w io.Writer, dir, name string) error {
+	f, err := os.Open(filepath.Clean(filepath.Join(dir, name)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // Address transactions file is absent if the extended API is provided.
+		}
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := uint64(info.Size()) // #nosec: file size is non-negative
+	hdr := make([]byte, 0, 1+4+len(name)+8)
+	hdr = append(hdr, stateSnapshotFile)
+	hdr = binary.BigEndian.AppendUint32(hdr, uint32(len(name))) // #nosec: names are short constants
+	hdr = append(hdr, name...)
+	hdr = binary.BigEndian.AppendUint64(hdr, size)
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(w, f, info.Size()); err != nil {
+		return errors.Wrapf(err, "failed to export file '%s'", name)
+	}
+	return nil
+}
+
+func writeStateSnapshotHeader(w io.Writer, scheme proto.Scheme, height proto.Height, sh crypto.Digest) error {
+	hdr := make([]byte, 0, len(stateSnapshotMagic)+2+8+crypto.DigestSize)
+	hdr = append(hdr, stateSnapshotMagic...)
+	hdr = append(hdr, stateSnapshotVersion, scheme)
+	hdr = binary.BigEndian.AppendUint64(hdr, height)
+	hdr = append(hdr, sh.Bytes()...)
+	_, err := w.Write(hdr)
+	return err
+}
+
+func writeStateSnapshotEntry(w io.Writer, key, value []byte) error {
+	rec := make([]byte, 0, 1+4+len(key)+4+len(value))
+	rec = append(rec, stateSnapshotEntry)
+	rec = binary.BigEndian.AppendUint32(rec, uint32(len(key))) // #nosec: database keys are shorter than 4 GiB
+	rec = append(rec, key...)
+	rec = binary.BigEndian.AppendUint32(rec, uint32(len(value))) // #nosec: database values are shorter than 4 GiB
+	rec = append(rec, value...)
+	_, err := w.Write(rec)
+	return err
+}
+
+// ImportStateSnapshot restores the state exported with ExportStateSnapshot into the data directory, which must be
+// absent or empty. Unlike the other modifications, the import replaces the whole state, so it's done before the
+// state is opened with NewState on the same directory, params and settings.
+//
+// The checksum of the snapshot is verified after reading, then the restored state is opened and its height and
+// snapshot state hash are compared with the ones of the snapshot header. On error the content of the directory
+// is incomplete and must be removed.
+func ImportStateSnapshot(r io.Reader, dataDir string, params StateParams, settings *settings.BlockchainSettings) error {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return wrapErr(Other, err)
+	}
+	if len(entries) > 0 {
+		return wrapErr(InvalidInputError, errors.Errorf("state directory '%s' is not empty", dataDir))
+	}
+	blockStorageDir := filepath.Join(dataDir, blocksStorDir)
+	if err := os.MkdirAll(blockStorageDir, 0750); err != nil {
+		return wrapErr(Other, errors.Wrap(err, "failed to create blocks directory"))
+	}
+	br := bufio.NewReader(r)
+	h := sha256.New()
+	tr := io.TeeReader(br, h)
+	height, sh, err := readStateSnapshotHeader(tr, settings.AddressSchemeCharacter)
+	if err != nil {
+		return wrapErr(InvalidInputError, err)
+	}
+	if err := importStateSnapshotRecords(tr, dataDir, blockStorageDir, params); err != nil {
+		return err
+	}
+	checksum := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(br, checksum); err != nil {
+		return wrapErr(InvalidInputError, errors.Wrap(err, "failed to read state snapshot checksum"))
+	}
+	if !bytes.Equal(checksum, h.Sum(nil)) {
+		return wrapErr(InvalidInputError, errors.New("state snapshot checksum mismatch"))
+	}
+	return verifyImportedState(dataDir, params, settings, height, sh)
+}
+
+func readStateSnapshotHeader(r io.Reader, scheme proto.Scheme) (proto.Height, crypto.Digest, error) {
+	hdr := make([]byte, len(stateSnapshotMagic)+2+8+crypto.DigestSize)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return 0, crypto.Digest{}, errors.Wrap(err, "failed to read state snapshot header")
+	}
+	if !bytes.Equal(hdr[:len(stateSnapshotMagic)], stateSnapshotMagic) {
+		return 0, crypto.Digest{}, errors.New("invalid state snapshot header")
+	}
+	hdr = hdr[len(stateSnapshotMagic):]
+	if v := hdr[0]; v != stateSnapshotVersion {
+		return 0, crypto.Digest{}, errors.Errorf("unsupported state snapshot version %d", v)
+	}
+	if s := hdr[1]; s != scheme {
+		return 0, crypto.Digest{}, errors.Errorf("state snapshot of scheme '%c' can't be imported with scheme '%c'",
+			s, scheme)
+	}
+	height := binary.BigEndian.Uint64(hdr[2:10])
+	sh, err := crypto.NewDigestFromBytes(hdr[10:])
+	if err != nil {
+		return 0, crypto.Digest{}, err
+	}
+	return height, sh, nil
+}
+
+func importStateSnapshotRecords(r io.Reader, dataDir, blockStorageDir string, params StateParams) (err error) {
+	params.DbParams.BloomFilterParams.Store.WithPath(filepath.Join(blockStorageDir, "bloom"))
+	db, err := keyvalue.NewKeyVal(filepath.Join(dataDir, keyvalueDir), params.DbParams)
+	if err != nil {
+		return wrapErr(Other, errors.Wrap(err, "failed to create db"))
+	}
+	defer func() {
+		if clErr := db.Close(); clErr != nil {
+			err = stderrs.Join(err, wrapErr(ClosureError, clErr))
+		}
+	}()
+	batch, err := db.NewBatch()
+	if err != nil {
+		return wrapErr(Other, errors.Wrap(err, "failed to create db batch"))
+	}
+	kind := make([]byte, 1)
+	for n := 1; ; n++ {
+		if _, rErr := io.ReadFull(r, kind); rErr != nil {
+			return wrapErr(InvalidInputError, errors.Wrap(rErr, "failed to read state snapshot record"))
+		}
+		switch kind[0] {
+		case stateSnapshotEnd:
+			if fErr := db.Flush(batch); fErr != nil {
+				return wrapErr(ModificationError, fErr)
+			}
+			return nil
+		case stateSnapshotEntry:
+			key, kErr := readStateSnapshotBytes(r)
+			if kErr != nil {
+				return wrapErr(InvalidInputError, kErr)
+			}
+			value, vErr := readStateSnapshotBytes(r)
+			if vErr != nil {
+				return wrapErr(InvalidInputError, vErr)
+			}
+			batch.Put(key, value)
+			if n%stateSnapshotBatchSize == 0 {
+				if fErr := db.Flush(batch); fErr != nil {
+					return wrapErr(ModificationError, fErr)
+				}
+			}
+		case stateSnapshotFile:
+			if fErr := importStateSnapshotFile(r, blockStorageDir); fErr != nil {
+				return fErr
+			}
+		default:
+			return wrapErr(InvalidInputError, errors.Errorf("unknown state snapshot record kind %d", kind[0]))
+		}
+	}
+}
+
+func importStateSnapshotFile(r io.Reader, dir string) (err error) {
+	name, err := readStateSnapshotBytes(r)
+	if err != nil {
+		return wrapErr(InvalidInputError, err)
+	}
+	if !slices.Contains(stateSnapshotFiles, string(name)) {
+		return wrapErr(InvalidInputError, errors.Errorf("unexpected file '%s' in state snapshot", name))
+	}
+	sizeBytes := make([]byte, 8)
+	if _, err := io.ReadFull(r, sizeBytes); err != nil {
+		return wrapErr(InvalidInputError, errors.Wrap(err, "failed to read file size"))
+	}
+	size := int64(binary.BigEndian.Uint64(sizeBytes)) // #nosec: the file is created by export with int64 size
+	f, err := os.OpenFile(filepath.Join(dir, string(name)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return wrapErr(Other, err)
+	}
+	defer func() {
+		if clErr := f.Close(); clErr != nil {
+			err = stderrs.Join(err, wrapErr(ClosureError, clErr))
+		}
+	}()
+	if _, err := io.CopyN(f, r, size); err != nil {
+		return wrapErr(InvalidInputError, errors.Wrapf(err, "failed to import file '%s'", name))
+	}
+	return nil
+}
+
+func readStateSnapshotBytes(r io.Reader) ([]byte, error) {
+	sizeBytes := make([]byte, 4)
+	if _, err := io.ReadFull(r, sizeBytes); err != nil {
+		return nil, errors.Wrap(err, "failed to read state snapshot record size")
+	}
+	b := make([]byte, binary.BigEndian.Uint32(sizeBytes))
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, errors.Wrap(err, "failed to read state snapshot record")
+	}
+	return b, nil
+}
+
+// verifyImportedState opens the restored state and checks its height and snapshot state hash.
+func verifyImportedState(
+	dataDir string,
+	params StateParams,
+	settings *settings.BlockchainSettings,
+	height proto.Height,
+	sh crypto.Digest,
+) (err error) {
+	s, err := newStateManager(dataDir, false, params, settings, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to open imported state")
+	}
+	defer func() {
+		if clErr := s.Close(); clErr != nil {
+			err = stderrs.Join(err, clErr)
+		}
+	}()
+	top, err := s.Height()
+	if err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	if top != height {
+		return wrapErr(InvalidInputError,
+			errors.Errorf("imported state height %d differs from the snapshot height %d", top, height),
+		)
+	}
+	imported, err := s.SnapshotStateHashAtHeight(height)
+	if err != nil {
+		return err
+	}
+	if imported != sh {
+		return wrapErr(InvalidInputError, errors.Errorf(
+			"imported state hash mismatch at height %d — snapshot '%s', imported '%s'", height, sh, imported),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/importer"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func TestExportImportStateSnapshot(t *testing.T) {
+	const height = proto.Height(20)
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	src := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		src, height-1, 1,
+	)
+	require.NoError(t, err)
+
+	err = src.ExportStateSnapshot(&bytes.Buffer{}, height-1)
+	assert.True(t, IsInvalidInput(err))
+
+	var buf bytes.Buffer
+	require.NoError(t, src.ExportStateSnapshot(&buf, height))
+	snapshot := buf.Bytes()
+
+	dataDir := filepath.Join(t.TempDir(), "imported")
+	require.NoError(t, ImportStateSnapshot(bytes.NewReader(snapshot), dataDir, DefaultTestingStateParams(), bs))
+	dst, err := newStateManager(dataDir, true, DefaultTestingStateParams(), bs, false)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dst.Close())
+	})
+
+	dstHeight, err := dst.Height()
+	require.NoError(t, err)
+	assert.Equal(t, height, dstHeight)
+	srcHash, err := src.SnapshotStateHashAtHeight(height)
+	require.NoError(t, err)
+	dstHash, err := dst.SnapshotStateHashAtHeight(height)
+	require.NoError(t, err)
+	assert.Equal(t, srcHash, dstHash)
+	assert.Equal(t, src.TopBlock().BlockID(), dst.TopBlock().BlockID())
+	generator, err := proto.NewAddressFromPublicKey(bs.AddressSchemeCharacter, src.TopBlock().GeneratorPublicKey)
+	require.NoError(t, err)
+	srcBalance, err := src.NewestWavesBalance(proto.NewRecipientFromAddress(generator))
+	require.NoError(t, err)
+	dstBalance, err := dst.NewestWavesBalance(proto.NewRecipientFromAddress(generator))
+	require.NoError(t, err)
+	assert.Positive(t, dstBalance)
+	assert.Equal(t, srcBalance, dstBalance)
+
+	// The snapshot is imported only into an empty directory.
+	err = ImportStateSnapshot(bytes.NewReader(snapshot), dataDir, DefaultTestingStateParams(), bs)
+	assert.True(t, IsInvalidInput(err))
+
+	// Corrupted snapshot is rejected by the checksum.
+	corrupted := bytes.Clone(snapshot)
+	corrupted[len(corrupted)-sha256.Size] ^= 0xff
+	err = ImportStateSnapshot(bytes.NewReader(corrupted), t.TempDir(), DefaultTestingStateParams(), bs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+
+	// Snapshot of the other network is rejected.
+	err = ImportStateSnapshot(bytes.NewReader(snapshot), t.TempDir(), DefaultTestingStateParams(),
+		settings.MustTestNetSettings())
+	assert.True(t, IsInvalidInput(err))
+}
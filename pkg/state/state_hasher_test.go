@@ -1,6 +1,8 @@
 package state
 
 import (
+	"encoding/binary"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -199,3 +201,100 @@ func TestLegacyStateHashSupport(t *testing.T) {
 	_, assetFoundBshRecord = assetsTmpSHRecords.componentByKey[string(assetKeyB.bytes())]
 	assert.True(t, assetFoundBshRecord)
 }
+
+// fillHashesComponents pushes the same n components to every legacy state hash category, the components are
+// spread evenly over the given blocks in order.
+func fillHashesComponents(t testing.TB, entities *blockchainEntitiesStorage, blockIDs []proto.BlockID, n int) {
+	hashers := []*stateHasher{
+		entities.accountsDataStor.hasher,
+		entities.scriptsStorage.getAccountScriptsHasher(),
+		entities.scriptsStorage.getAssetScriptsHasher(),
+		entities.leases.hasher,
+		entities.sponsoredAssets.hasher,
+		entities.aliases.hasher,
+	}
+	states := []map[proto.BlockID]*stateForHashes{
+		entities.balances.wavesHashesState,
+		entities.balances.assetsHashesState,
+		entities.balances.leaseHashesState,
+	}
+	for i := 0; i < n; i++ {
+		var pk [crypto.PublicKeySize]byte
+		binary.BigEndian.PutUint64(pk[:], uint64(i))
+		addr, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, crypto.PublicKey(pk))
+		require.NoError(t, err)
+		blockID := blockIDs[i*len(blockIDs)/n]
+		key := strconv.Itoa(i)
+		for _, h := range hashers {
+			require.NoError(t, h.push(key, &wavesRecordForHashes{addr: &addr, balance: uint64(i)}, blockID))
+		}
+		for _, st := range states {
+			if _, ok := st[blockID]; !ok {
+				st[blockID] = newStateForHashes()
+			}
+			st[blockID].set(key, &wavesRecordForHashes{addr: &addr, balance: uint64(i)})
+		}
+	}
+}
+
+// prepareHashesSerially calculates the pending hashes of all categories one by one, it's the reference
+// for the concurrent calculation of prepareHashes.
+func prepareHashesSerially(s *blockchainEntitiesStorage) error {
+	for _, prepare := range s.hashesPreparers() {
+		if err := prepare(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestPrepareHashesParallelEqualsSerial(t *testing.T) {
+	blockIDs := []proto.BlockID{blockID0, blockID1}
+	serial := createStorageObjectsWithOptions(t, testStorageObjectsOptions{Amend: true, CalculateHashes: true})
+	parallel := createStorageObjectsWithOptions(t, testStorageObjectsOptions{Amend: true, CalculateHashes: true})
+	for _, id := range blockIDs {
+		serial.addBlock(t, id)
+		parallel.addBlock(t, id)
+	}
+	fillHashesComponents(t, serial.entities, blockIDs, 1000)
+	fillHashesComponents(t, parallel.entities, blockIDs, 1000)
+
+	require.NoError(t, prepareHashesSerially(serial.entities))
+	require.NoError(t, parallel.entities.prepareHashes())
+
+	prev := crypto.Digest{}
+	for i, id := range blockIDs {
+		expected, err := serial.entities.putStateHash(prev[:], uint64(i+2), id)
+		require.NoError(t, err)
+		actual, err := parallel.entities.putStateHash(prev[:], uint64(i+2), id)
+		require.NoError(t, err)
+		assert.Equal(t, expected.FieldsHashes, actual.FieldsHashes)
+		assert.Equal(t, expected.SumHash, actual.SumHash)
+		assert.NotEqual(t, serial.entities.accountsDataStor.hasher.emptyHash, actual.DataEntryHash)
+		prev = actual.SumHash
+	}
+}
+
+func BenchmarkPrepareHashes(b *testing.B) {
+	const componentsCount = 100000
+	to := createStorageObjectsWithOptions(b, testStorageObjectsOptions{Amend: true, CalculateHashes: true})
+	for _, bc := range []struct {
+		name    string
+		prepare func() error
+	}{
+		{"serial", func() error { return prepareHashesSerially(to.entities) }},
+		{"parallel", to.entities.prepareHashes},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				to.entities.reset()
+				fillHashesComponents(b, to.entities, []proto.BlockID{blockID0}, componentsCount)
+				b.StartTimer()
+				if err := bc.prepare(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,12 +1,16 @@
 package state
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
+	"encoding/csv"
 	stderrs "errors"
 	"fmt"
+	"math"
 	"math/big"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -24,6 +28,7 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/ride"
 	"github.com/wavesplatform/gowaves/pkg/ride/ast"
 	ridec "github.com/wavesplatform/gowaves/pkg/ride/compiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/serialization"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/types"
 )
@@ -389,6 +394,151 @@ func TestTransactionByID(t *testing.T) {
 	assert.Equal(t, correctTx, tx)
 }
 
+func TestBlockSnapshot(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+
+	height := uint64(75)
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, height, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+
+	for _, h := range []proto.Height{2, height} {
+		block, bErr := manager.BlockByHeight(h)
+		require.NoError(t, bErr)
+		snapshot, sErr := manager.BlockSnapshot(block.BlockID())
+		require.NoError(t, sErr)
+		assert.Len(t, snapshot.TxSnapshots, len(block.Transactions))
+		expected, sErr := manager.SnapshotsAtHeight(h)
+		require.NoError(t, sErr)
+		assert.Equal(t, expected, *snapshot)
+	}
+
+	_, err = manager.BlockSnapshot(genRandBlockId(t))
+	assert.True(t, IsNotFound(err))
+}
+
+func TestBlockDataChanges(t *testing.T) {
+	manager, to := createMockStateManager(t, settings.MustMainNetSettings())
+	sender, recipient := testGlobal.senderInfo.addr, testGlobal.recipientInfo.addr
+
+	to.addBlock(t, blockID0)
+	to.addBlock(t, blockID1)
+	snapshot := proto.BlockSnapshot{TxSnapshots: [][]proto.AtomicSnapshot{
+		{
+			&proto.WavesBalanceSnapshot{Address: sender, Balance: 100},
+			&proto.DataEntriesSnapshot{Address: sender, DataEntries: proto.DataEntries{
+				&proto.IntegerDataEntry{Key: "a", Value: 1},
+				&proto.StringDataEntry{Key: "b", Value: "x"},
+			}},
+		},
+		{
+			&proto.DataEntriesSnapshot{Address: recipient, DataEntries: proto.DataEntries{
+				&proto.BooleanDataEntry{Key: "d", Value: true},
+			}},
+		},
+		{
+			&proto.DataEntriesSnapshot{Address: sender, DataEntries: proto.DataEntries{
+				&proto.IntegerDataEntry{Key: "a", Value: 2},
+				&proto.DeleteDataEntry{Key: "c"},
+			}},
+		},
+	}}
+	err := to.entities.snapshots.saveSnapshots(blockID0, 1, snapshot)
+	require.NoError(t, err)
+	err = to.entities.snapshots.saveSnapshots(blockID1, 2, proto.BlockSnapshot{TxSnapshots: [][]proto.AtomicSnapshot{
+		{&proto.WavesBalanceSnapshot{Address: recipient, Balance: 100}},
+	}})
+	require.NoError(t, err)
+	to.flush(t)
+
+	changes, err := manager.BlockDataChanges(blockID0)
+	require.NoError(t, err)
+	expected := map[proto.WavesAddress][]proto.DataEntry{
+		sender: {
+			&proto.IntegerDataEntry{Key: "a", Value: 2},
+			&proto.StringDataEntry{Key: "b", Value: "x"},
+			&proto.DeleteDataEntry{Key: "c"},
+		},
+		recipient: {
+			&proto.BooleanDataEntry{Key: "d", Value: true},
+		},
+	}
+	assert.Equal(t, expected, changes)
+
+	changes, err = manager.BlockDataChanges(blockID1)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = manager.BlockDataChanges(genRandBlockId(t))
+	assert.True(t, IsNotFound(err))
+}
+
+func TestBlockBalanceChanges(t *testing.T) {
+	manager, to := createMockStateManager(t, settings.MustMainNetSettings())
+	sender, recipient := testGlobal.senderInfo.addr, testGlobal.recipientInfo.addr
+	issuer := testGlobal.issuerInfo.addr
+	asset := testGlobal.asset0.assetID
+
+	to.addBlockAndDo(t, blockID0, func(blockID proto.BlockID) {
+		err := to.entities.balances.setWavesBalance(sender.ID(), wavesValue{
+			profile:       balanceProfile{balance: 1000},
+			balanceChange: true,
+		}, blockID)
+		require.NoError(t, err)
+		err = to.entities.balances.setAssetBalance(sender.ID(), proto.AssetIDFromDigest(asset), 50, blockID)
+		require.NoError(t, err)
+	})
+	to.addBlock(t, blockID1)
+	err := to.entities.snapshots.saveSnapshots(blockID1, 2, proto.BlockSnapshot{TxSnapshots: [][]proto.AtomicSnapshot{
+		{
+			&proto.WavesBalanceSnapshot{Address: sender, Balance: 900},
+			&proto.WavesBalanceSnapshot{Address: recipient, Balance: 100},
+		},
+		{
+			&proto.WavesBalanceSnapshot{Address: sender, Balance: 850},
+			&proto.AssetBalanceSnapshot{Address: sender, AssetID: asset, Balance: 30},
+			&proto.AssetBalanceSnapshot{Address: recipient, AssetID: asset, Balance: 20},
+		},
+		{
+			&proto.WavesBalanceSnapshot{Address: recipient, Balance: 90},
+			&proto.WavesBalanceSnapshot{Address: issuer, Balance: 10},
+		},
+	}})
+	require.NoError(t, err)
+	to.flush(t)
+
+	changes, err := manager.BlockBalanceChanges(blockID1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[proto.WavesAddress][2]uint64{
+		sender:    {1000, 850},
+		recipient: {0, 90},
+		issuer:    {0, 10},
+	}, changes)
+
+	assetID := proto.AssetIDFromDigest(asset)
+	changes, err = manager.BlockBalanceChanges(blockID1, &assetID)
+	require.NoError(t, err)
+	assert.Equal(t, map[proto.WavesAddress][2]uint64{
+		sender:    {50, 30},
+		recipient: {0, 20},
+	}, changes)
+
+	_, err = manager.BlockBalanceChanges(genRandBlockId(t), nil)
+	assert.True(t, IsNotFound(err))
+
+	// Balances before the block are not retained if the previous height is below the rollback window.
+	minHeight := make([]byte, 8)
+	binary.LittleEndian.PutUint64(minHeight, 2)
+	require.NoError(t, manager.stateDB.db.Put(rollbackMinHeightKeyBytes, minHeight))
+	_, err = manager.BlockBalanceChanges(blockID1, nil)
+	assert.True(t, IsInvalidInput(err))
+}
+
 func TestStateManager_TopBlock(t *testing.T) {
 	blocksPath, err := blocksPath()
 	bs := settings.MustMainNetSettings()
@@ -523,6 +673,8 @@ func createMockStateManager(t *testing.T, bs *settings.BlockchainSettings) (*sta
 		state.stateDB,
 		state.atx,
 		&snapshotApplier,
+		0,
+		nil,
 	)
 	require.NoError(t, err, "newTxAppender() failed")
 	state.appender = appender
@@ -530,6 +682,123 @@ func createMockStateManager(t *testing.T, bs *settings.BlockchainSettings) (*sta
 	return state, to
 }
 
+func TestAccountStateSize(t *testing.T) {
+	state, testObj := createMockStateManager(t, settings.MustMainNetSettings())
+	addr := testGlobal.senderInfo.addr
+	other := testGlobal.recipientInfo.addr
+
+	size, err := state.AccountStateSize(addr)
+	require.NoError(t, err)
+	assert.Zero(t, size, "unknown account occupies nothing")
+
+	testObj.addBlock(t, blockID0)
+	testObj.setWavesBalance(t, addr, balanceProfile{100, 0, 0}, blockID0)
+	entries := []proto.DataEntry{
+		&proto.IntegerDataEntry{Key: "int", Value: 12345},
+		&proto.StringDataEntry{Key: "string", Value: "some string value"},
+		&proto.DeleteDataEntry{Key: "deleted"},
+	}
+	for _, e := range entries {
+		require.NoError(t, testObj.entities.accountsDataStor.appendEntry(addr, e, blockID0))
+	}
+	err = testObj.entities.scriptsStorage.setAccountScript(addr, testGlobal.scriptBytes, testGlobal.senderInfo.pk, blockID0)
+	require.NoError(t, err)
+	assetID := proto.AssetIDFromDigest(testGlobal.asset0.assetID)
+	require.NoError(t, testObj.entities.balances.setAssetBalance(addr.ID(), assetID, 10, blockID0))
+	// zero balance of another asset isn't counted
+	zeroAssetID := proto.AssetIDFromDigest(testGlobal.asset1.assetID)
+	require.NoError(t, testObj.entities.balances.setAssetBalance(addr.ID(), zeroAssetID, 0, blockID0))
+	// state of other accounts isn't counted
+	testObj.setWavesBalance(t, other, balanceProfile{100, 0, 0}, blockID0)
+	require.NoError(t, testObj.entities.accountsDataStor.appendEntry(other, entries[0], blockID0))
+	testObj.flush(t)
+
+	addrNum, err := testObj.entities.accountsDataStor.addrToNum(addr)
+	require.NoError(t, err)
+	var expected int
+	for _, e := range entries[:2] { // delete entry isn't counted
+		value, mErr := e.MarshalValue()
+		require.NoError(t, mErr)
+		record := dataEntryRecord{value: value}
+		recordBytes, mErr := record.marshalBinary()
+		require.NoError(t, mErr)
+		key := accountsDataStorKey{addrNum: addrNum, entryKey: e.GetKey()}
+		expected += len(key.bytes()) + len(recordBytes)
+	}
+	scriptKey := accountScriptKey{addr: addr.ID()}
+	expected += len(scriptKey.bytes()) + len(testGlobal.scriptBytes)
+	wavesKey := wavesBalanceKey{address: addr.ID()}
+	wavesRecord := wavesBalanceRecord{balanceProfile{100, 0, 0}}
+	wavesRecordBytes, err := wavesRecord.marshalBinary()
+	require.NoError(t, err)
+	expected += len(wavesKey.bytes()) + len(wavesRecordBytes)
+	assetKey := assetBalanceKey{address: addr.ID(), asset: assetID}
+	assetRecord := assetBalanceRecord{balance: 10}
+	assetRecordBytes, err := assetRecord.marshalBinary()
+	require.NoError(t, err)
+	expected += len(assetKey.bytes()) + len(assetRecordBytes)
+
+	size, err = state.AccountStateSize(addr)
+	require.NoError(t, err)
+	assert.Equal(t, int64(expected), size)
+
+	// removal of the script decreases the size
+	testObj.addBlock(t, blockID1)
+	err = testObj.entities.scriptsStorage.setAccountScript(addr, proto.Script{}, testGlobal.senderInfo.pk, blockID1)
+	require.NoError(t, err)
+	testObj.flush(t)
+	size, err = state.AccountStateSize(addr)
+	require.NoError(t, err)
+	assert.Equal(t, int64(expected-len(scriptKey.bytes())-len(testGlobal.scriptBytes)), size)
+}
+
+func TestGeneratingBalances(t *testing.T) {
+	state, testObj := createMockStateManager(t, settings.MustMainNetSettings()) // generating balance depth is 50
+	first, err := proto.NewAddressFromString(addr0)
+	require.NoError(t, err)
+	second, err := proto.NewAddressFromString(addr1)
+	require.NoError(t, err)
+	const blocksCount = 60
+	for i := 1; i <= blocksCount; i++ {
+		blockID := genBlockId(byte(i))
+		testObj.addBlock(t, blockID)
+		switch i {
+		case 1:
+			testObj.setWavesBalance(t, first, balanceProfile{100, 0, 0}, blockID)
+			testObj.setWavesBalance(t, second, balanceProfile{300, 0, 0}, blockID)
+		case 2:
+			testObj.setWavesBalance(t, first, balanceProfile{200, 0, 0}, blockID)
+		case 30:
+			testObj.setWavesBalance(t, second, balanceProfile{50, 0, 0}, blockID)
+		}
+	}
+	testObj.flush(t)
+
+	for _, test := range []struct {
+		height    proto.Height
+		expected1 uint64
+		expected2 uint64
+	}{
+		{10, 100, 300}, // window [1, 10]
+		{29, 100, 300}, // window [1, 29]
+		{50, 100, 50},  // window [1, 50], the first balance of the first address is still in the window
+		{51, 200, 50},  // window [2, 51]
+		{60, 200, 50},  // window [11, 60]
+	} {
+		t.Run(fmt.Sprintf("height %d", test.height), func(t *testing.T) {
+			gbs, gbErr := state.GeneratingBalances(test.height, []proto.WavesAddress{first, second})
+			require.NoError(t, gbErr)
+			assert.Equal(t, map[proto.WavesAddress]uint64{first: test.expected1, second: test.expected2}, gbs)
+			// must be the same as for single address
+			for addr, gb := range gbs {
+				single, sErr := state.GeneratingBalance(proto.NewRecipientFromAddress(addr), test.height)
+				require.NoError(t, sErr)
+				assert.Equal(t, single, gb)
+			}
+		})
+	}
+}
+
 func TestGeneratingBalanceValuesForNewestFunctions(t *testing.T) {
 	const (
 		initialBalance = 100
@@ -900,3 +1169,943 @@ func TestGeneratingBalanceValuesInRide(t *testing.T) {
 		})
 	})
 }
+
+func TestSelectNonConflicting(t *testing.T) {
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), settings.MustMainNetSettings())
+
+	err := manager.stateDB.addBlock(blockID0)
+	require.NoError(t, err, "addBlock() failed")
+	waves := newWavesValueFromProfile(balanceProfile{10 * FeeUnit, 0, 0})
+	err = manager.stor.balances.setWavesBalance(testGlobal.senderInfo.addr.ID(), waves, blockID0)
+	require.NoError(t, err, "setWavesBalance() failed")
+	err = manager.flush()
+	require.NoError(t, err, "manager.flush() failed")
+
+	ts := proto.NewTimestampFromTime(time.Now())
+	first := proto.NewUnsignedPayment(testGlobal.senderInfo.pk, testGlobal.recipientInfo.addr, 8*FeeUnit, FeeUnit, ts)
+	require.NoError(t, first.Sign(proto.TestNetScheme, testGlobal.senderInfo.sk))
+	// The second payment is valid alone but overspends the sender's balance after the first one.
+	second := proto.NewUnsignedPayment(testGlobal.senderInfo.pk, testGlobal.recipientInfo.addr, 7*FeeUnit, FeeUnit, ts+1)
+	require.NoError(t, second.Sign(proto.TestNetScheme, testGlobal.senderInfo.sk))
+
+	accepted, rejected, err := manager.SelectNonConflicting([]proto.Transaction{first, second}, ts)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.Transaction{first}, accepted)
+	assert.Equal(t, []proto.Transaction{second}, rejected)
+
+	// The thread safe state takes the lock shared with the miner, the order of transactions decides the winner.
+	accepted, rejected, err = NewThreadSafeState(manager).SelectNonConflicting([]proto.Transaction{second, first}, ts)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.Transaction{second}, accepted)
+	assert.Equal(t, []proto.Transaction{first}, rejected)
+
+	// Dry-run doesn't change the state.
+	balance, err := manager.WavesBalance(proto.NewRecipientFromAddress(testGlobal.senderInfo.addr))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10*FeeUnit), balance)
+}
+
+func TestAllDataEntries(t *testing.T) {
+	state, testObj := createMockStateManager(t, settings.MustMainNetSettings())
+	addr := testGlobal.senderInfo.addr
+
+	entries, err := state.AllDataEntries(addr)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "unknown account has no data")
+
+	testObj.addBlock(t, blockID0)
+	expected := []proto.DataEntry{
+		&proto.IntegerDataEntry{Key: "int", Value: -12345},
+		&proto.BooleanDataEntry{Key: "bool", Value: true},
+		&proto.BinaryDataEntry{Key: "binary", Value: []byte{0x01, 0x02, 0x03}},
+		&proto.StringDataEntry{Key: "string", Value: "some string value"},
+	}
+	for _, e := range expected {
+		require.NoError(t, testObj.entities.accountsDataStor.appendEntry(addr, e, blockID0))
+	}
+	require.NoError(t, testObj.entities.accountsDataStor.appendEntry(addr, &proto.DeleteDataEntry{Key: "deleted"}, blockID0))
+	// data of other accounts isn't returned
+	other := testGlobal.recipientInfo.addr
+	require.NoError(t, testObj.entities.accountsDataStor.appendEntry(other, expected[0], blockID0))
+	testObj.flush(t)
+
+	entries, err = state.AllDataEntries(addr)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expected, entries)
+
+	var keys []string
+	stop := stderrs.New("stop")
+	err = state.IterateDataEntries(addr, func(entry proto.DataEntry) error {
+		keys = append(keys, entry.GetKey())
+		if len(keys) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Len(t, keys, 2)
+}
+
+func TestSetFeatureActivationHeight(t *testing.T) {
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), settings.MustMainNetSettings())
+	err := manager.SetFeatureActivationHeight(int16(settings.SmartAccounts), 1)
+	require.Error(t, err, "override must be disabled by default")
+
+	params := DefaultTestingStateParams()
+	params.UnsafeAllowFeatureActivationOverride = true
+	manager = newTestStateManager(t, true, params, settings.MustMainNetSettings())
+	err = manager.stateDB.addBlock(blockID0)
+	require.NoError(t, err, "addBlock() failed")
+	waves := newWavesValueFromProfile(balanceProfile{10 * FeeUnit, 0, 0})
+	err = manager.stor.balances.setWavesBalance(testGlobal.senderInfo.addr.ID(), waves, blockID0)
+	require.NoError(t, err, "setWavesBalance() failed")
+	err = manager.flush()
+	require.NoError(t, err, "manager.flush() failed")
+
+	// Block is added to the state database directly, so the height of the blocks storage is used, features are
+	// checked against it.
+	height := manager.rw.recentHeight()
+	tx := proto.NewUnsignedTransferWithProofs(2, testGlobal.senderInfo.pk, proto.NewOptionalAssetWaves(),
+		proto.NewOptionalAssetWaves(), defaultTimestamp, FeeUnit, FeeUnit,
+		proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), nil)
+	require.NoError(t, tx.Sign(proto.TestNetScheme, testGlobal.senderInfo.sk))
+	validate := func() error {
+		defer manager.ResetValidationList()
+		_, vErr := manager.ValidateNextTx(tx, defaultTimestamp, defaultTimestamp, 3, true)
+		return vErr
+	}
+
+	// Feature is activated at the next height, so it's not active yet.
+	err = manager.SetFeatureActivationHeight(int16(settings.SmartAccounts), height+1)
+	require.NoError(t, err)
+	active, err := manager.IsActiveAtHeight(int16(settings.SmartAccounts), height)
+	require.NoError(t, err)
+	assert.False(t, active)
+	active, err = manager.IsActiveAtHeight(int16(settings.SmartAccounts), height+1)
+	require.NoError(t, err)
+	assert.True(t, active)
+	activated, err := manager.IsActivated(int16(settings.SmartAccounts))
+	require.NoError(t, err)
+	assert.False(t, activated)
+	_, err = manager.ActivationHeight(int16(settings.SmartAccounts))
+	assert.Error(t, err)
+	assert.ErrorContains(t, validate(), "SmartAccounts feature has not been activated yet")
+
+	// Move activation to the current height.
+	err = manager.SetFeatureActivationHeight(int16(settings.SmartAccounts), height)
+	require.NoError(t, err)
+	activated, err = manager.IsActivated(int16(settings.SmartAccounts))
+	require.NoError(t, err)
+	assert.True(t, activated)
+	activationHeight, err := manager.ActivationHeight(int16(settings.SmartAccounts))
+	require.NoError(t, err)
+	assert.Equal(t, height, activationHeight)
+	assert.NoError(t, validate())
+}
+
+func TestAssetSupply(t *testing.T) {
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), settings.MustMainNetSettings())
+	err := manager.stateDB.addBlock(blockID0)
+	require.NoError(t, err, "addBlock() failed")
+	digest := testGlobal.asset0.assetID
+	assetID := proto.AssetIDFromDigest(digest)
+	issuer := testGlobal.issuerInfo.addr.ID()
+
+	_, _, err = manager.AssetSupply(assetID)
+	assert.True(t, IsNotFound(err))
+
+	check := func(total, circulating uint64) {
+		require.NoError(t, manager.flush(), "manager.flush() failed")
+		tot, circ, sErr := manager.AssetSupply(assetID)
+		require.NoError(t, sErr)
+		assert.Equal(t, total, tot)
+		assert.Equal(t, circulating, circ)
+	}
+
+	// Issue: the whole quantity belongs to the issuer, nothing is in circulation.
+	err = manager.stor.assets.issueAsset(assetID, defaultAssetInfo(proto.DigestTail(digest), true), blockID0)
+	require.NoError(t, err, "issueAsset() failed")
+	err = manager.stor.balances.setAssetBalance(issuer, assetID, 10000000, blockID0)
+	require.NoError(t, err, "setAssetBalance() failed")
+	check(10000000, 0)
+
+	// Issuer transfers a part of the quantity.
+	err = manager.stor.balances.setAssetBalance(issuer, assetID, 4000000, blockID0)
+	require.NoError(t, err, "setAssetBalance() failed")
+	check(10000000, 6000000)
+
+	// Reissue increases the total supply, reissued quantity is credited to the issuer.
+	err = manager.stor.assets.reissueAsset(assetID, &assetReissueChange{reissuable: true, diff: 5000000}, blockID0)
+	require.NoError(t, err, "reissueAsset() failed")
+	err = manager.stor.balances.setAssetBalance(issuer, assetID, 9000000, blockID0)
+	require.NoError(t, err, "setAssetBalance() failed")
+	check(15000000, 6000000)
+
+	// Burn by the issuer decreases both the total supply and the issuer's balance.
+	err = manager.stor.assets.burnAsset(assetID, &assetBurnChange{diff: 2000000}, blockID0)
+	require.NoError(t, err, "burnAsset() failed")
+	err = manager.stor.balances.setAssetBalance(issuer, assetID, 7000000, blockID0)
+	require.NoError(t, err, "setAssetBalance() failed")
+	check(13000000, 6000000)
+}
+
+func TestExportBalancesCSV(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+
+	height := uint64(20)
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, height, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+
+	var buf bytes.Buffer
+	err = manager.ExportBalancesCSV(&buf, nil, height)
+	require.NoError(t, err)
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, rows)
+	assert.Equal(t, []string{"address", "balance"}, rows[0])
+	number, err := manager.WavesAddressesNumber()
+	require.NoError(t, err)
+	assert.Len(t, rows[1:], int(number))
+	for _, row := range rows[1:] {
+		addr, aErr := proto.NewAddressFromString(row[0])
+		require.NoError(t, aErr)
+		balance, bErr := manager.WavesBalance(proto.NewRecipientFromAddress(addr))
+		require.NoError(t, bErr)
+		assert.Equal(t, strconv.FormatUint(balance, 10), row[1])
+	}
+
+	top, err := manager.Height()
+	require.NoError(t, err)
+	err = manager.ExportBalancesCSV(&buf, nil, top+1)
+	assert.True(t, IsInvalidInput(err))
+
+	// Heights below the rollback window are rejected, because the balances history isn't retained there.
+	minHeight := make([]byte, 8)
+	binary.LittleEndian.PutUint64(minHeight, 10)
+	require.NoError(t, manager.stateDB.db.Put(rollbackMinHeightKeyBytes, minHeight))
+	err = manager.ExportBalancesCSV(&buf, nil, 9)
+	assert.True(t, IsInvalidInput(err))
+	buf.Reset()
+	require.NoError(t, manager.ExportBalancesCSV(&buf, nil, 10))
+}
+
+type testTxMetricsCollector struct {
+	metrics map[proto.BlockID][]TxMetrics
+}
+
+func (c *testTxMetricsCollector) TxApplied(blockID proto.BlockID, metrics TxMetrics) {
+	c.metrics[blockID] = append(c.metrics[blockID], metrics)
+}
+
+func TestTxMetrics(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	collector := &testTxMetricsCollector{metrics: make(map[proto.BlockID][]TxMetrics)}
+	params := DefaultTestingStateParams()
+	params.TxMetrics = collector
+	manager := newTestStateManager(t, true, params, bs)
+
+	height := uint64(200)
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, height, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+
+	total := 0
+	for h := proto.Height(2); h <= height; h++ {
+		block, bErr := manager.BlockByHeight(h)
+		require.NoError(t, bErr)
+		metrics := collector.metrics[block.BlockID()]
+		require.Len(t, metrics, len(block.Transactions), "height %d", h)
+		total += len(metrics)
+		for i, tx := range block.Transactions {
+			id, idErr := tx.GetID(bs.AddressSchemeCharacter)
+			require.NoError(t, idErr)
+			assert.Equal(t, id, metrics[i].ID)
+			assert.Equal(t, tx.GetTypeInfo().Type, metrics[i].Type)
+			assert.GreaterOrEqual(t, metrics[i].Validation, time.Duration(0))
+			assert.GreaterOrEqual(t, metrics[i].ScriptExecution, time.Duration(0))
+			assert.GreaterOrEqual(t, metrics[i].SnapshotApplication, time.Duration(0))
+		}
+	}
+	assert.NotZero(t, total)
+}
+
+func TestScoresMatchBaseTargets(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, 20, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+	height, err := manager.Height()
+	require.NoError(t, err)
+
+	expected := big.NewInt(0)
+	for h := proto.Height(1); h <= height; h++ {
+		header, hErr := manager.HeaderByHeight(h)
+		require.NoError(t, hErr)
+		blockScore, cErr := CalculateScore(header.BaseTarget)
+		require.NoError(t, cErr)
+		prev := new(big.Int).Set(expected)
+		expected.Add(expected, blockScore)
+
+		score, sErr := manager.ScoreAtHeight(h)
+		require.NoError(t, sErr)
+		assert.Equal(t, 0, expected.Cmp(score), "score mismatch at height %d", h)
+		assert.Equal(t, 1, score.Cmp(prev), "score doesn't increase at height %d", h)
+	}
+	current, err := manager.CurrentScore()
+	require.NoError(t, err)
+	assert.Equal(t, 0, expected.Cmp(current))
+
+	_, err = manager.ScoreAtHeight(height + 1)
+	assert.True(t, IsInvalidInput(err))
+}
+
+func TestScriptsUsingBuiltin(t *testing.T) {
+	manager, to := createMockStateManager(t, settings.MustMainNetSettings())
+	compile := func(src string) proto.Script {
+		script, errs := ridec.Compile(src, false, true)
+		require.Empty(t, errs)
+		return script
+	}
+	const (
+		sha256ID          = "503"
+		sigVerifyID       = "500"
+		intFromStateID    = "1050"
+		intValueFromState = "@extrNative(1050)"
+	)
+	usingSha256 := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+sha256(tx.bodyBytes) != base58''`)
+	notUsingSha256 := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey) && getIntegerValue(this, "k") > 0`)
+	assetUsingSha256 := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ASSET #-}
+sha256(tx.bodyBytes) != base58''`)
+	assetID := crypto.MustDigestFromBase58("AiNNtMkp21Utu8QzDCcc9zzdHmosLKq8qCHfwMR4GJ9E")
+
+	to.addBlock(t, blockID0)
+	err := to.entities.scriptsStorage.setAccountScript(
+		testGlobal.senderInfo.addr, usingSha256, testGlobal.senderInfo.pk, blockID0,
+	)
+	require.NoError(t, err)
+	err = to.entities.scriptsStorage.setAccountScript(
+		testGlobal.recipientInfo.addr, notUsingSha256, testGlobal.recipientInfo.pk, blockID0,
+	)
+	require.NoError(t, err)
+	err = to.entities.scriptsStorage.setAssetScript(assetID, assetUsingSha256, blockID0)
+	require.NoError(t, err)
+	to.flush(t)
+
+	addrs, assets, err := ScriptsUsingBuiltin(context.Background(), manager, sha256ID)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.WavesAddress{testGlobal.senderInfo.addr}, addrs)
+	assert.Equal(t, []proto.AssetID{proto.AssetIDFromDigest(assetID)}, assets)
+
+	addrs, assets, err = ScriptsUsingBuiltin(context.Background(), manager, sigVerifyID)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.WavesAddress{testGlobal.recipientInfo.addr}, addrs)
+	assert.Empty(t, assets)
+
+	// Value extracting function and the wrapped one are the same built-in.
+	for _, name := range []string{intFromStateID, intValueFromState} {
+		addrs, assets, err = ScriptsUsingBuiltin(context.Background(), manager, name)
+		require.NoError(t, err)
+		assert.Equal(t, []proto.WavesAddress{testGlobal.recipientInfo.addr}, addrs)
+		assert.Empty(t, assets)
+	}
+
+	addrs, assets, err = ScriptsUsingBuiltin(context.Background(), manager, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, addrs)
+	assert.Empty(t, assets)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = ScriptsUsingBuiltin(ctx, manager, sha256ID)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The context is checked before each script, so the iteration stops right after the cancellation.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	err = manager.IterateStoredScripts(ctx, func(StoredScript) error {
+		calls++
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestFindScripts(t *testing.T) {
+	manager, to := createMockStateManager(t, settings.MustMainNetSettings())
+	compile := func(src string) proto.Script {
+		script, errs := ridec.Compile(src, false, true)
+		require.Empty(t, errs)
+		return script
+	}
+	dAppWithVerifier := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func a() = []
+
+@Callable(i)
+func b() = []
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)`)
+	dApp := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func a() = []`)
+	asset := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ASSET #-}
+true`)
+	assetID := crypto.MustDigestFromBase58("AiNNtMkp21Utu8QzDCcc9zzdHmosLKq8qCHfwMR4GJ9E")
+
+	to.addBlock(t, blockID0)
+	err := to.entities.scriptsStorage.setAccountScript(
+		testGlobal.senderInfo.addr, dAppWithVerifier, testGlobal.senderInfo.pk, blockID0,
+	)
+	require.NoError(t, err)
+	err = to.entities.scriptsStorage.setAccountScript(
+		testGlobal.recipientInfo.addr, dApp, testGlobal.recipientInfo.pk, blockID0,
+	)
+	require.NoError(t, err)
+	err = to.entities.scriptsStorage.setAssetScript(assetID, asset, blockID0)
+	require.NoError(t, err)
+	// Broken script is skipped by the search.
+	brokenKey := accountScriptKey{testGlobal.issuerInfo.addr.ID()}
+	err = to.hs.addNewEntry(accountScript, brokenKey.bytes(), proto.Script{0xff, 0x01}, blockID0)
+	require.NoError(t, err)
+	to.flush(t)
+
+	withVerifier := func(tree *ast.Tree) bool { return tree.IsDApp() && tree.HasVerifier() }
+	addrs, err := FindScripts(context.Background(), manager, withVerifier)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.WavesAddress{testGlobal.senderInfo.addr}, addrs)
+
+	// Asset scripts are not checked.
+	all := func(*ast.Tree) bool { return true }
+	addrs, err = FindScripts(context.Background(), manager, all)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []proto.WavesAddress{testGlobal.senderInfo.addr, testGlobal.recipientInfo.addr}, addrs)
+
+	withManyCallables := func(tree *ast.Tree) bool { return len(tree.Functions) > 2 }
+	addrs, err = FindScripts(context.Background(), manager, withManyCallables)
+	require.NoError(t, err)
+	assert.Empty(t, addrs)
+
+	// The predicate is called without holding the state lock.
+	mu := new(sync.RWMutex)
+	wrapped := NewThreadSafeReadWrapper(mu, manager)
+	addrs, err = FindScripts(context.Background(), wrapped, func(*ast.Tree) bool {
+		if !mu.TryLock() {
+			return false
+		}
+		mu.Unlock()
+		return true
+	})
+	require.NoError(t, err)
+	assert.Len(t, addrs, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = FindScripts(ctx, manager, all)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewestAccountIsDApp(t *testing.T) {
+	manager, to := createMockStateManager(t, settings.MustMainNetSettings())
+	compile := func(src string) proto.Script {
+		script, errs := ridec.Compile(src, false, true)
+		require.Empty(t, errs)
+		return script
+	}
+	dApp := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func call() = []`)
+	verifierOnly := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)`)
+	expression := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)`)
+	dAppAddr := testGlobal.senderInfo.addr
+	verifierOnlyAddr := testGlobal.recipientInfo.addr
+	expressionAddr := testGlobal.minerInfo.addr
+	plainAddr := testGlobal.issuerInfo.addr
+
+	to.addBlock(t, blockID0)
+	for addr, script := range map[proto.WavesAddress]proto.Script{
+		dAppAddr:         dApp,
+		verifierOnlyAddr: verifierOnly,
+		expressionAddr:   expression,
+	} {
+		err := to.entities.scriptsStorage.setAccountScript(addr, script, testGlobal.senderInfo.pk, blockID0)
+		require.NoError(t, err)
+	}
+	check := func() {
+		for addr, expected := range map[proto.WavesAddress]bool{
+			dAppAddr:         true,
+			verifierOnlyAddr: false,
+			expressionAddr:   false,
+			plainAddr:        false,
+		} {
+			isDApp, err := manager.NewestAccountIsDApp(addr)
+			require.NoError(t, err)
+			assert.Equal(t, expected, isDApp, addr.String())
+		}
+	}
+	check()
+	to.flush(t)
+	check()
+	require.NoError(t, to.entities.scriptsStorage.clearCache())
+	check()
+	// Only the scripts of dApps are decoded and cached, others are checked by the script basic info.
+	ss, ok := to.entities.scriptsStorage.(*scriptsStorage)
+	require.True(t, ok)
+	for addr, expected := range map[proto.WavesAddress]bool{
+		dAppAddr:         true,
+		verifierOnlyAddr: true,
+		expressionAddr:   false,
+	} {
+		key := accountScriptKey{addr.ID()}
+		_, cached := ss.cache.get(key.bytes())
+		assert.Equal(t, expected, cached, addr.String())
+	}
+}
+
+func TestStateAtBalancesMatchExport(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, 20, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+	top, err := manager.Height()
+	require.NoError(t, err)
+
+	for _, height := range []proto.Height{1, 2, 10, top} {
+		view, vErr := manager.StateAt(height)
+		require.NoError(t, vErr)
+		assert.Equal(t, height, view.Height())
+		var buf bytes.Buffer
+		require.NoError(t, manager.ExportBalancesCSV(&buf, nil, height))
+		rows, rErr := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, rErr)
+		require.NotEmpty(t, rows[1:])
+		for _, row := range rows[1:] {
+			addr, aErr := proto.NewAddressFromString(row[0])
+			require.NoError(t, aErr)
+			balance, bErr := view.WavesBalance(proto.NewRecipientFromAddress(addr))
+			require.NoError(t, bErr)
+			assert.Equal(t, row[1], strconv.FormatUint(balance, 10))
+			if height == top {
+				current, cErr := manager.WavesBalance(proto.NewRecipientFromAddress(addr))
+				require.NoError(t, cErr)
+				assert.Equal(t, current, balance)
+			}
+		}
+	}
+
+	_, err = manager.StateAt(0)
+	assert.True(t, IsInvalidInput(err))
+	_, err = manager.StateAt(top + 1)
+	assert.True(t, IsInvalidInput(err))
+}
+
+func TestStateAt(t *testing.T) {
+	manager, to := createMockStateManager(t, settings.MustMainNetSettings())
+	compile := func(src string) proto.Script {
+		script, errs := ridec.Compile(src, false, true)
+		require.Empty(t, errs)
+		return script
+	}
+	first := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func a() = []`)
+	second := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func b() = []`)
+	addr := testGlobal.senderInfo.addr
+	other := testGlobal.recipientInfo.addr
+	asset := proto.AssetIDFromDigest(testGlobal.asset0.assetID)
+	rcp := proto.NewRecipientFromAddress(addr)
+
+	to.addBlock(t, blockID0)
+	err := to.entities.balances.setWavesBalance(addr.ID(), newWavesValueFromProfile(balanceProfile{balance: 100}), blockID0)
+	require.NoError(t, err)
+	err = to.entities.accountsDataStor.appendEntry(addr, &proto.IntegerDataEntry{Key: "key", Value: 1}, blockID0)
+	require.NoError(t, err)
+	err = to.entities.scriptsStorage.setAccountScript(addr, first, testGlobal.senderInfo.pk, blockID0)
+	require.NoError(t, err)
+	to.addBlock(t, blockID1)
+	err = to.entities.balances.setWavesBalance(addr.ID(), newWavesValueFromProfile(balanceProfile{balance: 50}), blockID1)
+	require.NoError(t, err)
+	err = to.entities.balances.setAssetBalance(addr.ID(), asset, 10, blockID1)
+	require.NoError(t, err)
+	err = to.entities.accountsDataStor.appendEntry(addr, &proto.IntegerDataEntry{Key: "key", Value: 2}, blockID1)
+	require.NoError(t, err)
+	err = to.entities.accountsDataStor.appendEntry(addr, &proto.StringDataEntry{Key: "new", Value: "v"}, blockID1)
+	require.NoError(t, err)
+	err = to.entities.scriptsStorage.setAccountScript(addr, second, testGlobal.senderInfo.pk, blockID1)
+	require.NoError(t, err)
+	to.flush(t)
+
+	view, err := manager.StateAt(1)
+	require.NoError(t, err)
+	balance, err := view.WavesBalance(rcp)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), balance)
+	balance, err = view.AssetBalance(rcp, asset)
+	require.NoError(t, err)
+	assert.Zero(t, balance)
+	balance, err = view.WavesBalance(proto.NewRecipientFromAddress(other))
+	require.NoError(t, err)
+	assert.Zero(t, balance)
+	entry, err := view.RetrieveEntry(rcp, "key")
+	require.NoError(t, err)
+	assert.Equal(t, &proto.IntegerDataEntry{Key: "key", Value: 1}, entry)
+	_, err = view.RetrieveEntry(rcp, "new")
+	assert.True(t, IsNotFound(err))
+	script, err := view.ScriptBytesByAccount(rcp)
+	require.NoError(t, err)
+	assert.Equal(t, first, script)
+	_, err = view.ScriptBytesByAccount(proto.NewRecipientFromAddress(other))
+	assert.True(t, IsNotFound(err))
+
+	// At the top height reads through the view match the regular reads.
+	view, err = manager.StateAt(2)
+	require.NoError(t, err)
+	balance, err = view.WavesBalance(rcp)
+	require.NoError(t, err)
+	expectedBalance, err := manager.WavesBalance(rcp)
+	require.NoError(t, err)
+	assert.Equal(t, expectedBalance, balance)
+	balance, err = view.AssetBalance(rcp, asset)
+	require.NoError(t, err)
+	expectedBalance, err = manager.AssetBalance(rcp, asset)
+	require.NoError(t, err)
+	assert.Equal(t, expectedBalance, balance)
+	for _, key := range []string{"key", "new"} {
+		entry, err = view.RetrieveEntry(rcp, key)
+		require.NoError(t, err)
+		expectedEntry, eErr := manager.RetrieveEntry(rcp, key)
+		require.NoError(t, eErr)
+		assert.Equal(t, expectedEntry, entry)
+	}
+	script, err = view.ScriptBytesByAccount(rcp)
+	require.NoError(t, err)
+	assert.Equal(t, second, script)
+	_, err = view.RetrieveEntry(proto.NewRecipientFromAddress(other), "key")
+	assert.True(t, IsNotFound(err))
+
+	// Heights below the rollback window are rejected, as well as reads of the views created before the window moved.
+	old, err := manager.StateAt(1)
+	require.NoError(t, err)
+	minHeight := make([]byte, 8)
+	binary.LittleEndian.PutUint64(minHeight, 2)
+	require.NoError(t, manager.stateDB.db.Put(rollbackMinHeightKeyBytes, minHeight))
+	_, err = manager.StateAt(1)
+	assert.True(t, IsInvalidInput(err))
+	_, err = old.WavesBalance(rcp)
+	assert.True(t, IsInvalidInput(err))
+	_, err = old.AssetBalance(rcp, asset)
+	assert.True(t, IsInvalidInput(err))
+	_, err = old.RetrieveEntry(rcp, "key")
+	assert.True(t, IsInvalidInput(err))
+	_, err = old.ScriptBytesByAccount(rcp)
+	assert.True(t, IsInvalidInput(err))
+	view, err = manager.StateAt(2)
+	require.NoError(t, err)
+	_, err = view.WavesBalance(rcp)
+	assert.NoError(t, err)
+}
+
+func TestBlockHeaderWithTxCount(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, 50, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+	height, err := manager.Height()
+	require.NoError(t, err)
+
+	for h := uint64(1); h <= height; h++ {
+		header, count, hErr := manager.BlockHeaderWithTxCount(h)
+		require.NoError(t, hErr)
+		block, bErr := manager.BlockByHeight(h)
+		require.NoError(t, bErr)
+		assert.Equal(t, block.BlockHeader, *header)
+		assert.Equal(t, len(block.Transactions), count)
+	}
+	_, _, err = manager.BlockHeaderWithTxCount(height + 1)
+	assert.Error(t, err)
+}
+
+func TestBlockGenerationSignature(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, 100, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+	height, err := manager.Height()
+	require.NoError(t, err)
+
+	for h := uint64(1); h <= height; h++ {
+		header, hErr := manager.HeaderByHeight(h)
+		require.NoError(t, hErr)
+		genSig, vrf, gsErr := manager.BlockGenerationSignature(header.BlockID())
+		require.NoError(t, gsErr, "height %d", h)
+		assert.Equal(t, header.GenSignature.Bytes(), genSig, "height %d", h)
+		assert.Nil(t, vrf, "height %d", h)
+	}
+	_, _, err = manager.BlockGenerationSignature(proto.NewBlockIDFromDigest(crypto.Digest{}))
+	assert.Error(t, err)
+}
+
+func TestReestimateAllScripts(t *testing.T) {
+	manager, to := createMockStateManager(t, settings.MustMainNetSettings())
+	compile := func(src string) (proto.Script, *ast.Tree) {
+		script, errs := ridec.Compile(src, false, true)
+		require.Empty(t, errs)
+		tree, err := serialization.Parse(script)
+		require.NoError(t, err)
+		return script, tree
+	}
+	accountScript, accountTree := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+@Callable(i)
+func call(k: String) = [StringEntry(k, toBase58String(sha256(i.caller.bytes)))]
+
+@Verifier(tx)
+func verify() = sigVerify(tx.bodyBytes, tx.proofs[0], tx.senderPublicKey)`)
+	assetScriptBytes, assetTree := compile(`
+{-# STDLIB_VERSION 6 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+{-# SCRIPT_TYPE ASSET #-}
+sha256(tx.bodyBytes) != base58''`)
+	assetID := crypto.MustDigestFromBase58("AiNNtMkp21Utu8QzDCcc9zzdHmosLKq8qCHfwMR4GJ9E")
+	addr := testGlobal.senderInfo.addr
+
+	const oldVersion = 1
+	outdated := scriptEstimation{
+		currentEstimatorVersion: oldVersion,
+		estimation:              ride.TreeEstimation{Estimation: 1, Verifier: 1, Functions: map[string]int{"call": 1}},
+	}
+	to.addBlock(t, blockID0)
+	err := to.entities.scriptsStorage.setAccountScript(addr, accountScript, testGlobal.senderInfo.pk, blockID0)
+	require.NoError(t, err)
+	err = to.entities.scriptsComplexity.saveComplexitiesForAddr(addr, outdated, blockID0)
+	require.NoError(t, err)
+	err = to.entities.scriptsStorage.setAssetScript(assetID, assetScriptBytes, blockID0)
+	require.NoError(t, err)
+	err = to.entities.scriptsComplexity.saveComplexitiesForAsset(assetID, outdated, blockID0)
+	require.NoError(t, err)
+	// The script that can't be parsed doesn't stop the re-estimation of others.
+	brokenKey := assetScriptKey{assetID: proto.AssetIDFromDigest(crypto.MustDigestFromBase58(
+		"5nyYwR4D7kB6gkcsu2ThrBnHCxh5nzBUNPeF1kvXPdN2"))}
+	err = to.entities.hs.addNewEntry(assetScript, brokenKey.bytes(), []byte{0xff, 0xff, 0xff}, blockID0)
+	require.NoError(t, err)
+	outdatedRecord := estimationRecord{EstimatorVersion: oldVersion, Estimation: outdated.estimation}
+	outdatedBytes, err := outdatedRecord.marshalBinary()
+	require.NoError(t, err)
+	brokenComplexityKey := assetScriptComplexityKey{asset: brokenKey.assetID}
+	err = to.entities.hs.addNewEntry(assetScriptComplexity, brokenComplexityKey.bytes(), outdatedBytes, blockID0)
+	require.NoError(t, err)
+	to.flush(t)
+	// Complexity of the account script was updated in the next block.
+	to.addBlock(t, blockID1)
+	err = to.entities.scriptsComplexity.saveComplexitiesForAddr(addr, outdated, blockID1)
+	require.NoError(t, err)
+	to.flush(t)
+
+	manager.lastBlock.Store(&proto.Block{BlockHeader: proto.BlockHeader{BlockSignature: blockID1.Signature()}})
+
+	// The batch is limited and points to the rest of the scripts, the account script goes first.
+	res, err := manager.ReestimateScripts(context.Background(), maxEstimatorVersion, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Processed)
+	assert.Equal(t, 3, res.Total)
+	assert.NotNil(t, res.Next)
+	assert.Empty(t, res.Failures)
+	version, err := to.entities.scriptsComplexity.scriptEstimatorVersionByAddr(addr, true)
+	require.NoError(t, err)
+	assert.Equal(t, maxEstimatorVersion, version)
+
+	var reported [][2]int
+	progress := func(done, total int) { reported = append(reported, [2]int{done, total}) }
+	err = manager.ReestimateAllScripts(context.Background(), maxEstimatorVersion, progress)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, fmt.Sprintf("failed to re-estimate script by key %x", brokenKey.bytes()))
+	assert.Equal(t, [][2]int{{3, 3}}, reported)
+
+	expectedAccount, err := ride.EstimateTree(accountTree, maxEstimatorVersion)
+	require.NoError(t, err)
+	accountEst, err := to.entities.scriptsComplexity.scriptComplexityByAddress(addr)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAccount, *accountEst)
+
+	expectedAsset, err := ride.EstimateTree(assetTree, maxEstimatorVersion)
+	require.NoError(t, err)
+	assetEst, err := to.entities.scriptsComplexity.scriptComplexityByAsset(proto.AssetIDFromDigest(assetID))
+	require.NoError(t, err)
+	assert.Equal(t, expectedAsset, *assetEst)
+	assetKey := &assetScriptKey{assetID: proto.AssetIDFromDigest(assetID)}
+	version, err = to.entities.scriptsComplexity.estimatorVersionByScriptKey(assetKey)
+	require.NoError(t, err)
+	assert.Equal(t, maxEstimatorVersion, version)
+
+	// Repeated re-estimation with the same version skips already estimated scripts.
+	reported = nil
+	err = manager.ReestimateAllScripts(context.Background(), maxEstimatorVersion, progress)
+	require.Error(t, err)
+	assert.Equal(t, [][2]int{{3, 3}}, reported)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = manager.ReestimateAllScripts(ctx, oldVersion, progress)
+	assert.ErrorIs(t, err, context.Canceled)
+	version, err = to.entities.scriptsComplexity.scriptEstimatorVersionByAddr(addr, true)
+	require.NoError(t, err)
+	assert.Equal(t, maxEstimatorVersion, version)
+
+	err = manager.ReestimateAllScripts(context.Background(), maxEstimatorVersion+1, nil)
+	assert.Error(t, err)
+
+	// The new complexities were added at the top block, the history is not changed, so the rollback reverts them.
+	to.rollbackBlock(t, blockID1)
+	version, err = to.entities.scriptsComplexity.scriptEstimatorVersionByAddr(addr, true)
+	require.NoError(t, err)
+	assert.Equal(t, oldVersion, version)
+	version, err = to.entities.scriptsComplexity.estimatorVersionByScriptKey(assetKey)
+	require.NoError(t, err)
+	assert.Equal(t, oldVersion, version)
+	assetEst, err = to.entities.scriptsComplexity.scriptComplexityByAsset(proto.AssetIDFromDigest(assetID))
+	require.NoError(t, err)
+	assert.Equal(t, outdated.estimation, *assetEst)
+}
+
+func TestPendingFeatures(t *testing.T) {
+	bs := settings.MustMainNetSettings()
+	bs.FeaturesVotingPeriod = 10
+	bs.VotesForFeatureActivation = 6
+	bs.DoubleFeaturesPeriodsAfterHeight = math.MaxUint64
+	manager, to := createMockStateManager(t, bs)
+	f, ok := to.entities.features.(*features)
+	require.True(t, ok)
+	const (
+		votingID              = 1 // voted in the current period
+		approvedID            = 2 // approved in the previous period
+		activatedID           = 3 // activated at the first block of the current period
+		activatedEarlierID    = 4 // activated in the previous period
+		notVotedAnymoreID     = 5 // voted only in the previous period
+		blocksNum             = 14
+		resetHeight           = 10
+		approvalHeight        = 9
+		earlyActivationHeight = 5
+	)
+	ids := genRandBlockIds(t, blocksNum)
+	for i, id := range ids {
+		height := uint64(i + 1)
+		to.addBlock(t, id)
+		switch height {
+		case 2:
+			for _, fid := range []int16{approvedID, activatedID, activatedEarlierID} {
+				require.NoError(t, f.addVote(fid, id))
+			}
+		case 3:
+			require.NoError(t, f.addVote(notVotedAnymoreID, id))
+		case earlyActivationHeight:
+			require.NoError(t, f.activateFeature(activatedEarlierID, &activatedFeaturesRecord{height}, id))
+		case approvalHeight:
+			require.NoError(t, f.approveFeature(approvedID, &approvedFeaturesRecord{height}, id))
+			require.NoError(t, f.approveFeature(activatedID, &approvedFeaturesRecord{height}, id))
+		case resetHeight:
+			require.NoError(t, f.resetVotes(id))
+			require.NoError(t, f.activateFeature(activatedID, &activatedFeaturesRecord{height}, id))
+		case 11, 12, 13:
+			require.NoError(t, f.addVote(votingID, id))
+		}
+	}
+	to.flush(t)
+
+	statuses, err := manager.PendingFeatures(blocksNum)
+	require.NoError(t, err)
+	assert.Equal(t, []FeatureVoteStatus{
+		{FeatureID: votingID, Votes: 3, Threshold: 6, Status: FeatureVoting, BlocksRemaining: 5},
+		{FeatureID: approvedID, Votes: 0, Threshold: 6, Status: FeatureApproved, BlocksRemaining: 5},
+		{FeatureID: activatedID, Votes: 0, Threshold: 6, Status: FeatureActivated, BlocksRemaining: 5},
+	}, statuses)
+
+	statuses, err = manager.PendingFeatures(earlyActivationHeight)
+	require.NoError(t, err)
+	assert.Equal(t, []FeatureVoteStatus{
+		{FeatureID: approvedID, Votes: 1, Threshold: 6, Status: FeatureVoting, BlocksRemaining: 4},
+		{FeatureID: activatedID, Votes: 1, Threshold: 6, Status: FeatureVoting, BlocksRemaining: 4},
+		{FeatureID: activatedEarlierID, Votes: 1, Threshold: 6, Status: FeatureActivated, BlocksRemaining: 4},
+		{FeatureID: notVotedAnymoreID, Votes: 1, Threshold: 6, Status: FeatureVoting, BlocksRemaining: 4},
+	}, statuses)
+
+	_, err = manager.PendingFeatures(blocksNum + 1)
+	assert.True(t, IsInvalidInput(err))
+}
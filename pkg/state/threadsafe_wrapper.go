@@ -1,6 +1,8 @@
 package state
 
 import (
+	"context"
+	"io"
 	"math/big"
 	"sync"
 	"sync/atomic"
@@ -28,6 +30,12 @@ func (a *ThreadSafeReadWrapper) BlockVRF(blockHeader *proto.BlockHeader, blockHe
 	return a.s.BlockVRF(blockHeader, blockHeight)
 }
 
+func (a *ThreadSafeReadWrapper) BlockGenerationSignature(blockID proto.BlockID) ([]byte, []byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.BlockGenerationSignature(blockID)
+}
+
 func (a *ThreadSafeReadWrapper) MapR(f func(StateInfo) (interface{}, error)) (interface{}, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -62,6 +70,12 @@ func (a *ThreadSafeReadWrapper) HeaderByHeight(height proto.Height) (*proto.Bloc
 	return a.s.HeaderByHeight(height)
 }
 
+func (a *ThreadSafeReadWrapper) BlockHeaderWithTxCount(height proto.Height) (*proto.BlockHeader, int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.BlockHeaderWithTxCount(height)
+}
+
 func (a *ThreadSafeReadWrapper) Height() (proto.Height, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -92,6 +106,15 @@ func (a *ThreadSafeReadWrapper) GeneratingBalance(account proto.Recipient, heigh
 	return a.s.GeneratingBalance(account, height)
 }
 
+func (a *ThreadSafeReadWrapper) GeneratingBalances(
+	height proto.Height,
+	addrs []proto.WavesAddress,
+) (map[proto.WavesAddress]uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.GeneratingBalances(height, addrs)
+}
+
 func (a *ThreadSafeReadWrapper) WavesBalance(account proto.Recipient) (uint64, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -110,6 +133,12 @@ func (a *ThreadSafeReadWrapper) WavesAddressesNumber() (uint64, error) {
 	return a.s.WavesAddressesNumber()
 }
 
+// ExportBalancesCSV doesn't take the lock, because writing of all balances may take long and would block
+// the block application. The export reads only the data committed to DB.
+func (a *ThreadSafeReadWrapper) ExportBalancesCSV(w io.Writer, assetID *proto.AssetID, height proto.Height) error {
+	return a.s.ExportBalancesCSV(w, assetID, height)
+}
+
 func (a *ThreadSafeReadWrapper) ScoreAtHeight(height proto.Height) (*big.Int, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -182,6 +211,12 @@ func (a *ThreadSafeReadWrapper) AllFeatures() ([]int16, error) {
 	return a.s.AllFeatures()
 }
 
+func (a *ThreadSafeReadWrapper) PendingFeatures(height proto.Height) ([]FeatureVoteStatus, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.PendingFeatures(height)
+}
+
 func (a *ThreadSafeReadWrapper) EstimatorVersion() (int, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -194,6 +229,12 @@ func (a *ThreadSafeReadWrapper) AddrByAlias(alias proto.Alias) (proto.WavesAddre
 	return a.s.AddrByAlias(alias)
 }
 
+func (a *ThreadSafeReadWrapper) AddressByAliasAt(alias proto.Alias, height proto.Height) (proto.WavesAddress, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AddressByAliasAt(alias, height)
+}
+
 func (a *ThreadSafeReadWrapper) AliasesByAddr(addr proto.WavesAddress) ([]string, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -206,6 +247,21 @@ func (a *ThreadSafeReadWrapper) RetrieveEntries(account proto.Recipient) ([]prot
 	return a.s.RetrieveEntries(account)
 }
 
+func (a *ThreadSafeReadWrapper) AllDataEntries(addr proto.WavesAddress) ([]proto.DataEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AllDataEntries(addr)
+}
+
+func (a *ThreadSafeReadWrapper) IterateDataEntries(
+	addr proto.WavesAddress,
+	fn func(entry proto.DataEntry) error,
+) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.IterateDataEntries(addr, fn)
+}
+
 func (a *ThreadSafeReadWrapper) RetrieveEntry(account proto.Recipient, key string) (proto.DataEntry, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -262,6 +318,14 @@ func (a *ThreadSafeReadWrapper) NewAddrTransactionsIterator(addr proto.Address)
 	return a.s.NewAddrTransactionsIterator(addr)
 }
 
+func (a *ThreadSafeReadWrapper) AddressTransactions(
+	addr proto.WavesAddress, limit int, after *crypto.Digest,
+) ([]proto.Transaction, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AddressTransactions(addr, limit, after)
+}
+
 func (a *ThreadSafeReadWrapper) AssetIsSponsored(assetID proto.AssetID) (bool, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -280,6 +344,12 @@ func (a *ThreadSafeReadWrapper) AssetInfo(assetID proto.AssetID) (*proto.AssetIn
 	return a.s.AssetInfo(assetID)
 }
 
+func (a *ThreadSafeReadWrapper) AssetSupply(assetID proto.AssetID) (uint64, uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AssetSupply(assetID)
+}
+
 func (a *ThreadSafeReadWrapper) FullAssetInfo(assetID proto.AssetID) (*proto.FullAssetInfo, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -298,6 +368,18 @@ func (a *ThreadSafeReadWrapper) NFTList(account proto.Recipient, limit uint64, a
 	return a.s.NFTList(account, limit, afterAssetID)
 }
 
+func (a *ThreadSafeReadWrapper) AssetsIssuedBy(addr proto.WavesAddress) ([]proto.AssetID, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AssetsIssuedBy(addr)
+}
+
+func (a *ThreadSafeReadWrapper) AccountStateSize(addr proto.WavesAddress) (int64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AccountStateSize(addr)
+}
+
 func (a *ThreadSafeReadWrapper) ScriptBasicInfoByAccount(account proto.Recipient) (*proto.ScriptBasicInfo, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -322,18 +404,42 @@ func (a *ThreadSafeReadWrapper) NewestScriptByAccount(recipient proto.Recipient)
 	return a.s.NewestScriptByAccount(recipient)
 }
 
+func (a *ThreadSafeReadWrapper) EthereumCall(from, to proto.EthereumAddress, data []byte) ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.EthereumCall(from, to, data)
+}
+
 func (a *ThreadSafeReadWrapper) NewestScriptBytesByAccount(recipient proto.Recipient) (proto.Script, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 	return a.s.NewestScriptBytesByAccount(recipient)
 }
 
+func (a *ThreadSafeReadWrapper) NewestAccountIsDApp(addr proto.WavesAddress) (bool, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.NewestAccountIsDApp(addr)
+}
+
+// IterateStoredScripts doesn't take the lock, because fn may take long and would block the block application.
+// The scripts are read only from the data committed to DB.
+func (a *ThreadSafeReadWrapper) IterateStoredScripts(ctx context.Context, fn func(sc StoredScript) error) error {
+	return a.s.IterateStoredScripts(ctx, fn)
+}
+
 func (a *ThreadSafeReadWrapper) IsActiveLeasing(leaseID crypto.Digest) (bool, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 	return a.s.IsActiveLeasing(leaseID)
 }
 
+func (a *ThreadSafeReadWrapper) LeaseTotals(addr proto.WavesAddress, height proto.Height) (uint64, uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.LeaseTotals(addr, height)
+}
+
 func (a *ThreadSafeReadWrapper) InvokeResultByID(invokeID crypto.Digest) (*proto.ScriptResult, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -406,12 +512,94 @@ func (a *ThreadSafeReadWrapper) SnapshotsAtHeight(height proto.Height) (proto.Bl
 	return a.s.SnapshotsAtHeight(height)
 }
 
+func (a *ThreadSafeReadWrapper) BlockSnapshot(blockID proto.BlockID) (*proto.BlockSnapshot, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.BlockSnapshot(blockID)
+}
+
+func (a *ThreadSafeReadWrapper) BlockDataChanges(blockID proto.BlockID) (map[proto.WavesAddress][]proto.DataEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.BlockDataChanges(blockID)
+}
+
+func (a *ThreadSafeReadWrapper) BlockBalanceChanges(
+	blockID proto.BlockID, assetID *proto.AssetID,
+) (map[proto.WavesAddress][2]uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.BlockBalanceChanges(blockID, assetID)
+}
+
+func (a *ThreadSafeReadWrapper) StateDelta(from, to proto.Height) ([]BlockSnapshots, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.StateDelta(from, to)
+}
+
+func (a *ThreadSafeReadWrapper) ExportStateSnapshot(w io.Writer, height proto.Height) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.ExportStateSnapshot(w, height)
+}
+
+func (a *ThreadSafeReadWrapper) StateAt(height proto.Height) (ReadOnlyState, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	v, err := a.s.StateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return &threadSafeReadOnlyState{mu: a.mu, s: v}, nil
+}
+
+// threadSafeReadOnlyState guards reads through the pinned view with the same lock as ThreadSafeReadWrapper.
+type threadSafeReadOnlyState struct {
+	mu *sync.RWMutex
+	s  ReadOnlyState
+}
+
+func (a *threadSafeReadOnlyState) Height() proto.Height {
+	return a.s.Height()
+}
+
+func (a *threadSafeReadOnlyState) WavesBalance(account proto.Recipient) (uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.WavesBalance(account)
+}
+
+func (a *threadSafeReadOnlyState) AssetBalance(account proto.Recipient, assetID proto.AssetID) (uint64, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.AssetBalance(account, assetID)
+}
+
+func (a *threadSafeReadOnlyState) RetrieveEntry(account proto.Recipient, key string) (proto.DataEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.RetrieveEntry(account, key)
+}
+
+func (a *threadSafeReadOnlyState) ScriptBytesByAccount(account proto.Recipient) (proto.Script, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.ScriptBytesByAccount(account)
+}
+
 func (a *ThreadSafeReadWrapper) IsActiveLightNodeNewBlocksFields(blockHeight proto.Height) (bool, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 	return a.s.IsActiveLightNodeNewBlocksFields(blockHeight)
 }
 
+func (a *ThreadSafeReadWrapper) WarmCaches(ctx context.Context) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.s.WarmCaches(ctx)
+}
+
 func NewThreadSafeReadWrapper(mu *sync.RWMutex, s StateInfo) StateInfo {
 	return &ThreadSafeReadWrapper{
 		mu: mu,
@@ -506,6 +694,44 @@ func (a *ThreadSafeWriteWrapper) TxValidation(f func(validation TxValidation) er
 	return f(a.s)
 }
 
+// SelectNonConflicting takes the same lock as Map and TxValidation, so the dry-run doesn't interfere with the miner
+// and the UTX pool validation which use the same validation list.
+func (a *ThreadSafeWriteWrapper) SelectNonConflicting(
+	txs []proto.Transaction, currentTimestamp uint64,
+) ([]proto.Transaction, []proto.Transaction, error) {
+	a.lock()
+	defer a.unlock()
+	return a.s.SelectNonConflicting(txs, currentTimestamp)
+}
+
+func (a *ThreadSafeWriteWrapper) SetFeatureActivationHeight(featureID int16, height proto.Height) error {
+	a.lock()
+	defer a.unlock()
+	return a.s.SetFeatureActivationHeight(featureID, height)
+}
+
+func (a *ThreadSafeWriteWrapper) ReestimateScripts(
+	ctx context.Context, estimatorVersion int, from []byte, limit int,
+) (ScriptsReestimation, error) {
+	a.lock()
+	defer a.unlock()
+	return a.s.ReestimateScripts(ctx, estimatorVersion, from, limit)
+}
+
+// ReestimateAllScripts takes the lock for every batch of scripts separately, so blocks could be applied
+// between the batches.
+func (a *ThreadSafeWriteWrapper) ReestimateAllScripts(
+	ctx context.Context, estimatorVersion int, progress func(done, total int),
+) error {
+	return reestimateAllScripts(ctx, a, estimatorVersion, progress)
+}
+
+func (a *ThreadSafeWriteWrapper) ApplyStateDelta(delta []BlockSnapshots) error {
+	a.lock()
+	defer a.unlock()
+	return a.s.ApplyStateDelta(delta)
+}
+
 func (a *ThreadSafeWriteWrapper) StartProvidingExtendedApi() error {
 	a.lock()
 	defer a.unlock()
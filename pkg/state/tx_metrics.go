@@ -0,0 +1,60 @@
+package state
+
+import (
+	"time"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// TxMetrics is the execution metrics of the transaction applied in a block.
+// In light node mode the transaction isn't executed, the snapshot of the block is applied instead,
+// so only SnapshotApplication is measured and the other stages are zero.
+type TxMetrics struct {
+	ID   []byte
+	Type proto.TransactionType
+	// Validation is the time of checks of transaction data and signature.
+	Validation time.Duration
+	// ScriptExecution is the time of checks against state and execution of scripts.
+	ScriptExecution time.Duration
+	// SnapshotApplication is the time of application of transaction snapshots to state.
+	SnapshotApplication time.Duration
+}
+
+// TxMetricsCollector receives the execution metrics of every transaction applied during block application.
+// Transactions validated in UTX are not reported.
+type TxMetricsCollector interface {
+	TxApplied(blockID proto.BlockID, metrics TxMetrics)
+}
+
+// txMeter measures the stages of transaction application, it does nothing if it's disabled.
+type txMeter struct {
+	enabled bool
+	last    time.Time
+	metrics TxMetrics
+}
+
+func newTxMeter(enabled bool) txMeter {
+	if !enabled {
+		return txMeter{}
+	}
+	return txMeter{enabled: true, last: time.Now()}
+}
+
+// lap stores the time passed since the previous lap into the given stage.
+func (m *txMeter) lap(stage *time.Duration) {
+	if !m.enabled {
+		return
+	}
+	now := time.Now()
+	*stage = now.Sub(m.last)
+	m.last = now
+}
+
+func (m *txMeter) report(c TxMetricsCollector, blockID proto.BlockID, tx proto.Transaction, txID []byte) {
+	if !m.enabled {
+		return
+	}
+	m.metrics.ID = txID
+	m.metrics.Type = tx.GetTypeInfo().Type
+	c.TxApplied(blockID, m.metrics)
+}
@@ -60,6 +60,35 @@ func (ts txSnapshot) Apply(a extendedSnapshotApplier, tx proto.Transaction, vali
 	return nil
 }
 
+// ApplyBestEffort applies all atomic snapshots it can, the failures are collected and returned instead of aborting
+// the application on the first error. The transaction is passed to the applier's hooks and is stored along with
+// its status. If the transaction is nil, the transaction status snapshot is skipped, because there is nothing to store.
+// It's intended ONLY for offline analysis, like replaying historical snapshots, and MUST NOT be used in
+// consensus, which requires all-or-nothing application of snapshots.
+func (ts txSnapshot) ApplyBestEffort(a extendedSnapshotApplier, tx proto.Transaction) []error {
+	if err := a.BeforeTxSnapshotApply(tx, false); err != nil {
+		return []error{errors.Wrapf(err, "failed to execute before tx snapshot apply hook")}
+	}
+	var errs []error
+	for i, rs := range ts.regular {
+		if _, ok := rs.(*proto.TransactionStatusSnapshot); ok && tx == nil {
+			continue
+		}
+		if err := rs.Apply(a); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to apply regular transaction snapshot %d", i))
+		}
+	}
+	for i, is := range ts.internal {
+		if err := is.ApplyInternal(a); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to apply internal transaction snapshot %d", i))
+		}
+	}
+	if err := a.AfterTxSnapshotApply(); err != nil {
+		errs = append(errs, errors.Wrapf(err, "failed to execute after tx snapshot apply hook"))
+	}
+	return errs
+}
+
 func (ts txSnapshot) ApplyInitialSnapshot(a extendedSnapshotApplier) error {
 	// internal snapshots must be applied at the end
 	for _, rs := range ts.regular {
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// recordingSnapshotApplier records applied Waves balances and fails for the balances of the given address.
+type recordingSnapshotApplier struct {
+	extendedSnapshotApplier
+	failing  proto.WavesAddress
+	balances []proto.WavesBalanceSnapshot
+	internal int
+	after    bool
+	tx       proto.Transaction
+	statuses []proto.TransactionStatus
+}
+
+func (a *recordingSnapshotApplier) BeforeTxSnapshotApply(tx proto.Transaction, _ bool) error {
+	a.tx = tx
+	return nil
+}
+
+func (a *recordingSnapshotApplier) ApplyTransactionsStatus(snapshot proto.TransactionStatusSnapshot) error {
+	if a.tx == nil {
+		return errors.New("transaction is not set")
+	}
+	a.statuses = append(a.statuses, snapshot.Status)
+	return nil
+}
+
+func (a *recordingSnapshotApplier) AfterTxSnapshotApply() error {
+	a.after = true
+	return nil
+}
+
+func (a *recordingSnapshotApplier) ApplyWavesBalance(snapshot proto.WavesBalanceSnapshot) error {
+	if snapshot.Address == a.failing {
+		return errors.New("balance failure")
+	}
+	a.balances = append(a.balances, snapshot)
+	return nil
+}
+
+func (a *recordingSnapshotApplier) ApplyDAppComplexity(InternalDAppComplexitySnapshot) error {
+	a.internal++
+	return nil
+}
+
+func TestTxSnapshotApplyBestEffort(t *testing.T) {
+	good := testGlobal.senderInfo.addr
+	bad := testGlobal.recipientInfo.addr
+	ts := txSnapshot{
+		regular: []proto.AtomicSnapshot{
+			&proto.WavesBalanceSnapshot{Address: good, Balance: 1},
+			&proto.WavesBalanceSnapshot{Address: bad, Balance: 2},
+			&proto.WavesBalanceSnapshot{Address: good, Balance: 3},
+			&proto.WavesBalanceSnapshot{Address: bad, Balance: 4},
+		},
+		internal: []internalSnapshot{&InternalDAppComplexitySnapshot{ScriptAddress: good}},
+	}
+
+	a := &recordingSnapshotApplier{failing: bad}
+	errs := ts.ApplyBestEffort(a, nil)
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "failed to apply regular transaction snapshot 1: balance failure")
+	assert.EqualError(t, errs[1], "failed to apply regular transaction snapshot 3: balance failure")
+	assert.Equal(t, []proto.WavesBalanceSnapshot{{Address: good, Balance: 1}, {Address: good, Balance: 3}},
+		a.balances)
+	assert.Equal(t, 1, a.internal)
+	assert.True(t, a.after)
+
+	// Regular application stops on the first failure.
+	a = &recordingSnapshotApplier{failing: bad}
+	err := ts.Apply(a, nil, false)
+	assert.EqualError(t, err, "failed to apply regular transaction snapshot: balance failure")
+	assert.Len(t, a.balances, 1)
+	assert.False(t, a.after)
+}
+
+func TestTxSnapshotApplyBestEffortTransactionStatus(t *testing.T) {
+	addr := testGlobal.senderInfo.addr
+	ts := txSnapshot{
+		regular: []proto.AtomicSnapshot{
+			&proto.WavesBalanceSnapshot{Address: addr, Balance: 1},
+			&proto.TransactionStatusSnapshot{Status: proto.TransactionFailed},
+		},
+	}
+	tx := proto.NewUnsignedTransferWithProofs(2, testGlobal.senderInfo.pk, proto.NewOptionalAssetWaves(),
+		proto.NewOptionalAssetWaves(), defaultTimestamp, FeeUnit, FeeUnit,
+		proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), nil)
+
+	a := &recordingSnapshotApplier{}
+	require.Empty(t, ts.ApplyBestEffort(a, tx))
+	assert.Equal(t, tx, a.tx)
+	assert.Equal(t, []proto.TransactionStatus{proto.TransactionFailed}, a.statuses)
+	assert.Len(t, a.balances, 1)
+
+	// Without the transaction the status snapshot is skipped.
+	a = &recordingSnapshotApplier{}
+	require.Empty(t, ts.ApplyBestEffort(a, nil))
+	assert.Empty(t, a.statuses)
+	assert.Len(t, a.balances, 1)
+}
@@ -0,0 +1,163 @@
+package state
+
+import (
+	"context"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const (
+	// warmCachesBlocks is the number of the most recent blocks which transactions are used to warm up caches.
+	warmCachesBlocks = 100
+	// warmCachesMaxScripts limits the number of scripts loaded into the cache during warming up.
+	warmCachesMaxScripts = maxCacheSize / 10
+)
+
+// cachesWarmer loads the scripts of accounts and assets into the scripts cache and reads the sponsorships of
+// fee assets, so they get into the database cache. Every script and sponsorship is loaded only once.
+type cachesWarmer struct {
+	s            *stateManager
+	seen         map[string]struct{}
+	seenFees     map[crypto.Digest]struct{}
+	loaded       int
+	sponsorships int
+}
+
+func newCachesWarmer(s *stateManager) *cachesWarmer {
+	return &cachesWarmer{s: s, seen: make(map[string]struct{}), seenFees: make(map[crypto.Digest]struct{})}
+}
+
+func (w *cachesWarmer) full() bool {
+	return w.loaded >= warmCachesMaxScripts
+}
+
+func (w *cachesWarmer) warmAccount(addr proto.WavesAddress) error {
+	key := string(addr.Bytes())
+	if _, ok := w.seen[key]; ok {
+		return nil
+	}
+	w.seen[key] = struct{}{}
+	hasScript, err := w.s.stor.scriptsStorage.newestAccountHasScript(addr)
+	if err != nil || !hasScript {
+		return err
+	}
+	if _, err := w.s.stor.scriptsStorage.newestScriptByAddr(addr); err != nil {
+		return err
+	}
+	w.loaded++
+	return nil
+}
+
+func (w *cachesWarmer) warmAsset(asset proto.OptionalAsset) error {
+	if !asset.Present {
+		return nil
+	}
+	key := string(asset.ID.Bytes())
+	if _, ok := w.seen[key]; ok {
+		return nil
+	}
+	w.seen[key] = struct{}{}
+	assetID := proto.AssetIDFromDigest(asset.ID)
+	isSmart, err := w.s.stor.scriptsStorage.newestIsSmartAsset(assetID)
+	if err != nil || !isSmart {
+		return err
+	}
+	if _, err := w.s.stor.scriptsStorage.newestScriptByAsset(assetID); err != nil {
+		return err
+	}
+	w.loaded++
+	return nil
+}
+
+func (w *cachesWarmer) warmSponsorship(feeAsset proto.OptionalAsset) error {
+	if !feeAsset.Present {
+		return nil
+	}
+	if _, ok := w.seenFees[feeAsset.ID]; ok {
+		return nil
+	}
+	w.seenFees[feeAsset.ID] = struct{}{}
+	sponsored, err := w.s.stor.sponsoredAssets.newestIsSponsored(proto.AssetIDFromDigest(feeAsset.ID))
+	if err != nil {
+		return err
+	}
+	if sponsored {
+		w.sponsorships++
+	}
+	return nil
+}
+
+func (w *cachesWarmer) warmTransaction(tx proto.Transaction) error {
+	scheme := w.s.settings.AddressSchemeCharacter
+	sender, err := tx.GetSender(scheme)
+	if err != nil {
+		return err
+	}
+	senderAddr, err := sender.ToWavesAddress(scheme)
+	if err != nil {
+		return err
+	}
+	if err := w.warmAccount(senderAddr); err != nil {
+		return err
+	}
+	var (
+		assets   []proto.OptionalAsset
+		feeAsset proto.OptionalAsset
+	)
+	switch t := tx.(type) {
+	case *proto.TransferWithSig:
+		assets = []proto.OptionalAsset{t.AmountAsset, t.FeeAsset}
+		feeAsset = t.FeeAsset
+	case *proto.TransferWithProofs:
+		assets = []proto.OptionalAsset{t.AmountAsset, t.FeeAsset}
+		feeAsset = t.FeeAsset
+	case *proto.MassTransferWithProofs:
+		assets = []proto.OptionalAsset{t.Asset}
+	case *proto.InvokeScriptWithProofs:
+		dApp, rErr := w.s.NewestRecipientToAddress(t.ScriptRecipient)
+		if rErr != nil {
+			return rErr
+		}
+		if err := w.warmAccount(dApp); err != nil {
+			return err
+		}
+		assets = append(assets, t.FeeAsset)
+		feeAsset = t.FeeAsset
+		for _, p := range t.Payments {
+			assets = append(assets, p.Asset)
+		}
+	}
+	for _, a := range assets {
+		if err := w.warmAsset(a); err != nil {
+			return err
+		}
+	}
+	return w.warmSponsorship(feeAsset)
+}
+
+func (s *stateManager) WarmCaches(ctx context.Context) error {
+	height, err := s.Height()
+	if err != nil {
+		return wrapErr(RetrievalError, err)
+	}
+	w := newCachesWarmer(s)
+	for h := height; h > 0 && height-h < warmCachesBlocks && !w.full(); h-- {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block, err := s.BlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		for _, tx := range block.Transactions {
+			if w.full() {
+				break
+			}
+			if err := w.warmTransaction(tx); err != nil {
+				return wrapErr(RetrievalError, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wavesplatform/gowaves/pkg/importer"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+func TestWarmCaches(t *testing.T) {
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), settings.MustMainNetSettings())
+	err := manager.stateDB.addBlock(blockID0)
+	require.NoError(t, err, "addBlock() failed")
+	addr := testGlobal.senderInfo.addr
+	err = manager.stor.scriptsStorage.setAccountScript(addr, testGlobal.scriptBytes, testGlobal.senderInfo.pk, blockID0)
+	require.NoError(t, err, "setAccountScript() failed")
+	err = manager.flush()
+	require.NoError(t, err, "manager.flush() failed")
+	require.NoError(t, manager.stor.scriptsStorage.clearCache())
+
+	ss, ok := manager.stor.scriptsStorage.(*scriptsStorage)
+	require.True(t, ok)
+	key := accountScriptKey{addr.ID()}
+	_, cached := ss.cache.get(key.bytes())
+	require.False(t, cached)
+
+	tx := proto.NewUnsignedTransferWithProofs(2, testGlobal.senderInfo.pk, proto.NewOptionalAssetWaves(),
+		proto.NewOptionalAssetWaves(), defaultTimestamp, FeeUnit, FeeUnit,
+		proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), nil)
+	require.NoError(t, tx.Sign(proto.MainNetScheme, testGlobal.senderInfo.sk))
+	w := newCachesWarmer(manager)
+	require.NoError(t, w.warmTransaction(tx))
+	_, cached = ss.cache.get(key.bytes())
+	assert.True(t, cached)
+	assert.Equal(t, 1, w.loaded)
+	// Scripts are loaded only once.
+	require.NoError(t, w.warmTransaction(tx))
+	assert.Equal(t, 1, w.loaded)
+}
+
+func TestWarmCachesSponsorships(t *testing.T) {
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), settings.MustMainNetSettings())
+	err := manager.stateDB.addBlock(blockID0)
+	require.NoError(t, err, "addBlock() failed")
+	sponsored := testGlobal.asset0.asset
+	err = manager.stor.sponsoredAssets.sponsorAsset(sponsored.ID, 100, blockID0)
+	require.NoError(t, err, "sponsorAsset() failed")
+	err = manager.flush()
+	require.NoError(t, err, "manager.flush() failed")
+
+	transfer := func(feeAsset proto.OptionalAsset) proto.Transaction {
+		return proto.NewUnsignedTransferWithProofs(2, testGlobal.senderInfo.pk, proto.NewOptionalAssetWaves(),
+			feeAsset, defaultTimestamp, FeeUnit, FeeUnit,
+			proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), nil)
+	}
+	w := newCachesWarmer(manager)
+	require.NoError(t, w.warmTransaction(transfer(*sponsored)))
+	require.NoError(t, w.warmTransaction(transfer(*sponsored)))
+	require.NoError(t, w.warmTransaction(transfer(*testGlobal.asset1.asset)))
+	require.NoError(t, w.warmTransaction(transfer(proto.NewOptionalAssetWaves())))
+	assert.Equal(t, 1, w.sponsorships)
+	assert.Len(t, w.seenFees, 2)
+}
+
+func TestWarmCachesOfAppliedBlocks(t *testing.T) {
+	blocksPath, err := blocksPath()
+	require.NoError(t, err)
+	bs := settings.MustMainNetSettings()
+	manager := newTestStateManager(t, true, DefaultTestingStateParams(), bs)
+	err = importer.ApplyFromFile(
+		context.Background(),
+		importer.ImportParams{Schema: bs.AddressSchemeCharacter, BlockchainPath: blocksPath, LightNodeMode: false},
+		manager, 20, 1)
+	require.NoError(t, err, "ApplyFromFile() failed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, manager.WarmCaches(ctx), context.Canceled)
+	assert.NoError(t, manager.WarmCaches(context.Background()))
+}